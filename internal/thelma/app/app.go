@@ -8,6 +8,7 @@ import (
 	_ "github.com/broadinstitute/thelma/internal/thelma/app/logging" // import logging for side effects (trigger bootstrapping)
 	"github.com/broadinstitute/thelma/internal/thelma/app/metrics"
 	"github.com/broadinstitute/thelma/internal/thelma/app/paths"
+	"github.com/broadinstitute/thelma/internal/thelma/app/root"
 	"github.com/broadinstitute/thelma/internal/thelma/app/scratch"
 	"github.com/broadinstitute/thelma/internal/thelma/clients"
 	"github.com/broadinstitute/thelma/internal/thelma/ops"
@@ -37,6 +38,8 @@ type ThelmaApp interface {
 	Ops() ops.Ops
 	// Paths returns Paths for this ThelmaApp
 	Paths() paths.Paths
+	// Root returns the Thelma root directory for this ThelmaApp
+	Root() root.Root
 	// Scratch returns the Scratch instance for this ThelmaApp
 	Scratch() scratch.Scratch
 	// ShellRunner returns ShellRunner for this ThelmaApp
@@ -50,7 +53,7 @@ type ThelmaApp interface {
 }
 
 // New constructs a new ThelmaApp
-func New(cfg config.Config, creds credentials.Credentials, clients clients.Clients, installer autoupdate.AutoUpdate, scratch scratch.Scratch, shellRunner shell.Runner, stateLoader lazy.LazyE[terra.StateLoader], manageSingletons bool) (ThelmaApp, error) {
+func New(cfg config.Config, thelmaRoot root.Root, creds credentials.Credentials, clients clients.Clients, installer autoupdate.AutoUpdate, scratch scratch.Scratch, shellRunner shell.Runner, stateLoader lazy.LazyE[terra.StateLoader], manageSingletons bool) (ThelmaApp, error) {
 	app := &thelmaApp{}
 
 	// Initialize paths
@@ -66,6 +69,7 @@ func New(cfg config.Config, creds credentials.Credentials, clients clients.Clien
 		credentials:      creds,
 		installer:        installer,
 		paths:            _paths,
+		root:             thelmaRoot,
 		scratch:          scratch,
 		shellRunner:      shellRunner,
 		stateLoader:      stateLoader,
@@ -79,6 +83,7 @@ type thelmaApp struct {
 	credentials      credentials.Credentials
 	installer        autoupdate.AutoUpdate
 	paths            paths.Paths
+	root             root.Root
 	scratch          scratch.Scratch
 	shellRunner      shell.Runner
 	stateLoader      lazy.LazyE[terra.StateLoader]
@@ -102,13 +107,17 @@ func (t *thelmaApp) AutoUpdate() autoupdate.AutoUpdate {
 }
 
 func (t *thelmaApp) Ops() ops.Ops {
-	return ops.NewOps(t.clients)
+	return ops.NewOps(t.config, t.clients)
 }
 
 func (t *thelmaApp) Paths() paths.Paths {
 	return t.paths
 }
 
+func (t *thelmaApp) Root() root.Root {
+	return t.root
+}
+
 func (t *thelmaApp) Scratch() scratch.Scratch {
 	return t.scratch
 }
@@ -1,6 +1,11 @@
 package builder
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"time"
+
 	"github.com/broadinstitute/thelma/internal/thelma/app/autoupdate"
 	"github.com/broadinstitute/thelma/internal/thelma/app/metrics"
 	"github.com/broadinstitute/thelma/internal/thelma/app/scratch"
@@ -15,11 +20,40 @@ import (
 	"github.com/broadinstitute/thelma/internal/thelma/app/logging"
 	"github.com/broadinstitute/thelma/internal/thelma/app/root"
 	"github.com/broadinstitute/thelma/internal/thelma/clients"
+	sherlockClient "github.com/broadinstitute/thelma/internal/thelma/clients/sherlock"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	cacheState "github.com/broadinstitute/thelma/internal/thelma/state/providers/cache"
+	fileState "github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
 	sherlockState "github.com/broadinstitute/thelma/internal/thelma/state/providers/sherlock"
 	"github.com/broadinstitute/thelma/internal/thelma/utils/shell"
+	"github.com/pkg/errors"
+)
+
+// thelma configuration key for state provider settings
+const stateConfigKey = "state"
+
+// names of supported state providers
+const (
+	sherlockStateProvider = "sherlock"
+	fileStateProvider     = "file"
 )
 
+type stateConfig struct {
+	// Provider is the source Thelma should load state from
+	Provider string `default:"sherlock" validate:"oneof=sherlock file"`
+	File     struct {
+		// Path to a YAML or JSON state file, required if provider is "file"
+		Path string
+	}
+	Cache struct {
+		// Enabled if true, state loaded from Sherlock is cached on disk under $THELMA_ROOT/caches/state. Off by default,
+		// since cached state can be out of date; commands that act on state reload it regardless.
+		Enabled bool `default:"false"`
+		// TTL is how long cached state is used before it is reloaded from Sherlock
+		TTL time.Duration `default:"5m"`
+	}
+}
+
 // ThelmaBuilder is a utility for initializing new ThelmaApp instances
 type ThelmaBuilder interface {
 	// Build when first called, initializes a new ThelmaApp and saves it. Subsequent calls do nothing.
@@ -179,10 +213,10 @@ func (b *thelmaBuilder) Build() (app.ThelmaApp, error) {
 		}
 	}
 
-	stateLoader := b.buildStateLoader(cfg, _clients)
+	stateLoader := b.buildStateLoader(cfg, thelmaRoot, _clients)
 
 	// Initialize app
-	return app.New(cfg, _credentials, _clients, _installer, _scratch, shellRunner, stateLoader, b.manageSingletons)
+	return app.New(cfg, thelmaRoot, _credentials, _clients, _installer, _scratch, shellRunner, stateLoader, b.manageSingletons)
 }
 
 func (b *thelmaBuilder) buildShellRunner() (shell.Runner, error) {
@@ -197,7 +231,7 @@ func (b *thelmaBuilder) buildShellRunner() (shell.Runner, error) {
 	return shell.NewRunner(finder), nil
 }
 
-func (b *thelmaBuilder) buildStateLoader(cfg config.Config, clients clients.Clients) lazy.LazyE[terra.StateLoader] {
+func (b *thelmaBuilder) buildStateLoader(cfg config.Config, thelmaRoot root.Root, clients clients.Clients) lazy.LazyE[terra.StateLoader] {
 	if b.customStateLoader != nil {
 		return lazy.NewLazyE(func() (terra.StateLoader, error) {
 			return b.customStateLoader, nil
@@ -205,10 +239,72 @@ func (b *thelmaBuilder) buildStateLoader(cfg config.Config, clients clients.Clie
 	}
 
 	return lazy.NewLazyE(func() (terra.StateLoader, error) {
-		sherlock, err := clients.Sherlock()
-		if err != nil {
+		var stateCfg stateConfig
+		if err := cfg.Unmarshal(stateConfigKey, &stateCfg); err != nil {
 			return nil, err
 		}
-		return sherlockState.NewStateLoader(cfg.Home(), sherlock), nil
+		switch stateCfg.Provider {
+		case fileStateProvider:
+			if stateCfg.File.Path == "" {
+				return nil, errors.Errorf("%s.file.path must be set when %s.provider is %q", stateConfigKey, stateConfigKey, fileStateProvider)
+			}
+			return fileState.NewStateLoader(stateCfg.File.Path), nil
+		case sherlockStateProvider:
+			sherlockLoader := &deferredStateLoader{
+				build: func() (terra.StateLoader, error) {
+					sherlock, err := clients.Sherlock()
+					if err != nil {
+						return nil, err
+					}
+					return sherlockState.NewStateLoader(cfg.Home(), sherlock), nil
+				},
+			}
+			if !stateCfg.Cache.Enabled {
+				return sherlockLoader, nil
+			}
+			addr, err := sherlockClient.ConfiguredAddr(cfg)
+			if err != nil {
+				return nil, err
+			}
+			// key the cache file by Sherlock address, so switching between Sherlock instances doesn't return the wrong state
+			addrHash := sha256.Sum256([]byte(addr))
+			cacheFile := path.Join(thelmaRoot.CachesDir(), "state", fmt.Sprintf("sherlock-%x.json", addrHash[:8]))
+			return cacheState.NewStateLoader(cacheFile, stateCfg.Cache.TTL, sherlockLoader), nil
+		default:
+			return nil, errors.Errorf("unsupported state provider %q", stateCfg.Provider)
+		}
 	})
 }
+
+// deferredStateLoader defers construction of a state loader until state is first loaded. This lets the state cache
+// fall back to cached state if the loader can't be constructed (eg. because we're offline and can't get credentials).
+type deferredStateLoader struct {
+	build  func() (terra.StateLoader, error)
+	loader terra.StateLoader
+}
+
+func (d *deferredStateLoader) Load() (terra.State, error) {
+	if err := d.init(); err != nil {
+		return nil, err
+	}
+	return d.loader.Load()
+}
+
+func (d *deferredStateLoader) Reload() (terra.State, error) {
+	if err := d.init(); err != nil {
+		return nil, err
+	}
+	return d.loader.Reload()
+}
+
+func (d *deferredStateLoader) init() error {
+	if d.loader != nil {
+		return nil
+	}
+	loader, err := d.build()
+	if err != nil {
+		return err
+	}
+	d.loader = loader
+	return nil
+}
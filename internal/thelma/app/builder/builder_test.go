@@ -4,6 +4,8 @@ import (
 	"github.com/broadinstitute/thelma/internal/thelma/app/config"
 	"github.com/broadinstitute/thelma/internal/thelma/utils/shell"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path"
 	"testing"
 )
 
@@ -66,3 +68,51 @@ func TestSetShellRunner(t *testing.T) {
 
 	assert.Same(t, runner, _app.ShellRunner())
 }
+
+func TestFileStateProvider(t *testing.T) {
+	stateFile := path.Join(t.TempDir(), "state.yaml")
+	err := os.WriteFile(stateFile, []byte("environments: [{name: dev, base: live, lifecycle: static}]"), 0644)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	builder := NewBuilder().WithTestDefaults(t)
+	builder.SetConfigOverride("state.provider", "file")
+	builder.SetConfigOverride("state.file.path", stateFile)
+
+	_app, err := builder.Build()
+	if !assert.NoError(t, err) {
+		return
+	}
+	t.Cleanup(func() {
+		if err := _app.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	state, err := _app.State()
+	if !assert.NoError(t, err) {
+		return
+	}
+	exists, err := state.Environments().Exists("dev")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestFileStateProviderRequiresPath(t *testing.T) {
+	builder := NewBuilder().WithTestDefaults(t)
+	builder.SetConfigOverride("state.provider", "file")
+
+	_app, err := builder.Build()
+	if !assert.NoError(t, err) {
+		return
+	}
+	t.Cleanup(func() {
+		if err := _app.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	_, err = _app.State()
+	assert.ErrorContains(t, err, "state.file.path must be set")
+}
@@ -42,6 +42,8 @@ type Root interface {
 	ReleasesDir() string
 	// ShellDir path where Thelma generates shell scripts and utilities ($ROOT/shell)
 	ShellDir() string
+	// CheckpointsDir path where Thelma records progress of long-running operations, like BEE provisioning ($ROOT/checkpoints)
+	CheckpointsDir() string
 	// CreateDirectories create directories if they do not exist. Will be called as part of Thelma initialization
 	CreateDirectories() error
 }
@@ -114,6 +116,10 @@ func (r root) ShellDir() string {
 	return path.Join(r.Dir(), "shell")
 }
 
+func (r root) CheckpointsDir() string {
+	return path.Join(r.Dir(), "checkpoints")
+}
+
 func (r root) CreateDirectories() error {
 	dirs := []string{
 		r.CachesDir(),
@@ -122,6 +128,7 @@ func (r root) CreateDirectories() error {
 		r.LogDir(),
 		r.ReleasesDir(),
 		r.ShellDir(),
+		r.CheckpointsDir(),
 	}
 	for _, dir := range dirs {
 		if err := os.MkdirAll(dir, 0700); err != nil {
@@ -0,0 +1,365 @@
+package bee
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/filter"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// ActionType is a type of change a Reconciler can make to a BEE
+type ActionType string
+
+const (
+	// ActionCreate create the BEE from its template
+	ActionCreate ActionType = "create"
+	// ActionPin update the BEE's version overrides
+	ActionPin ActionType = "pin"
+	// ActionSync sync the BEE's Argo apps
+	ActionSync ActionType = "sync"
+	// ActionStart bring the BEE online
+	ActionStart ActionType = "start"
+	// ActionStop take the BEE offline
+	ActionStop ActionType = "stop"
+	// ActionSchedule replace the BEE's start/stop schedule
+	ActionSchedule ActionType = "schedule"
+	// ActionAutoDelete update the BEE's automatic deletion time
+	ActionAutoDelete ActionType = "auto-delete"
+)
+
+// autoDeleteTolerance how far a BEE's automatic deletion time can drift from its spec before it is updated. Specs
+// describe deletion relative to creation, and a new BEE's creation time is only known after it is created.
+const autoDeleteTolerance = time.Minute
+
+// Action is a single change in a Plan
+type Action struct {
+	Type        ActionType `json:"type" yaml:"type"`
+	Description string     `json:"description" yaml:"description"`
+}
+
+// Plan is the set of changes needed to bring a BEE in line with a Spec
+type Plan struct {
+	// Name of the BEE
+	Name string `json:"name" yaml:"name"`
+	// Actions changes that will be applied, in order
+	Actions []Action `json:"actions" yaml:"actions"`
+	// Conflicts differences between the spec and the existing BEE that can't be reconciled, because
+	// Thelma can't change the field on an existing BEE
+	Conflicts []string `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
+
+	spec Spec
+}
+
+// Empty returns true if the BEE already matches the spec
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0 && len(p.Conflicts) == 0
+}
+
+// Has returns true if the plan includes an action of the given type
+func (p *Plan) Has(actionType ActionType) bool {
+	for _, a := range p.Actions {
+		if a.Type == actionType {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the plan in a human-readable format
+func (p *Plan) String() string {
+	if p.Empty() {
+		return fmt.Sprintf("%s is up to date, no changes to apply\n", p.Name)
+	}
+	var sb strings.Builder
+	for _, c := range p.Conflicts {
+		sb.WriteString(fmt.Sprintf("! %s\n", c))
+	}
+	for _, a := range p.Actions {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", a.Type, a.Description))
+	}
+	return sb.String()
+}
+
+func (p *Plan) add(actionType ActionType, format string, args ...interface{}) {
+	p.Actions = append(p.Actions, Action{Type: actionType, Description: fmt.Sprintf(format, args...)})
+}
+
+func (p *Plan) conflict(format string, args ...interface{}) {
+	p.Conflicts = append(p.Conflicts, fmt.Sprintf(format, args...))
+}
+
+// ApplyOptions options for applying a Plan
+type ApplyOptions struct {
+	ProvisionExistingOptions
+	// ExportLogsOnFailure export container logs if a new BEE fails to come up
+	ExportLogsOnFailure bool
+}
+
+// Reconciler brings BEEs in line with declarative Specs, applying only what has changed
+type Reconciler interface {
+	// Plan computes the changes needed to bring the BEE described by the spec in line with it
+	Plan(spec Spec) (*Plan, error)
+	// Apply applies a plan. Returns an error without making any changes if the plan has conflicts.
+	Apply(plan *Plan, options ApplyOptions) (*Bee, error)
+}
+
+// NewReconciler returns a Reconciler that makes changes through the given Bees
+func NewReconciler(bees Bees) Reconciler {
+	return &reconciler{
+		bees: bees,
+		now:  time.Now,
+	}
+}
+
+type reconciler struct {
+	bees Bees
+	now  func() time.Time
+}
+
+func (r *reconciler) Plan(spec Spec) (*Plan, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Name: spec.Name, spec: spec}
+
+	matches, err := r.bees.FilterBees(filter.Environments().Matching(fmt.Sprintf("name == %q", spec.Name), func(e terra.Environment) bool {
+		return e.Name() == spec.Name
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		template, err := r.bees.GetTemplate(spec.Template)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkVersionReleases(spec, template); err != nil {
+			return nil, err
+		}
+		plan.add(ActionCreate, "create %s from template %s", spec.Name, spec.Template)
+		if spec.Offline {
+			plan.add(ActionStop, "stop %s", spec.Name)
+		}
+		return plan, nil
+	}
+
+	env := matches[0]
+	if err = checkVersionReleases(spec, env); err != nil {
+		return nil, err
+	}
+	planConflicts(plan, spec, env)
+
+	if !scheduleOptionsMatch(spec.scheduleOptions(), terra.ScheduleOptionsOf(env)) {
+		plan.add(ActionSchedule, "update start/stop schedule for %s", spec.Name)
+	}
+	if after, changed := autoDeleteChange(spec, env); changed {
+		plan.add(ActionAutoDelete, "delete %s after %s", spec.Name, after.UTC().Format(time.RFC3339))
+	}
+
+	if changes := versionChanges(spec, env); len(changes) > 0 {
+		plan.add(ActionPin, "update version overrides for %s (%s)", spec.Name, strings.Join(changes, ", "))
+	}
+
+	switch {
+	case spec.Offline && !env.Offline():
+		plan.add(ActionStop, "stop %s", spec.Name)
+	case !spec.Offline && env.Offline():
+		plan.add(ActionStart, "start %s", spec.Name)
+	case !spec.Offline && plan.Has(ActionPin):
+		// starting a BEE syncs it, and stopped BEEs will pick up new versions when they're next started,
+		// so a separate sync is only needed for running BEEs
+		plan.add(ActionSync, "sync Argo apps in %s", spec.Name)
+	}
+
+	return plan, nil
+}
+
+func (r *reconciler) Apply(plan *Plan, options ApplyOptions) (*Bee, error) {
+	if len(plan.Conflicts) > 0 {
+		return nil, errors.Errorf("can't apply spec to %s: %s", plan.Name, strings.Join(plan.Conflicts, "; "))
+	}
+
+	spec := plan.spec
+	var bee *Bee
+	var err error
+
+	for _, action := range plan.Actions {
+		log.Info().Msgf("Applying %s: %s", action.Type, action.Description)
+
+		switch action.Type {
+		case ActionCreate:
+			bee, err = r.bees.CreateWith(CreateOptions{
+				Template:      spec.Template,
+				CreateOptions: spec.createOptions(r.now()),
+				ProvisionOptions: ProvisionOptions{
+					PinOptions:               spec.pinOptions(),
+					Seed:                     !spec.Seed.Skip,
+					SeedOptions:              spec.seedOptions(),
+					ExportLogsOnFailure:      options.ExportLogsOnFailure,
+					ProvisionExistingOptions: options.ProvisionExistingOptions,
+				},
+			})
+		case ActionPin:
+			var env terra.Environment
+			if env, err = r.bees.GetBee(spec.Name); err != nil {
+				break
+			}
+			if env, err = r.bees.PinVersions(env, spec.pinOptions()); err != nil {
+				break
+			}
+			bee = &Bee{Environment: env}
+		case ActionSchedule:
+			bee, err = r.bees.SetSchedule(spec.Name, spec.scheduleOptions())
+		case ActionAutoDelete:
+			var env terra.Environment
+			if env, err = r.bees.GetBee(spec.Name); err != nil {
+				break
+			}
+			after, _ := autoDeleteChange(spec, env)
+			bee, err = r.bees.SetAutoDeleteAfter(spec.Name, after)
+		case ActionSync:
+			bee, err = r.bees.SyncWith(spec.Name, options.ProvisionExistingOptions)
+		case ActionStart, ActionStop:
+			bee, err = r.bees.StartStopWith(spec.Name, action.Type == ActionStop, StartStopOptions{
+				Notify: options.Notify,
+				Sync:   action.Type == ActionStart,
+			})
+		default:
+			err = errors.Errorf("unknown action type %q", action.Type)
+		}
+
+		if err != nil {
+			return bee, errors.Errorf("error applying %s to %s: %v", action.Type, spec.Name, err)
+		}
+	}
+
+	if bee == nil {
+		env, err := r.bees.GetBee(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+		bee = &Bee{Environment: env}
+	}
+	return bee, nil
+}
+
+// checkVersionReleases verifies that every release with a version override in the spec exists in the environment
+func checkVersionReleases(spec Spec, env terra.Environment) error {
+	for name := range spec.Versions {
+		if findRelease(env, name) == nil {
+			return errors.Errorf("spec has version overrides for %s, but %s has no release by that name", name, env.Name())
+		}
+	}
+	return nil
+}
+
+// planConflicts records differences in fields that Thelma can't change on an existing BEE
+func planConflicts(plan *Plan, spec Spec, env terra.Environment) {
+	if spec.Template != env.Template() {
+		plan.conflict("template is %s, spec has %s", env.Template(), spec.Template)
+	}
+	if spec.Owner != "" && spec.Owner != env.Owner() {
+		plan.conflict("owner is %s, spec has %s", env.Owner(), spec.Owner)
+	}
+	if spec.AutoDelete == nil && env.AutoDelete().Enabled() {
+		plan.conflict("auto-delete is enabled, spec has it disabled")
+	}
+}
+
+// autoDeleteChange returns the time the spec says the BEE should be deleted after, and whether that differs from the
+// BEE's current automatic deletion time
+func autoDeleteChange(spec Spec, env terra.Environment) (time.Time, bool) {
+	if spec.AutoDelete == nil {
+		return time.Time{}, false
+	}
+	after := env.CreatedAt().Add(spec.AutoDelete.After)
+	if !env.AutoDelete().Enabled() {
+		return after, true
+	}
+	drift := after.Sub(env.AutoDelete().After())
+	return after, drift > autoDeleteTolerance || drift < -autoDeleteTolerance
+}
+
+// scheduleOptionsMatch compares both the daily schedules and the cron/weekly schedule of two sets of schedule options
+func scheduleOptionsMatch(want terra.ScheduleOptions, have terra.ScheduleOptions) bool {
+	return dailyScheduleMatches(want.StopSchedule.Enabled, want.StopSchedule.RepeatingTime, false, have.StopSchedule.Enabled, have.StopSchedule.RepeatingTime, false) &&
+		dailyScheduleMatches(want.StartSchedule.Enabled, want.StartSchedule.RepeatingTime, want.StartSchedule.Weekends, have.StartSchedule.Enabled, have.StartSchedule.RepeatingTime, have.StartSchedule.Weekends) &&
+		schedulesMatch(want.Schedule, have.Schedule) &&
+		want.StartOnHolidays == have.StartOnHolidays
+}
+
+// dailyScheduleMatches compares daily schedules by time of day, since only the time of day of a repeating time is
+// significant
+func dailyScheduleMatches(wantEnabled bool, wantTime time.Time, wantWeekends bool, haveEnabled bool, haveTime time.Time, haveWeekends bool) bool {
+	if !wantEnabled || !haveEnabled {
+		return wantEnabled == haveEnabled
+	}
+	want := wantTime.UTC()
+	have := haveTime.UTC()
+	return want.Hour() == have.Hour() && want.Minute() == have.Minute() && wantWeekends == haveWeekends
+}
+
+// schedulesMatch compares cron/weekly schedules. A schedule that couldn't be read from state never matches, so
+// applying a spec repairs it.
+func schedulesMatch(want *schedule.Schedule, have *schedule.Schedule) bool {
+	if want == nil || have == nil {
+		return want == nil && have == nil
+	}
+	if have.Validate() != nil {
+		return false
+	}
+	return want.TimeZone == have.TimeZone && rulesMatch(want.Stop, have.Stop) && rulesMatch(want.Start, have.Start)
+}
+
+func rulesMatch(want *schedule.Rule, have *schedule.Rule) bool {
+	if want == nil || have == nil {
+		return want == nil && have == nil
+	}
+	return want.String() == have.String()
+}
+
+// versionChanges returns a description of each version in the spec that differs from the environment
+func versionChanges(spec Spec, env terra.Environment) []string {
+	var changes []string
+	if spec.TerraHelmfileRef != "" && spec.TerraHelmfileRef != env.TerraHelmfileRef() {
+		changes = append(changes, fmt.Sprintf("terraHelmfileRef %s -> %s", env.TerraHelmfileRef(), spec.TerraHelmfileRef))
+	}
+
+	var names []string
+	for name := range spec.Versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		override := spec.Versions[name]
+		release := findRelease(env, name)
+		if override.AppVersion != "" && override.AppVersion != release.AppVersion() {
+			changes = append(changes, fmt.Sprintf("%s appVersion %s -> %s", name, release.AppVersion(), override.AppVersion))
+		}
+		if override.ChartVersion != "" && override.ChartVersion != release.ChartVersion() {
+			changes = append(changes, fmt.Sprintf("%s chartVersion %s -> %s", name, release.ChartVersion(), override.ChartVersion))
+		}
+		if override.TerraHelmfileRef != "" && override.TerraHelmfileRef != release.TerraHelmfileRef() {
+			changes = append(changes, fmt.Sprintf("%s terraHelmfileRef %s -> %s", name, release.TerraHelmfileRef(), override.TerraHelmfileRef))
+		}
+	}
+	return changes
+}
+
+func findRelease(env terra.Environment, name string) terra.Release {
+	for _, r := range env.Releases() {
+		if r.Name() == name {
+			return r
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package bee
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	argocd_names "github.com/broadinstitute/thelma/internal/thelma/state/api/terra/argocd"
+	"github.com/broadinstitute/thelma/internal/thelma/toolbox/argocd"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func (suite *BeesTestSuite) TestReconcilerPlan() {
+	testCases := []struct {
+		name            string
+		spec            Spec
+		expectErr       string
+		expectActions   []ActionType
+		expectConflicts int
+	}{
+		{
+			name: "up to date",
+			spec: Spec{Name: beeName, Template: "swatomation", Owner: beeOwner},
+		},
+		{
+			name:          "new bee",
+			spec:          Spec{Name: "new-bee", Template: "swatomation"},
+			expectActions: []ActionType{ActionCreate},
+		},
+		{
+			name:          "new offline bee",
+			spec:          Spec{Name: "new-bee", Template: "swatomation", Offline: true},
+			expectActions: []ActionType{ActionCreate, ActionStop},
+		},
+		{
+			name: "version change",
+			spec: Spec{Name: beeName, Template: "swatomation", Versions: map[string]terra.VersionOverride{
+				"sam": {AppVersion: "sam-v3"},
+			}},
+			expectActions: []ActionType{ActionPin, ActionSync},
+		},
+		{
+			name: "matching versions",
+			spec: Spec{Name: beeName, Template: "swatomation", Versions: map[string]terra.VersionOverride{
+				"sam": {AppVersion: "sam-v2", ChartVersion: "4.5.6"},
+			}},
+		},
+		{
+			name:          "version change while stopping",
+			spec:          Spec{Name: beeName, Template: "swatomation", Offline: true, TerraHelmfileRef: "my-branch"},
+			expectActions: []ActionType{ActionPin, ActionStop},
+		},
+		{
+			name: "unknown release",
+			spec: Spec{Name: beeName, Template: "swatomation", Versions: map[string]terra.VersionOverride{
+				"nope": {AppVersion: "v1"},
+			}},
+			expectErr: "has no release by that name",
+		},
+		{
+			name:            "immutable fields",
+			spec:            Spec{Name: beeName, Template: "other-template", Owner: "someone-else@broadinstitute.org"},
+			expectConflicts: 2,
+		},
+		{
+			name:          "enable auto-delete",
+			spec:          Spec{Name: beeName, Template: "swatomation", AutoDelete: &AutoDeleteSpec{After: time.Hour}},
+			expectActions: []ActionType{ActionAutoDelete},
+		},
+		{
+			name:            "disable auto-delete",
+			spec:            Spec{Name: "expiring-bee", Template: "swatomation"},
+			expectConflicts: 1,
+		},
+		{
+			name: "daily schedule change",
+			spec: Spec{Name: beeName, Template: "swatomation", StopSchedule: &ScheduleSpec{
+				Time: time.Date(2022, 1, 1, 19, 0, 0, 0, time.UTC),
+			}},
+			expectActions: []ActionType{ActionSchedule},
+		},
+		{
+			name: "schedule change",
+			spec: Spec{Name: beeName, Template: "swatomation", Schedule: &schedule.Schedule{
+				TimeZone: "UTC",
+				Stop:     &schedule.Rule{Cron: "0 19 * * mon-fri"},
+			}},
+			expectActions: []ActionType{ActionSchedule},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			plan, err := NewReconciler(suite.bees).Plan(tc.spec)
+			if tc.expectErr != "" {
+				require.Error(suite.T(), err)
+				assert.Contains(suite.T(), err.Error(), tc.expectErr)
+				return
+			}
+			require.NoError(suite.T(), err)
+
+			var actions []ActionType
+			for _, a := range plan.Actions {
+				actions = append(actions, a.Type)
+			}
+			assert.Equal(suite.T(), tc.expectActions, actions)
+			assert.Len(suite.T(), plan.Conflicts, tc.expectConflicts)
+		})
+	}
+}
+
+func (suite *BeesTestSuite) TestReconcilerApply() {
+	suite.Run("pin and sync", func() {
+		spec := Spec{Name: beeName, Template: "swatomation", Versions: map[string]terra.VersionOverride{
+			"sam": {AppVersion: "sam-v3"},
+		}}
+		suite.expectPinReleaseVersions(map[string]terra.VersionOverride{"sam": {AppVersion: "sam-v3"}})
+		suite.mocks.argocd.EXPECT().SyncApp(argocd_names.GeneratorName(suite.env)).Return(argocd.SyncResult{Synced: true}, nil)
+		suite.expectSyncArgoAppsForReleases(true, 600)
+
+		r := NewReconciler(suite.bees)
+		plan, err := r.Plan(spec)
+		require.NoError(suite.T(), err)
+
+		bee, err := r.Apply(plan, ApplyOptions{ProvisionExistingOptions: ProvisionExistingOptions{
+			WaitHealthy:              true,
+			WaitHealthTimeoutSeconds: 600,
+		}})
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), beeName, bee.Environment.Name())
+	})
+
+	suite.Run("stop", func() {
+		suite.statefixture.Mocks().Environments.EXPECT().SetOffline(beeName, true).Return(nil)
+
+		r := NewReconciler(suite.bees)
+		plan, err := r.Plan(Spec{Name: beeName, Template: "swatomation", Offline: true})
+		require.NoError(suite.T(), err)
+
+		_, err = r.Apply(plan, ApplyOptions{})
+		require.NoError(suite.T(), err)
+	})
+
+	suite.Run("schedule and auto-delete", func() {
+		spec := Spec{
+			Name:            beeName,
+			Template:        "swatomation",
+			AutoDelete:      &AutoDeleteSpec{After: 72 * time.Hour},
+			Schedule:        &schedule.Schedule{TimeZone: "UTC", Stop: &schedule.Rule{Cron: "0 19 * * mon-fri"}},
+			StartOnHolidays: true,
+		}
+		suite.statefixture.Mocks().Environments.EXPECT().SetSchedule(beeName, spec.scheduleOptions()).Return(nil)
+		expected := time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)
+		suite.statefixture.Mocks().Environments.EXPECT().SetAutoDeleteAfter(beeName, mock.Anything).Run(func(_ string, after time.Time) {
+			assert.True(suite.T(), expected.Equal(after), "expected %s, got %s", expected, after)
+		}).Return(nil)
+
+		r := NewReconciler(suite.bees)
+		plan, err := r.Plan(spec)
+		require.NoError(suite.T(), err)
+		assert.Empty(suite.T(), plan.Conflicts)
+
+		_, err = r.Apply(plan, ApplyOptions{})
+		require.NoError(suite.T(), err)
+	})
+
+	suite.Run("conflicts are not applied", func() {
+		r := NewReconciler(suite.bees)
+		plan, err := r.Plan(Spec{Name: beeName, Template: "swatomation", Owner: "someone-else@broadinstitute.org"})
+		require.NoError(suite.T(), err)
+
+		_, err = r.Apply(plan, ApplyOptions{})
+		require.Error(suite.T(), err)
+		assert.Contains(suite.T(), err.Error(), "owner is "+beeOwner)
+	})
+}
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec([]byte(`
+name: my-bee
+template: swatomation
+versions:
+  sam:
+    appVersion: 1.2.3
+autoDelete:
+  after: 72h
+startSchedule:
+  time: 2022-01-01T07:00:00-05:00
+  weekends: true
+seed:
+  skipSteps: [create-agora]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", spec.Versions["sam"].AppVersion)
+	assert.Equal(t, 72*time.Hour, spec.AutoDelete.After)
+	assert.Equal(t, 12, spec.StartSchedule.Time.UTC().Hour())
+	assert.True(t, spec.StartSchedule.Weekends)
+
+	opts := spec.seedOptions()
+	assert.True(t, opts.Step1CreateElasticsearch)
+	assert.False(t, opts.Step5CreateAgora)
+
+	_, err = ParseSpec([]byte("name: my-bee\n"))
+	assert.ErrorContains(t, err, "missing required field: template")
+
+	_, err = ParseSpec([]byte("name: my-bee\ntemplate: swatomation\nschedule:\n  stop:\n    cron: 0 19 * * *\n"))
+	assert.ErrorContains(t, err, "invalid schedule")
+
+	_, err = ParseSpec([]byte("name: my-bee\ntemplate: swatomation\nseed:\n  skipSteps: [nope]\n"))
+	assert.ErrorContains(t, err, `invalid seed step "nope"`)
+}
@@ -4,17 +4,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/avast/retry-go"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/checkpoint"
 	"github.com/broadinstitute/thelma/internal/thelma/bee/cleanup"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/hibernation"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/profiles"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/quotas"
+	"github.com/broadinstitute/thelma/internal/thelma/clients/sherlock"
 	"github.com/broadinstitute/thelma/internal/thelma/clients/slack"
 	"github.com/broadinstitute/thelma/internal/thelma/ops"
 	"github.com/broadinstitute/thelma/internal/thelma/ops/artifacts"
 	"github.com/broadinstitute/thelma/internal/thelma/ops/logs"
 	"github.com/broadinstitute/thelma/internal/thelma/ops/status"
 	"github.com/pkg/errors"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/broadinstitute/thelma/internal/thelma/bee/seed"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/snapshots"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
 	argocd_names "github.com/broadinstitute/thelma/internal/thelma/state/api/terra/argocd"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/filter"
@@ -29,6 +36,7 @@ const beeDocLink = "https://broadworkbench.atlassian.net/wiki/spaces/IA/pages/28
 type Bees interface {
 	DeleteWith(name string, options DeleteOptions) (*Bee, error)
 	CreateWith(options CreateOptions) (*Bee, error)
+	CloneWith(sourceName string, options CloneOptions) (*Bee, error)
 	ProvisionWith(name string, options ProvisionOptions) (*Bee, error)
 	SyncWith(name string, options ProvisionExistingOptions) (*Bee, error)
 	StartStopWith(name string, offline bool, options StartStopOptions) (*Bee, error)
@@ -42,24 +50,65 @@ type Bees interface {
 	SyncArgoAppsIn(env terra.Environment, options ...argocd.SyncOption) (map[terra.Release]*status.Status, error)
 	ResetStatefulSets(env terra.Environment) (map[terra.Release]*status.Status, error)
 	RefreshBeeGenerator() error
+	NotifyExpiring(within time.Duration, options NotifyExpiringOptions) ([]ExpiryNotice, error)
+	Extend(name string, by time.Duration) (*Bee, error)
+	// SetAutoDeleteAfter schedules the BEE for automatic deletion after the given time, enabling automatic deletion if
+	// it isn't already
+	SetAutoDeleteAfter(name string, after time.Time) (*Bee, error)
+	// SetSchedule replaces the BEE's stop/start schedule settings; schedules that aren't enabled in the options are
+	// removed
+	SetSchedule(name string, options terra.ScheduleOptions) (*Bee, error)
+	GC(options GCOptions) (*GCReport, error)
+	Doctor(filter terra.EnvironmentFilter, options DoctorOptions) (*DoctorReport, error)
+	CreateSnapshot(name string, options SnapshotOptions) ([]snapshots.Snapshot, error)
+	ListSnapshots(name string) ([]snapshots.Snapshot, error)
+	RestoreSnapshot(name string, snapshotName string) (*Bee, error)
 }
 
 type DeleteOptions struct {
 	Unseed     bool
 	ExportLogs bool
+	// Checkpoints optional store to remove the BEE's provisioning checkpoint from
+	Checkpoints checkpoint.Store
+	// Hibernation optional store of hibernation manifests; if the BEE is hibernated, its volume snapshots are deleted
+	Hibernation hibernation.Store
 }
 
 type CreateOptions struct {
 	Template string
+	// Profile optional release profile; if set, releases in the template that aren't in the profile are disabled
+	// before the BEE is first synced
+	Profile *profiles.Profile
+	// IgnoreQuotas admin override to create the BEE even if it would exceed a quota, eg. for CI service accounts.
+	// Only allowed for users in the quotas' override allowlist or holding one of their override roles.
+	IgnoreQuotas bool
 	terra.CreateOptions
 	ProvisionOptions
 }
 
+type CloneOptions struct {
+	// Name to assign to the new BEE; if empty, a name will be generated
+	Name string
+	// Owner to assign to the new BEE; ignored if CopyOwner is true
+	Owner string
+	// CopyOwner copy the source BEE's owner to the new BEE
+	CopyOwner bool
+	// CopySchedule copy the source BEE's start and stop schedules to the new BEE
+	CopySchedule bool
+	// IgnoreQuotas admin override to create the BEE even if it would exceed a quota
+	IgnoreQuotas bool
+	ProvisionOptions
+}
+
 type ProvisionOptions struct {
 	PinOptions          PinOptions
 	Seed                bool
 	SeedOptions         seed.SeedOptions
 	ExportLogsOnFailure bool
+	// Checkpoints optional store for recording provisioning progress; if nil, progress is not recorded
+	Checkpoints checkpoint.Store
+	// Resume skip phases and seed steps that a previous provisioning attempt recorded as complete in Checkpoints
+	Resume bool
 	ProvisionExistingOptions
 }
 
@@ -83,6 +132,17 @@ type PinOptions struct {
 type StartStopOptions struct {
 	Notify bool
 	Sync   bool
+	// SkipWaitHealthy when syncing, don't wait for services to become healthy
+	SkipWaitHealthy bool
+	// Hibernate when stopping, snapshot the BEE's persistent volumes and delete its PVCs so disks don't bill while
+	// it's offline. Requires Hibernation.
+	Hibernate bool
+	// SnapshotClass VolumeSnapshotClass to use when hibernating; defaults to hibernation.DefaultSnapshotClass
+	SnapshotClass string
+	// Hibernation optional store for hibernation manifests. When starting, if the store has a manifest for the BEE,
+	// its PVCs are restored from snapshots before services come back up, and the snapshots are deleted once they
+	// have. Restoring requires Sync.
+	Hibernation hibernation.Store
 }
 
 // Bee encapsulates operational information about a BEE
@@ -92,7 +152,7 @@ type Bee struct {
 	ContainerLogsURL string
 }
 
-func NewBees(argocd argocd.ArgoCD, stateLoader terra.StateLoader, seeder seed.Seeder, cleanup cleanup.Cleanup, kubectl kubectl.Kubectl, ops ops.Ops, slack slack.Slack) (Bees, error) {
+func NewBees(argocd argocd.ArgoCD, stateLoader terra.StateLoader, seeder seed.Seeder, cleanup cleanup.Cleanup, snapshots snapshots.Snapshots, kubectl kubectl.Kubectl, ops ops.Ops, slack slack.Slack, quotas quotas.Quotas, users sherlock.Users) (Bees, error) {
 	state, err := stateLoader.Load()
 	if err != nil {
 		return nil, err
@@ -104,9 +164,12 @@ func NewBees(argocd argocd.ArgoCD, stateLoader terra.StateLoader, seeder seed.Se
 		stateLoader: stateLoader,
 		seeder:      seeder,
 		cleanup:     cleanup,
+		snapshots:   snapshots,
 		kubectl:     kubectl,
 		ops:         ops,
 		slack:       slack,
+		quotas:      quotas,
+		users:       users,
 	}, nil
 }
 
@@ -118,8 +181,11 @@ type bees struct {
 	seeder      seed.Seeder
 	kubectl     kubectl.Kubectl
 	cleanup     cleanup.Cleanup
+	snapshots   snapshots.Snapshots
 	ops         ops.Ops
 	slack       slack.Slack
+	quotas      quotas.Quotas
+	users       sherlock.Users
 }
 
 func (b *bees) CreateWith(options CreateOptions) (*Bee, error) {
@@ -129,13 +195,38 @@ func (b *bees) CreateWith(options CreateOptions) (*Bee, error) {
 		return nil, err
 	}
 
-	envName, err := b.state.Environments().CreateFromTemplate(template, options.CreateOptions)
+	// validate the profile before creating anything
+	var disabled []string
+	if options.Profile != nil {
+		disabled, err = options.Profile.Disabled(template)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	createOptions := options.CreateOptions
+	if err = b.checkQuotas(template, &createOptions, options.IgnoreQuotas); err != nil {
+		return nil, err
+	}
+	if options.Profile != nil {
+		createOptions.Profile = options.Profile.Name
+	}
+	envName, err := b.state.Environments().CreateFromTemplate(template, createOptions)
 	if err != nil {
 		return nil, err
 	}
 
 	log.Info().Msgf("Created new environment %s", envName)
 
+	if options.Profile != nil {
+		log.Info().Msgf("Applying profile %s to %s: disabling %d of %d releases", options.Profile.Name, envName, len(disabled), len(template.Releases()))
+		for _, release := range disabled {
+			if err = b.state.Environments().DisableRelease(envName, release); err != nil {
+				return nil, b.rollbackCreate(envName, errors.Errorf("error applying profile %s to %s: %v", options.Profile.Name, envName, err))
+			}
+		}
+	}
+
 	// Reload state; required since "creating an environment" just returns the name of what was created.
 	if err = b.reloadState(); err != nil {
 		return nil, err
@@ -143,6 +234,79 @@ func (b *bees) CreateWith(options CreateOptions) (*Bee, error) {
 	return b.ProvisionWith(envName, options.ProvisionOptions)
 }
 
+// cloneProfile returns a profile that keeps exactly the source BEE's enabled releases, so that a clone of a BEE
+// created with a profile (or with releases disabled by hand) isn't a full BEE. Returns nil if every release in the
+// source's template is enabled.
+func (b *bees) cloneProfile(source terra.Environment) (*profiles.Profile, error) {
+	template, err := b.GetTemplate(source.Template())
+	if err != nil {
+		return nil, err
+	}
+	if source.Profile() == "" && len(source.Releases()) == len(template.Releases()) {
+		return nil, nil
+	}
+	profile := &profiles.Profile{Name: source.Profile()}
+	for _, r := range source.Releases() {
+		profile.Releases = append(profile.Releases, r.Name())
+	}
+	sort.Strings(profile.Releases)
+	return profile, nil
+}
+
+// rollbackCreate deletes a newly-created environment from state after a failure that happened before anything was
+// deployed to it, so that the half-created BEE doesn't linger
+func (b *bees) rollbackCreate(envName string, cause error) error {
+	log.Warn().Msgf("Deleting %s from state: %v", envName, cause)
+	if err := b.state.Environments().Delete(envName); err != nil {
+		return errors.Errorf("%v; additionally, error deleting %s from state: %v (delete it with `thelma bee delete -n %s`)", cause, envName, err, envName)
+	}
+	return cause
+}
+
+// CloneWith creates a new BEE from the same template as the source BEE, with every release pinned to the
+// source BEE's current chart version, app version, and terra-helmfile ref.
+// Any PinOptions in the supplied options are ignored.
+func (b *bees) CloneWith(sourceName string, options CloneOptions) (*Bee, error) {
+	source, err := b.GetBee(sourceName)
+	if err != nil {
+		return nil, err
+	}
+	if source.Template() == "" {
+		return nil, errors.Errorf("can't clone %s: it has no template", sourceName)
+	}
+
+	overrides := make(map[string]terra.VersionOverride)
+	for _, r := range source.Releases() {
+		overrides[r.Name()] = terra.VersionOverride{
+			AppVersion:       r.AppVersion(),
+			ChartVersion:     r.ChartVersion(),
+			TerraHelmfileRef: r.TerraHelmfileRef(),
+		}
+	}
+
+	createOptions := CreateOptions{
+		Template:         source.Template(),
+		ProvisionOptions: options.ProvisionOptions,
+	}
+	createOptions.Name = options.Name
+	createOptions.Owner = options.Owner
+	if options.CopyOwner {
+		createOptions.Owner = source.Owner()
+	}
+	if options.CopySchedule {
+		createOptions.ScheduleOptions = terra.ScheduleOptionsOf(source)
+	}
+	createOptions.IgnoreQuotas = options.IgnoreQuotas
+	createOptions.PinOptions = PinOptions{FileOverrides: overrides}
+	createOptions.Profile, err = b.cloneProfile(source)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Msgf("Cloning %s (template %s) with %d pinned release version(s)", sourceName, source.Template(), len(overrides))
+	return b.CreateWith(createOptions)
+}
+
 func (b *bees) ProvisionWith(name string, options ProvisionOptions) (*Bee, error) {
 	bee, err := b.provisionBee(name, options)
 
@@ -190,21 +354,32 @@ func (b *bees) provisionBee(name string, options ProvisionOptions) (*Bee, error)
 		Environment: env,
 	}
 
-	env, err = b.PinVersions(env, options.PinOptions)
+	_progress, err := newProgress(name, options)
 	if err != nil {
-		return bee, errors.Errorf("error pinning versions for environment %q: %v", name, err)
+		return bee, err
 	}
-	if env == nil {
-		// don't think this could ever happen, but let's provide a useful error anyway
-		return bee, errors.Errorf("error pinning versions for environment %q: returned env is nil?", name)
+
+	if !_progress.skip(checkpoint.PinVersions) {
+		env, err = b.PinVersions(env, options.PinOptions)
+		if err != nil {
+			return bee, errors.Errorf("error pinning versions for environment %q: %v", name, err)
+		}
+		if env == nil {
+			// don't think this could ever happen, but let's provide a useful error anyway
+			return bee, errors.Errorf("error pinning versions for environment %q: returned env is nil?", name)
+		}
+		bee.Environment = env
+		_progress.complete(checkpoint.PinVersions)
 	}
-	bee.Environment = env
 
-	if err = b.provisionBeeNamespaceAndGenerator(bee); err != nil {
+	if err = b.provisionBeeNamespaceAndGenerator(bee, _progress); err != nil {
 		return bee, err
 	}
 
-	err = b.provisionBeeAppsAndSeed(bee, options)
+	err = b.provisionBeeAppsAndSeed(bee, options, _progress)
+	if err == nil {
+		_progress.finish()
+	}
 
 	if err != nil && options.ExportLogsOnFailure {
 		_, logErr := b.exportLogs(bee)
@@ -251,11 +426,18 @@ func (b *bees) trySendBeeProvisionNotification(env terra.Environment, maybeErr e
 	}
 }
 
-func (b *bees) provisionBeeNamespaceAndGenerator(bee *Bee) error {
+func (b *bees) provisionBeeNamespaceAndGenerator(bee *Bee, _progress *progress) error {
 	env := bee.Environment
 
-	if err := b.kubectl.CreateNamespace(env); err != nil {
-		return errors.Errorf("error creating namespace for environment %q: %v", env.Name(), err)
+	if !_progress.skip(checkpoint.Namespace) {
+		if err := b.kubectl.CreateNamespace(env); err != nil {
+			return errors.Errorf("error creating namespace for environment %q: %v", env.Name(), err)
+		}
+		_progress.complete(checkpoint.Namespace)
+	}
+
+	if _progress.skip(checkpoint.Generator) {
+		return nil
 	}
 
 	retryOptions := []retry.Option{
@@ -266,7 +448,7 @@ func (b *bees) provisionBeeNamespaceAndGenerator(bee *Bee) error {
 		}),
 	}
 
-	return retry.Do(func() error {
+	err := retry.Do(func() error {
 		log.Info().Msgf("Provisioning environment generator for %s...", env.Name())
 
 		if err := b.RefreshBeeGenerator(); err != nil {
@@ -283,38 +465,70 @@ func (b *bees) provisionBeeNamespaceAndGenerator(bee *Bee) error {
 		return nil
 	},
 		retryOptions...)
-}
-
-func (b *bees) provisionBeeAppsAndSeed(bee *Bee, options ProvisionOptions) error {
-	env := bee.Environment
-	if err := b.provisionBeeApps(bee, options.ProvisionExistingOptions); err != nil {
+	if err != nil {
 		return err
 	}
 
-	if options.Seed {
-		log.Info().Msgf("Seeding BEE with test data")
+	_progress.complete(checkpoint.Generator)
+	return nil
+}
 
-		retryOptions := []retry.Option{
-			retry.Attempts(10),
-			retry.Delay(1 * time.Second),
-			retry.DelayType(retry.BackOffDelay),
-			retry.MaxDelay(2 * time.Minute),
-			retry.OnRetry(func(n uint, err error) {
-				log.Warn().Msgf("Seeding attempt %d failed: %v", int(n)+1, err)
-			}),
+func (b *bees) provisionBeeAppsAndSeed(bee *Bee, options ProvisionOptions, _progress *progress) error {
+	env := bee.Environment
+	if !_progress.skip(checkpoint.SyncApps) {
+		if err := b.provisionBeeApps(bee, options.ProvisionExistingOptions); err != nil {
+			return err
 		}
+		_progress.complete(checkpoint.SyncApps)
+	}
 
-		err := retry.Do(func() error {
-			return b.seeder.Seed(env, options.SeedOptions)
-		}, retryOptions...)
+	if !options.Seed || _progress.skip(checkpoint.Seed) {
+		return nil
+	}
 
-		if err != nil {
+	log.Info().Msgf("Seeding BEE with test data")
+
+	if _progress == nil {
+		if err := b.seedWithRetries(env, options.SeedOptions); err != nil {
 			return errors.Errorf("error seeding environment %q after retries: %v", env.Name(), err)
 		}
+		return nil
+	}
+
+	// when recording progress, run seed steps individually so that a failed seed can be resumed from the failed step
+	steps, err := b.seeder.Steps(options.SeedOptions)
+	if err != nil {
+		return errors.Errorf("error listing seed steps for environment %q: %v", env.Name(), err)
+	}
+	for _, step := range steps {
+		if _progress.skipSeedStep(step.Name) {
+			continue
+		}
+		if err := b.seedWithRetries(env, step.Options); err != nil {
+			return errors.Errorf("error seeding environment %q (step %s) after retries: %v", env.Name(), step.Name, err)
+		}
+		_progress.completeSeedStep(step.Name)
 	}
+	_progress.complete(checkpoint.Seed)
 	return nil
 }
 
+func (b *bees) seedWithRetries(env terra.Environment, seedOptions seed.SeedOptions) error {
+	retryOptions := []retry.Option{
+		retry.Attempts(10),
+		retry.Delay(1 * time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.MaxDelay(2 * time.Minute),
+		retry.OnRetry(func(n uint, err error) {
+			log.Warn().Msgf("Seeding attempt %d failed: %v", int(n)+1, err)
+		}),
+	}
+
+	return retry.Do(func() error {
+		return b.seeder.Seed(env, seedOptions)
+	}, retryOptions...)
+}
+
 func (b *bees) provisionBeeApps(bee *Bee, options ProvisionExistingOptions) error {
 	if err := b.SyncEnvironmentGenerator(bee.Environment); err != nil {
 		return errors.Errorf("error syncing environment generator for %s: %v", bee.Environment.Name(), err)
@@ -374,6 +588,11 @@ func (b *bees) DeleteWith(name string, options DeleteOptions) (*Bee, error) {
 		}
 	}
 
+	// volume snapshots live in the namespace, so remove them before it's deleted
+	if options.Hibernation != nil {
+		b.removeHibernation(env, options.Hibernation)
+	}
+
 	if err = b.kubectl.DeleteNamespace(env); err != nil {
 		return bee, err
 	}
@@ -388,6 +607,12 @@ func (b *bees) DeleteWith(name string, options DeleteOptions) (*Bee, error) {
 
 	log.Info().Msgf("Deleted environment %s from state", name)
 
+	if options.Checkpoints != nil {
+		if err = options.Checkpoints.Delete(name); err != nil {
+			log.Warn().Err(err).Msgf("error deleting provisioning checkpoint for %s: %v", name, err)
+		}
+	}
+
 	log.Info().Msgf("Deleting Argo apps for %s", name)
 	if err = b.RefreshBeeGenerator(); err != nil {
 		return bee, err
@@ -404,6 +629,29 @@ func (b *bees) StartStopWith(name string, offline bool, options StartStopOptions
 		stateDescription = "started"
 	}
 
+	var manifest *hibernation.Manifest
+	if offline && options.Hibernate {
+		stateDescription = "hibernated"
+		if err := b.hibernate(name, options); err != nil {
+			return nil, err
+		}
+	} else if !offline && options.Hibernation != nil {
+		var err error
+		if manifest, err = options.Hibernation.Load(name); err != nil {
+			return nil, err
+		}
+		if manifest != nil {
+			// restored claims may not be bound until something mounts them, so the snapshots can only be deleted
+			// once services are back up
+			if !options.Sync {
+				return nil, errors.Errorf("%s is hibernated, its volumes can only be restored when syncing services", name)
+			}
+			if err = b.restoreFromHibernation(name, manifest); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if err := b.state.Environments().SetOffline(name, offline); err != nil {
 		return nil, err
 	}
@@ -425,14 +673,21 @@ func (b *bees) StartStopWith(name string, offline bool, options StartStopOptions
 	}
 
 	if options.Sync {
-		statuses, err := b.SyncArgoAppsIn(env, func(options *argocd.SyncOptions) {
-			options.SkipLegacyConfigsRestart = true
+		statuses, err := b.SyncArgoAppsIn(env, func(_options *argocd.SyncOptions) {
+			_options.SkipLegacyConfigsRestart = true
+			if options.SkipWaitHealthy {
+				_options.WaitHealthy = false
+			}
 		})
 		bee.Status = statuses
 		if err != nil {
 			return bee, err
 		}
 	}
+	if manifest != nil {
+		// services are back up on the restored volumes, so the snapshots and manifest are no longer needed
+		b.removeHibernation(env, options.Hibernation)
+	}
 	if options.Notify && env.Owner() != "" && b.slack != nil {
 		markdown := fmt.Sprintf("Your <https://broad.io/beehive/r/environment/%s|%s> BEE has been %s", env.Name(), env.Name(), stateDescription)
 		if err := b.slack.SendDirectMessage(env.Owner(), markdown); err != nil {
@@ -597,6 +852,63 @@ func (b *bees) FilterBees(_filter terra.EnvironmentFilter) ([]terra.Environment,
 	return _filter.Filter(allEnvs), nil
 }
 
+// checkQuotas returns an error if the authenticated Sherlock user, or the owner the BEE is being created for, already
+// holds as many BEEs as they're allowed, or if there are already as many BEEs from the template as there can be.
+// The caller is always charged, so that naming someone else as the owner doesn't get around the per-owner quota.
+// If no owner was given, the BEE is created for the caller, since that's who Sherlock would make the owner anyway.
+func (b *bees) checkQuotas(template terra.Environment, options *terra.CreateOptions, ignoreQuotas bool) error {
+	if ignoreQuotas {
+		user, err := b.users.CurrentUser()
+		if err != nil {
+			return errors.Errorf("error looking up current user to authorize ignoring BEE quotas: %v", err)
+		}
+		if err = b.quotas.AuthorizeOverride(user); err != nil {
+			return err
+		}
+		log.Info().Msgf("Ignoring BEE quotas for %s", user.Email)
+		return nil
+	}
+	if b.quotas.MaxPerOwner == 0 && b.quotas.MaxPerTemplate == 0 {
+		return nil
+	}
+
+	var owners []string
+	if b.quotas.MaxPerOwner > 0 {
+		user, err := b.users.CurrentUser()
+		if err != nil {
+			return errors.Errorf("error looking up current user to check BEE quotas: %v", err)
+		}
+		if options.Owner == "" {
+			options.Owner = user.Email
+		}
+		owners = append(owners, user.Email)
+		if !strings.EqualFold(options.Owner, user.Email) {
+			owners = append(owners, options.Owner)
+		}
+	}
+
+	// state may be cached, and other BEEs may have been created since it was loaded
+	if err := b.reloadState(); err != nil {
+		return err
+	}
+
+	for _, owner := range owners {
+		owned, err := b.FilterBees(filter.Environments().HasOwner(owner))
+		if err != nil {
+			return err
+		}
+		if err = b.quotas.CheckOwner(owner, owned); err != nil {
+			return err
+		}
+	}
+
+	fromTemplate, err := b.FilterBees(filter.Environments().HasTemplate(template))
+	if err != nil {
+		return err
+	}
+	return b.quotas.CheckTemplate(template.Name(), fromTemplate)
+}
+
 func (b *bees) templateNames() ([]string, error) {
 	allEnvs, err := b.state.Environments().All()
 	if err != nil {
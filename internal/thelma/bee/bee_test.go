@@ -2,28 +2,42 @@ package bee
 
 // Basic imports
 import (
+	"github.com/broadinstitute/thelma/internal/thelma/bee/checkpoint"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/cleanup"
 	cleanupmocks "github.com/broadinstitute/thelma/internal/thelma/bee/cleanup/mocks"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/hibernation"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/profiles"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/quotas"
 	"github.com/broadinstitute/thelma/internal/thelma/bee/seed"
 	seedmocks "github.com/broadinstitute/thelma/internal/thelma/bee/seed/mocks"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/snapshots"
+	snapshotsmocks "github.com/broadinstitute/thelma/internal/thelma/bee/snapshots/mocks"
+	bucketmocks "github.com/broadinstitute/thelma/internal/thelma/clients/google/bucket/testing/mocks"
+	"github.com/broadinstitute/thelma/internal/thelma/clients/sherlock"
+	sherlockmocks "github.com/broadinstitute/thelma/internal/thelma/clients/sherlock/mocks"
 	slackmocks "github.com/broadinstitute/thelma/internal/thelma/clients/slack/mocks"
 	"github.com/broadinstitute/thelma/internal/thelma/ops/artifacts"
 	"github.com/broadinstitute/thelma/internal/thelma/ops/logs"
 	logsmocks "github.com/broadinstitute/thelma/internal/thelma/ops/logs/mocks"
 	opsmocks "github.com/broadinstitute/thelma/internal/thelma/ops/mocks"
 	"github.com/broadinstitute/thelma/internal/thelma/ops/status"
+	statusmocks "github.com/broadinstitute/thelma/internal/thelma/ops/status/mocks"
 	syncmocks "github.com/broadinstitute/thelma/internal/thelma/ops/sync/mocks"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
 	argocd_names "github.com/broadinstitute/thelma/internal/thelma/state/api/terra/argocd"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/filter"
 	"github.com/broadinstitute/thelma/internal/thelma/state/testing/statefixtures"
 	"github.com/broadinstitute/thelma/internal/thelma/toolbox/argocd"
 	argomocks "github.com/broadinstitute/thelma/internal/thelma/toolbox/argocd/mocks"
 	kubectlmocks "github.com/broadinstitute/thelma/internal/thelma/toolbox/kubectl/mocks"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 const beeName = "my-bee"
@@ -41,13 +55,17 @@ type BeesTestSuite struct {
 	}
 
 	mocks struct {
-		argocd  *argomocks.ArgoCD
-		seeder  *seedmocks.Seeder
-		cleanup *cleanupmocks.Cleanup
-		kubectl *kubectlmocks.Kubectl
-		sync    *syncmocks.Sync
-		logs    *logsmocks.Logs
-		slack   *slackmocks.Slack
+		argocd    *argomocks.ArgoCD
+		seeder    *seedmocks.Seeder
+		cleanup   *cleanupmocks.Cleanup
+		snapshots *snapshotsmocks.Snapshots
+		kubectl   *kubectlmocks.Kubectl
+		ops       *opsmocks.Ops
+		sync      *syncmocks.Sync
+		status    *statusmocks.Reader
+		logs      *logsmocks.Logs
+		slack     *slackmocks.Slack
+		sherlock  *sherlockmocks.Client
 	}
 
 	bees Bees
@@ -66,24 +84,32 @@ func (suite *BeesTestSuite) SetupSubTest() {
 	suite.mocks.argocd = argomocks.NewArgoCD(suite.T())
 	suite.mocks.seeder = seedmocks.NewSeeder(suite.T())
 	suite.mocks.cleanup = cleanupmocks.NewCleanup(suite.T())
+	suite.mocks.snapshots = snapshotsmocks.NewSnapshots(suite.T())
 	suite.mocks.kubectl = kubectlmocks.NewKubectl(suite.T())
 
 	ops := opsmocks.NewOps(suite.T())
+	suite.mocks.ops = ops
 	suite.mocks.sync = syncmocks.NewSync(suite.T())
 	suite.mocks.logs = logsmocks.NewLogs(suite.T())
+	suite.mocks.status = statusmocks.NewReader(suite.T())
 	ops.EXPECT().Sync().Return(suite.mocks.sync, nil).Maybe()
+	ops.EXPECT().Status().Return(suite.mocks.status, nil).Maybe()
 	ops.EXPECT().Logs().Return(suite.mocks.logs).Maybe()
 
 	suite.mocks.slack = slackmocks.NewSlack(suite.T())
+	suite.mocks.sherlock = sherlockmocks.NewClient(suite.T())
 
 	bees, err := NewBees(
 		suite.mocks.argocd,
 		statefixture.Mocks().StateLoader,
 		suite.mocks.seeder,
 		suite.mocks.cleanup,
+		suite.mocks.snapshots,
 		suite.mocks.kubectl,
 		ops,
 		suite.mocks.slack,
+		quotas.Quotas{},
+		suite.mocks.sherlock,
 	)
 	require.NoError(suite.T(), err)
 	suite.bees = bees
@@ -245,6 +271,631 @@ func (suite *BeesTestSuite) TestProvisionWith() {
 	}
 }
 
+func (suite *BeesTestSuite) TestCloneWith() {
+	suite.Run("pins every release to the source BEE's versions", func() {
+		opts := CloneOptions{
+			Name:             "my-clone",
+			CopyOwner:        true,
+			ProvisionOptions: provisionOptions(),
+		}
+		opts.Seed = false
+
+		template := suite.statefixture.Environment("swatomation")
+		suite.statefixture.Mocks().Environments.EXPECT().CreateFromTemplate(template, mock.Anything).Run(func(_ terra.Environment, createOptions terra.CreateOptions) {
+			assert.Equal(suite.T(), "my-clone", createOptions.Name)
+			assert.Equal(suite.T(), beeOwner, createOptions.Owner)
+			assert.False(suite.T(), createOptions.StopSchedule.Enabled)
+		}).Return(beeName, nil)
+
+		// the fixture returns the source BEE for the new environment, so that provisioning can be verified
+		suite.expectPinReleaseVersions(map[string]terra.VersionOverride{
+			"leonardo":         {AppVersion: "leo-v100", ChartVersion: "1.2.3"},
+			"sam":              {AppVersion: "sam-v2", ChartVersion: "4.5.6"},
+			"workspacemanager": {AppVersion: "wsm-v02", ChartVersion: "7.8.9"},
+		})
+		suite.expectProvisionBeeNamespaceAndGenerator()
+		suite.expectSyncArgoAppsForReleases(opts.WaitHealthy, opts.WaitHealthTimeoutSeconds)
+
+		bee, err := suite.bees.CloneWith(beeName, opts)
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), beeName, bee.Environment.Name())
+	})
+
+	suite.Run("keeps only the source BEE's enabled releases", func() {
+		opts := CloneOptions{Name: "minimal-clone", ProvisionOptions: provisionOptions()}
+		opts.Seed = false
+
+		template := suite.statefixture.Environment("swatomation")
+		suite.statefixture.Mocks().Environments.EXPECT().CreateFromTemplate(template, mock.Anything).Run(func(_ terra.Environment, createOptions terra.CreateOptions) {
+			assert.Equal(suite.T(), "minimal", createOptions.Profile)
+		}).Return(beeName, nil)
+		suite.statefixture.Mocks().Environments.EXPECT().DisableRelease(beeName, "leonardo").Return(nil)
+		suite.statefixture.Mocks().Environments.EXPECT().DisableRelease(beeName, "workspacemanager").Return(nil)
+
+		suite.expectPinReleaseVersions(map[string]terra.VersionOverride{
+			"sam": {AppVersion: "sam-v1", ChartVersion: "4.5.5"},
+		})
+		suite.expectProvisionBeeNamespaceAndGenerator()
+		suite.expectSyncArgoAppsForReleases(opts.WaitHealthy, opts.WaitHealthTimeoutSeconds)
+
+		_, err := suite.bees.CloneWith("minimal-bee", opts)
+		require.NoError(suite.T(), err)
+	})
+
+	suite.Run("source must be a BEE", func() {
+		_, err := suite.bees.CloneWith("swatomation", CloneOptions{})
+		require.Error(suite.T(), err)
+		assert.Contains(suite.T(), err.Error(), "is not a BEE")
+	})
+}
+
+func (suite *BeesTestSuite) TestCreateWithProfile() {
+	suite.Run("disables releases not in the profile before syncing", func() {
+		opts := CreateOptions{
+			Template:         "swatomation",
+			Profile:          &profiles.Profile{Name: "minimal", Releases: []string{"sam"}},
+			ProvisionOptions: provisionOptions(),
+		}
+		opts.Seed = false
+
+		template := suite.statefixture.Environment("swatomation")
+		suite.statefixture.Mocks().Environments.EXPECT().CreateFromTemplate(template, mock.Anything).Run(func(_ terra.Environment, options terra.CreateOptions) {
+			assert.Equal(suite.T(), "minimal", options.Profile)
+		}).Return(beeName, nil)
+		suite.statefixture.Mocks().Environments.EXPECT().DisableRelease(beeName, "leonardo").Return(nil)
+		suite.statefixture.Mocks().Environments.EXPECT().DisableRelease(beeName, "workspacemanager").Return(nil)
+
+		suite.expectPinReleaseVersionsEmptyOverrides()
+		suite.expectProvisionBeeNamespaceAndGenerator()
+		suite.expectSyncArgoAppsForReleases(opts.WaitHealthy, opts.WaitHealthTimeoutSeconds)
+
+		_, err := suite.bees.CreateWith(opts)
+		require.NoError(suite.T(), err)
+	})
+
+	suite.Run("deletes the environment if the profile can't be applied", func() {
+		opts := CreateOptions{
+			Template: "swatomation",
+			Profile:  &profiles.Profile{Name: "minimal", Releases: []string{"sam"}},
+		}
+
+		template := suite.statefixture.Environment("swatomation")
+		suite.statefixture.Mocks().Environments.EXPECT().CreateFromTemplate(template, mock.Anything).Return("half-bee", nil)
+		suite.statefixture.Mocks().Environments.EXPECT().DisableRelease("half-bee", "leonardo").Return(nil)
+		suite.statefixture.Mocks().Environments.EXPECT().DisableRelease("half-bee", "workspacemanager").Return(errors.Errorf("boom"))
+		suite.statefixture.Mocks().Environments.EXPECT().Delete("half-bee").Return(nil)
+
+		_, err := suite.bees.CreateWith(opts)
+		require.Error(suite.T(), err)
+		assert.Contains(suite.T(), err.Error(), "error applying profile minimal to half-bee: boom")
+	})
+
+	suite.Run("profile is validated before the BEE is created", func() {
+		_, err := suite.bees.CreateWith(CreateOptions{
+			Template: "swatomation",
+			Profile:  &profiles.Profile{Name: "broken", Releases: []string{"sam", "rawls"}},
+		})
+		require.Error(suite.T(), err)
+		assert.Contains(suite.T(), err.Error(), `includes release "rawls", which does not exist in template swatomation`)
+	})
+}
+
+func (suite *BeesTestSuite) TestCreateWithQuotas() {
+	withQuotas := func(q quotas.Quotas) Bees {
+		bees, err := NewBees(suite.mocks.argocd, suite.statefixture.Mocks().StateLoader, suite.mocks.seeder, suite.mocks.cleanup,
+			suite.mocks.snapshots, suite.mocks.kubectl, suite.mocks.ops, suite.mocks.slack, q, suite.mocks.sherlock)
+		require.NoError(suite.T(), err)
+		return bees
+	}
+
+	suite.Run("owner at quota", func() {
+		suite.mocks.sherlock.EXPECT().CurrentUser().Return(sherlock.User{Email: "someone-else@broadinstitute.org"}, nil)
+		opts := CreateOptions{Template: "swatomation"}
+		opts.Owner = beeOwner
+		_, err := withQuotas(quotas.Quotas{MaxPerOwner: 3}).CreateWith(opts)
+		require.Error(suite.T(), err)
+		assert.Contains(suite.T(), err.Error(), beeOwner+" already has 3 BEE(s), and the limit is 3 per owner")
+		assert.Contains(suite.T(), err.Error(), "expiring-bee (owner "+beeOwner+", auto-deletes at 2020-01-02T15:04:00Z)")
+		assert.Contains(suite.T(), err.Error(), "my-bee (owner "+beeOwner+", no auto-delete)")
+	})
+
+	suite.Run("template at quota", func() {
+		suite.mocks.sherlock.EXPECT().CurrentUser().Return(sherlock.User{Email: "someone-else@broadinstitute.org"}, nil)
+		opts := CreateOptions{Template: "swatomation"}
+		opts.Owner = "someone-else@broadinstitute.org"
+		_, err := withQuotas(quotas.Quotas{MaxPerOwner: 3, MaxPerTemplate: 4}).CreateWith(opts)
+		require.Error(suite.T(), err)
+		assert.Contains(suite.T(), err.Error(), "there are already 4 BEE(s) from template swatomation")
+	})
+
+	suite.Run("owner defaults to the current user", func() {
+		suite.mocks.sherlock.EXPECT().CurrentUser().Return(sherlock.User{Email: beeOwner}, nil)
+
+		_, err := withQuotas(quotas.Quotas{MaxPerOwner: 3}).CreateWith(CreateOptions{Template: "swatomation"})
+		require.Error(suite.T(), err)
+		assert.Contains(suite.T(), err.Error(), beeOwner+" already has 3 BEE(s), and the limit is 3 per owner")
+	})
+
+	suite.Run("current user can't be looked up", func() {
+		suite.mocks.sherlock.EXPECT().CurrentUser().Return(sherlock.User{}, errors.New("unauthorized"))
+
+		_, err := withQuotas(quotas.Quotas{MaxPerOwner: 3}).CreateWith(CreateOptions{Template: "swatomation"})
+		assert.ErrorContains(suite.T(), err, "error looking up current user to check BEE quotas")
+	})
+
+	suite.Run("caller at quota can't name someone else as the owner", func() {
+		suite.mocks.sherlock.EXPECT().CurrentUser().Return(sherlock.User{Email: beeOwner}, nil)
+
+		opts := CreateOptions{Template: "swatomation"}
+		opts.Owner = "anything@example.com"
+		_, err := withQuotas(quotas.Quotas{MaxPerOwner: 3}).CreateWith(opts)
+		require.Error(suite.T(), err)
+		assert.Contains(suite.T(), err.Error(), beeOwner+" already has 3 BEE(s), and the limit is 3 per owner")
+	})
+
+	suite.Run("quotas are checked against reloaded state", func() {
+		opts := CreateOptions{Template: "swatomation"}
+		opts.Owner = beeOwner
+		_, err := withQuotas(quotas.Quotas{MaxPerTemplate: 3}).CreateWith(opts)
+		require.Error(suite.T(), err)
+		// nothing was created, so the only reload is the one before checking
+		suite.statefixture.Mocks().StateLoader.AssertNumberOfCalls(suite.T(), "Reload", 1)
+	})
+
+	suite.Run("admin override", func() {
+		opts := CreateOptions{Template: "swatomation", IgnoreQuotas: true, ProvisionOptions: provisionOptions()}
+		opts.Owner = beeOwner
+		opts.Seed = false
+
+		suite.mocks.sherlock.EXPECT().CurrentUser().Return(sherlock.User{Email: "ci@broad-dsde-qa.iam.gserviceaccount.com"}, nil)
+		template := suite.statefixture.Environment("swatomation")
+		suite.statefixture.Mocks().Environments.EXPECT().CreateFromTemplate(template, mock.Anything).Return(beeName, nil)
+		suite.expectPinReleaseVersionsEmptyOverrides()
+		suite.expectProvisionBeeNamespaceAndGenerator()
+		suite.expectSyncArgoAppsForReleases(opts.WaitHealthy, opts.WaitHealthTimeoutSeconds)
+
+		_, err := withQuotas(quotas.Quotas{MaxPerOwner: 1, MaxPerTemplate: 1, OverrideUsers: []string{"ci@broad-dsde-qa.iam.gserviceaccount.com"}}).CreateWith(opts)
+		require.NoError(suite.T(), err)
+	})
+
+	suite.Run("admin override requires authorization", func() {
+		opts := CreateOptions{Template: "swatomation", IgnoreQuotas: true}
+		opts.Owner = beeOwner
+
+		suite.mocks.sherlock.EXPECT().CurrentUser().Return(sherlock.User{Email: beeOwner, Roles: []string{"all-users"}}, nil)
+
+		_, err := withQuotas(quotas.Quotas{MaxPerOwner: 1, OverrideRoles: []string{"bee-admins"}}).CreateWith(opts)
+		assert.ErrorContains(suite.T(), err, beeOwner+" is not allowed to ignore BEE quotas")
+	})
+}
+
+func (suite *BeesTestSuite) TestDoctor() {
+	myBee := filter.Environments().NameIncludes(beeName)
+	healthy := &status.Status{Health: argocd.Healthy, Sync: argocd.Synced}
+
+	suite.Run("healthy", func() {
+		suite.mocks.status.EXPECT().Statuses(mock.Anything).Return(map[terra.Release]*status.Status{
+			suite.releases.leo: healthy,
+			suite.releases.sam: healthy,
+			suite.releases.wsm: healthy,
+		}, nil)
+
+		report, err := suite.bees.Doctor(myBee, DoctorOptions{Notify: true, Remediate: true})
+		require.NoError(suite.T(), err)
+		require.Len(suite.T(), report.Bees, 1)
+		assert.True(suite.T(), report.Bees[0].Healthy())
+		assert.Empty(suite.T(), report.Unhealthy())
+	})
+
+	suite.Run("unhealthy with remediation and notifications", func() {
+		suite.mocks.status.EXPECT().Statuses(mock.Anything).Return(map[terra.Release]*status.Status{
+			suite.releases.leo: healthy,
+			suite.releases.sam: {Health: argocd.Degraded, Sync: argocd.Synced},
+			suite.releases.wsm: {Health: argocd.Healthy, Sync: argocd.OutOfSync},
+		}, nil)
+		suite.mocks.argocd.EXPECT().SyncApp(argocd_names.GeneratorName(suite.env)).Return(argocd.SyncResult{Synced: true}, nil)
+		suite.mocks.slack.EXPECT().SendDirectMessage(beeOwner, mock.Anything).Return(nil)
+		suite.mocks.slack.EXPECT().SendDevopsAlert("BEE health: 1 of 1 BEEs are unhealthy", "my-bee: sam, workspacemanager", false).Return(nil)
+
+		report, err := suite.bees.Doctor(myBee, DoctorOptions{Notify: true, Alert: true, Remediate: true})
+		require.NoError(suite.T(), err)
+		require.Len(suite.T(), report.Unhealthy(), 1)
+		health := report.Unhealthy()[0]
+		assert.True(suite.T(), health.Remediated)
+		assert.True(suite.T(), health.Notified)
+		assert.Equal(suite.T(), []AppProblem{
+			{Release: "sam", Health: "Degraded", Sync: "Synced", Headline: "Degraded"},
+			{Release: "workspacemanager", Health: "Healthy", Sync: "OutOfSync", Headline: "Healthy"},
+		}, health.Problems)
+	})
+
+	suite.Run("falls back to individual statuses to find missing apps", func() {
+		suite.mocks.status.EXPECT().Statuses(mock.Anything).Return(nil, errors.Errorf("app not found"))
+		suite.mocks.status.EXPECT().Status(suite.releases.leo).Return(nil, errors.Errorf("app not found"))
+		suite.mocks.status.EXPECT().Status(suite.releases.sam).Return(healthy, nil)
+		suite.mocks.status.EXPECT().Status(suite.releases.wsm).Return(healthy, nil)
+
+		report, err := suite.bees.Doctor(myBee, DoctorOptions{})
+		require.NoError(suite.T(), err)
+		require.Len(suite.T(), report.Unhealthy(), 1)
+		assert.Equal(suite.T(), []AppProblem{
+			{Release: "leonardo", Health: "Missing", Sync: "Unknown", Headline: "could not read status from ArgoCD"},
+		}, report.Unhealthy()[0].Problems)
+	})
+}
+
+// recordingStore remembers the last checkpoint saved, since successful provisioning deletes it
+type recordingStore struct {
+	checkpoint.Store
+	saved *checkpoint.Checkpoint
+}
+
+func (s *recordingStore) Save(cp *checkpoint.Checkpoint) error {
+	s.saved = cp
+	return s.Store.Save(cp)
+}
+
+func (suite *BeesTestSuite) TestProvisionWithCheckpoints() {
+	suite.Run("records progress and removes the checkpoint on success", func() {
+		store := &recordingStore{Store: checkpoint.NewFileStore(suite.T().TempDir())}
+		opts := provisionOptions(func(options *ProvisionOptions) {
+			options.Checkpoints = store
+			options.SeedOptions.Step3AddSaSamPermissions = false
+			options.SeedOptions.Step4RegisterTestUsers = false
+			options.SeedOptions.Step5CreateAgora = false
+		})
+
+		suite.expectPinReleaseVersionsEmptyOverrides()
+		suite.expectProvisionBeeNamespaceAndGenerator()
+		suite.expectSyncArgoAppsForReleases(opts.WaitHealthy, opts.WaitHealthTimeoutSeconds)
+		steps := suite.expectSeedSteps(opts.SeedOptions, seed.StepCreateElasticsearch, seed.StepRegisterSaProfiles)
+		suite.expectSeed(steps[0].Options)
+		suite.expectSeed(steps[1].Options)
+
+		_, err := suite.bees.ProvisionWith(beeName, opts)
+		require.NoError(suite.T(), err)
+
+		require.NotNil(suite.T(), store.saved)
+		for _, phase := range checkpoint.Phases {
+			assert.True(suite.T(), store.saved.Done(phase), "phase %s should be complete", phase)
+		}
+		assert.Equal(suite.T(), []string{seed.StepCreateElasticsearch, seed.StepRegisterSaProfiles}, store.saved.SeedSteps)
+
+		cp, err := store.Load(beeName)
+		require.NoError(suite.T(), err)
+		assert.Nil(suite.T(), cp)
+	})
+
+	suite.Run("resume skips completed phases and seed steps", func() {
+		store := checkpoint.NewFileStore(suite.T().TempDir())
+		cp := checkpoint.New(beeName)
+		cp.Complete(checkpoint.PinVersions)
+		cp.Complete(checkpoint.Namespace)
+		cp.Complete(checkpoint.Generator)
+		cp.Complete(checkpoint.SyncApps)
+		cp.CompleteSeedStep(seed.StepCreateElasticsearch)
+		require.NoError(suite.T(), store.Save(cp))
+
+		opts := provisionOptions(func(options *ProvisionOptions) {
+			options.Checkpoints = store
+			options.Resume = true
+			options.SeedOptions.Step3AddSaSamPermissions = false
+			options.SeedOptions.Step4RegisterTestUsers = false
+			options.SeedOptions.Step5CreateAgora = false
+		})
+
+		steps := suite.expectSeedSteps(opts.SeedOptions, seed.StepCreateElasticsearch, seed.StepRegisterSaProfiles)
+		suite.expectSeed(steps[1].Options)
+
+		_, err := suite.bees.ProvisionWith(beeName, opts)
+		require.NoError(suite.T(), err)
+
+		cp, err = store.Load(beeName)
+		require.NoError(suite.T(), err)
+		assert.Nil(suite.T(), cp, "checkpoint should be removed once provisioning succeeds")
+	})
+}
+
+func (suite *BeesTestSuite) TestDeleteWithCheckpoints() {
+	store := checkpoint.NewFileStore(suite.T().TempDir())
+	require.NoError(suite.T(), store.Save(checkpoint.New(beeName)))
+
+	suite.mocks.kubectl.EXPECT().DeleteNamespace(suite.env).Return(nil)
+	suite.mocks.cleanup.EXPECT().Cleanup(suite.env).Return(nil)
+	suite.statefixture.Mocks().Environments.EXPECT().Delete(beeName).Return(nil)
+	suite.mocks.argocd.EXPECT().HardRefresh(generatorArgoApp).Return(nil)
+
+	_, err := suite.bees.DeleteWith(beeName, DeleteOptions{Checkpoints: store})
+	require.NoError(suite.T(), err)
+
+	cp, err := store.Load(beeName)
+	require.NoError(suite.T(), err)
+	assert.Nil(suite.T(), cp)
+}
+
+func (suite *BeesTestSuite) TestNotifyExpiring() {
+	suite.Run("notifies owners of expiring BEEs", func() {
+		suite.expectSendSlackNotificationToOwnerContaining("deleted after Thu Jan 2 15:04 UTC 2020")
+
+		expectedAfter := time.Date(2020, 1, 2, 15, 4, 0, 0, time.UTC)
+		suite.statefixture.Mocks().Environments.EXPECT().SetAutoDeleteNotified("expiring-bee", mock.Anything).Run(func(_ string, after time.Time) {
+			assert.True(suite.T(), expectedAfter.Equal(after), "expected %s, got %s", expectedAfter, after)
+		}).Return(nil)
+
+		notices, err := suite.bees.NotifyExpiring(24*time.Hour, NotifyExpiringOptions{})
+		require.NoError(suite.T(), err)
+		require.Len(suite.T(), notices, 2)
+		assert.Equal(suite.T(), "expiring-bee", notices[0].Name)
+		assert.Equal(suite.T(), beeOwner, notices[0].Owner)
+		assert.True(suite.T(), notices[0].Notified)
+
+		// owner was already notified about this deletion time, so isn't messaged again
+		assert.Equal(suite.T(), "notified-bee", notices[1].Name)
+		assert.False(suite.T(), notices[1].Notified)
+		assert.True(suite.T(), notices[1].PreviouslyNotified)
+	})
+
+	suite.Run("dry run does not notify", func() {
+		notices, err := suite.bees.NotifyExpiring(24*time.Hour, NotifyExpiringOptions{DryRun: true})
+		require.NoError(suite.T(), err)
+		require.Len(suite.T(), notices, 2)
+		assert.False(suite.T(), notices[0].Notified)
+	})
+}
+
+func (suite *BeesTestSuite) TestExtend() {
+	suite.Run("pushes back auto-delete time", func() {
+		expected := time.Date(2020, 1, 3, 15, 4, 0, 0, time.UTC)
+		suite.statefixture.Mocks().Environments.EXPECT().SetAutoDeleteAfter("expiring-bee", mock.Anything).Run(func(_ string, after time.Time) {
+			assert.True(suite.T(), expected.Equal(after), "expected %s, got %s", expected, after)
+		}).Return(nil)
+
+		bee, err := suite.bees.Extend("expiring-bee", 24*time.Hour)
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), "expiring-bee", bee.Environment.Name())
+	})
+
+	suite.Run("auto-delete must be enabled", func() {
+		_, err := suite.bees.Extend(beeName, 24*time.Hour)
+		require.Error(suite.T(), err)
+		assert.Contains(suite.T(), err.Error(), "not scheduled for automatic deletion")
+	})
+}
+
+func (suite *BeesTestSuite) TestSetSchedule() {
+	suite.Run("replaces schedule", func() {
+		var opts terra.ScheduleOptions
+		opts.Schedule = &schedule.Schedule{TimeZone: "UTC", Stop: &schedule.Rule{Cron: "0 19 * * *"}}
+		suite.statefixture.Mocks().Environments.EXPECT().SetSchedule(beeName, opts).Return(nil)
+
+		bee, err := suite.bees.SetSchedule(beeName, opts)
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), beeName, bee.Environment.Name())
+	})
+
+	suite.Run("rejects invalid schedules", func() {
+		var opts terra.ScheduleOptions
+		opts.Schedule = &schedule.Schedule{Stop: &schedule.Rule{Cron: "0 19 * * *"}}
+		_, err := suite.bees.SetSchedule(beeName, opts)
+		assert.ErrorContains(suite.T(), err, "invalid schedule")
+	})
+}
+
+func (suite *BeesTestSuite) TestStartStopWithHibernate() {
+	hibernated := []snapshots.Snapshot{
+		{Name: "hibernate-20240102-150405", Release: "sam", Claim: "data-sam-postgres-0", VolumeSnapshot: "data-sam-postgres-0-hibernate-20240102-150405", Ready: true},
+	}
+	manifestObject := beeName + "/hibernation/manifest.json"
+
+	suite.Run("stop snapshots volumes and deletes PVCs", func() {
+		_bucket := bucketmocks.NewBucket(suite.T())
+		_bucket.EXPECT().Exists(manifestObject).Return(false, nil)
+		_bucket.EXPECT().Write(manifestObject, mock.Anything).Return(nil)
+
+		suite.mocks.kubectl.EXPECT().ShutDown(suite.env).Return(nil)
+		suite.mocks.snapshots.EXPECT().Create(suite.env, mock.MatchedBy(func(name string) bool {
+			_, err := time.Parse(hibernationSnapshotFormat, name)
+			return err == nil
+		}), "custom-class").Return(hibernated, nil)
+		suite.mocks.snapshots.EXPECT().DeleteClaims(suite.env, mock.MatchedBy(func(name string) bool {
+			_, err := time.Parse(hibernationSnapshotFormat, name)
+			return err == nil
+		})).Return(nil)
+		suite.statefixture.Mocks().Environments.EXPECT().SetOffline(beeName, true).Return(nil)
+
+		_, err := suite.bees.StartStopWith(beeName, true, StartStopOptions{
+			Hibernate:     true,
+			SnapshotClass: "custom-class",
+			Hibernation:   hibernation.NewBucketStore(_bucket),
+		})
+		require.NoError(suite.T(), err)
+	})
+
+	suite.Run("stop does not delete PVCs if snapshots fail", func() {
+		_bucket := bucketmocks.NewBucket(suite.T())
+		_bucket.EXPECT().Exists(manifestObject).Return(false, nil)
+
+		suite.mocks.kubectl.EXPECT().ShutDown(suite.env).Return(nil)
+		suite.mocks.snapshots.EXPECT().Create(suite.env, mock.Anything, hibernation.DefaultSnapshotClass).Return(nil, errors.New("quota exceeded"))
+
+		_, err := suite.bees.StartStopWith(beeName, true, StartStopOptions{
+			Hibernate:   true,
+			Hibernation: hibernation.NewBucketStore(_bucket),
+		})
+		require.ErrorContains(suite.T(), err, "PVCs were not deleted")
+	})
+
+	suite.Run("hibernating requires a store", func() {
+		_, err := suite.bees.StartStopWith(beeName, true, StartStopOptions{Hibernate: true})
+		require.ErrorContains(suite.T(), err, "no hibernation store")
+	})
+
+	manifestJson := []byte(`{"environment":"my-bee","snapshot":"hibernate-20240102-150405","snapshots":[{"name":"hibernate-20240102-150405","release":"sam","claim":"data-sam-postgres-0","volumeSnapshot":"data-sam-postgres-0-hibernate-20240102-150405","ready":true}]}`)
+
+	suite.Run("start restores PVCs from snapshots and then deletes the snapshots", func() {
+		_bucket := bucketmocks.NewBucket(suite.T())
+		_bucket.EXPECT().Exists(manifestObject).Return(true, nil)
+		_bucket.EXPECT().Read(manifestObject).Return(manifestJson, nil)
+		_bucket.EXPECT().Delete(manifestObject).Return(nil)
+
+		suite.mocks.snapshots.EXPECT().Restore(suite.env, "hibernate-20240102-150405").Return(hibernated, nil)
+		suite.statefixture.Mocks().Environments.EXPECT().SetOffline(beeName, false).Return(nil)
+		suite.mocks.sync.EXPECT().Sync(mock.Anything, len(suite.getReleases()), mock.Anything).Return(nil, nil)
+		suite.mocks.snapshots.EXPECT().Delete(suite.env, "hibernate-20240102-150405").Return(nil)
+
+		_, err := suite.bees.StartStopWith(beeName, false, StartStopOptions{
+			Sync:        true,
+			Hibernation: hibernation.NewBucketStore(_bucket),
+		})
+		require.NoError(suite.T(), err)
+	})
+
+	suite.Run("start keeps the manifest if snapshots can't be deleted", func() {
+		_bucket := bucketmocks.NewBucket(suite.T())
+		_bucket.EXPECT().Exists(manifestObject).Return(true, nil)
+		_bucket.EXPECT().Read(manifestObject).Return(manifestJson, nil)
+
+		suite.mocks.snapshots.EXPECT().Restore(suite.env, "hibernate-20240102-150405").Return(hibernated, nil)
+		suite.statefixture.Mocks().Environments.EXPECT().SetOffline(beeName, false).Return(nil)
+		suite.mocks.sync.EXPECT().Sync(mock.Anything, len(suite.getReleases()), mock.Anything).Return(nil, nil)
+		suite.mocks.snapshots.EXPECT().Delete(suite.env, "hibernate-20240102-150405").Return(errors.New("forbidden"))
+
+		_, err := suite.bees.StartStopWith(beeName, false, StartStopOptions{
+			Sync:        true,
+			Hibernation: hibernation.NewBucketStore(_bucket),
+		})
+		require.NoError(suite.T(), err)
+	})
+
+	suite.Run("start can skip waiting for services to become healthy", func() {
+		_bucket := bucketmocks.NewBucket(suite.T())
+		_bucket.EXPECT().Exists(manifestObject).Return(true, nil)
+		_bucket.EXPECT().Read(manifestObject).Return(manifestJson, nil)
+		_bucket.EXPECT().Delete(manifestObject).Return(nil)
+
+		suite.mocks.snapshots.EXPECT().Restore(suite.env, "hibernate-20240102-150405").Return(hibernated, nil)
+		suite.statefixture.Mocks().Environments.EXPECT().SetOffline(beeName, false).Return(nil)
+		suite.mocks.sync.EXPECT().Sync(mock.Anything, len(suite.getReleases()), mock.Anything).Run(func(_ []terra.Release, _ int, options ...argocd.SyncOption) {
+			opts := argocd.SyncOptions{WaitHealthy: true}
+			for _, option := range options {
+				option(&opts)
+			}
+			assert.False(suite.T(), opts.WaitHealthy)
+		}).Return(nil, nil)
+		suite.mocks.snapshots.EXPECT().Delete(suite.env, "hibernate-20240102-150405").Return(nil)
+
+		_, err := suite.bees.StartStopWith(beeName, false, StartStopOptions{
+			Sync:            true,
+			SkipWaitHealthy: true,
+			Hibernation:     hibernation.NewBucketStore(_bucket),
+		})
+		require.NoError(suite.T(), err)
+	})
+
+	suite.Run("start won't restore without syncing", func() {
+		_bucket := bucketmocks.NewBucket(suite.T())
+		_bucket.EXPECT().Exists(manifestObject).Return(true, nil)
+		_bucket.EXPECT().Read(manifestObject).Return(manifestJson, nil)
+
+		_, err := suite.bees.StartStopWith(beeName, false, StartStopOptions{
+			Hibernation: hibernation.NewBucketStore(_bucket),
+		})
+		require.ErrorContains(suite.T(), err, "can only be restored when syncing")
+	})
+
+	suite.Run("delete removes hibernation snapshots", func() {
+		_bucket := bucketmocks.NewBucket(suite.T())
+		_bucket.EXPECT().Exists(manifestObject).Return(true, nil)
+		_bucket.EXPECT().Read(manifestObject).Return(manifestJson, nil)
+		_bucket.EXPECT().Delete(manifestObject).Return(nil)
+
+		suite.mocks.snapshots.EXPECT().Delete(suite.env, "hibernate-20240102-150405").Return(nil)
+		suite.mocks.kubectl.EXPECT().DeleteNamespace(suite.env).Return(nil)
+		suite.mocks.cleanup.EXPECT().Cleanup(suite.env).Return(nil)
+		suite.statefixture.Mocks().Environments.EXPECT().Delete(beeName).Return(nil)
+		suite.mocks.argocd.EXPECT().HardRefresh(generatorArgoApp).Return(nil)
+
+		_, err := suite.bees.DeleteWith(beeName, DeleteOptions{Hibernation: hibernation.NewBucketStore(_bucket)})
+		require.NoError(suite.T(), err)
+	})
+}
+
+func (suite *BeesTestSuite) TestSnapshots() {
+	snapshot := snapshots.Snapshot{Name: "known-good", Release: "sam", Claim: "data-sam-postgres-0", Ready: true}
+
+	suite.Run("create quiesces and brings services back up", func() {
+		suite.mocks.kubectl.EXPECT().ShutDown(suite.env).Return(nil)
+		suite.mocks.snapshots.EXPECT().Create(suite.env, "known-good", "snapshot-class").Return([]snapshots.Snapshot{snapshot}, nil)
+		suite.mocks.sync.EXPECT().Sync(mock.Anything, len(suite.getReleases())).Return(nil, nil)
+
+		created, err := suite.bees.CreateSnapshot(beeName, SnapshotOptions{Name: "known-good", SnapshotClass: "snapshot-class", Quiesce: true})
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), []snapshots.Snapshot{snapshot}, created)
+	})
+
+	suite.Run("create defaults name to a timestamp", func() {
+		suite.mocks.snapshots.EXPECT().Create(suite.env, mock.MatchedBy(func(name string) bool {
+			_, err := time.Parse(snapshotNameFormat, name)
+			return err == nil
+		}), "snapshot-class").Return(nil, nil)
+
+		_, err := suite.bees.CreateSnapshot(beeName, SnapshotOptions{SnapshotClass: "snapshot-class"})
+		require.NoError(suite.T(), err)
+	})
+
+	suite.Run("restore replaces PVCs and syncs", func() {
+		suite.mocks.snapshots.EXPECT().List(suite.env).Return([]snapshots.Snapshot{snapshot}, nil)
+		suite.mocks.kubectl.EXPECT().ShutDown(suite.env).Return(nil)
+		suite.mocks.snapshots.EXPECT().Restore(suite.env, "known-good").Return([]snapshots.Snapshot{snapshot}, nil)
+		suite.mocks.sync.EXPECT().Sync(mock.Anything, len(suite.getReleases()), mock.Anything).Run(func(_ []terra.Release, _ int, options ...argocd.SyncOption) {
+			var opts argocd.SyncOptions
+			for _, option := range options {
+				option(&opts)
+			}
+			assert.True(suite.T(), opts.SyncIfNoDiff)
+		}).Return(nil, nil)
+
+		_, err := suite.bees.RestoreSnapshot(beeName, "known-good")
+		require.NoError(suite.T(), err)
+	})
+
+	suite.Run("restore does not shut down if snapshot is missing", func() {
+		suite.mocks.snapshots.EXPECT().List(suite.env).Return([]snapshots.Snapshot{snapshot}, nil)
+
+		_, err := suite.bees.RestoreSnapshot(beeName, "nope")
+		require.ErrorContains(suite.T(), err, "no snapshot named nope")
+	})
+}
+
+func (suite *BeesTestSuite) TestGC() {
+	orphan := cleanup.Resource{Kind: cleanup.Bucket, Project: "broad-dsde-qa", Name: "zz99-rawls", Prefix: "zz99"}
+	failing := cleanup.Resource{Kind: cleanup.ServiceAccount, Project: "broad-dsde-qa", Name: "zz99-sam@broad-dsde-qa.iam.gserviceaccount.com", Prefix: "zz99"}
+	// prefix belongs to my-bee in the fixture, eg. because it was created while orphans were being listed
+	claimed := cleanup.Resource{Kind: cleanup.SqlDatabase, Project: "broad-dsde-qa", Parent: "bees", Name: "abcd_rawls", Prefix: "abcd"}
+
+	suite.Run("dry run does not delete", func() {
+		suite.mocks.cleanup.EXPECT().FindOrphans(mock.Anything, time.Hour).Return([]cleanup.Resource{orphan}, nil)
+
+		report, err := suite.bees.GC(GCOptions{DryRun: true, GracePeriod: time.Hour})
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), []cleanup.Resource{orphan}, report.Orphans)
+		assert.Empty(suite.T(), report.Deleted)
+		assert.Contains(suite.T(), report.String(), "bucket broad-dsde-qa/zz99-rawls")
+	})
+
+	suite.Run("deletes orphans and reports failures", func() {
+		suite.mocks.cleanup.EXPECT().FindOrphans(mock.Anything, time.Duration(0)).Return([]cleanup.Resource{orphan, failing, claimed}, nil)
+		suite.mocks.cleanup.EXPECT().Delete(orphan).Return(nil)
+		suite.mocks.cleanup.EXPECT().Delete(failing).Return(errors.New("permission denied"))
+
+		report, err := suite.bees.GC(GCOptions{})
+		require.Error(suite.T(), err)
+		assert.Equal(suite.T(), []cleanup.Resource{orphan, failing}, report.Orphans)
+		assert.Equal(suite.T(), []cleanup.Resource{orphan}, report.Deleted)
+		assert.Equal(suite.T(), "permission denied", report.Failed[failing.String()])
+	})
+}
+
 func (s *BeesTestSuite) TestSeedingRetriesSucceedEventually() {
 	b := &Bee{Environment: s.env}
 	opts := provisionOptions()
@@ -264,7 +915,7 @@ func (s *BeesTestSuite) TestSeedingRetriesSucceedEventually() {
 	s.mocks.seeder.EXPECT().Seed(s.env, opts.SeedOptions).
 		Return(nil).Once()
 
-	err := s.bees.(*bees).provisionBeeAppsAndSeed(b, opts)
+	err := s.bees.(*bees).provisionBeeAppsAndSeed(b, opts, nil)
 	require.NoError(s.T(), err)
 }
 
@@ -374,6 +1025,18 @@ func (suite *BeesTestSuite) expectSeed(opts seed.SeedOptions) {
 	suite.mocks.seeder.EXPECT().Seed(suite.env, opts).Return(nil)
 }
 
+// expectSeedSteps expects the seeder to be asked for its steps, and returns one step per name that runs only that step
+func (suite *BeesTestSuite) expectSeedSteps(opts seed.SeedOptions, names ...string) []seed.Step {
+	var steps []seed.Step
+	for _, name := range names {
+		stepOpts := opts
+		stepOpts.OnlyStep = name
+		steps = append(steps, seed.Step{Name: name, Options: stepOpts})
+	}
+	suite.mocks.seeder.EXPECT().Steps(opts).Return(steps, nil)
+	return steps
+}
+
 func (suite *BeesTestSuite) expectExportLogs() {
 	suite.expectExportLogsReturnError(nil)
 }
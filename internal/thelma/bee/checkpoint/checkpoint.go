@@ -0,0 +1,104 @@
+// Package checkpoint records BEE provisioning progress, so that a failed provision can be resumed without repeating
+// phases that already completed
+package checkpoint
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Phase is a single phase of BEE provisioning
+type Phase string
+
+const (
+	// PinVersions pin the BEE's release versions
+	PinVersions Phase = "pin-versions"
+	// Namespace create the BEE's Kubernetes namespace
+	Namespace Phase = "namespace"
+	// Generator provision the BEE's Argo app generator
+	Generator Phase = "generator"
+	// SyncApps sync the BEE's Argo apps
+	SyncApps Phase = "sync-apps"
+	// Seed seed the BEE with test data
+	Seed Phase = "seed"
+)
+
+// Phases all provisioning phases, in the order they are run
+var Phases = []Phase{PinVersions, Namespace, Generator, SyncApps, Seed}
+
+// Checkpoint records provisioning progress for a single environment
+type Checkpoint struct {
+	// Environment name of the environment being provisioned
+	Environment string `json:"environment" yaml:"environment"`
+	// StartedAt when provisioning first started
+	StartedAt time.Time `json:"startedAt" yaml:"startedAt"`
+	// UpdatedAt when this checkpoint was last updated
+	UpdatedAt time.Time `json:"updatedAt" yaml:"updatedAt"`
+	// Phases completion time of each completed phase
+	Phases map[Phase]time.Time `json:"phases,omitempty" yaml:"phases,omitempty"`
+	// SeedSteps names of completed seed steps, so a partially-completed seed phase can be resumed
+	SeedSteps []string `json:"seedSteps,omitempty" yaml:"seedSteps,omitempty"`
+}
+
+// New returns an empty checkpoint for the given environment
+func New(environment string) *Checkpoint {
+	now := time.Now()
+	return &Checkpoint{
+		Environment: environment,
+		StartedAt:   now,
+		UpdatedAt:   now,
+		Phases:      make(map[Phase]time.Time),
+	}
+}
+
+// Done returns true if the given phase has completed
+func (c *Checkpoint) Done(phase Phase) bool {
+	_, exists := c.Phases[phase]
+	return exists
+}
+
+// Complete marks the given phase as completed
+func (c *Checkpoint) Complete(phase Phase) {
+	if c.Phases == nil {
+		c.Phases = make(map[Phase]time.Time)
+	}
+	c.UpdatedAt = time.Now()
+	c.Phases[phase] = c.UpdatedAt
+}
+
+// SeedStepDone returns true if the given seed step has completed
+func (c *Checkpoint) SeedStepDone(step string) bool {
+	for _, s := range c.SeedSteps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// CompleteSeedStep marks the given seed step as completed
+func (c *Checkpoint) CompleteSeedStep(step string) {
+	if c.SeedStepDone(step) {
+		return
+	}
+	c.UpdatedAt = time.Now()
+	c.SeedSteps = append(c.SeedSteps, step)
+}
+
+// String renders the checkpoint in a human-readable format, one phase per line
+func (c *Checkpoint) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s (started %s, updated %s)\n", c.Environment, c.StartedAt.Format(time.RFC3339), c.UpdatedAt.Format(time.RFC3339)))
+	for _, phase := range Phases {
+		if completedAt, done := c.Phases[phase]; done {
+			sb.WriteString(fmt.Sprintf("  [x] %s (%s)\n", phase, completedAt.Format(time.RFC3339)))
+		} else {
+			sb.WriteString(fmt.Sprintf("  [ ] %s\n", phase))
+		}
+		if phase == Seed && len(c.SeedSteps) > 0 {
+			sb.WriteString(fmt.Sprintf("      completed seed steps: %s\n", strings.Join(c.SeedSteps, ", ")))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,77 @@
+package checkpoint
+
+import (
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/clients/google/bucket/testing/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint(t *testing.T) {
+	cp := New("my-bee")
+	assert.False(t, cp.Done(Namespace))
+
+	cp.Complete(Namespace)
+	cp.CompleteSeedStep("create-agora")
+	cp.CompleteSeedStep("create-agora")
+
+	assert.True(t, cp.Done(Namespace))
+	assert.False(t, cp.Done(Seed))
+	assert.True(t, cp.SeedStepDone("create-agora"))
+	assert.Equal(t, []string{"create-agora"}, cp.SeedSteps)
+
+	assert.Contains(t, cp.String(), "[x] namespace")
+	assert.Contains(t, cp.String(), "[ ] seed")
+	assert.Contains(t, cp.String(), "completed seed steps: create-agora")
+}
+
+func TestFileStore(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+
+	cp, err := store.Load("my-bee")
+	require.NoError(t, err)
+	assert.Nil(t, cp)
+
+	saved := New("my-bee")
+	saved.Complete(PinVersions)
+	require.NoError(t, store.Save(saved))
+
+	cp, err = store.Load("my-bee")
+	require.NoError(t, err)
+	require.NotNil(t, cp)
+	assert.Equal(t, "my-bee", cp.Environment)
+	assert.True(t, cp.Done(PinVersions))
+
+	require.NoError(t, store.Delete("my-bee"))
+	require.NoError(t, store.Delete("my-bee"))
+	cp, err = store.Load("my-bee")
+	require.NoError(t, err)
+	assert.Nil(t, cp)
+}
+
+func TestMultiStore(t *testing.T) {
+	local := NewFileStore(t.TempDir())
+
+	remote := mocks.NewBucket(t)
+	remote.EXPECT().Name().Return("my-bucket").Maybe()
+	remote.EXPECT().Write("my-bee/provisioning/checkpoint.json", mock.Anything).Return(errors.New("upload failed"))
+	remote.EXPECT().Exists("other-bee/provisioning/checkpoint.json").Return(true, nil)
+	remote.EXPECT().Read("other-bee/provisioning/checkpoint.json").Return([]byte(`{"environment":"other-bee","phases":{"namespace":"2022-01-01T00:00:00Z"}}`), nil)
+
+	store := NewMultiStore(local, NewBucketStore(remote))
+
+	// secondary errors are ignored
+	require.NoError(t, store.Save(New("my-bee")))
+	cp, err := store.Load("my-bee")
+	require.NoError(t, err)
+	assert.Equal(t, "my-bee", cp.Environment)
+
+	// falls back to secondary if the primary has no checkpoint
+	cp, err = store.Load("other-bee")
+	require.NoError(t, err)
+	require.NotNil(t, cp)
+	assert.True(t, cp.Done(Namespace))
+}
@@ -0,0 +1,176 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+
+	"github.com/broadinstitute/thelma/internal/thelma/clients/google/bucket"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// Store persists checkpoints
+type Store interface {
+	// Load returns the checkpoint for the given environment, or nil if there is none
+	Load(environment string) (*Checkpoint, error)
+	// Save persists a checkpoint
+	Save(checkpoint *Checkpoint) error
+	// Delete removes the checkpoint for the given environment, if there is one
+	Delete(environment string) error
+}
+
+// NewFileStore returns a Store that saves checkpoints as JSON files in the given directory
+func NewFileStore(dir string) Store {
+	return &fileStore{dir: dir}
+}
+
+type fileStore struct {
+	dir string
+}
+
+func (s *fileStore) Load(environment string) (*Checkpoint, error) {
+	content, err := os.ReadFile(s.file(environment))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Errorf("error reading checkpoint for %s: %v", environment, err)
+	}
+	return unmarshal(environment, content)
+}
+
+func (s *fileStore) Save(checkpoint *Checkpoint) error {
+	content, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return errors.Errorf("error marshalling checkpoint for %s: %v", checkpoint.Environment, err)
+	}
+	if err = os.MkdirAll(s.dir, 0700); err != nil {
+		return errors.Errorf("error creating checkpoint dir %s: %v", s.dir, err)
+	}
+	if err = os.WriteFile(s.file(checkpoint.Environment), content, 0600); err != nil {
+		return errors.Errorf("error writing checkpoint for %s: %v", checkpoint.Environment, err)
+	}
+	return nil
+}
+
+func (s *fileStore) Delete(environment string) error {
+	if err := os.Remove(s.file(environment)); err != nil && !os.IsNotExist(err) {
+		return errors.Errorf("error deleting checkpoint for %s: %v", environment, err)
+	}
+	return nil
+}
+
+func (s *fileStore) file(environment string) string {
+	return path.Join(s.dir, environment+".json")
+}
+
+// NewBucketStore returns a Store that saves checkpoints to a GCS bucket, so that provisioning can be resumed
+// from a different machine (for example, a re-run of a CI job)
+func NewBucketStore(_bucket bucket.Bucket) Store {
+	return &bucketStore{bucket: _bucket}
+}
+
+type bucketStore struct {
+	bucket bucket.Bucket
+}
+
+func (s *bucketStore) Load(environment string) (*Checkpoint, error) {
+	exists, err := s.bucket.Exists(s.object(environment))
+	if err != nil {
+		return nil, errors.Errorf("error checking for checkpoint for %s in %s: %v", environment, s.bucket.Name(), err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	content, err := s.bucket.Read(s.object(environment))
+	if err != nil {
+		return nil, errors.Errorf("error reading checkpoint for %s from %s: %v", environment, s.bucket.Name(), err)
+	}
+	return unmarshal(environment, content)
+}
+
+func (s *bucketStore) Save(checkpoint *Checkpoint) error {
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.Errorf("error marshalling checkpoint for %s: %v", checkpoint.Environment, err)
+	}
+	if err = s.bucket.Write(s.object(checkpoint.Environment), content); err != nil {
+		return errors.Errorf("error writing checkpoint for %s to %s: %v", checkpoint.Environment, s.bucket.Name(), err)
+	}
+	return nil
+}
+
+func (s *bucketStore) Delete(environment string) error {
+	exists, err := s.bucket.Exists(s.object(environment))
+	if err != nil || !exists {
+		return err
+	}
+	return s.bucket.Delete(s.object(environment))
+}
+
+// object path matches the layout used for other operational artifacts, eg. "my-bee/provisioning/checkpoint.json"
+func (s *bucketStore) object(environment string) string {
+	return path.Join(environment, "provisioning", "checkpoint.json")
+}
+
+// NewMultiStore returns a Store that saves checkpoints to all the given stores, and loads from the first store
+// that has a checkpoint. Errors from all but the first store are logged and ignored.
+func NewMultiStore(primary Store, secondaries ...Store) Store {
+	return &multiStore{primary: primary, secondaries: secondaries}
+}
+
+type multiStore struct {
+	primary     Store
+	secondaries []Store
+}
+
+func (s *multiStore) Load(environment string) (*Checkpoint, error) {
+	checkpoint, err := s.primary.Load(environment)
+	if err != nil || checkpoint != nil {
+		return checkpoint, err
+	}
+	for _, secondary := range s.secondaries {
+		checkpoint, err = secondary.Load(environment)
+		if err != nil {
+			log.Warn().Err(err).Msgf("error loading checkpoint for %s, ignoring: %v", environment, err)
+			continue
+		}
+		if checkpoint != nil {
+			return checkpoint, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *multiStore) Save(checkpoint *Checkpoint) error {
+	if err := s.primary.Save(checkpoint); err != nil {
+		return err
+	}
+	for _, secondary := range s.secondaries {
+		if err := secondary.Save(checkpoint); err != nil {
+			log.Warn().Err(err).Msgf("error saving checkpoint for %s, ignoring: %v", checkpoint.Environment, err)
+		}
+	}
+	return nil
+}
+
+func (s *multiStore) Delete(environment string) error {
+	if err := s.primary.Delete(environment); err != nil {
+		return err
+	}
+	for _, secondary := range s.secondaries {
+		if err := secondary.Delete(environment); err != nil {
+			log.Warn().Err(err).Msgf("error deleting checkpoint for %s, ignoring: %v", environment, err)
+		}
+	}
+	return nil
+}
+
+func unmarshal(environment string, content []byte) (*Checkpoint, error) {
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(content, &checkpoint); err != nil {
+		return nil, errors.Errorf("error parsing checkpoint for %s: %v", environment, err)
+	}
+	return &checkpoint, nil
+}
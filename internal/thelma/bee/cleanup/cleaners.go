@@ -0,0 +1,200 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/broadinstitute/thelma/internal/thelma/clients/google"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/api/iam/v1"
+	"google.golang.org/api/iterator"
+)
+
+// DefaultCleaners returns resource cleaners for all kinds of resources BEEs are known to create
+func DefaultCleaners(googleClients google.Clients) []ResourceCleaner {
+	return []ResourceCleaner{
+		NewBucketCleaner(googleClients),
+		NewServiceAccountCleaner(googleClients),
+		NewSqlDatabaseCleaner(googleClients),
+	}
+}
+
+// NewBucketCleaner returns a ResourceCleaner for GCS buckets
+func NewBucketCleaner(googleClients google.Clients) ResourceCleaner {
+	return &bucketCleaner{googleClients: googleClients}
+}
+
+type bucketCleaner struct {
+	googleClients google.Clients
+}
+
+func (c *bucketCleaner) Kind() Kind {
+	return Bucket
+}
+
+func (c *bucketCleaner) List(projectId string) ([]Resource, error) {
+	client, err := c.googleClients.Storage()
+	if err != nil {
+		return nil, err
+	}
+	defer closeQuietly(client)
+
+	var resources []Resource
+	it := client.Buckets(context.Background(), projectId)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Errorf("error listing buckets in %s: %v", projectId, err)
+		}
+		if prefix, matches := matchPrefix(Bucket, attrs.Name); matches {
+			resources = append(resources, Resource{Kind: Bucket, Project: projectId, Name: attrs.Name, Prefix: prefix, CreatedAt: attrs.Created})
+		}
+	}
+	return resources, nil
+}
+
+// Delete deletes all objects (including noncurrent versions) in the bucket, and then the bucket itself
+func (c *bucketCleaner) Delete(resource Resource) error {
+	client, err := c.googleClients.Storage()
+	if err != nil {
+		return err
+	}
+	defer closeQuietly(client)
+
+	handle := client.Bucket(resource.Name)
+	it := handle.Objects(context.Background(), &storage.Query{Versions: true})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return errors.Errorf("error listing objects in bucket %s: %v", resource.Name, err)
+		}
+		if err = handle.Object(attrs.Name).Generation(attrs.Generation).Delete(context.Background()); err != nil {
+			return errors.Errorf("error deleting object gs://%s/%s: %v", resource.Name, attrs.Name, err)
+		}
+	}
+
+	if err = handle.Delete(context.Background()); err != nil {
+		return errors.Errorf("error deleting bucket %s: %v", resource.Name, err)
+	}
+	log.Debug().Msgf("Deleted bucket: %s", resource.Name)
+	return nil
+}
+
+// NewServiceAccountCleaner returns a ResourceCleaner for GCP service accounts
+func NewServiceAccountCleaner(googleClients google.Clients) ResourceCleaner {
+	return &serviceAccountCleaner{googleClients: googleClients}
+}
+
+type serviceAccountCleaner struct {
+	googleClients google.Clients
+}
+
+func (c *serviceAccountCleaner) Kind() Kind {
+	return ServiceAccount
+}
+
+// List matches service accounts by account id, the portion of the email before the @. The IAM API doesn't report
+// when service accounts were created, so CreatedAt is left unset.
+func (c *serviceAccountCleaner) List(projectId string) ([]Resource, error) {
+	client, err := c.googleClients.Iam()
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	err = client.Projects.ServiceAccounts.List(fmt.Sprintf("projects/%s", projectId)).Pages(context.Background(), func(resp *iam.ListServiceAccountsResponse) error {
+		for _, sa := range resp.Accounts {
+			accountId := strings.SplitN(sa.Email, "@", 2)[0]
+			if prefix, matches := matchPrefix(ServiceAccount, accountId); matches {
+				resources = append(resources, Resource{Kind: ServiceAccount, Project: projectId, Name: sa.Email, Prefix: prefix})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Errorf("error listing service accounts in %s: %v", projectId, err)
+	}
+	return resources, nil
+}
+
+func (c *serviceAccountCleaner) Delete(resource Resource) error {
+	client, err := c.googleClients.Iam()
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("projects/%s/serviceAccounts/%s", resource.Project, resource.Name)
+	if _, err = client.Projects.ServiceAccounts.Delete(name).Do(); err != nil {
+		return errors.Errorf("error deleting service account %s: %v", resource.Name, err)
+	}
+	log.Debug().Msgf("Deleted service account: %s", resource.Name)
+	return nil
+}
+
+// NewSqlDatabaseCleaner returns a ResourceCleaner for databases in Cloud SQL instances
+func NewSqlDatabaseCleaner(googleClients google.Clients) ResourceCleaner {
+	return &sqlDatabaseCleaner{googleClients: googleClients}
+}
+
+type sqlDatabaseCleaner struct {
+	googleClients google.Clients
+}
+
+func (c *sqlDatabaseCleaner) Kind() Kind {
+	return SqlDatabase
+}
+
+// List matches databases in all Cloud SQL instances in the project. The Cloud SQL API doesn't report when databases
+// were created, so CreatedAt is left unset.
+func (c *sqlDatabaseCleaner) List(projectId string) ([]Resource, error) {
+	client, err := c.googleClients.SqlAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := client.ListInstances(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	for _, instance := range instances {
+		databases, err := client.ListDatabases(projectId, instance)
+		if err != nil {
+			return nil, err
+		}
+		for _, database := range databases {
+			if prefix, matches := matchPrefix(SqlDatabase, database); matches {
+				resources = append(resources, Resource{Kind: SqlDatabase, Project: projectId, Parent: instance, Name: database, Prefix: prefix})
+			}
+		}
+	}
+	return resources, nil
+}
+
+func (c *sqlDatabaseCleaner) Delete(resource Resource) error {
+	client, err := c.googleClients.SqlAdmin()
+	if err != nil {
+		return err
+	}
+	if err = client.DeleteDatabase(resource.Project, resource.Parent, resource.Name); err != nil {
+		return err
+	}
+	log.Debug().Msgf("Deleted database: %s/%s", resource.Parent, resource.Name)
+	return nil
+}
+
+func closeQuietly(client *storage.Client) {
+	if err := client.Close(); err != nil {
+		log.Warn().Err(err).Msgf("error closing storage client: %v", err)
+	}
+}
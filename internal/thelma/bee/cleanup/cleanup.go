@@ -4,6 +4,8 @@ package cleanup
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/broadinstitute/thelma/internal/thelma/clients/google"
@@ -29,17 +31,28 @@ var topicIdFormats = []string{
 }
 
 type Cleanup interface {
+	// Cleanup deletes cloud resources belonging to a BEE that is being deleted
 	Cleanup(bee terra.Environment) error
+	// FindOrphans returns cloud resources in the BEE projects of the given environments that follow a BEE naming
+	// pattern, but whose unique resource prefix doesn't belong to any of them. Resources created within the grace
+	// period are skipped; resources whose creation time isn't known are not.
+	FindOrphans(environments []terra.Environment, gracePeriod time.Duration) ([]Resource, error)
+	// Delete deletes a single resource returned by FindOrphans
+	Delete(resource Resource) error
 }
 
-func NewCleanup(googleClients google.Clients) Cleanup {
+// NewCleanup returns a new Cleanup. In addition to PubSub topics, resources named with a BEE's
+// unique resource prefix will be cleaned up by the given resource cleaners.
+func NewCleanup(googleClients google.Clients, cleaners ...ResourceCleaner) Cleanup {
 	return &cleanup{
 		googleClients: googleClients,
+		cleaners:      cleaners,
 	}
 }
 
 type cleanup struct {
 	googleClients google.Clients
+	cleaners      []ResourceCleaner
 }
 
 func (c *cleanup) Cleanup(bee terra.Environment) error {
@@ -47,7 +60,120 @@ func (c *cleanup) Cleanup(bee terra.Environment) error {
 		panic(errors.Errorf("%s is not a dynamic environment, won't attempt to cleanup its resources", bee.Name()))
 	}
 
-	return c.cleanupPubsubTopics(bee)
+	if err := c.cleanupPubsubTopics(bee); err != nil {
+		return err
+	}
+	return c.cleanupPrefixedResources(bee)
+}
+
+func (c *cleanup) FindOrphans(environments []terra.Environment, gracePeriod time.Duration) ([]Resource, error) {
+	livePrefixes := set.NewStringSet()
+	projects := set.NewStringSet()
+	releaseNames := set.NewStringSet()
+	for _, env := range environments {
+		if env.UniqueResourcePrefix() != "" {
+			livePrefixes.Add(env.UniqueResourcePrefix())
+		}
+		if !env.Lifecycle().IsDynamic() && !env.Lifecycle().IsTemplate() {
+			continue
+		}
+		// only search the projects BEEs are deployed to; other projects a template's releases point at can be
+		// shared with resources that have nothing to do with BEEs
+		if env.DefaultCluster() != nil {
+			projects.Add(env.DefaultCluster().Project())
+		}
+		for _, r := range env.Releases() {
+			releaseNames.Add(r.Name())
+		}
+	}
+
+	sortedProjects := projects.Elements()
+	sort.Strings(sortedProjects)
+	knownReleases := releaseNames.Elements()
+	cutoff := time.Now().Add(-gracePeriod)
+
+	var orphans []Resource
+	for _, projectId := range sortedProjects {
+		resources, err := c.listPrefixedResources(projectId)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range resources {
+			if livePrefixes.Exists(resource.Prefix) {
+				continue
+			}
+			if !resource.namedForRelease(knownReleases) {
+				log.Debug().Msgf("Ignoring %s: name doesn't match any BEE release", resource)
+				continue
+			}
+			if resource.CreatedAt.After(cutoff) {
+				log.Debug().Msgf("Ignoring %s: created %s, within the %s grace period", resource, resource.CreatedAt, gracePeriod)
+				continue
+			}
+			orphans = append(orphans, resource)
+		}
+	}
+	return orphans, nil
+}
+
+func (c *cleanup) Delete(resource Resource) error {
+	for _, cleaner := range c.cleaners {
+		if cleaner.Kind() == resource.Kind {
+			return cleaner.Delete(resource)
+		}
+	}
+	return errors.Errorf("no cleaner is configured for resources of kind %q", resource.Kind)
+}
+
+// clean up resources named with the environment's unique resource prefix. Like FindOrphans, only the BEE's own
+// project is searched, and only resources named for one of the BEE's releases are deleted, so that resources in
+// shared projects that happen to start with the same 4 characters (eg. "data-billing-exports") are left alone.
+func (c *cleanup) cleanupPrefixedResources(env terra.Environment) error {
+	if len(c.cleaners) == 0 || env.UniqueResourcePrefix() == "" {
+		return nil
+	}
+	if env.DefaultCluster() == nil {
+		log.Warn().Msgf("%s has no default cluster, won't delete resources with prefix %s", env.Name(), env.UniqueResourcePrefix())
+		return nil
+	}
+
+	var releaseNames []string
+	for _, r := range env.Releases() {
+		releaseNames = append(releaseNames, r.Name())
+	}
+
+	projectId := env.DefaultCluster().Project()
+	log.Info().Msgf("Deleting resources with prefix %s for %s in %s", env.UniqueResourcePrefix(), env.Name(), projectId)
+	resources, err := c.listPrefixedResources(projectId)
+	if err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		if resource.Prefix != env.UniqueResourcePrefix() {
+			continue
+		}
+		if !resource.namedForRelease(releaseNames) {
+			log.Debug().Msgf("Keeping %s: name doesn't match any of %s's releases", resource, env.Name())
+			continue
+		}
+		if err = c.Delete(resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// list resources named with any unique resource prefix, across all cleaners
+func (c *cleanup) listPrefixedResources(projectId string) ([]Resource, error) {
+	var resources []Resource
+	for _, cleaner := range c.cleaners {
+		r, err := cleaner.List(projectId)
+		if err != nil {
+			return nil, errors.Errorf("error listing %s resources in %s: %v", cleaner.Kind(), projectId, err)
+		}
+		resources = append(resources, r...)
+	}
+	return resources, nil
 }
 
 func (c *cleanup) cleanupPubsubTopics(env terra.Environment) error {
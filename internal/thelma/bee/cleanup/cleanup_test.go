@@ -2,6 +2,7 @@ package cleanup
 
 import (
 	"testing"
+	"time"
 
 	"cloud.google.com/go/pubsub/apiv1/pubsubpb"
 	"github.com/broadinstitute/thelma/internal/thelma/clients/google/mocks"
@@ -124,3 +125,126 @@ func Test_projectIds(t *testing.T) {
 
 	assert.ElementsMatch(t, []string{"broad-dsde-qa"}, projectIds(bee))
 }
+
+// fakeCleaner is an in-memory ResourceCleaner
+type fakeCleaner struct {
+	kind      Kind
+	resources map[string][]Resource
+	deleted   []Resource
+}
+
+func (f *fakeCleaner) Kind() Kind {
+	return f.kind
+}
+
+func (f *fakeCleaner) List(projectId string) ([]Resource, error) {
+	return f.resources[projectId], nil
+}
+
+func (f *fakeCleaner) Delete(resource Resource) error {
+	f.deleted = append(f.deleted, resource)
+	return nil
+}
+
+func Test_CleanupPrefixedResources(t *testing.T) {
+	cluster := statemocks.NewCluster(t)
+	cluster.EXPECT().Project().Return("bee-project")
+	release := statemocks.NewRelease(t)
+	release.EXPECT().Name().Return("rawls")
+
+	bee := statemocks.NewEnvironment(t)
+	bee.EXPECT().Name().Return("fake-bee")
+	bee.EXPECT().UniqueResourcePrefix().Return("data")
+	bee.EXPECT().DefaultCluster().Return(cluster)
+	bee.EXPECT().Releases().Return([]terra.Release{release})
+
+	mine := Resource{Kind: Bucket, Project: "bee-project", Name: "data-rawls", Prefix: "data"}
+	theirs := Resource{Kind: Bucket, Project: "bee-project", Name: "cd34-rawls", Prefix: "cd34"}
+	// matches the prefix, but isn't named for any of the BEE's releases
+	unrelated := Resource{Kind: Bucket, Project: "bee-project", Name: "data-billing-exports", Prefix: "data"}
+	// other projects the BEE's releases point at aren't searched at all
+	shared := Resource{Kind: Bucket, Project: "shared-project", Name: "data-rawls", Prefix: "data"}
+	cleaner := &fakeCleaner{kind: Bucket, resources: map[string][]Resource{
+		"bee-project":    {mine, theirs, unrelated},
+		"shared-project": {shared},
+	}}
+
+	c := &cleanup{cleaners: []ResourceCleaner{cleaner}}
+	require.NoError(t, c.cleanupPrefixedResources(bee))
+	assert.Equal(t, []Resource{mine}, cleaner.deleted)
+}
+
+func Test_FindOrphans(t *testing.T) {
+	beeCluster := statemocks.NewCluster(t)
+	beeCluster.EXPECT().Project().Return("bee-project")
+	rawls := statemocks.NewRelease(t)
+	rawls.EXPECT().Name().Return("rawls")
+	workspaceManager := statemocks.NewRelease(t)
+	workspaceManager.EXPECT().Name().Return("workspace-manager")
+
+	bee := statemocks.NewEnvironment(t)
+	bee.EXPECT().UniqueResourcePrefix().Return("ab12")
+	bee.EXPECT().Lifecycle().Return(terra.Dynamic)
+	bee.EXPECT().DefaultCluster().Return(beeCluster)
+	bee.EXPECT().Releases().Return([]terra.Release{rawls, workspaceManager})
+
+	static := statemocks.NewEnvironment(t)
+	static.EXPECT().UniqueResourcePrefix().Return("")
+	static.EXPECT().Lifecycle().Return(terra.Static)
+
+	live := Resource{Kind: SqlDatabase, Project: "bee-project", Parent: "bees", Name: "ab12_rawls", Prefix: "ab12"}
+	orphan := Resource{Kind: SqlDatabase, Project: "bee-project", Parent: "bees", Name: "cd34_rawls", Prefix: "cd34"}
+	orphanWithDash := Resource{Kind: SqlDatabase, Project: "bee-project", Parent: "bees", Name: "cd34_workspace_manager_db", Prefix: "cd34"}
+	notBee := Resource{Kind: SqlDatabase, Project: "bee-project", Parent: "bees", Name: "prod_billing", Prefix: "prod"}
+	databases := &fakeCleaner{kind: SqlDatabase, resources: map[string][]Resource{"bee-project": {live, orphan, orphanWithDash, notBee}}}
+
+	oldBucket := Resource{Kind: Bucket, Project: "bee-project", Name: "cd34-rawls-bucket", Prefix: "cd34", CreatedAt: time.Now().Add(-48 * time.Hour)}
+	newBucket := Resource{Kind: Bucket, Project: "bee-project", Name: "ef56-rawls-bucket", Prefix: "ef56", CreatedAt: time.Now().Add(-time.Hour)}
+	buckets := &fakeCleaner{kind: Bucket, resources: map[string][]Resource{"bee-project": {oldBucket, newBucket}}}
+
+	c := NewCleanup(nil, databases, buckets)
+	orphans, err := c.FindOrphans([]terra.Environment{bee, static}, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []Resource{orphan, orphanWithDash, oldBucket}, orphans)
+
+	require.NoError(t, c.Delete(orphan))
+	assert.Equal(t, []Resource{orphan}, databases.deleted)
+
+	err = c.Delete(Resource{Kind: ServiceAccount, Name: "cd34-sam@bee-project.iam.gserviceaccount.com"})
+	assert.ErrorContains(t, err, "no cleaner is configured")
+}
+
+func Test_matchPrefix(t *testing.T) {
+	testCases := []struct {
+		kind    Kind
+		name    string
+		prefix  string
+		matches bool
+	}{
+		{kind: Bucket, name: "ab12-rawls-bucket", prefix: "ab12", matches: true},
+		{kind: Bucket, name: "ab12_rawls"},
+		{kind: Bucket, name: "broad-dsde-qa-bucket"},
+		{kind: Bucket, name: "1abc-rawls"},
+		{kind: Bucket, name: "ab12"},
+		{kind: ServiceAccount, name: "ab12-sam", prefix: "ab12", matches: true},
+		{kind: SqlDatabase, name: "ab12_rawls", prefix: "ab12", matches: true},
+		{kind: SqlDatabase, name: "ab12-rawls"},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.kind)+"/"+tc.name, func(t *testing.T) {
+			prefix, matches := matchPrefix(tc.kind, tc.name)
+			assert.Equal(t, tc.matches, matches)
+			assert.Equal(t, tc.prefix, prefix)
+		})
+	}
+}
+
+func Test_namedForRelease(t *testing.T) {
+	releases := []string{"rawls", "workspace-manager"}
+	assert.True(t, Resource{Kind: Bucket, Name: "ab12-rawls"}.namedForRelease(releases))
+	assert.True(t, Resource{Kind: Bucket, Name: "ab12-workspace-manager-logs"}.namedForRelease(releases))
+	assert.True(t, Resource{Kind: ServiceAccount, Name: "ab12-rawls@bee-project.iam.gserviceaccount.com"}.namedForRelease(releases))
+	assert.True(t, Resource{Kind: SqlDatabase, Name: "ab12_workspace_manager"}.namedForRelease(releases))
+	assert.False(t, Resource{Kind: Bucket, Name: "ab12-rawlsy"}.namedForRelease(releases))
+	assert.False(t, Resource{Kind: Bucket, Name: "data-billing-exports"}.namedForRelease(releases))
+}
@@ -3,8 +3,11 @@
 package mocks
 
 import (
+	cleanup "github.com/broadinstitute/thelma/internal/thelma/bee/cleanup"
 	terra "github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Cleanup is an autogenerated mock type for the Cleanup type
@@ -62,6 +65,103 @@ func (_c *Cleanup_Cleanup_Call) RunAndReturn(run func(terra.Environment) error)
 	return _c
 }
 
+// Delete provides a mock function with given fields: resource
+func (_m *Cleanup) Delete(resource cleanup.Resource) error {
+	ret := _m.Called(resource)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(cleanup.Resource) error); ok {
+		r0 = rf(resource)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Cleanup_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type Cleanup_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - resource cleanup.Resource
+func (_e *Cleanup_Expecter) Delete(resource interface{}) *Cleanup_Delete_Call {
+	return &Cleanup_Delete_Call{Call: _e.mock.On("Delete", resource)}
+}
+
+func (_c *Cleanup_Delete_Call) Run(run func(resource cleanup.Resource)) *Cleanup_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(cleanup.Resource))
+	})
+	return _c
+}
+
+func (_c *Cleanup_Delete_Call) Return(_a0 error) *Cleanup_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Cleanup_Delete_Call) RunAndReturn(run func(cleanup.Resource) error) *Cleanup_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindOrphans provides a mock function with given fields: environments, gracePeriod
+func (_m *Cleanup) FindOrphans(environments []terra.Environment, gracePeriod time.Duration) ([]cleanup.Resource, error) {
+	ret := _m.Called(environments, gracePeriod)
+
+	var r0 []cleanup.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]terra.Environment, time.Duration) ([]cleanup.Resource, error)); ok {
+		return rf(environments, gracePeriod)
+	}
+	if rf, ok := ret.Get(0).(func([]terra.Environment, time.Duration) []cleanup.Resource); ok {
+		r0 = rf(environments, gracePeriod)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cleanup.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]terra.Environment, time.Duration) error); ok {
+		r1 = rf(environments, gracePeriod)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Cleanup_FindOrphans_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindOrphans'
+type Cleanup_FindOrphans_Call struct {
+	*mock.Call
+}
+
+// FindOrphans is a helper method to define mock.On call
+//   - environments []terra.Environment
+//   - gracePeriod time.Duration
+func (_e *Cleanup_Expecter) FindOrphans(environments interface{}, gracePeriod interface{}) *Cleanup_FindOrphans_Call {
+	return &Cleanup_FindOrphans_Call{Call: _e.mock.On("FindOrphans", environments, gracePeriod)}
+}
+
+func (_c *Cleanup_FindOrphans_Call) Run(run func(environments []terra.Environment, gracePeriod time.Duration)) *Cleanup_FindOrphans_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]terra.Environment), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Cleanup_FindOrphans_Call) Return(_a0 []cleanup.Resource, _a1 error) *Cleanup_FindOrphans_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Cleanup_FindOrphans_Call) RunAndReturn(run func([]terra.Environment, time.Duration) ([]cleanup.Resource, error)) *Cleanup_FindOrphans_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewCleanup creates a new instance of Cleanup. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCleanup(t interface {
@@ -0,0 +1,120 @@
+package cleanup
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Kind is a kind of cloud resource that can be cleaned up
+type Kind string
+
+const (
+	// Bucket a GCS bucket
+	Bucket Kind = "bucket"
+	// ServiceAccount a GCP service account
+	ServiceAccount Kind = "service-account"
+	// SqlDatabase a database in a Cloud SQL instance
+	SqlDatabase Kind = "sql-database"
+)
+
+// DefaultGracePeriod resources created more recently than this are never reported as orphans, so that resources for a
+// BEE that is still being created aren't mistaken for leftovers
+const DefaultGracePeriod = 24 * time.Hour
+
+// namePatterns match the names BEEs give to each kind of resource: the BEE's unique resource prefix (a lowercase
+// letter followed by 3 lowercase letters or digits), a separator, and then a name starting with the release that
+// created the resource, eg. "a1b2-rawls-bucket" (bucket), "a1b2-sam" (service account id), "a1b2_rawls" (database).
+var namePatterns = map[Kind]*regexp.Regexp{
+	Bucket:         regexp.MustCompile(`^([a-z][a-z0-9]{3})-([a-z0-9][a-z0-9._-]*)$`),
+	ServiceAccount: regexp.MustCompile(`^([a-z][a-z0-9]{3})-([a-z0-9][a-z0-9-]*)$`),
+	SqlDatabase:    regexp.MustCompile(`^([a-z][a-z0-9]{3})_([a-z0-9][a-z0-9_]*)$`),
+}
+
+// nameSeparators the separator between words in each kind of resource's name
+var nameSeparators = map[Kind]string{
+	Bucket:         "-",
+	ServiceAccount: "-",
+	SqlDatabase:    "_",
+}
+
+// Resource is a cloud resource named with a BEE's unique resource prefix
+type Resource struct {
+	// Kind kind of resource
+	Kind Kind `json:"kind" yaml:"kind"`
+	// Project GCP project the resource lives in
+	Project string `json:"project" yaml:"project"`
+	// Parent name of the resource's parent, for resources that are nested (eg. the Cloud SQL instance for a database)
+	Parent string `json:"parent,omitempty" yaml:"parent,omitempty"`
+	// Name of the resource (for service accounts, the full email address)
+	Name string `json:"name" yaml:"name"`
+	// Prefix unique resource prefix the resource is named with
+	Prefix string `json:"prefix" yaml:"prefix"`
+	// CreatedAt when the resource was created, or the zero time for kinds of resources that don't record it
+	CreatedAt time.Time `json:"createdAt,omitempty" yaml:"createdAt,omitempty"`
+}
+
+// String returns a human-readable identifier for the resource, eg. "bucket broad-dsde-qa/a1b2-rawls"
+func (r Resource) String() string {
+	if r.Parent != "" {
+		return fmt.Sprintf("%s %s/%s/%s", r.Kind, r.Project, r.Parent, r.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Project, r.Name)
+}
+
+// ResourceCleaner lists and deletes cloud resources of a single kind that are named with a unique resource prefix
+type ResourceCleaner interface {
+	// Kind returns the kind of resource this cleaner handles
+	Kind() Kind
+	// List returns all resources of this kind in the given project that are named with a unique resource prefix
+	List(projectId string) ([]Resource, error)
+	// Delete deletes the given resource
+	Delete(resource Resource) error
+}
+
+// namedForRelease returns true if the resource's name, after its unique resource prefix, starts with one of the given
+// release names
+func (r Resource) namedForRelease(releaseNames []string) bool {
+	_, rest, matches := parseName(r.Kind, r.baseName())
+	if !matches {
+		return false
+	}
+	sep := nameSeparators[r.Kind]
+	for _, release := range releaseNames {
+		// database names can't contain dashes
+		release = strings.ReplaceAll(release, "-", sep)
+		if rest == release || strings.HasPrefix(rest, release+sep) {
+			return true
+		}
+	}
+	return false
+}
+
+// baseName returns the part of the resource's name that BEEs control (for service accounts, the account id)
+func (r Resource) baseName() string {
+	if r.Kind == ServiceAccount {
+		return strings.SplitN(r.Name, "@", 2)[0]
+	}
+	return r.Name
+}
+
+// matchPrefix returns the unique resource prefix the name starts with, if it follows the naming pattern BEEs use
+// for the given kind of resource
+func matchPrefix(kind Kind, name string) (string, bool) {
+	prefix, _, matches := parseName(kind, name)
+	return prefix, matches
+}
+
+// parseName splits a name following a BEE naming pattern into its unique resource prefix and the rest of the name
+func parseName(kind Kind, name string) (prefix string, rest string, matches bool) {
+	pattern, known := namePatterns[kind]
+	if !known {
+		return "", "", false
+	}
+	match := pattern.FindStringSubmatch(name)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
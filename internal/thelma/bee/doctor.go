@@ -0,0 +1,241 @@
+package bee
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/broadinstitute/thelma/internal/thelma/ops/status"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/filter"
+	"github.com/broadinstitute/thelma/internal/thelma/toolbox/argocd"
+	"github.com/rs/zerolog/log"
+)
+
+type DoctorOptions struct {
+	// Notify if true, DM owners of unhealthy BEEs
+	Notify bool
+	// Alert if true, post a digest of unhealthy BEEs to the DevOps alert channel
+	Alert bool
+	// Remediate if true, re-sync the generator for BEEs with missing or out-of-sync apps
+	Remediate bool
+}
+
+// AppProblem describes an Argo app in a BEE that is not healthy and synced
+type AppProblem struct {
+	Release  string `json:"release" yaml:"release"`
+	Health   string `json:"health" yaml:"health"`
+	Sync     string `json:"sync" yaml:"sync"`
+	Headline string `json:"headline" yaml:"headline"`
+}
+
+// BeeHealth summarizes the health of a single BEE
+type BeeHealth struct {
+	Name  string `json:"name" yaml:"name"`
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	// Skipped reason the BEE was not checked, if it wasn't
+	Skipped string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+	// Problems apps that are degraded, missing, or out of sync
+	Problems []AppProblem `json:"problems,omitempty" yaml:"problems,omitempty"`
+	// Remediated true if the BEE's generator was re-synced
+	Remediated bool `json:"remediated,omitempty" yaml:"remediated,omitempty"`
+	// Notified true if the owner was sent a Slack message
+	Notified bool `json:"notified,omitempty" yaml:"notified,omitempty"`
+}
+
+// Healthy returns true if the BEE was checked and has no problems
+func (h BeeHealth) Healthy() bool {
+	return h.Skipped == "" && len(h.Problems) == 0
+}
+
+// DoctorReport summarizes the health of a fleet of BEEs
+type DoctorReport struct {
+	Bees []BeeHealth `json:"bees" yaml:"bees"`
+}
+
+// Unhealthy returns BEEs that were checked and have problems
+func (r *DoctorReport) Unhealthy() []BeeHealth {
+	var unhealthy []BeeHealth
+	for _, h := range r.Bees {
+		if h.Skipped == "" && !h.Healthy() {
+			unhealthy = append(unhealthy, h)
+		}
+	}
+	return unhealthy
+}
+
+func (r *DoctorReport) String() string {
+	var sb strings.Builder
+	unhealthy := r.Unhealthy()
+	sb.WriteString(fmt.Sprintf("%d of %d BEEs are unhealthy\n", len(unhealthy), len(r.Bees)))
+	for _, h := range unhealthy {
+		sb.WriteString(h.Name)
+		if h.Owner != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", h.Owner))
+		}
+		if h.Remediated {
+			sb.WriteString(" [generator re-synced]")
+		}
+		sb.WriteString(":\n")
+		for _, p := range h.Problems {
+			sb.WriteString(fmt.Sprintf("  %s: %s/%s: %s\n", p.Release, p.Health, p.Sync, p.Headline))
+		}
+	}
+	for _, h := range r.Bees {
+		if h.Skipped != "" {
+			sb.WriteString(fmt.Sprintf("skipped %s: %s\n", h.Name, h.Skipped))
+		}
+	}
+	return sb.String()
+}
+
+// Doctor checks the status of every app in each BEE matching the filter, and reports apps that are degraded, missing,
+// or out of sync. Depending on options, it also notifies owners, posts a digest, and re-syncs generators.
+func (b *bees) Doctor(_filter terra.EnvironmentFilter, options DoctorOptions) (*DoctorReport, error) {
+	envs, err := b.FilterBees(_filter)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(envs, func(i, j int) bool { return envs[i].Name() < envs[j].Name() })
+
+	reader, err := b.ops.Status()
+	if err != nil {
+		return nil, err
+	}
+	allReleases, err := b.state.Releases().All()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DoctorReport{Bees: []BeeHealth{}}
+	for _, env := range envs {
+		health := BeeHealth{Name: env.Name(), Owner: env.Owner()}
+		if env.Offline() {
+			health.Skipped = "offline"
+			report.Bees = append(report.Bees, health)
+			continue
+		}
+
+		releases := filter.Releases().BelongsToEnvironment(env).Filter(allReleases)
+		health.Problems = diagnose(reader, releases)
+
+		if !health.Healthy() {
+			if options.Remediate && needsGeneratorSync(health.Problems) {
+				if err = b.SyncEnvironmentGenerator(env); err != nil {
+					log.Warn().Err(err).Msgf("error re-syncing generator for %s: %v", env.Name(), err)
+				} else {
+					health.Remediated = true
+				}
+			}
+			if options.Notify {
+				health.Notified = b.trySendDoctorNotification(env, health)
+			}
+		}
+		report.Bees = append(report.Bees, health)
+	}
+
+	if options.Alert {
+		b.trySendDoctorDigest(report)
+	}
+	return report, nil
+}
+
+// diagnose returns problems with the given releases. If statuses can't be read in bulk (usually because an Argo
+// app is missing), statuses are read one at a time so the releases that can't be read can be identified.
+func diagnose(reader status.Reader, releases []terra.Release) []AppProblem {
+	statuses, err := reader.Statuses(releases)
+	if err != nil {
+		log.Debug().Err(err).Msgf("error reading statuses, will read them individually: %v", err)
+		statuses = make(map[terra.Release]*status.Status)
+		for _, release := range releases {
+			_status, err := reader.Status(release)
+			if err != nil {
+				statuses[release] = nil
+				continue
+			}
+			statuses[release] = _status
+		}
+	}
+
+	var problems []AppProblem
+	for _, release := range releases {
+		_status, exists := statuses[release]
+		if !exists || _status == nil {
+			problems = append(problems, AppProblem{
+				Release:  release.Name(),
+				Health:   argocd.Missing.String(),
+				Sync:     argocd.UnknownSyncStatus.String(),
+				Headline: "could not read status from ArgoCD",
+			})
+			continue
+		}
+		if _status.IsHealthy() && _status.Sync == argocd.Synced {
+			continue
+		}
+		problems = append(problems, AppProblem{
+			Release:  release.Name(),
+			Health:   _status.Health.String(),
+			Sync:     _status.Sync.String(),
+			Headline: _status.Headline(),
+		})
+	}
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Release < problems[j].Release })
+	return problems
+}
+
+// needsGeneratorSync returns true if any problems could be fixed by re-syncing the environment's generator
+func needsGeneratorSync(problems []AppProblem) bool {
+	for _, p := range problems {
+		if p.Health == argocd.Missing.String() || p.Sync == argocd.OutOfSync.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *bees) trySendDoctorNotification(env terra.Environment, health BeeHealth) bool {
+	if env.Owner() == "" {
+		return false
+	}
+	if b.slack == nil {
+		log.Warn().Msgf("Would have notified %s that %s is unhealthy but Slack client wasn't present; perhaps it errored earlier", env.Owner(), env.Name())
+		return false
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Your <https://broad.io/beehive/r/environment/%s|%s> BEE has %d unhealthy app(s):\n", env.Name(), env.Name(), len(health.Problems)))
+	for _, p := range health.Problems {
+		sb.WriteString(fmt.Sprintf("• *%s*: %s\n", p.Release, p.Headline))
+	}
+	if health.Remediated {
+		sb.WriteString("Its generator was re-synced automatically. ")
+	}
+	sb.WriteString(fmt.Sprintf("To sync it, run `thelma bee sync --name %s`.", env.Name()))
+
+	if err := b.slack.SendDirectMessage(env.Owner(), sb.String()); err != nil {
+		log.Warn().Msgf("Wasn't able to notify %s: %v", env.Owner(), err)
+		return false
+	}
+	return true
+}
+
+func (b *bees) trySendDoctorDigest(report *DoctorReport) {
+	if b.slack == nil {
+		log.Warn().Msgf("Would have posted BEE health digest but Slack client wasn't present; perhaps it errored earlier")
+		return
+	}
+	unhealthy := report.Unhealthy()
+	title := fmt.Sprintf("BEE health: %d of %d BEEs are unhealthy", len(unhealthy), len(report.Bees))
+
+	var lines []string
+	for _, h := range unhealthy {
+		var releases []string
+		for _, p := range h.Problems {
+			releases = append(releases, p.Release)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", h.Name, strings.Join(releases, ", ")))
+	}
+	if err := b.slack.SendDevopsAlert(title, strings.Join(lines, "\n"), len(unhealthy) == 0); err != nil {
+		log.Warn().Msgf("Wasn't able to post BEE health digest: %v", err)
+	}
+}
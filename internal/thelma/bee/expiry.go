@@ -0,0 +1,124 @@
+package bee
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/filter"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// expiryTimeFormat is used for deletion times in Slack messages; includes the zone so there's no ambiguity
+const expiryTimeFormat = "Mon Jan 2 15:04 MST 2006"
+
+type NotifyExpiringOptions struct {
+	// DryRun if true, find expiring BEEs but don't notify their owners
+	DryRun bool
+}
+
+// ExpiryNotice describes a BEE that is scheduled for automatic deletion soon
+type ExpiryNotice struct {
+	Name        string    `json:"name" yaml:"name"`
+	Owner       string    `json:"owner,omitempty" yaml:"owner,omitempty"`
+	DeleteAfter time.Time `json:"deleteAfter" yaml:"deleteAfter"`
+	// Notified true if the owner was sent a Slack message
+	Notified bool `json:"notified" yaml:"notified"`
+	// PreviouslyNotified true if the owner was already notified about this deletion time, so wasn't messaged again
+	PreviouslyNotified bool `json:"previouslyNotified,omitempty" yaml:"previouslyNotified,omitempty"`
+}
+
+func (b *bees) NotifyExpiring(within time.Duration, options NotifyExpiringOptions) ([]ExpiryNotice, error) {
+	expiring, err := b.FilterBees(filter.Environments().AutoDeleteDueWithin(within).And(
+		filter.Environments().Matching("!preventDeletion()", func(env terra.Environment) bool {
+			return !env.PreventDeletion()
+		}),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	notices := make([]ExpiryNotice, 0, len(expiring))
+	for _, env := range expiring {
+		notice := ExpiryNotice{
+			Name:        env.Name(),
+			Owner:       env.Owner(),
+			DeleteAfter: env.AutoDelete().After(),
+		}
+		// owners are only messaged once per deletion time; extending a BEE changes it, so they'll hear again
+		if env.AutoDelete().NotifiedFor().Equal(notice.DeleteAfter) {
+			log.Debug().Msgf("%s was already notified that %s is expiring", env.Owner(), env.Name())
+			notice.PreviouslyNotified = true
+		} else if !options.DryRun {
+			notice.Notified = b.trySendExpiryNotification(env)
+			if notice.Notified {
+				if err = b.state.Environments().SetAutoDeleteNotified(env.Name(), notice.DeleteAfter); err != nil {
+					return notices, errors.Errorf("notified %s that %s is expiring, but failed to record it: %v", env.Owner(), env.Name(), err)
+				}
+			}
+		}
+		notices = append(notices, notice)
+	}
+	return notices, nil
+}
+
+func (b *bees) trySendExpiryNotification(env terra.Environment) bool {
+	if env.Owner() == "" {
+		log.Warn().Msgf("%s will be deleted at %s but has no owner to notify", env.Name(), env.AutoDelete().After())
+		return false
+	}
+	if b.slack == nil {
+		log.Warn().Msgf("Would have notified %s that %s is expiring but Slack client wasn't present; perhaps it errored earlier", env.Owner(), env.Name())
+		return false
+	}
+
+	markdown := fmt.Sprintf("Your <https://broad.io/beehive/r/environment/%s|%s> BEE will be automatically deleted after %s. "+
+		"To keep it longer, run `thelma bee extend --name %s --by 24h`.",
+		env.Name(), env.Name(), env.AutoDelete().After().UTC().Format(expiryTimeFormat), env.Name())
+
+	if err := b.slack.SendDirectMessage(env.Owner(), markdown); err != nil {
+		log.Warn().Msgf("Wasn't able to notify %s: %v", env.Owner(), err)
+		return false
+	}
+	log.Info().Msgf("Notified %s that %s is expiring", env.Owner(), env.Name())
+	return true
+}
+
+func (b *bees) Extend(name string, by time.Duration) (*Bee, error) {
+	if by <= 0 {
+		return nil, errors.Errorf("can't extend %s by %s: duration must be greater than zero", name, by)
+	}
+	env, err := b.GetBee(name)
+	if err != nil {
+		return nil, err
+	}
+	if !env.AutoDelete().Enabled() {
+		return nil, errors.Errorf("%s is not scheduled for automatic deletion, nothing to extend", name)
+	}
+	return b.SetAutoDeleteAfter(name, env.AutoDelete().After().Add(by))
+}
+
+func (b *bees) SetAutoDeleteAfter(name string, after time.Time) (*Bee, error) {
+	env, err := b.GetBee(name)
+	if err != nil {
+		return nil, err
+	}
+	if err = b.state.Environments().SetAutoDeleteAfter(name, after); err != nil {
+		return nil, errors.Errorf("error updating auto-delete time for %s: %v", name, err)
+	}
+	if env.AutoDelete().Enabled() {
+		log.Info().Msgf("%s will now be deleted after %s (was: %s)", name, after, env.AutoDelete().After())
+	} else {
+		log.Info().Msgf("%s will now be deleted after %s", name, after)
+	}
+
+	if err = b.reloadState(); err != nil {
+		return nil, err
+	}
+	env, err = b.GetBee(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Bee{Environment: env}, nil
+}
@@ -0,0 +1,139 @@
+package bee
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/bee/cleanup"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/set"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type GCOptions struct {
+	// DryRun if true, find orphaned resources but don't delete them
+	DryRun bool
+	// GracePeriod resources created more recently than this are never treated as orphans
+	GracePeriod time.Duration
+}
+
+// GCReport summarizes a garbage collection run
+type GCReport struct {
+	DryRun bool `json:"dryRun" yaml:"dryRun"`
+	// Orphans resources whose unique resource prefix doesn't belong to any live environment
+	Orphans []cleanup.Resource `json:"orphans" yaml:"orphans"`
+	// Deleted orphans that were successfully deleted
+	Deleted []cleanup.Resource `json:"deleted" yaml:"deleted"`
+	// Failed orphans that could not be deleted, with the error message
+	Failed map[string]string `json:"failed,omitempty" yaml:"failed,omitempty"`
+}
+
+func (r *GCReport) String() string {
+	var sb strings.Builder
+	if len(r.Orphans) == 0 {
+		sb.WriteString("No orphaned resources found\n")
+		return sb.String()
+	}
+
+	if r.DryRun {
+		sb.WriteString(fmt.Sprintf("Found %d orphaned resources (dry run, nothing was deleted):\n", len(r.Orphans)))
+		for _, resource := range r.Orphans {
+			sb.WriteString(fmt.Sprintf("  %s\n", resource))
+		}
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Deleted %d of %d orphaned resources:\n", len(r.Deleted), len(r.Orphans)))
+	for _, resource := range r.Deleted {
+		sb.WriteString(fmt.Sprintf("  %s\n", resource))
+	}
+	if len(r.Failed) > 0 {
+		sb.WriteString(fmt.Sprintf("Failed to delete %d resources:\n", len(r.Failed)))
+		for _, resource := range r.Orphans {
+			if msg, failed := r.Failed[resource.String()]; failed {
+				sb.WriteString(fmt.Sprintf("  %s: %s\n", resource, msg))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// GC finds cloud resources that were left behind by deleted BEEs and, unless this is a dry run, deletes them.
+// Deletion continues past individual failures; an error is returned at the end if any deletion failed.
+func (b *bees) GC(options GCOptions) (*GCReport, error) {
+	// anything missing from state is treated as an orphan, so never look for orphans against a stale cache
+	if err := b.reloadState(); err != nil {
+		return nil, errors.Errorf("error reloading state, refusing to look for orphaned resources: %v", err)
+	}
+	environments, err := b.state.Environments().All()
+	if err != nil {
+		return nil, err
+	}
+
+	orphans, err := b.cleanup.FindOrphans(environments, options.GracePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	// not every kind of resource records when it was created, so the grace period can't protect all of them from
+	// a BEE created while orphans were being listed; check them against state again
+	orphans, err = b.confirmOrphans(orphans)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GCReport{
+		DryRun:  options.DryRun,
+		Orphans: orphans,
+		Deleted: []cleanup.Resource{},
+		Failed:  make(map[string]string),
+	}
+	if options.DryRun {
+		return report, nil
+	}
+
+	for _, resource := range orphans {
+		log.Info().Msgf("Deleting orphaned %s", resource)
+		if err = b.cleanup.Delete(resource); err != nil {
+			log.Error().Err(err).Msgf("error deleting orphaned %s: %v", resource, err)
+			report.Failed[resource.String()] = err.Error()
+			continue
+		}
+		report.Deleted = append(report.Deleted, resource)
+	}
+
+	if len(report.Failed) > 0 {
+		return report, errors.Errorf("failed to delete %d of %d orphaned resources", len(report.Failed), len(orphans))
+	}
+	return report, nil
+}
+
+// confirmOrphans reloads state and returns only the orphans whose unique resource prefix still doesn't belong to an
+// environment
+func (b *bees) confirmOrphans(orphans []cleanup.Resource) ([]cleanup.Resource, error) {
+	if len(orphans) == 0 {
+		return orphans, nil
+	}
+	if err := b.reloadState(); err != nil {
+		return nil, errors.Errorf("error reloading state, refusing to delete orphaned resources: %v", err)
+	}
+	environments, err := b.state.Environments().All()
+	if err != nil {
+		return nil, err
+	}
+	livePrefixes := set.NewStringSet()
+	for _, env := range environments {
+		livePrefixes.Add(env.UniqueResourcePrefix())
+	}
+
+	var confirmed []cleanup.Resource
+	for _, resource := range orphans {
+		if livePrefixes.Exists(resource.Prefix) {
+			log.Info().Msgf("Not deleting %s: prefix %s now belongs to an environment", resource, resource.Prefix)
+			continue
+		}
+		confirmed = append(confirmed, resource)
+	}
+	return confirmed, nil
+}
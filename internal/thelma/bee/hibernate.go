@@ -0,0 +1,105 @@
+package bee
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/bee/hibernation"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// hibernationSnapshotFormat is used to name hibernation snapshots, eg. "hibernate-20240102-150405". They are taken
+// with the same snapshots implementation as `bee snapshot`, so they show up in `bee snapshot list` too.
+const hibernationSnapshotFormat = "hibernate-20060102-150405"
+
+// hibernate scales the BEE down, snapshots its persistent volumes, records the snapshots in the hibernation store,
+// and then deletes the snapshotted PVCs so that the underlying disks are released
+func (b *bees) hibernate(name string, options StartStopOptions) error {
+	if options.Hibernation == nil {
+		return errors.Errorf("can't hibernate %s, no hibernation store was configured", name)
+	}
+	env, err := b.GetBee(name)
+	if err != nil {
+		return err
+	}
+
+	existing, err := options.Hibernation.Load(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return errors.Errorf("%s is already hibernated (since %s); start it before hibernating again", name, existing.HibernatedAt.Format(time.RFC3339))
+	}
+
+	snapshotClass := options.SnapshotClass
+	if snapshotClass == "" {
+		snapshotClass = hibernation.DefaultSnapshotClass
+	}
+
+	// scale down first, so nothing is writing to the volumes while they're snapshotted
+	if err = b.kubectl.ShutDown(env); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	snapshotName := now.UTC().Format(hibernationSnapshotFormat)
+	created, err := b.snapshots.Create(env, snapshotName, snapshotClass)
+	if err != nil {
+		return errors.Errorf("error snapshotting volumes for %s, PVCs were not deleted: %v", name, err)
+	}
+
+	if err = options.Hibernation.Save(&hibernation.Manifest{
+		Environment:  name,
+		HibernatedAt: now,
+		Snapshot:     snapshotName,
+		Snapshots:    created,
+	}); err != nil {
+		return errors.Errorf("error saving hibernation manifest for %s, PVCs were not deleted: %v", name, err)
+	}
+
+	log.Info().Msgf("Snapshotted %d volumes for %s, deleting PVCs", len(created), name)
+	return b.snapshots.DeleteClaims(env, snapshotName)
+}
+
+// restoreFromHibernation restores the BEE's PVCs from the snapshots recorded in the manifest
+func (b *bees) restoreFromHibernation(name string, manifest *hibernation.Manifest) error {
+	env, err := b.GetBee(name)
+	if err != nil {
+		return err
+	}
+
+	if len(manifest.Snapshots) == 0 {
+		log.Info().Msgf("%s was hibernated at %s without any volumes, nothing to restore", name, manifest.HibernatedAt.Format(time.RFC3339))
+		return nil
+	}
+
+	log.Info().Msgf("%s was hibernated at %s, restoring %d volumes from snapshot %s", name, manifest.HibernatedAt.Format(time.RFC3339), len(manifest.Snapshots), manifest.Snapshot)
+	if _, err = b.snapshots.Restore(env, manifest.Snapshot); err != nil {
+		return errors.Errorf("error restoring volumes for %s: %v", name, err)
+	}
+	return nil
+}
+
+// removeHibernation deletes the BEE's hibernation snapshots and then its manifest, if it has one. Failures are
+// logged rather than returned, since they only leave behind snapshots that cost money rather than break anything.
+func (b *bees) removeHibernation(env terra.Environment, store hibernation.Store) {
+	manifest, err := store.Load(env.Name())
+	if err != nil {
+		log.Warn().Err(err).Msgf("error loading hibernation manifest for %s, its volume snapshots may need to be deleted by hand: %v", env.Name(), err)
+		return
+	}
+	if manifest == nil {
+		return
+	}
+	if len(manifest.Snapshots) > 0 {
+		if err = b.snapshots.Delete(env, manifest.Snapshot); err != nil {
+			// keep the manifest, so there's still a record of the snapshots
+			log.Warn().Err(err).Msgf("error deleting hibernation snapshots for %s: %v", env.Name(), err)
+			return
+		}
+	}
+	if err = store.Delete(env.Name()); err != nil {
+		log.Warn().Err(err).Msgf("error removing hibernation manifest for %s: %v", env.Name(), err)
+	}
+}
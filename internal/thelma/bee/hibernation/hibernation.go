@@ -0,0 +1,90 @@
+// Package hibernation records the volume snapshots taken when a BEE is hibernated, so that its data can be restored
+// when it is started again
+package hibernation
+
+import (
+	"encoding/json"
+	"path"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/bee/snapshots"
+	"github.com/broadinstitute/thelma/internal/thelma/clients/google/bucket"
+	"github.com/pkg/errors"
+)
+
+// DefaultSnapshotClass name of the VolumeSnapshotClass used to snapshot BEE volumes
+const DefaultSnapshotClass = "bee-volume-snapshots"
+
+// Manifest records the snapshots of a hibernated BEE's persistent volume claims
+type Manifest struct {
+	// Environment name of the hibernated environment
+	Environment string `json:"environment" yaml:"environment"`
+	// HibernatedAt when the environment was hibernated
+	HibernatedAt time.Time `json:"hibernatedAt" yaml:"hibernatedAt"`
+	// Snapshot name of the snapshot taken of the environment's persistent volume claims, eg. "hibernate-20240102-150405"
+	Snapshot string `json:"snapshot" yaml:"snapshot"`
+	// Snapshots volume snapshots making up the snapshot, one per persistent volume claim
+	Snapshots []snapshots.Snapshot `json:"snapshots" yaml:"snapshots"`
+}
+
+// Store persists hibernation manifests
+type Store interface {
+	// Load returns the manifest for the given environment, or nil if it is not hibernated
+	Load(environment string) (*Manifest, error)
+	// Save persists a manifest
+	Save(manifest *Manifest) error
+	// Delete removes the manifest for the given environment, if there is one
+	Delete(environment string) error
+}
+
+// NewBucketStore returns a Store that saves manifests to a GCS bucket, normally the BEE's cluster artifact bucket
+func NewBucketStore(_bucket bucket.Bucket) Store {
+	return &bucketStore{bucket: _bucket}
+}
+
+type bucketStore struct {
+	bucket bucket.Bucket
+}
+
+func (s *bucketStore) Load(environment string) (*Manifest, error) {
+	exists, err := s.bucket.Exists(s.object(environment))
+	if err != nil {
+		return nil, errors.Errorf("error checking for hibernation manifest for %s in %s: %v", environment, s.bucket.Name(), err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	content, err := s.bucket.Read(s.object(environment))
+	if err != nil {
+		return nil, errors.Errorf("error reading hibernation manifest for %s from %s: %v", environment, s.bucket.Name(), err)
+	}
+	var manifest Manifest
+	if err = json.Unmarshal(content, &manifest); err != nil {
+		return nil, errors.Errorf("error parsing hibernation manifest for %s: %v", environment, err)
+	}
+	return &manifest, nil
+}
+
+func (s *bucketStore) Save(manifest *Manifest) error {
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return errors.Errorf("error marshalling hibernation manifest for %s: %v", manifest.Environment, err)
+	}
+	if err = s.bucket.Write(s.object(manifest.Environment), content); err != nil {
+		return errors.Errorf("error writing hibernation manifest for %s to %s: %v", manifest.Environment, s.bucket.Name(), err)
+	}
+	return nil
+}
+
+func (s *bucketStore) Delete(environment string) error {
+	exists, err := s.bucket.Exists(s.object(environment))
+	if err != nil || !exists {
+		return err
+	}
+	return s.bucket.Delete(s.object(environment))
+}
+
+// object path matches the layout used for other operational artifacts, eg. "my-bee/hibernation/manifest.json"
+func (s *bucketStore) object(environment string) string {
+	return path.Join(environment, "hibernation", "manifest.json")
+}
@@ -0,0 +1,34 @@
+package hibernation
+
+import (
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/clients/google/bucket/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketStore(t *testing.T) {
+	_bucket := mocks.NewBucket(t)
+	_bucket.EXPECT().Name().Return("my-bucket").Maybe()
+	store := NewBucketStore(_bucket)
+
+	_bucket.EXPECT().Exists("my-bee/hibernation/manifest.json").Return(false, nil).Once()
+	manifest, err := store.Load("my-bee")
+	require.NoError(t, err)
+	assert.Nil(t, manifest)
+
+	_bucket.EXPECT().Write("my-bee/hibernation/manifest.json", mock.Anything).Return(nil)
+	require.NoError(t, store.Save(&Manifest{Environment: "my-bee"}))
+
+	_bucket.EXPECT().Exists("my-bee/hibernation/manifest.json").Return(true, nil)
+	_bucket.EXPECT().Read("my-bee/hibernation/manifest.json").Return([]byte(`{"environment":"my-bee","snapshots":[{"name":"data-mongodb-0-20240102","claim":"data-mongodb-0","size":"10Gi"}]}`), nil)
+	manifest, err = store.Load("my-bee")
+	require.NoError(t, err)
+	require.NotNil(t, manifest)
+	assert.Equal(t, "data-mongodb-0", manifest.Snapshots[0].Claim)
+
+	_bucket.EXPECT().Delete("my-bee/hibernation/manifest.json").Return(nil)
+	require.NoError(t, store.Delete("my-bee"))
+}
@@ -0,0 +1,52 @@
+// Package holidays loads the holiday calendar that BEE start schedules skip, so BEEs don't spend a day off running
+// for nobody
+package holidays
+
+import (
+	"os"
+	"path"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/config"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/pkg/errors"
+)
+
+const configKey = "bee.holidays"
+
+type holidaysConfig struct {
+	// ICS optional path to an iCalendar file of holidays; relative paths are resolved against the etc directory
+	ICS string
+	// Dates optional list of holidays, eg. bee.holidays.dates: [{date: 2023-12-25, name: Christmas Day}]
+	Dates []schedule.Holiday
+}
+
+// Load returns the holidays listed in Thelma config and in the ICS file it points to, if any
+func Load(thelmaConfig config.Config, etcDir string) (schedule.Holidays, error) {
+	var cfg holidaysConfig
+	if err := thelmaConfig.Unmarshal(configKey, &cfg); err != nil {
+		return nil, err
+	}
+
+	all := cfg.Dates
+	if cfg.ICS != "" {
+		file := cfg.ICS
+		if !path.IsAbs(file) {
+			file = path.Join(etcDir, file)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Errorf("error reading holiday calendar from %s: %v", file, err)
+		}
+		fromFile, err := schedule.ParseICS(content)
+		if err != nil {
+			return nil, errors.Errorf("error parsing holiday calendar from %s: %v", file, err)
+		}
+		all = append(fromFile, all...)
+	}
+
+	holidays, err := schedule.NewHolidays(all)
+	if err != nil {
+		return nil, errors.Errorf("%s: %v", configKey, err)
+	}
+	return holidays, nil
+}
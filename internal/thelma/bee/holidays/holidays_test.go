@@ -0,0 +1,67 @@
+package holidays
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testICS = `BEGIN:VCALENDAR
+BEGIN:VEVENT
+DTSTART;VALUE=DATE:20231225
+DTEND;VALUE=DATE:20231226
+SUMMARY:Christmas Day
+END:VEVENT
+END:VCALENDAR
+`
+
+func Test_Load(t *testing.T) {
+	etcDir := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(etcDir, "holidays.ics"), []byte(testICS), 0644))
+
+	t.Run("no config", func(t *testing.T) {
+		thelmaConfig, err := config.NewTestConfig(t)
+		require.NoError(t, err)
+		holidays, err := Load(thelmaConfig, etcDir)
+		require.NoError(t, err)
+		assert.Empty(t, holidays)
+	})
+
+	t.Run("ics and dates", func(t *testing.T) {
+		thelmaConfig, err := config.NewTestConfig(t, map[string]interface{}{
+			"bee.holidays.ics": "holidays.ics",
+			"bee.holidays.dates": []map[string]interface{}{
+				{"date": "2023-12-26", "name": "Institute Holiday"},
+			},
+		})
+		require.NoError(t, err)
+		holidays, err := Load(thelmaConfig, etcDir)
+		require.NoError(t, err)
+		assert.Equal(t, "Christmas Day", holidays.Name(time.Date(2023, 12, 25, 7, 0, 0, 0, time.UTC)))
+		assert.Equal(t, "Institute Holiday", holidays.Name(time.Date(2023, 12, 26, 7, 0, 0, 0, time.UTC)))
+		assert.False(t, holidays.IsHoliday(time.Date(2023, 12, 27, 7, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("missing ics", func(t *testing.T) {
+		thelmaConfig, err := config.NewTestConfig(t, map[string]interface{}{
+			"bee.holidays.ics": "missing.ics",
+		})
+		require.NoError(t, err)
+		_, err = Load(thelmaConfig, etcDir)
+		assert.ErrorContains(t, err, "error reading holiday calendar")
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		thelmaConfig, err := config.NewTestConfig(t, map[string]interface{}{
+			"bee.holidays.dates": []map[string]interface{}{{"date": "christmas"}},
+		})
+		require.NoError(t, err)
+		_, err = Load(thelmaConfig, etcDir)
+		assert.ErrorContains(t, err, "invalid holiday date")
+	})
+}
@@ -0,0 +1,128 @@
+// Package profiles defines named sets of releases to keep enabled when creating a BEE, so that teams who only need a
+// handful of services don't have to wait for every release in a template to become healthy
+package profiles
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/config"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const configKey = "bee"
+
+// File name of the profiles file in the terra-helmfile etc directory ($THELMA_HOME/etc/bee-profiles.yaml)
+const File = "bee-profiles.yaml"
+
+// Profile is a named set of releases to keep enabled in a BEE; all other releases in the template are disabled
+type Profile struct {
+	// Name of the profile, eg. "minimal"
+	Name string `json:"name" yaml:"name"`
+	// Description optional human-readable description of the profile
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Releases names of releases to keep enabled
+	Releases []string `json:"releases" yaml:"releases"`
+}
+
+type profilesConfig struct {
+	// Profiles maps profile names to profiles, eg. bee.profiles.minimal.releases
+	Profiles map[string]Profile
+}
+
+// Profiles is a collection of named profiles
+type Profiles map[string]Profile
+
+// Load returns profiles defined in Thelma config and in the profiles file in the given etc directory.
+// Profiles in the file take precedence over profiles of the same name in config.
+func Load(thelmaConfig config.Config, etcDir string) (Profiles, error) {
+	var cfg profilesConfig
+	if err := thelmaConfig.Unmarshal(configKey, &cfg); err != nil {
+		return nil, err
+	}
+
+	profiles := make(Profiles)
+	for name, profile := range cfg.Profiles {
+		profile.Name = name
+		profiles[name] = profile
+	}
+
+	file := path.Join(etcDir, File)
+	content, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, errors.Errorf("error reading BEE profiles from %s: %v", file, err)
+	}
+
+	var fromFile map[string]Profile
+	if err = yaml.Unmarshal(content, &fromFile); err != nil {
+		return nil, errors.Errorf("error parsing BEE profiles from %s: %v", file, err)
+	}
+	for name, profile := range fromFile {
+		if _, exists := profiles[name]; exists {
+			log.Debug().Msgf("BEE profile %q in %s overrides profile of the same name in config", name, file)
+		}
+		profile.Name = name
+		profiles[name] = profile
+	}
+	return profiles, nil
+}
+
+// Names returns the names of all profiles, sorted
+func (p Profiles) Names() []string {
+	var names []string
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the profile with the given name, or an error listing valid profiles if there is none
+func (p Profiles) Get(name string) (Profile, error) {
+	profile, exists := p[name]
+	if !exists {
+		if len(p) == 0 {
+			return Profile{}, errors.Errorf("no BEE profile by the name %q exists; no profiles are defined", name)
+		}
+		return Profile{}, errors.Errorf("no BEE profile by the name %q exists, valid profiles are: %s", name, strings.Join(p.Names(), ", "))
+	}
+	return profile, nil
+}
+
+// Disabled returns the names of releases in the template that the profile does not keep enabled, sorted.
+// Returns an error if the profile lists a release that does not exist in the template.
+func (profile Profile) Disabled(template terra.Environment) ([]string, error) {
+	templateReleases := releaseNames(template)
+	keep := make(map[string]struct{})
+	for _, release := range profile.Releases {
+		if _, exists := templateReleases[release]; !exists {
+			return nil, errors.Errorf("profile %q includes release %q, which does not exist in template %s", profile.Name, release, template.Name())
+		}
+		keep[release] = struct{}{}
+	}
+
+	var disabled []string
+	for release := range templateReleases {
+		if _, exists := keep[release]; !exists {
+			disabled = append(disabled, release)
+		}
+	}
+	sort.Strings(disabled)
+	return disabled, nil
+}
+
+func releaseNames(env terra.Environment) map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, release := range env.Releases() {
+		names[release.Name()] = struct{}{}
+	}
+	return names
+}
@@ -0,0 +1,87 @@
+package profiles
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/config"
+	"github.com/broadinstitute/thelma/internal/thelma/state/testing/statefixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Load(t *testing.T) {
+	thelmaConfig, err := config.NewTestConfig(t, map[string]interface{}{
+		"bee.profiles": map[string]interface{}{
+			"minimal": map[string]interface{}{
+				"releases": []string{"sam"},
+			},
+			"workspaces": map[string]interface{}{
+				"description": "Workspace services",
+				"releases":    []string{"sam", "rawls"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	etcDir := t.TempDir()
+
+	t.Run("config only", func(t *testing.T) {
+		profiles, err := Load(thelmaConfig, etcDir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"minimal", "workspaces"}, profiles.Names())
+		assert.Equal(t, Profile{Name: "workspaces", Description: "Workspace services", Releases: []string{"sam", "rawls"}}, profiles["workspaces"])
+	})
+
+	t.Run("file overrides config", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path.Join(etcDir, File), []byte(`
+minimal:
+  releases: [sam, leonardo]
+notebooks:
+  releases: [sam, leonardo]
+`), 0644))
+		profiles, err := Load(thelmaConfig, etcDir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"minimal", "notebooks", "workspaces"}, profiles.Names())
+		assert.Equal(t, []string{"sam", "leonardo"}, profiles["minimal"].Releases)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path.Join(etcDir, File), []byte(`not: [valid`), 0644))
+		_, err := Load(thelmaConfig, etcDir)
+		assert.ErrorContains(t, err, "error parsing BEE profiles")
+	})
+}
+
+func Test_Get(t *testing.T) {
+	profiles := Profiles{"minimal": {Name: "minimal"}, "workspaces": {Name: "workspaces"}}
+
+	profile, err := profiles.Get("minimal")
+	require.NoError(t, err)
+	assert.Equal(t, "minimal", profile.Name)
+
+	_, err = profiles.Get("tiny")
+	assert.ErrorContains(t, err, `no BEE profile by the name "tiny" exists, valid profiles are: minimal, workspaces`)
+
+	_, err = Profiles{}.Get("tiny")
+	assert.ErrorContains(t, err, "no profiles are defined")
+}
+
+func Test_Disabled(t *testing.T) {
+	fixture, err := statefixtures.LoadFixtureFromFile("testdata/statefixture.yaml")
+	require.NoError(t, err)
+	template := fixture.Environment("swatomation")
+
+	profiles := Profiles{
+		"minimal":    {Name: "minimal", Releases: []string{"sam"}},
+		"workspaces": {Name: "workspaces", Releases: []string{"rawls", "sam"}},
+	}
+
+	disabled, err := profiles["workspaces"].Disabled(template)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"leonardo"}, disabled)
+
+	_, err = Profile{Name: "broken", Releases: []string{"sam", "cromwell"}}.Disabled(template)
+	assert.ErrorContains(t, err, `profile "broken" includes release "cromwell", which does not exist in template swatomation`)
+}
@@ -0,0 +1,95 @@
+package bee
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/bee/checkpoint"
+	"github.com/rs/zerolog/log"
+)
+
+// progress records provisioning progress for a single BEE. A nil *progress is valid and records nothing,
+// so that callers don't need to check whether checkpointing is enabled.
+type progress struct {
+	store      checkpoint.Store
+	checkpoint *checkpoint.Checkpoint
+	resume     bool
+}
+
+// newProgress loads or initializes provisioning progress for the given BEE. Returns nil if checkpointing is not
+// enabled in the options.
+func newProgress(name string, options ProvisionOptions) (*progress, error) {
+	if options.Checkpoints == nil {
+		return nil, nil
+	}
+
+	p := &progress{store: options.Checkpoints, resume: options.Resume}
+
+	if options.Resume {
+		cp, err := options.Checkpoints.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		if cp == nil {
+			log.Warn().Msgf("No provisioning checkpoint found for %s, will provision from the beginning", name)
+		} else {
+			log.Info().Msgf("Resuming provisioning for %s from checkpoint:\n%s", name, cp.String())
+			p.checkpoint = cp
+		}
+	}
+	if p.checkpoint == nil {
+		p.checkpoint = checkpoint.New(name)
+	}
+	return p, nil
+}
+
+// skip returns true if the phase was completed by a previous attempt and should be skipped
+func (p *progress) skip(phase checkpoint.Phase) bool {
+	if p == nil || !p.resume || !p.checkpoint.Done(phase) {
+		return false
+	}
+	log.Info().Msgf("Skipping %s for %s, already completed", phase, p.checkpoint.Environment)
+	return true
+}
+
+// skipSeedStep returns true if the seed step was completed by a previous attempt and should be skipped
+func (p *progress) skipSeedStep(step string) bool {
+	if p == nil || !p.resume || !p.checkpoint.SeedStepDone(step) {
+		return false
+	}
+	log.Info().Msgf("Skipping seed step %s for %s, already completed", step, p.checkpoint.Environment)
+	return true
+}
+
+// complete records that the phase completed
+func (p *progress) complete(phase checkpoint.Phase) {
+	if p == nil {
+		return
+	}
+	p.checkpoint.Complete(phase)
+	p.save()
+}
+
+// completeSeedStep records that the seed step completed
+func (p *progress) completeSeedStep(step string) {
+	if p == nil {
+		return
+	}
+	p.checkpoint.CompleteSeedStep(step)
+	p.save()
+}
+
+// finish removes the checkpoint once provisioning has succeeded, since there's nothing left to resume. Failures are
+// logged rather than returned.
+func (p *progress) finish() {
+	if p == nil {
+		return
+	}
+	if err := p.store.Delete(p.checkpoint.Environment); err != nil {
+		log.Warn().Err(err).Msgf("error deleting provisioning checkpoint for %s: %v", p.checkpoint.Environment, err)
+	}
+}
+
+// save persists the checkpoint; failures are logged rather than returned, since they shouldn't fail provisioning
+func (p *progress) save() {
+	if err := p.store.Save(p.checkpoint); err != nil {
+		log.Warn().Err(err).Msgf("error saving provisioning checkpoint for %s: %v", p.checkpoint.Environment, err)
+	}
+}
@@ -0,0 +1,97 @@
+// Package quotas limits how many BEEs can exist at once, so one person (or one template) can't tie up the BEE
+// cluster
+package quotas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/config"
+	"github.com/broadinstitute/thelma/internal/thelma/clients/sherlock"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/pkg/errors"
+)
+
+const configKey = "bee.quotas"
+
+// Quotas limits on the number of BEEs that can exist at once. Zero means no limit.
+type Quotas struct {
+	// MaxPerOwner maximum number of BEEs a single owner can hold, eg. bee.quotas.maxPerOwner: 3
+	MaxPerOwner int `validate:"min=0"`
+	// MaxPerTemplate maximum number of BEEs that can exist from a single template
+	MaxPerTemplate int `validate:"min=0"`
+	// OverrideUsers emails of Sherlock users allowed to ignore quotas, eg. CI service accounts
+	OverrideUsers []string
+	// OverrideRoles names of Sherlock roles whose holders are allowed to ignore quotas
+	OverrideRoles []string
+}
+
+// Load returns the quotas in Thelma config
+func Load(thelmaConfig config.Config) (Quotas, error) {
+	var quotas Quotas
+	if err := thelmaConfig.Unmarshal(configKey, &quotas); err != nil {
+		return Quotas{}, err
+	}
+	return quotas, nil
+}
+
+// CheckOwner returns an error listing the owner's existing BEEs if they already hold MaxPerOwner of them
+func (q Quotas) CheckOwner(owner string, existing []terra.Environment) error {
+	if q.MaxPerOwner == 0 || len(existing) < q.MaxPerOwner {
+		return nil
+	}
+	return quotaError(fmt.Sprintf("%s already has %d BEE(s), and the limit is %d per owner", owner, len(existing), q.MaxPerOwner), existing)
+}
+
+// CheckTemplate returns an error listing the template's existing BEEs if there are already MaxPerTemplate of them
+func (q Quotas) CheckTemplate(template string, existing []terra.Environment) error {
+	if q.MaxPerTemplate == 0 || len(existing) < q.MaxPerTemplate {
+		return nil
+	}
+	return quotaError(fmt.Sprintf("there are already %d BEE(s) from template %s, and the limit is %d per template", len(existing), template, q.MaxPerTemplate), existing)
+}
+
+// AuthorizeOverride returns an error unless the user is allowed to ignore quotas, either because they're listed in
+// OverrideUsers or because they hold one of the OverrideRoles
+func (q Quotas) AuthorizeOverride(user sherlock.User) error {
+	for _, email := range q.OverrideUsers {
+		if strings.EqualFold(email, user.Email) {
+			return nil
+		}
+	}
+	for _, role := range q.OverrideRoles {
+		for _, held := range user.Roles {
+			if role == held {
+				return nil
+			}
+		}
+	}
+	return errors.Errorf("%s is not allowed to ignore BEE quotas; only users listed in %s.overrideUsers or holding a role in %s.overrideRoles can", user.Email, configKey, configKey)
+}
+
+func quotaError(summary string, existing []terra.Environment) error {
+	sorted := make([]terra.Environment, len(existing))
+	copy(sorted, existing)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+
+	var sb strings.Builder
+	sb.WriteString("BEE quota exceeded: ")
+	sb.WriteString(summary)
+	sb.WriteString(":\n")
+	for _, env := range sorted {
+		sb.WriteString(fmt.Sprintf("  %s (owner %s, %s)\n", env.Name(), env.Owner(), autoDeleteDescription(env)))
+	}
+	sb.WriteString(`delete one with "thelma bee delete", or wait for one to be auto-deleted`)
+	return errors.New(sb.String())
+}
+
+func autoDeleteDescription(env terra.Environment) string {
+	if env.AutoDelete() == nil || !env.AutoDelete().Enabled() {
+		return "no auto-delete"
+	}
+	return "auto-deletes at " + env.AutoDelete().After().UTC().Format(time.RFC3339)
+}
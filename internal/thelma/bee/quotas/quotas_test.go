@@ -0,0 +1,84 @@
+package quotas
+
+import (
+	"testing"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/config"
+	"github.com/broadinstitute/thelma/internal/thelma/clients/sherlock"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	statemocks "github.com/broadinstitute/thelma/internal/thelma/state/api/terra/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Load(t *testing.T) {
+	thelmaConfig, err := config.NewTestConfig(t, map[string]interface{}{
+		"bee.quotas.maxPerOwner": 3,
+	})
+	require.NoError(t, err)
+	quotas, err := Load(thelmaConfig)
+	require.NoError(t, err)
+	assert.Equal(t, Quotas{MaxPerOwner: 3}, quotas)
+
+	thelmaConfig, err = config.NewTestConfig(t, map[string]interface{}{
+		"bee.quotas.overrideUsers": []string{"ci@broad-dsde-qa.iam.gserviceaccount.com"},
+		"bee.quotas.overrideRoles": []string{"bee-admins"},
+	})
+	require.NoError(t, err)
+	quotas, err = Load(thelmaConfig)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ci@broad-dsde-qa.iam.gserviceaccount.com"}, quotas.OverrideUsers)
+	assert.Equal(t, []string{"bee-admins"}, quotas.OverrideRoles)
+
+	thelmaConfig, err = config.NewTestConfig(t, map[string]interface{}{
+		"bee.quotas.maxPerTemplate": -1,
+	})
+	require.NoError(t, err)
+	_, err = Load(thelmaConfig)
+	assert.Error(t, err)
+}
+
+func Test_Check(t *testing.T) {
+	newBee := func(name string, deleteAfter time.Time) terra.Environment {
+		autoDelete := statemocks.NewAutoDelete(t)
+		autoDelete.EXPECT().Enabled().Return(!deleteAfter.IsZero()).Maybe()
+		autoDelete.EXPECT().After().Return(deleteAfter).Maybe()
+		env := statemocks.NewEnvironment(t)
+		env.EXPECT().Name().Return(name).Maybe()
+		env.EXPECT().Owner().Return("someone@broadinstitute.org").Maybe()
+		env.EXPECT().AutoDelete().Return(autoDelete).Maybe()
+		return env
+	}
+	existing := []terra.Environment{
+		newBee("b-bee", time.Date(2023, 2, 24, 15, 0, 0, 0, time.UTC)),
+		newBee("a-bee", time.Time{}),
+	}
+
+	assert.NoError(t, Quotas{}.CheckOwner("someone@broadinstitute.org", existing))
+	assert.NoError(t, Quotas{MaxPerOwner: 3}.CheckOwner("someone@broadinstitute.org", existing))
+	assert.NoError(t, Quotas{MaxPerTemplate: 3}.CheckTemplate("swatomation", existing))
+
+	err := Quotas{MaxPerOwner: 2}.CheckOwner("someone@broadinstitute.org", existing)
+	require.Error(t, err)
+	assert.Equal(t, `BEE quota exceeded: someone@broadinstitute.org already has 2 BEE(s), and the limit is 2 per owner:
+  a-bee (owner someone@broadinstitute.org, no auto-delete)
+  b-bee (owner someone@broadinstitute.org, auto-deletes at 2023-02-24T15:00:00Z)
+delete one with "thelma bee delete", or wait for one to be auto-deleted`, err.Error())
+
+	err = Quotas{MaxPerTemplate: 1}.CheckTemplate("swatomation", existing)
+	assert.ErrorContains(t, err, "there are already 2 BEE(s) from template swatomation, and the limit is 1 per template")
+}
+
+func Test_AuthorizeOverride(t *testing.T) {
+	q := Quotas{
+		OverrideUsers: []string{"ci@broad-dsde-qa.iam.gserviceaccount.com"},
+		OverrideRoles: []string{"bee-admins"},
+	}
+	assert.NoError(t, q.AuthorizeOverride(sherlock.User{Email: "CI@broad-dsde-qa.iam.gserviceaccount.com"}))
+	assert.NoError(t, q.AuthorizeOverride(sherlock.User{Email: "someone@broadinstitute.org", Roles: []string{"all-users", "bee-admins"}}))
+
+	err := q.AuthorizeOverride(sherlock.User{Email: "someone@broadinstitute.org", Roles: []string{"all-users"}})
+	assert.ErrorContains(t, err, "someone@broadinstitute.org is not allowed to ignore BEE quotas")
+	assert.Error(t, Quotas{}.AuthorizeOverride(sherlock.User{Email: "someone@broadinstitute.org"}))
+}
@@ -0,0 +1,31 @@
+package bee
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+func (b *bees) SetSchedule(name string, options terra.ScheduleOptions) (*Bee, error) {
+	if _, err := b.GetBee(name); err != nil {
+		return nil, err
+	}
+	if options.Schedule != nil {
+		if err := options.Schedule.Validate(); err != nil {
+			return nil, errors.Errorf("invalid schedule for %s: %v", name, err)
+		}
+	}
+	if err := b.state.Environments().SetSchedule(name, options); err != nil {
+		return nil, errors.Errorf("error updating schedule for %s: %v", name, err)
+	}
+	log.Info().Msgf("Updated schedule for %s", name)
+
+	if err := b.reloadState(); err != nil {
+		return nil, err
+	}
+	env, err := b.GetBee(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Bee{Environment: env}, nil
+}
@@ -35,42 +35,42 @@ type AgoraPermission struct {
 	Role string `json:"role"`
 }
 
+// ServiceAccountSecret identifies a Kubernetes secret containing a Google service account key
+type ServiceAccountSecret struct {
+	// KubernetesSecretName name of the secret; a % verb is replaced with the cluster's project suffix
+	KubernetesSecretName string
+	// KubernetesSecretKey key in the secret that holds the service account key JSON
+	KubernetesSecretKey string
+}
+
+// defaultServiceAccountSecrets secrets Thelma uses to authenticate as each app, keyed by release name.
+// Fields can be overridden (and new apps added) in config, eg. seed.auth.rawls.kubernetesSecretName
+var defaultServiceAccountSecrets = map[string]ServiceAccountSecret{
+	"rawls":         {KubernetesSecretName: "rawls-sa-secret", KubernetesSecretKey: "rawls-account.json"},
+	"sam":           {KubernetesSecretName: "sam-sa-secret", KubernetesSecretKey: "sam-account.json"},
+	"leonardo":      {KubernetesSecretName: "leonardo-sa-secret", KubernetesSecretKey: "leonardo-account.json"},
+	"firecloudorch": {KubernetesSecretName: "firecloudorch-sa-secret", KubernetesSecretKey: "firecloud-account.json"},
+	// WSM dev SA used for both Dev and QA BEEs, as of 7/13/2022
+	"workspacemanager": {KubernetesSecretName: "workspacemanager-sa-secret", KubernetesSecretKey: "service-account.json"},
+	"tsps":             {KubernetesSecretName: "tsps-sa-secret", KubernetesSecretKey: "service-account.json"},
+	"teaspoons":        {KubernetesSecretName: "teaspoons-sa-secret", KubernetesSecretKey: "service-account.json"},
+	"datarepo":         {KubernetesSecretName: "jade-sa", KubernetesSecretKey: "datareposerviceaccount"},
+}
+
+// deprecatedAuthKeys maps the seed.auth keys Thelma used before secrets were keyed by release name to the release
+// they apply to. They're still honored so existing config keeps working, but a key for the release name wins.
+var deprecatedAuthKeys = map[string]string{
+	"firecloudOrch":    "firecloudorch",
+	"workspaceManager": "workspacemanager",
+	"TSPS":             "tsps",
+}
+
 type seedConfig struct {
-	Auth struct {
-		Rawls struct {
-			KubernetesSecretName string `default:"rawls-sa-secret"`
-			KubernetesSecretKey  string `default:"rawls-account.json"`
-		}
-		Sam struct {
-			KubernetesSecretName string `default:"sam-sa-secret"`
-			KubernetesSecretKey  string `default:"sam-account.json"`
-		}
-		Leonardo struct {
-			KubernetesSecretName string `default:"leonardo-sa-secret"`
-			KubernetesSecretKey  string `default:"leonardo-account.json"`
-		}
-		FirecloudOrch struct {
-			KubernetesSecretName string `default:"firecloudorch-sa-secret"`
-			KubernetesSecretKey  string `default:"firecloud-account.json"`
-		}
-		WorkspaceManager struct {
-			// WSM dev SA used for both Dev and QA BEEs, as of 7/13/2022
-			KubernetesSecretName string `default:"workspacemanager-sa-secret"`
-			KubernetesSecretKey  string `default:"service-account.json"`
-		}
-		TSPS struct {
-			KubernetesSecretName string `default:"tsps-sa-secret"`
-			KubernetesSecretKey  string `default:"service-account.json"`
-		}
-		Teaspoons struct {
-			KubernetesSecretName string `default:"teaspoons-sa-secret"`
-			KubernetesSecretKey  string `default:"service-account.json"`
-		}
-		Datarepo struct {
-			KubernetesSecretName string `default:"jade-sa"`
-			KubernetesSecretKey  string `default:"datareposerviceaccount"`
-		}
-	}
+	// Auth service account secrets, keyed by release name; merged over defaultServiceAccountSecrets
+	Auth map[string]ServiceAccountSecret
+	// Steps additional seed steps, keyed by step name
+	Steps map[string]HTTPStepConfig
+
 	TestUsers struct {
 		Dev []TestUser
 		QA  []TestUser
@@ -101,40 +101,41 @@ type seedConfig struct {
 	}
 }
 
+// serviceAccountSecret returns the secret to use to authenticate as the given app, if there is one
+func (c seedConfig) serviceAccountSecret(releaseName string) (ServiceAccountSecret, bool) {
+	secret, exists := defaultServiceAccountSecrets[releaseName]
+	if override, overridden := c.Auth[releaseName]; overridden {
+		exists = true
+		if override.KubernetesSecretName != "" {
+			secret.KubernetesSecretName = override.KubernetesSecretName
+		}
+		if override.KubernetesSecretKey != "" {
+			secret.KubernetesSecretKey = override.KubernetesSecretKey
+		}
+	}
+	if !exists || secret.KubernetesSecretName == "" || secret.KubernetesSecretKey == "" {
+		return ServiceAccountSecret{}, false
+	}
+	return secret, true
+}
+
 func (s *seeder) googleAuthAs(appRelease terra.AppRelease, options ...google.Option) (google.Clients, error) {
 	config, err := s.configWithBasicDefaults()
 	if err != nil {
 		return nil, err
 	}
-	var secretName, secretKey string
-	switch appRelease.Name() {
-	case "rawls":
-		secretName = config.Auth.Rawls.KubernetesSecretName
-		secretKey = config.Auth.Rawls.KubernetesSecretKey
-	case "sam":
-		secretName = config.Auth.Sam.KubernetesSecretName
-		secretKey = config.Auth.Sam.KubernetesSecretKey
-	case "leonardo":
-		secretName = config.Auth.Leonardo.KubernetesSecretName
-		secretKey = config.Auth.Leonardo.KubernetesSecretKey
-	case "firecloudorch":
-		secretName = config.Auth.FirecloudOrch.KubernetesSecretName
-		secretKey = config.Auth.FirecloudOrch.KubernetesSecretKey
-	case "workspacemanager":
-		secretName = config.Auth.WorkspaceManager.KubernetesSecretName
-		secretKey = config.Auth.WorkspaceManager.KubernetesSecretKey
-        case "teaspoons":
-		secretName = config.Auth.Teaspoons.KubernetesSecretName
-		secretKey = config.Auth.Teaspoons.KubernetesSecretKey		
-	case "tsps":
-		secretName = config.Auth.TSPS.KubernetesSecretName
-		secretKey = config.Auth.TSPS.KubernetesSecretKey
-	case "datarepo":
-		secretName = config.Auth.Datarepo.KubernetesSecretName
-		secretKey = config.Auth.Datarepo.KubernetesSecretKey
-	default:
+	secret, exists := config.serviceAccountSecret(appRelease.Name())
+	if !exists {
 		return nil, errors.Errorf("thelma doesn't know how to authenticate as %s", appRelease.Name())
 	}
+	return s.googleAuthWithSecret(appRelease, secret, options...)
+}
+
+// googleAuthWithSecret returns Google clients authenticated with the service account key in the given secret,
+// which is read from the release's namespace
+func (s *seeder) googleAuthWithSecret(appRelease terra.AppRelease, saSecret ServiceAccountSecret, options ...google.Option) (google.Clients, error) {
+	secretName := saSecret.KubernetesSecretName
+	secretKey := saSecret.KubernetesSecretKey
 	if strings.ContainsRune(secretName, '%') {
 		secretName = fmt.Sprintf(secretName, appRelease.Cluster().ProjectSuffix())
 	}
@@ -165,9 +166,36 @@ func (s *seeder) configWithBasicDefaults() (seedConfig, error) {
 	if err != nil {
 		return config, errors.Errorf("error reading seed config: %v", err)
 	}
+	config.Auth = withoutDeprecatedAuthKeys(config.Auth)
 	return config, nil
 }
 
+// withoutDeprecatedAuthKeys moves secrets configured under a deprecated key to the release name they apply to
+func withoutDeprecatedAuthKeys(auth map[string]ServiceAccountSecret) map[string]ServiceAccountSecret {
+	result := make(map[string]ServiceAccountSecret, len(auth))
+	for key, secret := range auth {
+		if _, deprecated := deprecatedAuthKeys[key]; !deprecated {
+			result[key] = secret
+		}
+	}
+	for key, releaseName := range deprecatedAuthKeys {
+		secret, exists := auth[key]
+		if !exists {
+			continue
+		}
+		log.Warn().Msgf("config key %s.auth.%s is deprecated, use %s.auth.%s instead", configKey, key, configKey, releaseName)
+		current := result[releaseName]
+		if current.KubernetesSecretName == "" {
+			current.KubernetesSecretName = secret.KubernetesSecretName
+		}
+		if current.KubernetesSecretKey == "" {
+			current.KubernetesSecretKey = secret.KubernetesSecretKey
+		}
+		result[releaseName] = current
+	}
+	return result
+}
+
 func (s *seeder) configWithTestUsers() (seedConfig, error) {
 	config, err := s.configWithBasicDefaults()
 	if err != nil {
@@ -0,0 +1,225 @@
+package seed
+
+import (
+	"net/http"
+
+	"github.com/broadinstitute/thelma/internal/thelma/clients/google/terraapi"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultHTTPStepOrder steps declared in config run after all built-in steps unless they set an order
+const defaultHTTPStepOrder = 1000
+
+// HTTPStepConfig declares a seed step that makes an HTTP request against an app in the BEE. Example:
+//
+//	seed:
+//	  steps:
+//	    create-my-resource-type:
+//	      order: 350
+//	      retries: 3
+//	      release: sam
+//	      as: myservice
+//	      auth:
+//	        kubernetesSecretName: myservice-sa-secret
+//	        kubernetesSecretKey: service-account.json
+//	      check:
+//	        method: GET
+//	        path: /api/resources/v2/my-resource-type/default
+//	      request:
+//	        method: POST
+//	        path: /api/resources/v2/my-resource-type/default
+//	        body: '{"policies": {}}'
+//	        expectStatus: [201, 204]
+type HTTPStepConfig struct {
+	// Order steps are run in ascending order; built-in steps are ordered 100 through 600. Defaults to 1000.
+	Order int
+	// Retries how many times to retry the request if it fails
+	Retries int
+	// Release name of the release to send the request to; the step is skipped if the BEE doesn't have it
+	Release string
+	// As name of the app to authenticate as, using its service account secret. Defaults to Release.
+	As string
+	// Auth optional service account secret to authenticate with, in place of the secret for As
+	Auth *ServiceAccountSecret
+	// Request the request that performs the step
+	Request HTTPRequest
+	// Check optional idempotency check; if it returns an expected status, the step is already done and is skipped
+	Check *HTTPRequest
+}
+
+// HTTPRequest is a request to make against an app
+type HTTPRequest struct {
+	// Method HTTP method; defaults to POST for requests and GET for checks
+	Method string
+	// Path path relative to the app's URL, eg. "/api/resources/v2/my-resource-type"
+	Path string
+	// Body optional JSON request body
+	Body string
+	// ExpectStatus status codes that indicate success; defaults to any 2xx status
+	ExpectStatus []int
+}
+
+type httpStep struct {
+	name   string
+	config HTTPStepConfig
+	// secret service account secret to authenticate with
+	secret ServiceAccountSecret
+	// newClient returns a client for the given release, authenticated with the given secret
+	newClient func(appRelease terra.AppRelease, secret ServiceAccountSecret) (terraapi.AppClient, error)
+}
+
+func (s *seeder) newHTTPStep(name string, stepConfig HTTPStepConfig, config seedConfig) (*httpStep, error) {
+	if stepConfig.Release == "" {
+		return nil, errors.Errorf("seed step %q is missing required field: release", name)
+	}
+	if stepConfig.Request.Path == "" {
+		return nil, errors.Errorf("seed step %q is missing required field: request.path", name)
+	}
+	if stepConfig.Check != nil && stepConfig.Check.Path == "" {
+		return nil, errors.Errorf("seed step %q is missing required field: check.path", name)
+	}
+	if stepConfig.Retries < 0 {
+		return nil, errors.Errorf("invalid retries %d for seed step %q: must not be negative", stepConfig.Retries, name)
+	}
+	if stepConfig.Order == 0 {
+		stepConfig.Order = defaultHTTPStepOrder
+	}
+	if stepConfig.Request.Method == "" {
+		stepConfig.Request.Method = http.MethodPost
+	}
+	if stepConfig.Check != nil && stepConfig.Check.Method == "" {
+		stepConfig.Check.Method = http.MethodGet
+	}
+
+	var secret ServiceAccountSecret
+	if stepConfig.Auth != nil {
+		secret = *stepConfig.Auth
+		if secret.KubernetesSecretName == "" || secret.KubernetesSecretKey == "" {
+			return nil, errors.Errorf("seed step %q must set both auth.kubernetesSecretName and auth.kubernetesSecretKey", name)
+		}
+	} else {
+		as := stepConfig.As
+		if as == "" {
+			as = stepConfig.Release
+		}
+		var exists bool
+		secret, exists = config.serviceAccountSecret(as)
+		if !exists {
+			return nil, errors.Errorf("seed step %q: thelma doesn't know how to authenticate as %s; set seed.auth.%s or the step's auth field", name, as, as)
+		}
+	}
+
+	return &httpStep{
+		name:      name,
+		config:    stepConfig,
+		secret:    secret,
+		newClient: s.appClientWithSecret,
+	}, nil
+}
+
+func (h *httpStep) Name() string {
+	return h.name
+}
+
+func (h *httpStep) Order() int {
+	return h.config.Order
+}
+
+func (h *httpStep) Retries() int {
+	return h.config.Retries
+}
+
+// Enabled steps declared in config run by default, unless --no-steps was supplied
+func (h *httpStep) Enabled(opts SeedOptions) bool {
+	return !opts.NoSteps
+}
+
+func (h *httpStep) Done(appReleases map[string]terra.AppRelease) (bool, error) {
+	if h.config.Check == nil {
+		return false, nil
+	}
+	client, present, err := h.client(appReleases)
+	if err != nil || !present {
+		return false, err
+	}
+	ok, err := h.config.Check.do(client)
+	if ok {
+		return true, nil
+	}
+	if err != nil {
+		log.Debug().Err(err).Msgf("idempotency check for seed step %s did not pass: %v", h.name, err)
+	}
+	return false, nil
+}
+
+func (h *httpStep) Run(appReleases map[string]terra.AppRelease, _ SeedOptions) error {
+	log.Info().Msgf("running seed step %s...", h.name)
+	client, present, err := h.client(appReleases)
+	if err != nil {
+		return err
+	}
+	if !present {
+		log.Info().Msgf("%s not present in environment, skipping", h.config.Release)
+		return nil
+	}
+	if _, err = h.config.Request.do(client); err != nil {
+		return errors.Errorf("seed step %s failed: %v", h.name, err)
+	}
+	log.Info().Msg("...done")
+	return nil
+}
+
+// client returns a client for the step's release, or false if the release isn't in the BEE
+func (h *httpStep) client(appReleases map[string]terra.AppRelease) (terraapi.AppClient, bool, error) {
+	appRelease, present := appReleases[h.config.Release]
+	if !present {
+		return nil, false, nil
+	}
+	client, err := h.newClient(appRelease, h.secret)
+	if err != nil {
+		return nil, true, err
+	}
+	return client, true, nil
+}
+
+// do makes the request, returning true if the response had an expected status. A non-nil error is returned for
+// unexpected statuses and for requests that couldn't be made at all.
+func (r HTTPRequest) do(client terraapi.AppClient) (bool, error) {
+	resp, body, err := client.Request(r.Method, r.Path, r.Body)
+	if resp == nil {
+		if err == nil {
+			err = errors.Errorf("no response")
+		}
+		return false, errors.Errorf("%s %s: %v", r.Method, r.Path, err)
+	}
+	if r.expects(resp.StatusCode) {
+		return true, nil
+	}
+	return false, errors.Errorf("%s %s returned unexpected status %s (%s)", r.Method, r.Path, resp.Status, body)
+}
+
+func (r HTTPRequest) expects(statusCode int) bool {
+	if len(r.ExpectStatus) == 0 {
+		return statusCode >= 200 && statusCode <= 299
+	}
+	for _, expected := range r.ExpectStatus {
+		if statusCode == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *seeder) appClientWithSecret(appRelease terra.AppRelease, secret ServiceAccountSecret) (terraapi.AppClient, error) {
+	googleClient, err := s.googleAuthWithSecret(appRelease, secret)
+	if err != nil {
+		return nil, err
+	}
+	terraClient, err := googleClient.Terra()
+	if err != nil {
+		return nil, err
+	}
+	return terraClient.App(appRelease), nil
+}
@@ -0,0 +1,162 @@
+package seed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/config"
+	"github.com/broadinstitute/thelma/internal/thelma/clients/google/terraapi"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/mocks"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	googleoauth "google.golang.org/api/oauth2/v2"
+)
+
+func Test_HTTPStep(t *testing.T) {
+	stepRetryDelay = 0
+
+	var created bool
+	var postCount int
+	// fake app that fails the first POST, then creates the resource
+	fakeApp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/resource":
+			if created {
+				w.WriteHeader(http.StatusOK)
+			} else {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/api/resource":
+			postCount++
+			if postCount == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			created = true
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer fakeApp.Close()
+
+	sam := mocks.NewAppRelease(t)
+	sam.EXPECT().URL().Return(fakeApp.URL).Maybe()
+	appReleases := map[string]terra.AppRelease{"sam": sam}
+
+	thelmaConfig, err := config.NewTestConfig(t, map[string]interface{}{
+		"seed.auth.myservice.kubernetesSecretName": "myservice-sa-secret",
+		"seed.auth.myservice.kubernetesSecretKey":  "sa.json",
+	})
+	require.NoError(t, err)
+	s := &seeder{config: thelmaConfig}
+	cfg, err := s.configWithBasicDefaults()
+	require.NoError(t, err)
+
+	step, err := s.newHTTPStep("create-resource", HTTPStepConfig{
+		Retries: 1,
+		Release: "sam",
+		As:      "myservice",
+		Request: HTTPRequest{Path: "/api/resource", ExpectStatus: []int{http.StatusCreated}},
+		Check:   &HTTPRequest{Path: "/api/resource"},
+	}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, defaultHTTPStepOrder, step.Order())
+	assert.Equal(t, ServiceAccountSecret{KubernetesSecretName: "myservice-sa-secret", KubernetesSecretKey: "sa.json"}, step.secret)
+
+	step.newClient = func(appRelease terra.AppRelease, secret ServiceAccountSecret) (terraapi.AppClient, error) {
+		assert.Equal(t, "myservice-sa-secret", secret.KubernetesSecretName)
+		return terraapi.NewClient(testutils.NewFakeTokenSource("fake-token"), &googleoauth.Userinfo{}).App(appRelease), nil
+	}
+
+	done, err := step.Done(appReleases)
+	require.NoError(t, err)
+	assert.False(t, done)
+
+	require.NoError(t, runStep(step, appReleases, SeedOptions{}))
+	assert.Equal(t, 2, postCount, "should retry the failed request once")
+
+	require.NoError(t, runStep(step, appReleases, SeedOptions{}))
+	assert.Equal(t, 2, postCount, "should skip the step once the check passes")
+
+	require.NoError(t, runStep(step, map[string]terra.AppRelease{}, SeedOptions{}), "should skip steps for missing releases")
+}
+
+func Test_HTTPStepFailure(t *testing.T) {
+	stepRetryDelay = 0
+
+	var postCount int
+	fakeApp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		postCount++
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte("already exists"))
+	}))
+	defer fakeApp.Close()
+
+	sam := mocks.NewAppRelease(t)
+	sam.EXPECT().URL().Return(fakeApp.URL)
+
+	step := &httpStep{
+		name:   "create-resource",
+		config: HTTPStepConfig{Retries: 2, Release: "sam", Request: HTTPRequest{Method: http.MethodPost, Path: "/api/resource"}},
+		newClient: func(appRelease terra.AppRelease, _ ServiceAccountSecret) (terraapi.AppClient, error) {
+			return terraapi.NewClient(testutils.NewFakeTokenSource("fake-token"), &googleoauth.Userinfo{}).App(appRelease), nil
+		},
+	}
+
+	err := runStep(step, map[string]terra.AppRelease{"sam": sam}, SeedOptions{})
+	assert.ErrorContains(t, err, "409 Conflict")
+	assert.Equal(t, 3, postCount)
+
+	step.config.Request.ExpectStatus = []int{http.StatusCreated, http.StatusConflict}
+	assert.NoError(t, runStep(step, map[string]terra.AppRelease{"sam": sam}, SeedOptions{}), "409 should be accepted when expected")
+}
+
+func Test_serviceAccountSecret(t *testing.T) {
+	thelmaConfig, err := config.NewTestConfig(t, map[string]interface{}{
+		"seed.auth.rawls.kubernetesSecretName": "rawls-override",
+	})
+	require.NoError(t, err)
+	s := &seeder{config: thelmaConfig}
+	cfg, err := s.configWithBasicDefaults()
+	require.NoError(t, err)
+
+	secret, exists := cfg.serviceAccountSecret("rawls")
+	require.True(t, exists)
+	assert.Equal(t, ServiceAccountSecret{KubernetesSecretName: "rawls-override", KubernetesSecretKey: "rawls-account.json"}, secret)
+
+	secret, exists = cfg.serviceAccountSecret("datarepo")
+	require.True(t, exists)
+	assert.Equal(t, "jade-sa", secret.KubernetesSecretName)
+
+	_, exists = cfg.serviceAccountSecret("mystery-service")
+	assert.False(t, exists)
+}
+
+func Test_serviceAccountSecretDeprecatedKeys(t *testing.T) {
+	thelmaConfig, err := config.NewTestConfig(t, map[string]interface{}{
+		"seed.auth.firecloudOrch.kubernetesSecretName":   "orch-override",
+		"seed.auth.workspaceManager.kubernetesSecretKey": "wsm-key.json",
+		"seed.auth.TSPS.kubernetesSecretName":            "tsps-deprecated",
+		"seed.auth.tsps.kubernetesSecretName":            "tsps-current",
+	})
+	require.NoError(t, err)
+	s := &seeder{config: thelmaConfig}
+	cfg, err := s.configWithBasicDefaults()
+	require.NoError(t, err)
+
+	secret, exists := cfg.serviceAccountSecret("firecloudorch")
+	require.True(t, exists)
+	assert.Equal(t, ServiceAccountSecret{KubernetesSecretName: "orch-override", KubernetesSecretKey: "firecloud-account.json"}, secret)
+
+	secret, exists = cfg.serviceAccountSecret("workspacemanager")
+	require.True(t, exists)
+	assert.Equal(t, ServiceAccountSecret{KubernetesSecretName: "workspacemanager-sa-secret", KubernetesSecretKey: "wsm-key.json"}, secret)
+
+	secret, exists = cfg.serviceAccountSecret("tsps")
+	require.True(t, exists)
+	assert.Equal(t, "tsps-current", secret.KubernetesSecretName)
+}
@@ -64,6 +64,60 @@ func (_c *Seeder_Seed_Call) RunAndReturn(run func(terra.Environment, seed.SeedOp
 	return _c
 }
 
+// Steps provides a mock function with given fields: seedOptions
+func (_m *Seeder) Steps(seedOptions seed.SeedOptions) ([]seed.Step, error) {
+	ret := _m.Called(seedOptions)
+
+	var r0 []seed.Step
+	var r1 error
+	if rf, ok := ret.Get(0).(func(seed.SeedOptions) ([]seed.Step, error)); ok {
+		return rf(seedOptions)
+	}
+	if rf, ok := ret.Get(0).(func(seed.SeedOptions) []seed.Step); ok {
+		r0 = rf(seedOptions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]seed.Step)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(seed.SeedOptions) error); ok {
+		r1 = rf(seedOptions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Seeder_Steps_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Steps'
+type Seeder_Steps_Call struct {
+	*mock.Call
+}
+
+// Steps is a helper method to define mock.On call
+//   - seedOptions seed.SeedOptions
+func (_e *Seeder_Expecter) Steps(seedOptions interface{}) *Seeder_Steps_Call {
+	return &Seeder_Steps_Call{Call: _e.mock.On("Steps", seedOptions)}
+}
+
+func (_c *Seeder_Steps_Call) Run(run func(seedOptions seed.SeedOptions)) *Seeder_Steps_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(seed.SeedOptions))
+	})
+	return _c
+}
+
+func (_c *Seeder_Steps_Call) Return(_a0 []seed.Step, _a1 error) *Seeder_Steps_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Seeder_Steps_Call) RunAndReturn(run func(seed.SeedOptions) ([]seed.Step, error)) *Seeder_Steps_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Unseed provides a mock function with given fields: env, unseedOptions
 func (_m *Seeder) Unseed(env terra.Environment, unseedOptions seed.UnseedOptions) error {
 	ret := _m.Called(env, unseedOptions)
@@ -0,0 +1,72 @@
+package seed
+
+import (
+	"sort"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/pkg/errors"
+)
+
+// StepDefinition is a seed step that can be added to a Registry
+type StepDefinition interface {
+	// Name of the step, eg. "create-agora"
+	Name() string
+	// Order steps are run in ascending order; steps with the same order are run in the order they were registered
+	Order() int
+	// Retries how many times to retry the step if it fails
+	Retries() int
+	// Enabled returns true if the step should be run with the given options
+	Enabled(opts SeedOptions) bool
+	// Done returns true if the step has already been completed for the given releases and can be skipped
+	Done(appReleases map[string]terra.AppRelease) (bool, error)
+	// Run the step against the given releases
+	Run(appReleases map[string]terra.AppRelease, opts SeedOptions) error
+}
+
+// Registry is an ordered collection of seed steps
+type Registry struct {
+	steps []StepDefinition
+}
+
+// NewRegistry returns a new, empty Registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a step to the registry. Returns an error if a step with the same name is already registered.
+func (r *Registry) Register(step StepDefinition) error {
+	if _, exists := r.Get(step.Name()); exists {
+		return errors.Errorf("a seed step named %q is already registered", step.Name())
+	}
+	r.steps = append(r.steps, step)
+	return nil
+}
+
+// Get returns the step with the given name, if there is one
+func (r *Registry) Get(name string) (StepDefinition, bool) {
+	for _, step := range r.steps {
+		if step.Name() == name {
+			return step, true
+		}
+	}
+	return nil, false
+}
+
+// Steps returns all registered steps, in the order they should be run
+func (r *Registry) Steps() []StepDefinition {
+	steps := make([]StepDefinition, len(r.steps))
+	copy(steps, r.steps)
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].Order() < steps[j].Order()
+	})
+	return steps
+}
+
+// Names returns the names of all registered steps, in the order they should be run
+func (r *Registry) Names() []string {
+	var names []string
+	for _, step := range r.Steps() {
+		names = append(names, step.Name())
+	}
+	return names
+}
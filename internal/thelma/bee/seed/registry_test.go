@@ -0,0 +1,137 @@
+package seed
+
+import (
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/config"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStep struct {
+	name  string
+	order int
+}
+
+func (f fakeStep) Name() string                                           { return f.name }
+func (f fakeStep) Order() int                                             { return f.order }
+func (f fakeStep) Retries() int                                           { return 0 }
+func (f fakeStep) Enabled(_ SeedOptions) bool                             { return true }
+func (f fakeStep) Done(_ map[string]terra.AppRelease) (bool, error)       { return false, nil }
+func (f fakeStep) Run(_ map[string]terra.AppRelease, _ SeedOptions) error { return nil }
+
+func Test_Registry(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(fakeStep{name: "c", order: 300}))
+	require.NoError(t, registry.Register(fakeStep{name: "a", order: 100}))
+	require.NoError(t, registry.Register(fakeStep{name: "b", order: 300}))
+
+	assert.ErrorContains(t, registry.Register(fakeStep{name: "a", order: 200}), `"a" is already registered`)
+	assert.Equal(t, []string{"a", "c", "b"}, registry.Names(), "should sort by order, then registration order")
+
+	step, exists := registry.Get("b")
+	require.True(t, exists)
+	assert.Equal(t, 300, step.Order())
+	_, exists = registry.Get("d")
+	assert.False(t, exists)
+}
+
+func Test_SeederSteps(t *testing.T) {
+	thelmaConfig, err := config.NewTestConfig(t, map[string]interface{}{
+		"seed.steps.create-my-resource.order":        350,
+		"seed.steps.create-my-resource.release":      "sam",
+		"seed.steps.create-my-resource.request.path": "/api/resources/v2/my-resource",
+		"seed.steps.ping-leo.release":                "leonardo",
+		"seed.steps.ping-leo.request.path":           "/status",
+	})
+	require.NoError(t, err)
+	s := &seeder{config: thelmaConfig}
+
+	all := SeedOptions{
+		Step1CreateElasticsearch: true,
+		Step2RegisterSaProfiles:  true,
+		Step3AddSaSamPermissions: true,
+		Step4RegisterTestUsers:   true,
+		Step5CreateAgora:         true,
+	}
+
+	testCases := []struct {
+		name     string
+		opts     func(opts *SeedOptions)
+		expected []string
+	}{
+		{
+			name: "all steps",
+			expected: []string{
+				StepCreateElasticsearch,
+				StepRegisterSaProfiles,
+				StepAddSaSamPermissions,
+				"create-my-resource",
+				StepRegisterTestUsers,
+				StepCreateAgora,
+				"ping-leo",
+			},
+		},
+		{
+			name: "extra users and skipped steps",
+			opts: func(opts *SeedOptions) {
+				opts.Step6ExtraUser = []string{"use-adc"}
+				opts.SkipSteps = []string{StepCreateAgora, "ping-leo"}
+			},
+			expected: []string{
+				StepCreateElasticsearch,
+				StepRegisterSaProfiles,
+				StepAddSaSamPermissions,
+				"create-my-resource",
+				StepRegisterTestUsers,
+				StepExtraUser,
+			},
+		},
+		{
+			name: "no steps disables config steps",
+			opts: func(opts *SeedOptions) {
+				*opts = SeedOptions{Step5CreateAgora: true}
+				opts.NoSteps = true
+			},
+			expected: []string{StepCreateAgora},
+		},
+		{
+			name: "only step",
+			opts: func(opts *SeedOptions) {
+				opts.OnlyStep = "create-my-resource"
+			},
+			expected: []string{"create-my-resource"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := all
+			if tc.opts != nil {
+				tc.opts(&opts)
+			}
+			steps, err := s.Steps(opts)
+			require.NoError(t, err)
+
+			var names []string
+			for _, step := range steps {
+				names = append(names, step.Name)
+				assert.Equal(t, step.Name, step.Options.OnlyStep)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func Test_SeederStepsInvalidConfig(t *testing.T) {
+	thelmaConfig, err := config.NewTestConfig(t, map[string]interface{}{
+		"seed.steps.mystery.release":      "mystery-service",
+		"seed.steps.mystery.request.path": "/api/thing",
+	})
+	require.NoError(t, err)
+	s := &seeder{config: thelmaConfig}
+
+	_, err = s.Steps(SeedOptions{})
+	assert.ErrorContains(t, err, "doesn't know how to authenticate as mystery-service")
+}
@@ -1,6 +1,10 @@
 package seed
 
 import (
+	"sort"
+	"time"
+
+	"github.com/avast/retry-go"
 	"github.com/broadinstitute/thelma/internal/thelma/app/config"
 	"github.com/broadinstitute/thelma/internal/thelma/clients"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
@@ -9,6 +13,9 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// stepRetryDelay initial delay between retries of a failed seed step
+var stepRetryDelay = 2 * time.Second
+
 type commonOptions struct {
 	Force                   bool
 	NoSteps                 bool
@@ -23,9 +30,26 @@ type SeedOptions struct {
 	Step5CreateAgora         bool
 	Step6ExtraUser           []string
 	RegisterSelfShortcut     bool
+	// SkipSteps names of steps to skip, including steps declared in config
+	SkipSteps []string
+	// OnlyStep if set, run only the step with this name
+	OnlyStep string
 	commonOptions
 }
 
+// selects returns true if these options don't exclude the named step
+func (o SeedOptions) selects(stepName string) bool {
+	if o.OnlyStep != "" && o.OnlyStep != stepName {
+		return false
+	}
+	for _, skip := range o.SkipSteps {
+		if skip == stepName {
+			return false
+		}
+	}
+	return true
+}
+
 type UnseedOptions struct {
 	Step1UnregisterAllUsers bool
 	commonOptions
@@ -33,6 +57,9 @@ type UnseedOptions struct {
 
 type Seeder interface {
 	Seed(env terra.Environment, seedOptions SeedOptions) error
+	// Steps returns the steps that Seed would run with the given options, in order, each with options that run
+	// only that step
+	Steps(seedOptions SeedOptions) ([]Step, error)
 	Unseed(env terra.Environment, unseedOptions UnseedOptions) error
 }
 
@@ -55,38 +82,93 @@ func New(kubectl kubectl.Kubectl, clientFactory clients.Clients, thelmaConfig co
 func (s *seeder) Seed(bee terra.Environment, seedOptions SeedOptions) error {
 	appReleases := getAppReleases(bee)
 
-	if seedOptions.Step1CreateElasticsearch {
-		if err := seedOptions.handleErrorWithForce(s.seedStep1CreateElasticsearch(appReleases, seedOptions)); err != nil {
-			return err
-		}
+	registry, err := s.registry()
+	if err != nil {
+		return err
 	}
-	if seedOptions.Step2RegisterSaProfiles {
-		if err := seedOptions.handleErrorWithForce(s.seedStep2RegisterSaProfiles(appReleases, seedOptions)); err != nil {
-			return err
+	for _, step := range registry.Steps() {
+		if !seedOptions.selects(step.Name()) || !step.Enabled(seedOptions) {
+			continue
 		}
-	}
-	if seedOptions.Step3AddSaSamPermissions {
-		if err := seedOptions.handleErrorWithForce(s.seedStep3AddSaSamPermissions(appReleases, seedOptions)); err != nil {
+		if err = seedOptions.handleErrorWithForce(runStep(step, appReleases, seedOptions)); err != nil {
 			return err
 		}
 	}
-	if seedOptions.Step4RegisterTestUsers {
-		if err := seedOptions.handleErrorWithForce(s.seedStep4RegisterTestUsers(appReleases, seedOptions)); err != nil {
-			return err
+
+	return nil
+}
+
+func (s *seeder) Steps(seedOptions SeedOptions) ([]Step, error) {
+	registry, err := s.registry()
+	if err != nil {
+		return nil, err
+	}
+	var steps []Step
+	for _, step := range registry.Steps() {
+		if !seedOptions.selects(step.Name()) || !step.Enabled(seedOptions) {
+			continue
 		}
+		opts := seedOptions
+		opts.OnlyStep = step.Name()
+		steps = append(steps, Step{Name: step.Name(), Options: opts})
 	}
-	if seedOptions.Step5CreateAgora {
-		if err := seedOptions.handleErrorWithForce(s.seedStep5CreateAgora(appReleases, seedOptions)); err != nil {
-			return err
+	return steps, nil
+}
+
+// registry returns a registry of the built-in steps plus any steps declared in config
+func (s *seeder) registry() (*Registry, error) {
+	config, err := s.configWithBasicDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	registry := NewRegistry()
+	for _, step := range s.builtinSteps() {
+		if err = registry.Register(step); err != nil {
+			return nil, err
 		}
 	}
-	if len(seedOptions.Step6ExtraUser) > 0 {
-		if err := seedOptions.handleErrorWithForce(s.seedStep6ExtraUser(appReleases, seedOptions)); err != nil {
-			return err
+
+	// register in a stable order, so steps with the same order always run in the same sequence
+	var names []string
+	for name := range config.Steps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		step, err := s.newHTTPStep(name, config.Steps[name], config)
+		if err != nil {
+			return nil, err
 		}
+		if err = registry.Register(step); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// runStep runs a step unless its idempotency check says it is already done, retrying as configured
+func runStep(step StepDefinition, appReleases map[string]terra.AppRelease, seedOptions SeedOptions) error {
+	done, err := step.Done(appReleases)
+	if err != nil {
+		log.Warn().Err(err).Msgf("error checking whether seed step %s is done, will run it: %v", step.Name(), err)
+	} else if done {
+		log.Info().Msgf("seed step %s was already completed, skipping", step.Name())
+		return nil
 	}
 
-	return nil
+	return retry.Do(
+		func() error {
+			return step.Run(appReleases, seedOptions)
+		},
+		retry.Attempts(uint(step.Retries()+1)),
+		retry.Delay(stepRetryDelay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.OnRetry(func(n uint, err error) {
+			log.Warn().Msgf("seed step %s failed (attempt %d of %d): %v", step.Name(), n+1, step.Retries()+1, err)
+		}),
+	)
 }
 
 func (s *seeder) Unseed(bee terra.Environment, unseedOptions UnseedOptions) error {
@@ -0,0 +1,99 @@
+package seed
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+)
+
+// names of built-in seed steps
+const (
+	StepCreateElasticsearch = "create-elasticsearch"
+	StepRegisterSaProfiles  = "register-sa-profiles"
+	StepAddSaSamPermissions = "add-sa-sam-permissions"
+	StepRegisterTestUsers   = "register-test-users"
+	StepCreateAgora         = "create-agora"
+	StepExtraUser           = "extra-user"
+)
+
+// Step is a single enabled seed step
+type Step struct {
+	// Name of the step, eg. "create-agora"
+	Name string
+	// Options seed options that run only this step
+	Options SeedOptions
+}
+
+// builtinStep is a seed step implemented in Thelma. Built-in steps are ordered in increments of 100, so that steps
+// declared in config can be run between them.
+type builtinStep struct {
+	name    string
+	order   int
+	enabled func(opts SeedOptions) bool
+	run     func(appReleases map[string]terra.AppRelease, opts SeedOptions) error
+}
+
+func (b builtinStep) Name() string {
+	return b.name
+}
+
+func (b builtinStep) Order() int {
+	return b.order
+}
+
+// Retries is 0 for built-in steps, because they handle retries (and conflicts) internally
+func (b builtinStep) Retries() int {
+	return 0
+}
+
+func (b builtinStep) Enabled(opts SeedOptions) bool {
+	return b.enabled(opts)
+}
+
+// Done is always false for built-in steps; they are written to be safely re-run
+func (b builtinStep) Done(_ map[string]terra.AppRelease) (bool, error) {
+	return false, nil
+}
+
+func (b builtinStep) Run(appReleases map[string]terra.AppRelease, opts SeedOptions) error {
+	return b.run(appReleases, opts)
+}
+
+func (s *seeder) builtinSteps() []StepDefinition {
+	return []StepDefinition{
+		builtinStep{
+			name:    StepCreateElasticsearch,
+			order:   100,
+			enabled: func(opts SeedOptions) bool { return opts.Step1CreateElasticsearch },
+			run:     s.seedStep1CreateElasticsearch,
+		},
+		builtinStep{
+			name:    StepRegisterSaProfiles,
+			order:   200,
+			enabled: func(opts SeedOptions) bool { return opts.Step2RegisterSaProfiles },
+			run:     s.seedStep2RegisterSaProfiles,
+		},
+		builtinStep{
+			name:    StepAddSaSamPermissions,
+			order:   300,
+			enabled: func(opts SeedOptions) bool { return opts.Step3AddSaSamPermissions },
+			run:     s.seedStep3AddSaSamPermissions,
+		},
+		builtinStep{
+			name:    StepRegisterTestUsers,
+			order:   400,
+			enabled: func(opts SeedOptions) bool { return opts.Step4RegisterTestUsers },
+			run:     s.seedStep4RegisterTestUsers,
+		},
+		builtinStep{
+			name:    StepCreateAgora,
+			order:   500,
+			enabled: func(opts SeedOptions) bool { return opts.Step5CreateAgora },
+			run:     s.seedStep5CreateAgora,
+		},
+		builtinStep{
+			name:    StepExtraUser,
+			order:   600,
+			enabled: func(opts SeedOptions) bool { return len(opts.Step6ExtraUser) > 0 },
+			run:     s.seedStep6ExtraUser,
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package bee
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/bee/snapshots"
+	"github.com/broadinstitute/thelma/internal/thelma/toolbox/argocd"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// snapshotNameFormat is used to name snapshots when no name is given, eg. "20240102-150405"
+const snapshotNameFormat = "20060102-150405"
+
+type SnapshotOptions struct {
+	// Name of the snapshot; defaults to a timestamp
+	Name string
+	// SnapshotClass VolumeSnapshotClass to use for the snapshot
+	SnapshotClass string
+	// Quiesce scale the BEE's workloads down while snapshots are taken, so that the snapshots are consistent,
+	// and sync them back up afterwards
+	Quiesce bool
+}
+
+func (b *bees) CreateSnapshot(name string, options SnapshotOptions) ([]snapshots.Snapshot, error) {
+	env, err := b.GetBee(name)
+	if err != nil {
+		return nil, err
+	}
+	snapshotName := options.Name
+	if snapshotName == "" {
+		snapshotName = time.Now().UTC().Format(snapshotNameFormat)
+	}
+	if err = snapshots.ValidateName(snapshotName); err != nil {
+		return nil, err
+	}
+
+	if options.Quiesce {
+		if err = b.kubectl.ShutDown(env); err != nil {
+			return nil, err
+		}
+	}
+
+	created, snapshotErr := b.snapshots.Create(env, snapshotName, options.SnapshotClass)
+
+	if options.Quiesce {
+		// bring services back up even if the snapshot failed
+		log.Info().Msgf("Syncing ArgoCD to bring services in %s back up", env.Name())
+		if _, err = b.SyncArgoAppsIn(env); err != nil && snapshotErr == nil {
+			return created, err
+		}
+	}
+	return created, snapshotErr
+}
+
+func (b *bees) ListSnapshots(name string) ([]snapshots.Snapshot, error) {
+	env, err := b.GetBee(name)
+	if err != nil {
+		return nil, err
+	}
+	return b.snapshots.List(env)
+}
+
+// RestoreSnapshot rolls the BEE's persistent volumes back to the given snapshot. Like ResetStatefulSets, this
+// replaces the BEE's PVCs, but with restored data instead of empty disks.
+func (b *bees) RestoreSnapshot(name string, snapshotName string) (*Bee, error) {
+	env, err := b.GetBee(name)
+	if err != nil {
+		return nil, err
+	}
+	bee := &Bee{Environment: env}
+
+	// check the snapshot is restorable before taking anything down
+	existing, err := b.snapshots.List(env)
+	if err != nil {
+		return bee, err
+	}
+	var found bool
+	for _, snapshot := range existing {
+		if snapshot.Name != snapshotName {
+			continue
+		}
+		found = true
+		if !snapshot.Ready {
+			return bee, errors.Errorf("volume snapshot %s is not ready to use, can't restore %s", snapshot.VolumeSnapshot, snapshotName)
+		}
+	}
+	if !found {
+		return bee, errors.Errorf("no snapshot named %s exists for %s", snapshotName, name)
+	}
+
+	if err = b.kubectl.ShutDown(env); err != nil {
+		return bee, err
+	}
+	if _, err = b.snapshots.Restore(env, snapshotName); err != nil {
+		return bee, err
+	}
+
+	log.Info().Msgf("Syncing ArgoCD to bring services back up on restored disks")
+	bee.Status, err = b.SyncArgoAppsIn(env, func(options *argocd.SyncOptions) {
+		options.SyncIfNoDiff = true
+	})
+	return bee, err
+}
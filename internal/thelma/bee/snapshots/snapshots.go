@@ -0,0 +1,530 @@
+// Package snapshots captures and restores a BEE's persistent volume claims using Kubernetes VolumeSnapshots
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/clients/kubernetes"
+	"github.com/broadinstitute/thelma/internal/thelma/clients/kubernetes/kubecfg"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/argocd"
+	"github.com/broadinstitute/thelma/internal/thelma/utils"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// prefix for labels and annotations used by Thelma
+const labelPrefix = "thelma.terra.bio/"
+
+const (
+	environmentLabel = labelPrefix + "environment"
+	releaseLabel     = labelPrefix + "release"
+	snapshotLabel    = labelPrefix + "snapshot"
+
+	claimAnnotation        = labelPrefix + "claim"
+	sizeAnnotation         = labelPrefix + "size"
+	storageClassAnnotation = labelPrefix + "storage-class"
+	accessModesAnnotation  = labelPrefix + "access-modes"
+)
+
+const defaultPollInterval = 5 * time.Second
+const defaultTimeout = 30 * time.Minute
+
+var volumeSnapshotResource = schema.GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+// Snapshot is a VolumeSnapshot of a single persistent volume claim belonging to a BEE
+type Snapshot struct {
+	// Name of the snapshot this volume snapshot is part of; a snapshot covers all of a BEE's claims
+	Name string `json:"name" yaml:"name"`
+	// Release name of the release the claim belongs to
+	Release string `json:"release" yaml:"release"`
+	// Claim name of the persistent volume claim that was snapshotted
+	Claim string `json:"claim" yaml:"claim"`
+	// VolumeSnapshot name of the VolumeSnapshot resource
+	VolumeSnapshot string `json:"volumeSnapshot" yaml:"volumeSnapshot"`
+	// Namespace the VolumeSnapshot lives in
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// Ready true if the snapshot is ready to be restored
+	Ready bool `json:"ready" yaml:"ready"`
+	// CreatedAt when the VolumeSnapshot was created
+	CreatedAt time.Time `json:"createdAt" yaml:"createdAt"`
+	// Size requested storage size of the claim, eg. "10Gi"
+	Size string `json:"size" yaml:"size"`
+	// StorageClass storage class of the claim
+	StorageClass string `json:"storageClass,omitempty" yaml:"storageClass,omitempty"`
+	// AccessModes access modes of the claim
+	AccessModes []string `json:"accessModes,omitempty" yaml:"accessModes,omitempty"`
+}
+
+// Snapshots captures and restores a BEE's persistent volume claims. Snapshots are labeled with the environment and
+// release they belong to, so a BEE can be rolled back to a known-good state instead of being reset and re-seeded.
+type Snapshots interface {
+	// Create snapshots every persistent volume claim belonging to the environment's releases and waits for the
+	// snapshots to be ready to use
+	Create(env terra.Environment, name string, snapshotClass string) ([]Snapshot, error)
+	// List returns all snapshots of the environment's persistent volume claims
+	List(env terra.Environment) ([]Snapshot, error)
+	// Restore replaces the environment's persistent volume claims with claims restored from the named snapshot.
+	// Workloads that mount the claims must be shut down first.
+	Restore(env terra.Environment, name string) ([]Snapshot, error)
+	// Delete deletes the volume snapshots making up the named snapshot, if there are any
+	Delete(env terra.Environment, name string) error
+	// DeleteClaims deletes the persistent volume claims captured by the named snapshot, so that their disks are
+	// released. Workloads that mount the claims must be shut down first.
+	DeleteClaims(env terra.Environment, name string) error
+}
+
+func New(k8sclients kubernetes.Clients) Snapshots {
+	return &snapshots{
+		k8sclients:   k8sclients,
+		pollInterval: defaultPollInterval,
+		timeout:      defaultTimeout,
+	}
+}
+
+type snapshots struct {
+	k8sclients   kubernetes.Clients
+	pollInterval time.Duration
+	timeout      time.Duration
+}
+
+// ValidateName returns an error if the name can't be used to label snapshots
+func ValidateName(name string) error {
+	if errs := validation.IsValidLabelValue(name); len(errs) > 0 || name == "" {
+		return errors.Errorf("invalid snapshot name %q: must be 63 characters or less and consist of alphanumeric characters, '-', '_' or '.'", name)
+	}
+	return nil
+}
+
+// releaseClients API clients for the cluster a release is deployed to
+type releaseClients struct {
+	release terra.Release
+	typed   k8s.Interface
+	dynamic dynamic.Interface
+}
+
+func (s *snapshots) Create(env terra.Environment, name string, snapshotClass string) ([]Snapshot, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	existing, err := s.listMatching(env, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, errors.Errorf("a snapshot named %s already exists for %s", name, env.Name())
+	}
+
+	clients, err := s.clientsForReleases(env)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []Snapshot
+	for _, rc := range clients {
+		claims, err := claimsForRelease(rc)
+		if err != nil {
+			return nil, err
+		}
+		for _, claim := range claims {
+			snapshot := newSnapshot(name, rc.release, claim)
+			obj := volumeSnapshotObject(env, snapshot, snapshotClass)
+			log.Info().Msgf("Creating volume snapshot %s of %s for %s", snapshot.VolumeSnapshot, snapshot.Claim, rc.release.Name())
+			if _, err = rc.dynamic.Resource(volumeSnapshotResource).Namespace(snapshot.Namespace).Create(context.Background(), obj, metav1.CreateOptions{}); err != nil {
+				return nil, errors.Errorf("error creating volume snapshot %s: %v", snapshot.VolumeSnapshot, err)
+			}
+			created = append(created, snapshot)
+		}
+	}
+
+	if len(created) == 0 {
+		log.Warn().Msgf("%s has no persistent volume claims to snapshot", env.Name())
+		return nil, nil
+	}
+
+	log.Info().Msgf("Waiting for %d volume snapshots to be ready", len(created))
+	return s.waitForReady(env, name)
+}
+
+func (s *snapshots) List(env terra.Environment) ([]Snapshot, error) {
+	return s.listMatching(env, "")
+}
+
+func (s *snapshots) Restore(env terra.Environment, name string) ([]Snapshot, error) {
+	matching, clients, err := s.matchingWithClients(env, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(matching) == 0 {
+		return nil, errors.Errorf("no snapshot named %s exists for %s", name, env.Name())
+	}
+	for _, snapshot := range matching {
+		if !snapshot.Ready {
+			return nil, errors.Errorf("volume snapshot %s is not ready to use, can't restore %s", snapshot.VolumeSnapshot, name)
+		}
+	}
+
+	for _, snapshot := range matching {
+		if err = s.restoreClaim(clients[snapshot.Release].typed, snapshot); err != nil {
+			return nil, err
+		}
+	}
+	return matching, nil
+}
+
+func (s *snapshots) Delete(env terra.Environment, name string) error {
+	matching, clients, err := s.matchingWithClients(env, name)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range matching {
+		log.Info().Msgf("Deleting volume snapshot %s", snapshot.VolumeSnapshot)
+		err = clients[snapshot.Release].dynamic.Resource(volumeSnapshotResource).Namespace(snapshot.Namespace).Delete(context.Background(), snapshot.VolumeSnapshot, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Errorf("error deleting volume snapshot %s: %v", snapshot.VolumeSnapshot, err)
+		}
+	}
+	return nil
+}
+
+func (s *snapshots) DeleteClaims(env terra.Environment, name string) error {
+	matching, clients, err := s.matchingWithClients(env, name)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range matching {
+		if err = s.deleteClaim(clients[snapshot.Release].typed, snapshot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchingWithClients lists the volume snapshots making up the named snapshot, along with API clients for the
+// releases they belong to
+func (s *snapshots) matchingWithClients(env terra.Environment, name string) ([]Snapshot, map[string]releaseClients, error) {
+	// an empty name would match every snapshot of the environment
+	if err := ValidateName(name); err != nil {
+		return nil, nil, err
+	}
+	matching, err := s.listMatching(env, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	clients, err := s.clientsForReleases(env)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, snapshot := range matching {
+		if _, exists := clients[snapshot.Release]; !exists {
+			return nil, nil, errors.Errorf("snapshot %s includes release %s, which is not in %s", name, snapshot.Release, env.Name())
+		}
+	}
+	return matching, clients, nil
+}
+
+// listMatching lists snapshots for the environment, optionally restricted to the given snapshot name
+func (s *snapshots) listMatching(env terra.Environment, name string) ([]Snapshot, error) {
+	clients, err := s.clientsForReleases(env)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := map[string]string{environmentLabel: env.Name()}
+	if name != "" {
+		selector[snapshotLabel] = name
+	}
+
+	// releases in the same namespace share a dynamic client, so list each namespace once
+	listed := make(map[string]struct{})
+	var result []Snapshot
+	for _, rc := range clients {
+		key := rc.release.ClusterName() + "/" + rc.release.Namespace()
+		if _, done := listed[key]; done {
+			continue
+		}
+		listed[key] = struct{}{}
+
+		list, err := rc.dynamic.Resource(volumeSnapshotResource).Namespace(rc.release.Namespace()).List(context.Background(), metav1.ListOptions{
+			LabelSelector: utils.JoinSelector(selector),
+		})
+		if err != nil {
+			return nil, errors.Errorf("error listing volume snapshots in %s: %v", rc.release.Namespace(), err)
+		}
+		for _, item := range list.Items {
+			result = append(result, fromVolumeSnapshotObject(item))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].VolumeSnapshot < result[j].VolumeSnapshot
+	})
+	return result, nil
+}
+
+func (s *snapshots) waitForReady(env terra.Environment, name string) ([]Snapshot, error) {
+	deadline := time.Now().Add(s.timeout)
+	for {
+		matching, err := s.listMatching(env, name)
+		if err != nil {
+			return nil, err
+		}
+		ready := true
+		for _, snapshot := range matching {
+			ready = ready && snapshot.Ready
+		}
+		if ready {
+			return matching, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("timed out after %s waiting for volume snapshots for %s to be ready", s.timeout, name)
+		}
+		time.Sleep(s.pollInterval)
+	}
+}
+
+// restoreClaim deletes the snapshot's claim, if it exists, and re-creates it from the snapshot
+func (s *snapshots) restoreClaim(client k8s.Interface, snapshot Snapshot) error {
+	if err := s.deleteClaim(client, snapshot); err != nil {
+		return err
+	}
+
+	claim, err := restoredClaim(snapshot)
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("Restoring %s from volume snapshot %s", snapshot.Claim, snapshot.VolumeSnapshot)
+	if _, err = client.CoreV1().PersistentVolumeClaims(snapshot.Namespace).Create(context.Background(), claim, metav1.CreateOptions{}); err != nil {
+		return errors.Errorf("error restoring persistent volume claim %s: %v", snapshot.Claim, err)
+	}
+	return nil
+}
+
+// deleteClaim deletes the snapshot's claim, if it exists, and waits for it to be removed
+func (s *snapshots) deleteClaim(client k8s.Interface, snapshot Snapshot) error {
+	claims := client.CoreV1().PersistentVolumeClaims(snapshot.Namespace)
+
+	err := claims.Delete(context.Background(), snapshot.Claim, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Errorf("error deleting persistent volume claim %s: %v", snapshot.Claim, err)
+	}
+
+	// claims aren't removed until nothing is using them, so wait for the claim to go before returning
+	deadline := time.Now().Add(s.timeout)
+	for {
+		_, err = claims.Get(context.Background(), snapshot.Claim, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Errorf("error checking for deletion of persistent volume claim %s: %v", snapshot.Claim, err)
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out after %s waiting for persistent volume claim %s to be deleted", s.timeout, snapshot.Claim)
+		}
+		time.Sleep(s.pollInterval)
+	}
+}
+
+// clientsForReleases returns API clients for each of the environment's releases, keyed by release name
+func (s *snapshots) clientsForReleases(env terra.Environment) (map[string]releaseClients, error) {
+	_kubecfg, err := s.k8sclients.Kubecfg()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]releaseClients)
+	byContext := make(map[string]releaseClients)
+	for _, release := range env.Releases() {
+		kubectx, err := _kubecfg.ForRelease(release)
+		if err != nil {
+			return nil, err
+		}
+		rc, exists := byContext[kubectx.ContextName()]
+		if !exists {
+			if rc, err = s.newReleaseClients(kubectx); err != nil {
+				return nil, err
+			}
+			byContext[kubectx.ContextName()] = rc
+		}
+		rc.release = release
+		result[release.Name()] = rc
+	}
+	return result, nil
+}
+
+func (s *snapshots) newReleaseClients(kubectx kubecfg.Kubectx) (releaseClients, error) {
+	typed, err := s.k8sclients.ForKubectx(kubectx)
+	if err != nil {
+		return releaseClients{}, err
+	}
+	dyn, err := s.k8sclients.DynamicForKubectx(kubectx)
+	if err != nil {
+		return releaseClients{}, err
+	}
+	return releaseClients{typed: typed, dynamic: dyn}, nil
+}
+
+// claimsForRelease returns the persistent volume claims created from the volume claim templates of the release's
+// statefulsets. Claims are named "<template>-<statefulset>-<ordinal>" and are labeled with the statefulset's selector.
+func claimsForRelease(rc releaseClients) ([]corev1.PersistentVolumeClaim, error) {
+	namespace := rc.release.Namespace()
+	appSelector := argocd.ApplicationSelector(argocd.ApplicationName(rc.release))
+	statefulsets, err := rc.typed.AppsV1().StatefulSets(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: utils.JoinSelector(appSelector),
+	})
+	if err != nil {
+		return nil, errors.Errorf("error listing statefulsets for %s: %v", rc.release.Name(), err)
+	}
+
+	var result []corev1.PersistentVolumeClaim
+	for _, sts := range statefulsets.Items {
+		if len(sts.Spec.VolumeClaimTemplates) == 0 || sts.Spec.Selector == nil {
+			continue
+		}
+		claims, err := rc.typed.CoreV1().PersistentVolumeClaims(namespace).List(context.Background(), metav1.ListOptions{
+			LabelSelector: utils.JoinSelector(sts.Spec.Selector.MatchLabels),
+		})
+		if err != nil {
+			return nil, errors.Errorf("error listing persistent volume claims for %s: %v", sts.Name, err)
+		}
+		for _, claim := range claims.Items {
+			for _, template := range sts.Spec.VolumeClaimTemplates {
+				if strings.HasPrefix(claim.Name, fmt.Sprintf("%s-%s-", template.Name, sts.Name)) {
+					result = append(result, claim)
+					break
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+func newSnapshot(name string, release terra.Release, claim corev1.PersistentVolumeClaim) Snapshot {
+	snapshot := Snapshot{
+		Name:           name,
+		Release:        release.Name(),
+		Claim:          claim.Name,
+		VolumeSnapshot: fmt.Sprintf("%s-%s", claim.Name, name),
+		Namespace:      claim.Namespace,
+	}
+	if snapshot.Namespace == "" {
+		snapshot.Namespace = release.Namespace()
+	}
+	if claim.Spec.StorageClassName != nil {
+		snapshot.StorageClass = *claim.Spec.StorageClassName
+	}
+	if size, exists := claim.Spec.Resources.Requests[corev1.ResourceStorage]; exists {
+		snapshot.Size = size.String()
+	}
+	for _, mode := range claim.Spec.AccessModes {
+		snapshot.AccessModes = append(snapshot.AccessModes, string(mode))
+	}
+	return snapshot
+}
+
+func volumeSnapshotObject(env terra.Environment, snapshot Snapshot, snapshotClass string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": volumeSnapshotResource.GroupVersion().String(),
+			"kind":       "VolumeSnapshot",
+			"spec": map[string]interface{}{
+				"volumeSnapshotClassName": snapshotClass,
+				"source": map[string]interface{}{
+					"persistentVolumeClaimName": snapshot.Claim,
+				},
+			},
+		},
+	}
+	obj.SetName(snapshot.VolumeSnapshot)
+	obj.SetNamespace(snapshot.Namespace)
+	obj.SetLabels(map[string]string{
+		environmentLabel: env.Name(),
+		releaseLabel:     snapshot.Release,
+		snapshotLabel:    snapshot.Name,
+	})
+	obj.SetAnnotations(map[string]string{
+		claimAnnotation:        snapshot.Claim,
+		sizeAnnotation:         snapshot.Size,
+		storageClassAnnotation: snapshot.StorageClass,
+		accessModesAnnotation:  strings.Join(snapshot.AccessModes, ","),
+	})
+	return obj
+}
+
+func fromVolumeSnapshotObject(obj unstructured.Unstructured) Snapshot {
+	labels := obj.GetLabels()
+	annotations := obj.GetAnnotations()
+
+	snapshot := Snapshot{
+		Name:           labels[snapshotLabel],
+		Release:        labels[releaseLabel],
+		Claim:          annotations[claimAnnotation],
+		VolumeSnapshot: obj.GetName(),
+		Namespace:      obj.GetNamespace(),
+		CreatedAt:      obj.GetCreationTimestamp().Time,
+		Size:           annotations[sizeAnnotation],
+		StorageClass:   annotations[storageClassAnnotation],
+	}
+	if modes := annotations[accessModesAnnotation]; modes != "" {
+		snapshot.AccessModes = strings.Split(modes, ",")
+	}
+	if ready, found, _ := unstructured.NestedBool(obj.Object, "status", "readyToUse"); found {
+		snapshot.Ready = ready
+	}
+	if snapshot.Size == "" {
+		if restoreSize, found, _ := unstructured.NestedString(obj.Object, "status", "restoreSize"); found {
+			snapshot.Size = restoreSize
+		}
+	}
+	return snapshot
+}
+
+func restoredClaim(snapshot Snapshot) (*corev1.PersistentVolumeClaim, error) {
+	size, err := resource.ParseQuantity(snapshot.Size)
+	if err != nil {
+		return nil, errors.Errorf("invalid size %q for volume snapshot %s: %v", snapshot.Size, snapshot.VolumeSnapshot, err)
+	}
+
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshot.Claim,
+			Namespace: snapshot.Namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			DataSource: &corev1.TypedLocalObjectReference{
+				APIGroup: utils.Nullable(volumeSnapshotResource.Group),
+				Kind:     "VolumeSnapshot",
+				Name:     snapshot.VolumeSnapshot,
+			},
+		},
+	}
+	if snapshot.StorageClass != "" {
+		claim.Spec.StorageClassName = utils.Nullable(snapshot.StorageClass)
+	}
+	for _, mode := range snapshot.AccessModes {
+		claim.Spec.AccessModes = append(claim.Spec.AccessModes, corev1.PersistentVolumeAccessMode(mode))
+	}
+	claim.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: size}
+	return claim, nil
+}
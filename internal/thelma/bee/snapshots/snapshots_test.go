@@ -0,0 +1,164 @@
+package snapshots
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kubecfgmocks "github.com/broadinstitute/thelma/internal/thelma/clients/kubernetes/kubecfg/mocks"
+	k8smocks "github.com/broadinstitute/thelma/internal/thelma/clients/kubernetes/mocks"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	statemocks "github.com/broadinstitute/thelma/internal/thelma/state/api/terra/mocks"
+	"github.com/broadinstitute/thelma/internal/thelma/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const namespace = "terra-my-bee"
+
+func TestCreateListRestore(t *testing.T) {
+	env, typed, dyn, _snapshots := setup(t)
+
+	// pretend the CSI driver finishes snapshots immediately
+	dyn.PrependReactor("create", "volumesnapshots", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		require.NoError(t, unstructured.SetNestedField(obj.Object, true, "status", "readyToUse"))
+		return false, nil, nil
+	})
+
+	created, err := _snapshots.Create(env, "known-good", "snapshot-class")
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, Snapshot{
+		Name:           "known-good",
+		Release:        "sam",
+		Claim:          "data-sam-postgres-0",
+		VolumeSnapshot: "data-sam-postgres-0-known-good",
+		Namespace:      namespace,
+		Ready:          true,
+		Size:           "10Gi",
+		StorageClass:   "standard-rwo",
+		AccessModes:    []string{"ReadWriteOnce"},
+	}, created[0])
+
+	_, err = _snapshots.Create(env, "known-good", "snapshot-class")
+	assert.ErrorContains(t, err, "already exists")
+
+	listed, err := _snapshots.List(env)
+	require.NoError(t, err)
+	assert.Equal(t, created, listed)
+
+	_, err = _snapshots.Restore(env, "nope")
+	assert.ErrorContains(t, err, "no snapshot named nope")
+
+	restored, err := _snapshots.Restore(env, "known-good")
+	require.NoError(t, err)
+	assert.Equal(t, created, restored)
+
+	claim, err := typed.CoreV1().PersistentVolumeClaims(namespace).Get(context.Background(), "data-sam-postgres-0", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, claim.Spec.DataSource)
+	assert.Equal(t, "data-sam-postgres-0-known-good", claim.Spec.DataSource.Name)
+	assert.Equal(t, "10Gi", claim.Spec.Resources.Requests.Storage().String())
+}
+
+func TestDeleteClaimsAndDelete(t *testing.T) {
+	env, typed, dyn, _snapshots := setup(t)
+
+	dyn.PrependReactor("create", "volumesnapshots", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		require.NoError(t, unstructured.SetNestedField(obj.Object, true, "status", "readyToUse"))
+		return false, nil, nil
+	})
+
+	_, err := _snapshots.Create(env, "hibernate-20240102-150405", "snapshot-class")
+	require.NoError(t, err)
+
+	require.NoError(t, _snapshots.DeleteClaims(env, "hibernate-20240102-150405"))
+	_, err = typed.CoreV1().PersistentVolumeClaims(namespace).Get(context.Background(), "data-sam-postgres-0", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	require.NoError(t, _snapshots.Delete(env, "hibernate-20240102-150405"))
+	listed, err := _snapshots.List(env)
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	// nothing left to delete
+	assert.NoError(t, _snapshots.Delete(env, "hibernate-20240102-150405"))
+}
+
+func TestValidateName(t *testing.T) {
+	assert.NoError(t, ValidateName("known-good.2024"))
+	assert.Error(t, ValidateName(""))
+	assert.Error(t, ValidateName("not a label"))
+}
+
+func setup(t *testing.T) (terra.Environment, *fake.Clientset, *dynamicfake.FakeDynamicClient, *snapshots) {
+	destination := statemocks.NewDestination(t)
+	destination.EXPECT().Name().Return("my-bee").Maybe()
+
+	release := statemocks.NewRelease(t)
+	release.EXPECT().Name().Return("sam").Maybe()
+	release.EXPECT().Namespace().Return(namespace).Maybe()
+	release.EXPECT().ClusterName().Return("terra-qa-bees").Maybe()
+	release.EXPECT().Destination().Return(destination).Maybe()
+
+	env := statemocks.NewEnvironment(t)
+	env.EXPECT().Name().Return("my-bee").Maybe()
+	env.EXPECT().Releases().Return([]terra.Release{release}).Maybe()
+
+	selector := map[string]string{"app": "sam-postgres"}
+	typed := fake.NewSimpleClientset(
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sam-postgres",
+				Namespace: namespace,
+				Labels:    map[string]string{"argocd.argoproj.io/instance": "sam-my-bee"},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Selector:             &metav1.LabelSelector{MatchLabels: selector},
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}},
+			},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-sam-postgres-0", Namespace: namespace, Labels: selector},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: utils.Nullable("standard-rwo"),
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			},
+		},
+	)
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		volumeSnapshotResource: "VolumeSnapshotList",
+	})
+
+	kubectx := kubecfgmocks.NewKubectx(t)
+	kubectx.EXPECT().ContextName().Return("terra-qa-bees").Maybe()
+	kubecfg := kubecfgmocks.NewKubeconfig(t)
+	kubecfg.EXPECT().ForRelease(release).Return(kubectx, nil).Maybe()
+
+	k8sclients := k8smocks.NewClients(t)
+	k8sclients.EXPECT().Kubecfg().Return(kubecfg, nil).Maybe()
+	k8sclients.EXPECT().ForKubectx(kubectx).Return(typed, nil).Maybe()
+	k8sclients.EXPECT().DynamicForKubectx(kubectx).Return(dyn, nil).Maybe()
+
+	return env, typed, dyn, &snapshots{
+		k8sclients:   k8sclients,
+		pollInterval: time.Millisecond,
+		timeout:      time.Second,
+	}
+}
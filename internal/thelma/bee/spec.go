@@ -0,0 +1,227 @@
+package bee
+
+import (
+	"os"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/bee/seed"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/validate"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultSeedRegistrationParallelism matches the default for the --registration-parallelism seed flag
+const defaultSeedRegistrationParallelism = 20
+
+// skippableSeedSteps seed steps that can be listed in a Spec's seed.skipSteps
+var skippableSeedSteps = []string{
+	seed.StepCreateElasticsearch,
+	seed.StepRegisterSaProfiles,
+	seed.StepAddSaSamPermissions,
+	seed.StepRegisterTestUsers,
+	seed.StepCreateAgora,
+}
+
+// Spec is a declarative description of a BEE, usually loaded from a YAML file. Example:
+//
+//	name: my-bee
+//	template: swatomation
+//	owner: me@broadinstitute.org
+//	terraHelmfileRef: my-branch
+//	versions:
+//	  sam:
+//	    appVersion: 1.2.3
+//	offline: false
+//	autoDelete:
+//	  after: 72h
+//	stopSchedule:
+//	  time: 2022-01-01T19:00:00-05:00
+//	startSchedule:
+//	  time: 2022-01-01T07:00:00-05:00
+//	  weekends: false
+//	schedule:
+//	  timeZone: America/New_York
+//	  stop:
+//	    weekly: {mon: "19:00", tue: "19:00", wed: "19:00", thu: "19:00", fri: "15:00"}
+//	startOnHolidays: false
+//	seed:
+//	  skipSteps: [create-agora]
+type Spec struct {
+	// Name of the BEE
+	Name string `yaml:"name"`
+	// Template to create the BEE from
+	Template string `yaml:"template"`
+	// Owner optional email address of the BEE's owner
+	Owner string `yaml:"owner,omitempty"`
+	// TerraHelmfileRef optional terra-helmfile ref to pin the BEE to
+	TerraHelmfileRef string `yaml:"terraHelmfileRef,omitempty"`
+	// Versions optional version overrides for individual releases, keyed by release name
+	Versions map[string]terra.VersionOverride `yaml:"versions,omitempty"`
+	// Offline if true, the BEE should be stopped
+	Offline bool `yaml:"offline,omitempty"`
+	// AutoDelete optional automatic deletion settings
+	AutoDelete *AutoDeleteSpec `yaml:"autoDelete,omitempty"`
+	// StopSchedule optional daily time to stop the BEE
+	StopSchedule *ScheduleSpec `yaml:"stopSchedule,omitempty"`
+	// StartSchedule optional weekday time to start the BEE
+	StartSchedule *ScheduleSpec `yaml:"startSchedule,omitempty"`
+	// Schedule optional cron or weekly start/stop schedule
+	Schedule *schedule.Schedule `yaml:"schedule,omitempty"`
+	// StartOnHolidays if true, the start schedule also applies on holidays
+	StartOnHolidays bool `yaml:"startOnHolidays,omitempty"`
+	// Seed options for seeding the BEE after creation
+	Seed SeedSpec `yaml:"seed,omitempty"`
+}
+
+// AutoDeleteSpec schedules a BEE for deletion some time after it is created
+type AutoDeleteSpec struct {
+	// After how long after creation the BEE should be deleted, eg. "72h"
+	After time.Duration `yaml:"after"`
+}
+
+// ScheduleSpec a repeating time of day
+type ScheduleSpec struct {
+	// Time an RFC-3339 timestamp; only the time of day is used
+	Time time.Time `yaml:"time"`
+	// Weekends if true, the schedule also applies on weekends (start schedules only)
+	Weekends bool `yaml:"weekends,omitempty"`
+}
+
+// SeedSpec controls how a BEE is seeded after it is created. Seeding only happens on creation;
+// changing these settings for an existing BEE has no effect.
+type SeedSpec struct {
+	// Skip if true, don't seed the BEE at all
+	Skip bool `yaml:"skip,omitempty"`
+	// SkipSteps names of individual seed steps to skip, eg. "create-agora"
+	SkipSteps []string `yaml:"skipSteps,omitempty"`
+	// Force attempt to ignore errors during seeding
+	Force bool `yaml:"force,omitempty"`
+	// ExtraUsers additional users to register with Orch and Sam
+	ExtraUsers []string `yaml:"extraUsers,omitempty"`
+	// RegisterSelf register the current gcloud user with Orch and Sam
+	RegisterSelf bool `yaml:"registerSelf,omitempty"`
+}
+
+// LoadSpec reads a Spec from a YAML file
+func LoadSpec(file string) (Spec, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return Spec{}, errors.Errorf("error reading BEE spec %s: %v", file, err)
+	}
+	spec, err := ParseSpec(content)
+	if err != nil {
+		return Spec{}, errors.Errorf("error loading BEE spec %s: %v", file, err)
+	}
+	return spec, nil
+}
+
+// ParseSpec parses and validates a Spec from YAML
+func ParseSpec(content []byte) (Spec, error) {
+	var spec Spec
+	if err := yaml.Unmarshal(content, &spec); err != nil {
+		return Spec{}, errors.Errorf("error parsing BEE spec: %v", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return Spec{}, err
+	}
+	return spec, nil
+}
+
+// Validate checks that the spec's fields are well-formed
+func (s Spec) Validate() error {
+	if s.Name == "" {
+		return errors.Errorf("BEE spec is missing required field: name")
+	}
+	if err := validate.EnvironmentName(s.Name); err != nil {
+		return errors.Errorf("invalid BEE name %q: %v", s.Name, err)
+	}
+	if s.Template == "" {
+		return errors.Errorf("BEE spec is missing required field: template")
+	}
+	if s.AutoDelete != nil && s.AutoDelete.After <= 0 {
+		return errors.Errorf("invalid autoDelete.after %s: must be greater than zero", s.AutoDelete.After)
+	}
+	if s.Schedule != nil {
+		if err := s.Schedule.Validate(); err != nil {
+			return errors.Errorf("invalid schedule: %v", err)
+		}
+	}
+	for _, step := range s.Seed.SkipSteps {
+		if !containsString(skippableSeedSteps, step) {
+			return errors.Errorf("invalid seed step %q in seed.skipSteps, valid steps are: %v", step, skippableSeedSteps)
+		}
+	}
+	return nil
+}
+
+// pinOptions returns the PinOptions that pin a BEE to this spec's versions
+func (s Spec) pinOptions() PinOptions {
+	var opts PinOptions
+	opts.Flags.TerraHelmfileRef = s.TerraHelmfileRef
+	opts.FileOverrides = s.Versions
+	return opts
+}
+
+// seedOptions returns the SeedOptions described by this spec
+func (s Spec) seedOptions() seed.SeedOptions {
+	skip := func(step string) bool {
+		return containsString(s.Seed.SkipSteps, step)
+	}
+	opts := seed.SeedOptions{
+		Step1CreateElasticsearch: !skip(seed.StepCreateElasticsearch),
+		Step2RegisterSaProfiles:  !skip(seed.StepRegisterSaProfiles),
+		Step3AddSaSamPermissions: !skip(seed.StepAddSaSamPermissions),
+		Step4RegisterTestUsers:   !skip(seed.StepRegisterTestUsers),
+		Step5CreateAgora:         !skip(seed.StepCreateAgora),
+		Step6ExtraUser:           s.Seed.ExtraUsers,
+	}
+	// mirrors the --me seed flag
+	if s.Seed.RegisterSelf {
+		opts.Step6ExtraUser = append(opts.Step6ExtraUser, "use-adc")
+	}
+	opts.Force = s.Seed.Force
+	opts.RegistrationParallelism = defaultSeedRegistrationParallelism
+	return opts
+}
+
+// createOptions returns the terra.CreateOptions for creating a new environment from this spec
+func (s Spec) createOptions(now time.Time) terra.CreateOptions {
+	opts := terra.CreateOptions{
+		Name:            s.Name,
+		Owner:           s.Owner,
+		ScheduleOptions: s.scheduleOptions(),
+	}
+	if s.AutoDelete != nil {
+		opts.AutoDelete.Enabled = true
+		opts.AutoDelete.After = now.Add(s.AutoDelete.After)
+	}
+	return opts
+}
+
+// scheduleOptions returns the terra.ScheduleOptions described by this spec
+func (s Spec) scheduleOptions() terra.ScheduleOptions {
+	var opts terra.ScheduleOptions
+	if s.StopSchedule != nil {
+		opts.StopSchedule.Enabled = true
+		opts.StopSchedule.RepeatingTime = s.StopSchedule.Time
+	}
+	if s.StartSchedule != nil {
+		opts.StartSchedule.Enabled = true
+		opts.StartSchedule.RepeatingTime = s.StartSchedule.Time
+		opts.StartSchedule.Weekends = s.StartSchedule.Weekends
+	}
+	opts.Schedule = s.Schedule
+	opts.StartOnHolidays = s.StartOnHolidays
+	return opts
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -12,6 +12,7 @@ const pathRoot = "__ROOT__"
 type Graph struct {
 	nodes     map[string]*graphNode
 	topoOrder map[string]int
+	depths    map[string]int
 }
 
 // graphNode node in a dependency graph
@@ -44,8 +45,9 @@ func NewGraph(dependencies map[string][]string) (*Graph, error) {
 	}
 
 	topoOrder := computeTopoOrdering(nodes)
+	depths := computeDepths(nodes, topoOrder)
 
-	return &Graph{nodes: nodes, topoOrder: topoOrder}, nil
+	return &Graph{nodes: nodes, topoOrder: topoOrder, depths: depths}, nil
 }
 
 // TopoSort will sort the given charts in topological order.
@@ -66,6 +68,38 @@ func (graph *Graph) TopoSort(chartNames []string) {
 	})
 }
 
+// Waves will group the given charts into waves, such that every chart's dependencies (including transitive
+// dependencies that are not in the given list) are in earlier waves.
+// Eg. suppose we have
+// A <- B <- C       (C depends on B, which depends on A)
+// D <- E
+// G
+//
+// Waves([]string{"C", "E", "A", "G"}) will return
+// [][]string{{"A", "G"}, {"E"}, {"C"}}
+//
+// Charts within a wave are sorted by name. Charts that are not in the graph are placed in the first wave.
+func (graph *Graph) Waves(chartNames []string) [][]string {
+	byDepth := make(map[int][]string)
+	var depths []int
+	for _, chartName := range chartNames {
+		depth := graph.depths[chartName]
+		if _, exists := byDepth[depth]; !exists {
+			depths = append(depths, depth)
+		}
+		byDepth[depth] = append(byDepth[depth], chartName)
+	}
+	sort.Ints(depths)
+
+	var waves [][]string
+	for _, depth := range depths {
+		wave := byDepth[depth]
+		sort.Strings(wave)
+		waves = append(waves, wave)
+	}
+	return waves
+}
+
 // Return the names of the dependents for the given chart
 func (graph *Graph) GetDependents(chartName string) []string {
 	var result []string
@@ -150,6 +184,30 @@ func computeTopoOrdering(nodes map[string]*graphNode) map[string]int {
 	return topoOrder
 }
 
+// compute the depth of each node in the graph: 0 for nodes with no dependencies, otherwise 1 + the greatest depth
+// of any of the node's dependencies
+func computeDepths(nodes map[string]*graphNode, topoOrder map[string]int) map[string]int {
+	ordered := make([]*graphNode, 0, len(nodes))
+	for _, node := range nodes {
+		ordered = append(ordered, node)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return topoOrder[ordered[i].chartName] < topoOrder[ordered[j].chartName]
+	})
+
+	depths := make(map[string]int, len(nodes))
+	for _, node := range ordered {
+		depth := 0
+		for _, dep := range node.dependencies {
+			if depths[dep.chartName]+1 > depth {
+				depth = depths[dep.chartName] + 1
+			}
+		}
+		depths[node.chartName] = depth
+	}
+	return depths
+}
+
 func addDependency(node *graphNode, dep *graphNode) {
 	node.dependencies = append(node.dependencies, dep)
 	dep.dependents = append(dep.dependents, node)
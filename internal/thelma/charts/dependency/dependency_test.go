@@ -124,6 +124,41 @@ func TestCycleDetection(t *testing.T) {
 	assert.Regexp(t, "e -> c", err.Error())
 }
 
+func TestWaves(t *testing.T) {
+	g := testGraph(t)
+
+	testCases := []struct {
+		input    []string
+		expected [][]string
+	}{
+		{
+			input:    []string{},
+			expected: nil,
+		},
+		{
+			input:    []string{"f", "c"},
+			expected: [][]string{{"c", "f"}},
+		},
+		{
+			input:    []string{"d", "e", "c", "a", "b", "f"},
+			expected: [][]string{{"c", "f"}, {"b", "e"}, {"a"}, {"d"}},
+		},
+		{
+			// transitive dependencies are respected even if intermediate charts are not included
+			input:    []string{"d", "c"},
+			expected: [][]string{{"c"}, {"d"}},
+		},
+		{
+			input:    []string{"unknown", "a"},
+			expected: [][]string{{"unknown"}, {"a"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, g.Waves(tc.input), "input: %v", tc.input)
+	}
+}
+
 func testGraph(t *testing.T) *Graph {
 	deps := map[string][]string{
 		"a": {"b", "c"},
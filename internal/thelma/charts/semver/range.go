@@ -0,0 +1,178 @@
+package semver
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
+	"strconv"
+	"strings"
+)
+
+// Range is a set of constraints on a semantic version, such as ">=1.2.0 <2.0.0".
+//
+// Supported syntax:
+//
+//	1.2.3            exactly 1.2.3 (also =1.2.3 or ==1.2.3)
+//	!=1.2.3          anything but 1.2.3
+//	>1.2.3, >=1.2.3  greater than (or equal to) 1.2.3
+//	<1.2.3, <=1.2.3  less than (or equal to) 1.2.3
+//	~1.2.3           >=1.2.3 <1.3.0 (patch-level changes)
+//	^1.2.3           >=1.2.3 <2.0.0 (minor-level changes; ^0.2.3 is >=0.2.3 <0.3.0)
+//
+// Constraints separated by whitespace or commas must all match. Groups of constraints separated by "||" are
+// alternatives, eg. "<1.0.0 || >=2.0.0". Partial versions like "1.2" are treated as "1.2.0".
+type Range struct {
+	source       string
+	alternatives [][]constraint
+}
+
+type constraint struct {
+	operator string
+	version  string
+}
+
+// range operators, longest first so that prefix matching picks the right one
+var rangeOperators = []string{">=", "<=", "!=", "==", ">", "<", "=", "~", "^"}
+
+// ParseRange parses a semantic version range
+func ParseRange(s string) (Range, error) {
+	r := Range{source: strings.TrimSpace(s)}
+	if r.source == "" {
+		return r, errors.Errorf("invalid version range: empty")
+	}
+
+	for _, alternative := range strings.Split(r.source, "||") {
+		fields := strings.FieldsFunc(alternative, func(c rune) bool {
+			return c == ',' || c == ' ' || c == '\t'
+		})
+		if len(fields) == 0 {
+			return r, errors.Errorf("invalid version range %q: empty alternative", r.source)
+		}
+
+		var constraints []constraint
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			op := ""
+			for _, candidate := range rangeOperators {
+				if strings.HasPrefix(field, candidate) {
+					op = candidate
+					break
+				}
+			}
+			version := strings.TrimPrefix(field, op)
+			if version == "" && i+1 < len(fields) {
+				// support a space between operator and version, eg. ">= 1.2.3"
+				i++
+				version = fields[i]
+			}
+			if !IsValid(version) {
+				return r, errors.Errorf("invalid version range %q: %q is not a valid semantic version", r.source, version)
+			}
+			expanded, err := expandConstraint(op, normalize(version))
+			if err != nil {
+				return r, errors.Errorf("invalid version range %q: %v", r.source, err)
+			}
+			constraints = append(constraints, expanded...)
+		}
+		r.alternatives = append(r.alternatives, constraints)
+	}
+
+	return r, nil
+}
+
+// Contains returns true if the given version satisfies the range. Invalid versions never satisfy a range.
+func (r Range) Contains(version string) bool {
+	if !IsValid(version) {
+		return false
+	}
+	v := normalize(version)
+	for _, constraints := range r.alternatives {
+		if allMatch(constraints, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the range as it was originally supplied
+func (r Range) String() string {
+	return r.source
+}
+
+func allMatch(constraints []constraint, v string) bool {
+	for _, c := range constraints {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c constraint) matches(v string) bool {
+	cmp := semver.Compare(v, c.version)
+	switch c.operator {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	panic(fmt.Sprintf("unknown operator %q", c.operator))
+}
+
+// expandConstraint converts an operator and a normalized version into primitive constraints
+func expandConstraint(op string, version string) ([]constraint, error) {
+	switch op {
+	case "", "=", "==":
+		return []constraint{{operator: "=", version: version}}, nil
+	case "!=", ">", ">=", "<", "<=":
+		return []constraint{{operator: op, version: version}}, nil
+	case "~":
+		major, minor, err := majorMinor(version)
+		if err != nil {
+			return nil, err
+		}
+		return []constraint{
+			{operator: ">=", version: version},
+			{operator: "<", version: fmt.Sprintf("v%d.%d.0", major, minor+1)},
+		}, nil
+	case "^":
+		major, minor, err := majorMinor(version)
+		if err != nil {
+			return nil, err
+		}
+		upper := fmt.Sprintf("v%d.0.0", major+1)
+		if major == 0 {
+			upper = fmt.Sprintf("v0.%d.0", minor+1)
+		}
+		return []constraint{
+			{operator: ">=", version: version},
+			{operator: "<", version: upper},
+		}, nil
+	}
+	return nil, errors.Errorf("unknown operator %q", op)
+}
+
+// majorMinor returns the major and minor components of a normalized version
+func majorMinor(version string) (int, int, error) {
+	tokens := strings.Split(strings.TrimPrefix(semver.MajorMinor(version), "v"), ".")
+	if len(tokens) != 2 {
+		return 0, 0, errors.Errorf("invalid semantic version %q", version)
+	}
+	major, err := strconv.Atoi(tokens[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	minor, err := strconv.Atoi(tokens[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return major, minor, nil
+}
@@ -0,0 +1,86 @@
+package semver
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	testCases := []struct {
+		input    string
+		matches  []string
+		excludes []string
+	}{
+		{
+			input:    "1.2.3",
+			matches:  []string{"1.2.3", "v1.2.3"},
+			excludes: []string{"1.2.4", "1.2.3-beta"},
+		},
+		{
+			input:    "!=1.2.3",
+			matches:  []string{"1.2.4", "0.1.0"},
+			excludes: []string{"1.2.3"},
+		},
+		{
+			input:    ">=1.2.0 <2.0.0",
+			matches:  []string{"1.2.0", "1.9.9"},
+			excludes: []string{"1.1.9", "2.0.0", "some-sha"},
+		},
+		{
+			input:    ">= 1.2, <= 1.3",
+			matches:  []string{"1.2.0", "1.3.0"},
+			excludes: []string{"1.3.1"},
+		},
+		{
+			input:    "~1.2.3",
+			matches:  []string{"1.2.3", "1.2.10"},
+			excludes: []string{"1.2.2", "1.3.0"},
+		},
+		{
+			input:    "^1.2.3",
+			matches:  []string{"1.2.3", "1.9.0"},
+			excludes: []string{"1.2.2", "2.0.0"},
+		},
+		{
+			input:    "^0.2.3",
+			matches:  []string{"0.2.3", "0.2.9"},
+			excludes: []string{"0.3.0"},
+		},
+		{
+			input:    "<1.0.0 || >=2.0.0",
+			matches:  []string{"0.9.0", "2.0.0"},
+			excludes: []string{"1.0.0", "1.5.0"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			r, err := ParseRange(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.input, r.String())
+			for _, v := range tc.matches {
+				assert.True(t, r.Contains(v), "%s should contain %s", tc.input, v)
+			}
+			for _, v := range tc.excludes {
+				assert.False(t, r.Contains(v), "%s should not contain %s", tc.input, v)
+			}
+		})
+	}
+}
+
+func TestParseRangeErrors(t *testing.T) {
+	testCases := map[string]string{
+		"":             "empty",
+		">=1.0.0 ||":   "empty alternative",
+		">=banana":     `"banana" is not a valid semantic version`,
+		"1.0.0 >=":     `"" is not a valid semantic version`,
+		"=>1.0.0":      `">1.0.0" is not a valid semantic version`,
+		">=1.0.0 2.x":  `"2.x" is not a valid semantic version`,
+		"~1.0.0 ^fish": `"fish" is not a valid semantic version`,
+	}
+	for input, expectedErr := range testCases {
+		_, err := ParseRange(input)
+		assert.ErrorContains(t, err, expectedErr, "input: %q", input)
+	}
+}
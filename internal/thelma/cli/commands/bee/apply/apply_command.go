@@ -0,0 +1,146 @@
+package apply
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/bee"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/views"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Create or update a BEE to match a declarative YAML spec
+
+If the BEE does not exist, it is created from the spec's template. If it does, only the
+differences between the spec and the BEE are applied: version overrides are re-pinned and
+synced, schedules and the auto-delete time are updated, and the BEE is started or stopped.
+
+Owner and template can only be set when a BEE is created, and auto-delete can't be turned
+off once enabled; if these differ from an existing BEE, no changes are applied.
+
+Example spec:
+
+  name: swat-grungy-puma
+  template: swatomation
+  owner: me@broadinstitute.org
+  terraHelmfileRef: my-branch
+  versions:
+    sam:
+      appVersion: 1.2.3
+  offline: false
+  autoDelete:
+    after: 72h
+  stopSchedule:
+    time: 2022-01-01T19:00:00-05:00
+  startSchedule:
+    time: 2022-01-01T07:00:00-05:00
+    weekends: false
+  schedule:
+    timeZone: America/New_York
+    stop:
+      weekly: {mon: "19:00", tue: "19:00", wed: "19:00", thu: "19:00", fri: "15:00"}
+  startOnHolidays: false
+  seed:
+    skipSteps: [create-agora]
+
+Examples:
+
+# Preview changes
+thelma bee apply -f bee.yaml --dry-run
+
+# Apply changes
+thelma bee apply -f bee.yaml
+`
+
+var flagNames = struct {
+	file                      string
+	dryRun                    string
+	waitHealthy               string
+	waitHealthyTimeoutSeconds string
+	notify                    string
+	exportLogsOnFailure       string
+}{
+	file:                      "file",
+	dryRun:                    "dry-run",
+	waitHealthy:               "wait-healthy",
+	waitHealthyTimeoutSeconds: "wait-healthy-timeout-seconds",
+	notify:                    "notify",
+	exportLogsOnFailure:       "export-logs-on-failure",
+}
+
+type options struct {
+	file   string
+	dryRun bool
+	bee.ApplyOptions
+}
+
+type applyCommand struct {
+	options options
+	spec    bee.Spec
+}
+
+func NewBeeApplyCommand() cli.ThelmaCommand {
+	return &applyCommand{}
+}
+
+func (cmd *applyCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "apply -f FILE"
+	cobraCommand.Short = "Create or update a BEE from a YAML spec"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().StringVarP(&cmd.options.file, flagNames.file, "f", "", "Required. Path to a YAML file containing the BEE spec")
+	cobraCommand.Flags().BoolVar(&cmd.options.dryRun, flagNames.dryRun, false, "Print the changes that would be applied without applying them")
+	cobraCommand.Flags().BoolVar(&cmd.options.WaitHealthy, flagNames.waitHealthy, true, "Wait for BEE's Argo apps to become healthy after syncing")
+	cobraCommand.Flags().IntVar(&cmd.options.WaitHealthTimeoutSeconds, flagNames.waitHealthyTimeoutSeconds, 1800, "How long to wait for BEE's Argo apps to become healthy after syncing")
+	cobraCommand.Flags().BoolVar(&cmd.options.Notify, flagNames.notify, true, "Attempt to notify the owner via Slack when changes are applied")
+	cobraCommand.Flags().BoolVar(&cmd.options.ExportLogsOnFailure, flagNames.exportLogsOnFailure, true, "Export container logs to GCS if BEE creation fails")
+}
+
+func (cmd *applyCommand) PreRun(_ app.ThelmaApp, ctx cli.RunContext) error {
+	if !ctx.CobraCommand().Flags().Changed(flagNames.file) {
+		return errors.Errorf("no spec file specified; --%s is required", flagNames.file)
+	}
+	spec, err := bee.LoadSpec(cmd.options.file)
+	if err != nil {
+		return err
+	}
+	cmd.spec = spec
+	return nil
+}
+
+func (cmd *applyCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+	reconciler := bee.NewReconciler(bees)
+
+	plan, err := reconciler.Plan(cmd.spec)
+	if err != nil {
+		return err
+	}
+
+	if cmd.options.dryRun {
+		ctx.SetOutput(plan)
+		if len(plan.Conflicts) > 0 {
+			return errors.Errorf("spec can't be applied to %s: %d conflict(s)", plan.Name, len(plan.Conflicts))
+		}
+		return nil
+	}
+
+	if plan.Empty() {
+		log.Info().Msgf("%s is up to date, no changes to apply", plan.Name)
+	}
+
+	_bee, err := reconciler.Apply(plan, cmd.options.ApplyOptions)
+	if _bee != nil {
+		ctx.SetOutput(views.DescribeBee(_bee))
+	}
+	return err
+}
+
+func (cmd *applyCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
@@ -0,0 +1,137 @@
+package clone
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/bee"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/seedflags"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/views"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/validate"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Create a new BEE with the same template and versions as an existing BEE
+
+Every release in the new BEE is pinned to the source BEE's current chart version,
+app version, and terra-helmfile ref. Releases that are disabled in the source BEE
+(for example, because it was created with --profile) are disabled in the new BEE too.
+
+Examples:
+
+# Clone a teammate's BEE, making yourself the owner
+thelma bee clone --from=swat-grungy-puma --name=swat-grungy-puma-2 --owner=me@broadinstitute.org
+
+# Clone a BEE along with its owner and schedules, without seeding
+thelma bee clone --from=swat-grungy-puma --copy-owner --copy-schedule --seed=false
+`
+
+var flagNames = struct {
+	from                      string
+	name                      string
+	owner                     string
+	copyOwner                 string
+	copySchedule              string
+	generatorOnly             string
+	waitHealthy               string
+	waitHealthyTimeoutSeconds string
+	seed                      string
+	notify                    string
+	exportLogsOnFailure       string
+	ignoreQuotas              string
+}{
+	from:                      "from",
+	name:                      "name",
+	owner:                     "owner",
+	copyOwner:                 "copy-owner",
+	copySchedule:              "copy-schedule",
+	generatorOnly:             "generator-only",
+	waitHealthy:               "wait-healthy",
+	waitHealthyTimeoutSeconds: "wait-healthy-timeout-seconds",
+	seed:                      "seed",
+	notify:                    "notify",
+	exportLogsOnFailure:       "export-logs-on-failure",
+	ignoreQuotas:              "ignore-quotas",
+}
+
+type options struct {
+	from string
+	bee.CloneOptions
+}
+
+type cloneCommand struct {
+	options   options
+	seedFlags seedflags.SeedFlags
+}
+
+func NewBeeCloneCommand() cli.ThelmaCommand {
+	return &cloneCommand{
+		seedFlags: seedflags.NewSeedFlags(func(options *seedflags.Options) {
+			options.Prefix = "seed-"
+			options.NoShortHand = true
+		}),
+	}
+}
+
+func (cmd *cloneCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "clone"
+	cobraCommand.Short = "Create a new BEE with the same versions as an existing BEE"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().StringVar(&cmd.options.from, flagNames.from, "", "Required. Name of the BEE to clone")
+	cobraCommand.Flags().StringVarP(&cmd.options.Name, flagNames.name, "n", "", "Name for the new BEE. If not given, a name will be generated")
+	cobraCommand.Flags().StringVarP(&cmd.options.Owner, flagNames.owner, "o", "", "Email address of the owner of the new BEE")
+	cobraCommand.Flags().BoolVar(&cmd.options.CopyOwner, flagNames.copyOwner, false, "Copy the source BEE's owner to the new BEE")
+	cobraCommand.Flags().BoolVar(&cmd.options.CopySchedule, flagNames.copySchedule, false, "Copy the source BEE's start and stop schedules to the new BEE")
+	cobraCommand.Flags().BoolVar(&cmd.options.SyncGeneratorOnly, flagNames.generatorOnly, false, "Sync the BEE generator but not the BEE's Argo apps")
+	cobraCommand.Flags().BoolVar(&cmd.options.WaitHealthy, flagNames.waitHealthy, true, "Wait for BEE's Argo apps to become healthy after syncing")
+	cobraCommand.Flags().IntVar(&cmd.options.WaitHealthTimeoutSeconds, flagNames.waitHealthyTimeoutSeconds, 1800, "How long to wait for BEE's Argo apps to become healthy after syncing")
+	cobraCommand.Flags().BoolVar(&cmd.options.Seed, flagNames.seed, true, `Seed BEE after creation (run "thelma bee seed -h" for more info)`)
+	cobraCommand.Flags().BoolVar(&cmd.options.ExportLogsOnFailure, flagNames.exportLogsOnFailure, true, "Export container logs to GCS if BEE creation fails")
+	cobraCommand.Flags().BoolVar(&cmd.options.Notify, flagNames.notify, true, "Attempt to notify the owner via Slack upon success")
+	cobraCommand.Flags().BoolVar(&cmd.options.IgnoreQuotas, flagNames.ignoreQuotas, false, "Admin override: create the BEE even if it would exceed a quota (only for users in bee.quotas.overrideUsers or bee.quotas.overrideRoles)")
+
+	cmd.seedFlags.AddFlags(cobraCommand)
+}
+
+func (cmd *cloneCommand) PreRun(_ app.ThelmaApp, ctx cli.RunContext) error {
+	flags := ctx.CobraCommand().Flags()
+
+	if !flags.Changed(flagNames.from) {
+		return errors.Errorf("no source BEE specified; --%s is required", flagNames.from)
+	}
+	if flags.Changed(flagNames.name) {
+		if err := validate.EnvironmentName(cmd.options.Name); err != nil {
+			return errors.Errorf("--%s: %q is not a valid environment name: %v", flagNames.name, cmd.options.Name, err)
+		}
+	}
+	if cmd.options.CopyOwner && flags.Changed(flagNames.owner) {
+		return errors.Errorf("--%s and --%s are incompatible", flagNames.owner, flagNames.copyOwner)
+	}
+
+	seedOptions, err := cmd.seedFlags.GetOptions(ctx.CobraCommand())
+	if err != nil {
+		return err
+	}
+	cmd.options.SeedOptions = seedOptions
+
+	return nil
+}
+
+func (cmd *cloneCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+
+	_bee, err := bees.CloneWith(cmd.options.from, cmd.options.CloneOptions)
+	if _bee != nil {
+		ctx.SetOutput(views.DescribeBee(_bee))
+	}
+	return err
+}
+
+func (cmd *cloneCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
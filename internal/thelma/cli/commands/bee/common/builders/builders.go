@@ -3,8 +3,15 @@ package builders
 import (
 	"github.com/broadinstitute/thelma/internal/thelma/app"
 	"github.com/broadinstitute/thelma/internal/thelma/bee"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/checkpoint"
 	"github.com/broadinstitute/thelma/internal/thelma/bee/cleanup"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/hibernation"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/holidays"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/profiles"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/quotas"
 	"github.com/broadinstitute/thelma/internal/thelma/bee/seed"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/snapshots"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 )
@@ -20,7 +27,8 @@ func NewBees(thelmaApp app.ThelmaApp) (bee.Bees, error) {
 		return nil, err
 	}
 
-	_cleanup := cleanup.NewCleanup(thelmaApp.Clients().Google())
+	googleClients := thelmaApp.Clients().Google()
+	_cleanup := cleanup.NewCleanup(googleClients, cleanup.DefaultCleaners(googleClients)...)
 
 	kubectl, err := thelmaApp.Clients().Kubernetes().Kubectl()
 	if err != nil {
@@ -37,7 +45,19 @@ func NewBees(thelmaApp app.ThelmaApp) (bee.Bees, error) {
 	if err != nil {
 		return nil, err
 	}
-	return bee.NewBees(_argocd, stateLoader, seeder, _cleanup, kubectl, thelmaApp.Ops(), slack)
+	_snapshots := snapshots.New(thelmaApp.Clients().Kubernetes())
+
+	_quotas, err := quotas.Load(thelmaApp.Config())
+	if err != nil {
+		return nil, err
+	}
+	// used to resolve who's creating a BEE when checking quotas
+	sherlockClient, err := thelmaApp.Clients().Sherlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return bee.NewBees(_argocd, stateLoader, seeder, _cleanup, _snapshots, kubectl, thelmaApp.Ops(), slack, _quotas, sherlockClient)
 }
 
 func newSeeder(thelma app.ThelmaApp) (seed.Seeder, error) {
@@ -48,3 +68,61 @@ func newSeeder(thelma app.ThelmaApp) (seed.Seeder, error) {
 
 	return seed.New(_kubectl, thelma.Clients(), thelma.Config(), thelma.ShellRunner()), nil
 }
+
+// NewHibernationStore returns a store for hibernation manifests in the BEE's cluster artifact bucket
+func NewHibernationStore(thelmaApp app.ThelmaApp, bees bee.Bees, name string) (hibernation.Store, error) {
+	env, err := bees.GetBee(name)
+	if err != nil {
+		return nil, err
+	}
+	_bucket, err := thelmaApp.Clients().Google().Bucket(env.DefaultCluster().ArtifactBucket())
+	if err != nil {
+		return nil, errors.Errorf("error initializing artifact bucket client: %v", err)
+	}
+	return hibernation.NewBucketStore(_bucket), nil
+}
+
+// NewHibernatedStore returns a store for hibernation manifests if the BEE is hibernated, or nil if it isn't, so that
+// commands only pass a store along when there's something to restore or clean up
+func NewHibernatedStore(thelmaApp app.ThelmaApp, bees bee.Bees, name string) (hibernation.Store, error) {
+	store, err := NewHibernationStore(thelmaApp, bees, name)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := store.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, nil
+	}
+	return store, nil
+}
+
+// NewCheckpointStore returns a store for BEE provisioning checkpoints in the Thelma root. If upload is true,
+// checkpoints are also saved to the BEE's cluster artifact bucket.
+func NewCheckpointStore(thelmaApp app.ThelmaApp, bees bee.Bees, name string, upload bool) (checkpoint.Store, error) {
+	local := checkpoint.NewFileStore(thelmaApp.Root().CheckpointsDir())
+	if !upload {
+		return local, nil
+	}
+	env, err := bees.GetBee(name)
+	if err != nil {
+		return nil, err
+	}
+	_bucket, err := thelmaApp.Clients().Google().Bucket(env.DefaultCluster().ArtifactBucket())
+	if err != nil {
+		return nil, errors.Errorf("error initializing artifact bucket client: %v", err)
+	}
+	return checkpoint.NewMultiStore(local, checkpoint.NewBucketStore(_bucket)), nil
+}
+
+// NewHolidays returns the holiday calendar that start schedules skip, from the bee.holidays config
+func NewHolidays(thelmaApp app.ThelmaApp) (schedule.Holidays, error) {
+	return holidays.Load(thelmaApp.Config(), thelmaApp.Paths().EtcDir())
+}
+
+// NewProfiles returns BEE release profiles defined in config and in $THELMA_HOME/etc/bee-profiles.yaml
+func NewProfiles(thelmaApp app.ThelmaApp) (profiles.Profiles, error) {
+	return profiles.Load(thelmaApp.Config(), thelmaApp.Paths().EtcDir())
+}
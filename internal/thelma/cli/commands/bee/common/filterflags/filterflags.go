@@ -2,7 +2,7 @@ package filterflags
 
 import (
 	"github.com/broadinstitute/thelma/internal/thelma/app"
-	"github.com/broadinstitute/thelma/internal/thelma/cli/flags"
+	thelmaflags "github.com/broadinstitute/thelma/internal/thelma/cli/flags"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/filter"
 	"github.com/pkg/errors"
@@ -12,38 +12,61 @@ import (
 )
 
 type flagValues struct {
-	template     string
-	nameIncludes string
-	olderThan    time.Duration
+	template         string
+	nameIncludes     string
+	olderThan        time.Duration
+	owner            []string
+	createdBefore    time.Time
+	createdAfter     time.Time
+	offline          bool
+	autoDeleteWithin time.Duration
+	hasStartSchedule bool
+	hasStopSchedule  bool
 }
 
 var flagNames = struct {
-	template     string
-	nameIncludes string
-	olderThan    string
+	template         string
+	nameIncludes     string
+	olderThan        string
+	owner            string
+	createdBefore    string
+	createdAfter     string
+	offline          string
+	autoDeleteWithin string
+	hasStartSchedule string
+	hasStopSchedule  string
 }{
-	template:     "template",
-	nameIncludes: "name-includes",
-	olderThan:    "older-than",
+	template:         "template",
+	nameIncludes:     "name-includes",
+	olderThan:        "older-than",
+	owner:            "owner",
+	createdBefore:    "created-before",
+	createdAfter:     "created-after",
+	offline:          "offline",
+	autoDeleteWithin: "auto-delete-within",
+	hasStartSchedule: "has-start-schedule",
+	hasStopSchedule:  "has-stop-schedule",
 }
 
 type filterFlags struct {
-	options  flags.Options
+	options  thelmaflags.Options
 	flagVals flagValues
+	// flagSet is the set the flags were added to, used to check whether boolean flags were explicitly set
+	flagSet *pflag.FlagSet
 }
 
 // FilterFlags adds bee filtering flags to a cobra command and supports converting those flags to a terra.EnvironmentFilter
 type FilterFlags interface {
-	// AddFlags add bee filtering flags such as --name-includes, --older-than, and --template to a command
+	// AddFlags add bee filtering flags such as --name-includes, --older-than, --owner, and --template to a command
 	AddFlags(*cobra.Command)
 	// GetFilter should be called during a Run function to get a terra.EnvironemntFilter that matches the given filter flags
 	GetFilter(thelmaApp app.ThelmaApp) (terra.EnvironmentFilter, error)
 }
 
 // NewFilterFlags returns a new filterFlags
-func NewFilterFlags(opts ...flags.Option) FilterFlags {
+func NewFilterFlags(opts ...thelmaflags.Option) FilterFlags {
 	return &filterFlags{
-		options: flags.AsOptions(opts),
+		options: thelmaflags.AsOptions(opts),
 	}
 }
 
@@ -52,7 +75,15 @@ func (f *filterFlags) AddFlags(cobraCommand *cobra.Command) {
 		flags.StringVarP(&f.flagVals.template, flagNames.template, "t", "", "Only include BEEs created from the given template")
 		flags.StringVarP(&f.flagVals.nameIncludes, flagNames.nameIncludes, "i", "", "Only include BEEs with names that include the given substring")
 		flags.DurationVar(&f.flagVals.olderThan, flagNames.olderThan, 0, "Only include BEEs older than the given duration")
+		flags.StringSliceVar(&f.flagVals.owner, flagNames.owner, []string{}, "Only include BEEs owned by the given user(s)")
+		thelmaflags.TimeVar(flags, &f.flagVals.createdBefore, flagNames.createdBefore, "Only include BEEs created before the given time (RFC3339 or YYYY-MM-DD)")
+		thelmaflags.TimeVar(flags, &f.flagVals.createdAfter, flagNames.createdAfter, "Only include BEEs created after the given time (RFC3339 or YYYY-MM-DD)")
+		flags.BoolVar(&f.flagVals.offline, flagNames.offline, false, "Only include BEEs that are currently offline (or online, if set to false)")
+		flags.DurationVar(&f.flagVals.autoDeleteWithin, flagNames.autoDeleteWithin, 0, "Only include BEEs that will be automatically deleted within the given duration")
+		flags.BoolVar(&f.flagVals.hasStartSchedule, flagNames.hasStartSchedule, false, "Only include BEEs with a start schedule (or without one, if set to false)")
+		flags.BoolVar(&f.flagVals.hasStopSchedule, flagNames.hasStopSchedule, false, "Only include BEEs with a stop schedule (or without one, if set to false)")
 	})
+	f.flagSet = cobraCommand.Flags()
 }
 
 func (f *filterFlags) GetFilter(thelmaApp app.ThelmaApp) (terra.EnvironmentFilter, error) {
@@ -82,5 +113,49 @@ func (f *filterFlags) GetFilter(thelmaApp app.ThelmaApp) (terra.EnvironmentFilte
 		filters = append(filters, filter.Environments().OlderThan(f.flagVals.olderThan))
 	}
 
+	if len(f.flagVals.owner) > 0 {
+		filters = append(filters, filter.Environments().HasOwner(f.flagVals.owner...))
+	}
+
+	if f.changed(flagNames.createdBefore) {
+		filters = append(filters, filter.Environments().CreatedBefore(f.flagVals.createdBefore))
+	}
+
+	if f.changed(flagNames.createdAfter) {
+		filters = append(filters, filter.Environments().CreatedAfter(f.flagVals.createdAfter))
+	}
+
+	if f.changed(flagNames.offline) {
+		filters = append(filters, negateUnless(f.flagVals.offline, filter.Environments().IsOffline()))
+	}
+
+	if f.flagVals.autoDeleteWithin > 0 {
+		filters = append(filters, filter.Environments().AutoDeleteDueWithin(f.flagVals.autoDeleteWithin))
+	}
+
+	if f.changed(flagNames.hasStartSchedule) {
+		filters = append(filters, negateUnless(f.flagVals.hasStartSchedule, filter.Environments().HasStartSchedule()))
+	}
+
+	if f.changed(flagNames.hasStopSchedule) {
+		filters = append(filters, negateUnless(f.flagVals.hasStopSchedule, filter.Environments().HasStopSchedule()))
+	}
+
 	return filter.Environments().And(filters...), nil
 }
+
+// changed returns true if the user explicitly set the given flag
+func (f *filterFlags) changed(baseName string) bool {
+	if f.flagSet == nil {
+		return false
+	}
+	return f.flagSet.Changed(f.options.NormalizedFlagName(baseName))
+}
+
+// negateUnless returns the filter if the boolean flag value is true, or its negation if false
+func negateUnless(value bool, f terra.EnvironmentFilter) terra.EnvironmentFilter {
+	if value {
+		return f
+	}
+	return f.Negate()
+}
@@ -0,0 +1,200 @@
+package scheduleflags
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type flagValues struct {
+	dailyStopTime      string
+	dailyStartTime     string
+	dailyStartWeekends bool
+	scheduleTimeZone   string
+	stopCron           string
+	startCron          string
+	stopWeekly         string
+	startWeekly        string
+	startOnHolidays    bool
+}
+
+// flagNames the names of all this command's CLI flags are kept in a struct so they can be easily referenced in error messages
+var flagNames = struct {
+	dailyStopTime      string
+	dailyStartTime     string
+	dailyStartWeekends string
+	scheduleTimeZone   string
+	stopCron           string
+	startCron          string
+	stopWeekly         string
+	startWeekly        string
+	startOnHolidays    string
+}{
+	dailyStopTime:      "daily-stop-time",
+	dailyStartTime:     "daily-start-time",
+	dailyStartWeekends: "daily-start-weekends",
+	scheduleTimeZone:   "schedule-time-zone",
+	stopCron:           "stop-cron",
+	startCron:          "start-cron",
+	stopWeekly:         "stop-weekly",
+	startWeekly:        "start-weekly",
+	startOnHolidays:    "start-on-holidays",
+}
+
+type scheduleFlags struct {
+	options flagValues
+}
+
+// ScheduleFlags adds BEE schedule CLI flags to a cobra command and supports converting those flags to a
+// terra.ScheduleOptions struct
+type ScheduleFlags interface {
+	// AddFlags add schedule flags such as --daily-stop-time, --stop-cron, and so forth to a Cobra command
+	AddFlags(*cobra.Command)
+	// Changed returns true if any schedule flags were set on the command line
+	Changed(*cobra.Command) bool
+	// GetScheduleOptions can be called during a PreRun function to get a terra.ScheduleOptions populated with settings
+	// from schedule flags. It returns an error if the schedule is invalid, or if it has a stop and start so close
+	// together (as of now) that `thelma bees apply-schedule` would skip them both.
+	GetScheduleOptions(cobraCommand *cobra.Command, now time.Time) (terra.ScheduleOptions, error)
+}
+
+// NewScheduleFlags returns a new ScheduleFlags
+func NewScheduleFlags() ScheduleFlags {
+	return &scheduleFlags{}
+}
+
+func (s *scheduleFlags) AddFlags(cobraCommand *cobra.Command) {
+	cobraCommand.Flags().StringVar(&s.options.dailyStopTime, flagNames.dailyStopTime, "", "An ISO-8601 time (repeating daily) to stop the BEE.")
+	cobraCommand.Flags().StringVar(&s.options.dailyStartTime, flagNames.dailyStartTime, "", "An ISO-8601 time (repeating weekdays) to stop the BEE.")
+	cobraCommand.Flags().BoolVar(&s.options.dailyStartWeekends, flagNames.dailyStartWeekends, false, "If the daily start time should also apply on weekend days.")
+
+	cobraCommand.Flags().StringVar(&s.options.scheduleTimeZone, flagNames.scheduleTimeZone, "", "IANA time zone for --stop-*/--start-* schedules (eg. America/New_York)")
+	cobraCommand.Flags().StringVar(&s.options.stopCron, flagNames.stopCron, "", "A 5-field cron `expression` for when to stop the BEE (eg. \"0 19 * * mon-fri\")")
+	cobraCommand.Flags().StringVar(&s.options.startCron, flagNames.startCron, "", "A 5-field cron `expression` for when to start the BEE (eg. \"0 7 * * mon-fri\")")
+	cobraCommand.Flags().StringVar(&s.options.stopWeekly, flagNames.stopWeekly, "", "Per-weekday `times` to stop the BEE (eg. mon-thu=19:00,fri=15:00)")
+	cobraCommand.Flags().StringVar(&s.options.startWeekly, flagNames.startWeekly, "", "Per-weekday `times` to start the BEE (eg. mon-fri=07:00)")
+	cobraCommand.Flags().BoolVar(&s.options.startOnHolidays, flagNames.startOnHolidays, false, "If the start schedule should also apply on holidays listed in Thelma config")
+}
+
+func (s *scheduleFlags) Changed(cobraCommand *cobra.Command) bool {
+	flags := cobraCommand.Flags()
+	for _, name := range []string{
+		flagNames.dailyStopTime, flagNames.dailyStartTime, flagNames.dailyStartWeekends, flagNames.scheduleTimeZone,
+		flagNames.stopCron, flagNames.startCron, flagNames.stopWeekly, flagNames.startWeekly, flagNames.startOnHolidays,
+	} {
+		if flags.Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *scheduleFlags) GetScheduleOptions(cobraCommand *cobra.Command, now time.Time) (terra.ScheduleOptions, error) {
+	var opts terra.ScheduleOptions
+	flags := cobraCommand.Flags()
+
+	if flags.Changed(flagNames.dailyStopTime) {
+		t, err := time.Parse(time.RFC3339, s.options.dailyStopTime)
+		if err != nil {
+			return opts, errors.Errorf("%s was an invalid time: %v", s.options.dailyStopTime, err)
+		}
+		opts.StopSchedule.Enabled = true
+		opts.StopSchedule.RepeatingTime = t
+	}
+
+	if flags.Changed(flagNames.dailyStartTime) {
+		t, err := time.Parse(time.RFC3339, s.options.dailyStartTime)
+		if err != nil {
+			return opts, errors.Errorf("%s was an invalid time: %v", s.options.dailyStartTime, err)
+		}
+		opts.StartSchedule.Enabled = true
+		opts.StartSchedule.RepeatingTime = t
+		opts.StartSchedule.Weekends = s.options.dailyStartWeekends
+	}
+
+	_schedule, err := s.parseSchedule(cobraCommand)
+	if err != nil {
+		return opts, err
+	}
+	opts.Schedule = _schedule
+	opts.StartOnHolidays = s.options.startOnHolidays
+
+	if err = checkConflicts(opts, now); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// parseSchedule builds a cron/weekly schedule from the --stop-*, --start-*, and --schedule-time-zone flags
+func (s *scheduleFlags) parseSchedule(cobraCommand *cobra.Command) (*schedule.Schedule, error) {
+	flags := cobraCommand.Flags()
+	if !flags.Changed(flagNames.stopCron) && !flags.Changed(flagNames.startCron) &&
+		!flags.Changed(flagNames.stopWeekly) && !flags.Changed(flagNames.startWeekly) {
+		if flags.Changed(flagNames.scheduleTimeZone) {
+			return nil, errors.Errorf("--%s requires at least one of --%s, --%s, --%s, or --%s", flagNames.scheduleTimeZone, flagNames.stopCron, flagNames.startCron, flagNames.stopWeekly, flagNames.startWeekly)
+		}
+		return nil, nil
+	}
+	if flags.Changed(flagNames.dailyStopTime) || flags.Changed(flagNames.dailyStartTime) {
+		return nil, errors.Errorf("--%s and --%s can't be combined with cron or weekly schedules", flagNames.dailyStopTime, flagNames.dailyStartTime)
+	}
+	if !flags.Changed(flagNames.scheduleTimeZone) {
+		return nil, errors.Errorf("--%s is required for cron and weekly schedules", flagNames.scheduleTimeZone)
+	}
+
+	rule := func(cronFlag string, cron string, weeklyFlag string, weekly string) (*schedule.Rule, error) {
+		if flags.Changed(cronFlag) && flags.Changed(weeklyFlag) {
+			return nil, errors.Errorf("--%s and --%s can't be combined", cronFlag, weeklyFlag)
+		}
+		if flags.Changed(cronFlag) {
+			return &schedule.Rule{Cron: cron}, nil
+		}
+		if flags.Changed(weeklyFlag) {
+			times, err := schedule.ParseWeekly(weekly)
+			if err != nil {
+				return nil, errors.Errorf("--%s: %v", weeklyFlag, err)
+			}
+			return &schedule.Rule{Weekly: times}, nil
+		}
+		return nil, nil
+	}
+
+	_schedule := &schedule.Schedule{TimeZone: s.options.scheduleTimeZone}
+	var err error
+	if _schedule.Stop, err = rule(flagNames.stopCron, s.options.stopCron, flagNames.stopWeekly, s.options.stopWeekly); err != nil {
+		return nil, err
+	}
+	if _schedule.Start, err = rule(flagNames.startCron, s.options.startCron, flagNames.startWeekly, s.options.startWeekly); err != nil {
+		return nil, err
+	}
+	if err = _schedule.Validate(); err != nil {
+		return nil, errors.Errorf("invalid schedule: %v", err)
+	}
+	return _schedule, nil
+}
+
+// checkConflicts rejects schedules with a stop and start so close together that `thelma bees apply-schedule`
+// would skip them both
+func checkConflicts(opts terra.ScheduleOptions, now time.Time) error {
+	var err error
+	if opts.Schedule != nil {
+		err = opts.Schedule.CheckConflicts(now, schedule.DefaultApplyWindow)
+	} else if opts.StopSchedule.Enabled || opts.StartSchedule.Enabled {
+		var daily schedule.Daily
+		if opts.StopSchedule.Enabled {
+			daily.Stop = &opts.StopSchedule.RepeatingTime
+		}
+		if opts.StartSchedule.Enabled {
+			daily.Start = &opts.StartSchedule.RepeatingTime
+			daily.StartWeekends = opts.StartSchedule.Weekends
+		}
+		err = daily.CheckConflicts(now, schedule.DefaultApplyWindow)
+	}
+	if err != nil {
+		return errors.Errorf("invalid schedule: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package scheduleflags
+
+import (
+	"testing"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetScheduleOptions(t *testing.T) {
+	now, err := time.Parse(time.RFC3339, "2023-02-23T12:00:00-05:00")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected *schedule.Schedule
+		err      string
+	}{
+		{
+			name: "no schedule",
+		},
+		{
+			name: "weekly stop and cron start",
+			args: []string{"--schedule-time-zone=America/New_York", "--stop-weekly=mon-thu=19:00,fri=15:00", "--start-cron=0 7 * * mon-fri"},
+			expected: &schedule.Schedule{
+				TimeZone: "America/New_York",
+				Stop:     &schedule.Rule{Weekly: map[string]string{"mon": "19:00", "tue": "19:00", "wed": "19:00", "thu": "19:00", "fri": "15:00"}},
+				Start:    &schedule.Rule{Cron: "0 7 * * mon-fri"},
+			},
+		},
+		{
+			name: "missing time zone",
+			args: []string{"--stop-cron=0 19 * * *"},
+			err:  "--schedule-time-zone is required",
+		},
+		{
+			name: "time zone without rules",
+			args: []string{"--schedule-time-zone=UTC"},
+			err:  "requires at least one of",
+		},
+		{
+			name: "cron and weekly",
+			args: []string{"--schedule-time-zone=UTC", "--stop-cron=0 19 * * *", "--stop-weekly=mon=19:00"},
+			err:  "can't be combined",
+		},
+		{
+			name: "daily and cron",
+			args: []string{"--schedule-time-zone=UTC", "--stop-cron=0 19 * * *", "--daily-start-time=2022-01-01T07:00:00-05:00"},
+			err:  "can't be combined with cron or weekly schedules",
+		},
+		{
+			name: "invalid cron",
+			args: []string{"--schedule-time-zone=UTC", "--stop-cron=0 25 * * *"},
+			err:  "invalid schedule",
+		},
+		{
+			name: "cron schedule too close on fridays",
+			args: []string{"--schedule-time-zone=America/New_York", "--stop-weekly=mon-thu=19:00,fri=07:05", "--start-cron=0 7 * * mon-fri"},
+			err:  "only 5m0s apart",
+		},
+		{
+			name: "daily schedule",
+			args: []string{"--daily-stop-time=2022-01-01T19:00:00-05:00", "--daily-start-time=2022-01-01T07:00:00-05:00"},
+		},
+		{
+			name: "daily schedule too close",
+			args: []string{"--daily-stop-time=2022-01-01T07:10:00-05:00", "--daily-start-time=2022-01-01T07:00:00-05:00"},
+			err:  "invalid schedule: start at",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			flags := NewScheduleFlags()
+			cobraCommand := &cobra.Command{}
+			flags.AddFlags(cobraCommand)
+			require.NoError(t, cobraCommand.Flags().Parse(tc.args))
+
+			opts, err := flags.GetScheduleOptions(cobraCommand, now)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, opts.Schedule)
+			assert.Equal(t, len(tc.args) > 0, flags.Changed(cobraCommand))
+		})
+	}
+}
@@ -39,6 +39,7 @@ var flagNames = struct {
 	step5CreateAgora         string
 	step6ExtraUser           string
 	noSteps                  string
+	skipStep                 string
 	registerSelfShortcut     string
 	registrationParallelism  string
 }{
@@ -50,6 +51,7 @@ var flagNames = struct {
 	step5CreateAgora:         "step-5-create-agora",
 	step6ExtraUser:           "step-6-extra-user",
 	noSteps:                  "no-steps",
+	skipStep:                 "skip-step",
 	registerSelfShortcut:     "me",
 	registrationParallelism:  "registration-parallelism",
 }
@@ -84,6 +86,9 @@ func (s *seedFlags) AddFlags(cobraCommand *cobra.Command) {
 	cobraCommand.Flags().BoolVar(&s.seedOptions.NoSteps, s.withPrefix(flagNames.noSteps), false, "convenience flag to skip all unspecified steps, which would otherwise run by default")
 	s.maybeHide(cobraCommand, flagNames.noSteps)
 
+	cobraCommand.Flags().StringSliceVar(&s.seedOptions.SkipSteps, s.withPrefix(flagNames.skipStep), []string{}, "skip a seed step by `name`, including steps declared in Thelma config (can specify multiple times)")
+	s.maybeHide(cobraCommand, flagNames.skipStep)
+
 	cobraCommand.Flags().BoolVar(&s.seedOptions.RegisterSelfShortcut, s.withPrefix(flagNames.registerSelfShortcut), false, "shorthand for --step-6-extra-user use-adc")
 	s.maybeHide(cobraCommand, flagNames.registerSelfShortcut)
 
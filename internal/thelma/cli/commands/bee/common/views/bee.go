@@ -4,6 +4,7 @@ import (
 	"github.com/broadinstitute/thelma/internal/thelma/bee"
 	"github.com/broadinstitute/thelma/internal/thelma/ops/status"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
 	"time"
 )
 
@@ -17,10 +18,13 @@ type BeeSummary struct {
 type BeeDetail struct {
 	BeeSummary
 	Template             string                   `json:"template" yaml:"template"`
+	Profile              string                   `json:"profile,omitempty" yaml:"profile,omitempty"`
 	PreventDeletion      bool                     `json:"preventDeletion,omitempty" yaml:"preventDeletion,omitempty"`
 	DeleteAfter          time.Time                `json:"deleteAfter,omitempty" yaml:"deleteAfter,omitempty"`
 	StopSchedule         ScheduleDetail           `json:"stopSchedule,omitempty" yaml:"stopSchedule,omitempty"`
 	StartSchedule        ScheduleDetail           `json:"startSchedule,omitempty" yaml:"startSchedule,omitempty"`
+	Schedule             *schedule.Schedule       `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	StartOnHolidays      bool                     `json:"startOnHolidays,omitempty" yaml:"startOnHolidays,omitempty"`
 	TerraHelmfileRef     string                   `json:"terraHelmfileRef,omitempty" yaml:"terraHelmfileRef,omitempty"`
 	UniqueResourcePrefix string                   `json:"uniqueResourcePrefix" yaml:"uniqueResourcePrefix"`
 	Versions             map[string]string        `json:"overrides,omitempty" yaml:",omitempty"`
@@ -99,6 +103,7 @@ func DescribeBeeEnv(bee terra.Environment, opts ...DescribeOption) BeeDetail {
 			Running: !bee.Offline(),
 		},
 		Template:        bee.Template(),
+		Profile:         bee.Profile(),
 		PreventDeletion: bee.PreventDeletion(),
 		DeleteAfter:     timeIfEnabled(bee.AutoDelete().After(), bee.AutoDelete().Enabled()),
 		StopSchedule: ScheduleDetail{
@@ -111,6 +116,8 @@ func DescribeBeeEnv(bee terra.Environment, opts ...DescribeOption) BeeDetail {
 			RepeatingTime: timeIfEnabled(bee.OfflineScheduleEndTime(), bee.OfflineScheduleEndEnabled()),
 			Weekends:      bee.OfflineScheduleEndEnabled() && bee.OfflineScheduleEndWeekends(),
 		},
+		Schedule:             bee.OfflineSchedule(),
+		StartOnHolidays:      bee.StartOnHolidays(),
 		TerraHelmfileRef:     bee.TerraHelmfileRef(),
 		UniqueResourcePrefix: bee.UniqueResourcePrefix(),
 		Services:             releaseDetails,
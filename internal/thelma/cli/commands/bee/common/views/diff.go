@@ -0,0 +1,47 @@
+package views
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/diff"
+)
+
+// BeeDiff struct used for presenting version drift between a BEE and another environment in yaml & json output
+type BeeDiff struct {
+	Bee      string        `json:"bee" yaml:"bee"`
+	Against  string        `json:"against" yaml:"against"`
+	Releases []ReleaseDiff `json:"releases,omitempty" yaml:"releases,omitempty"`
+}
+
+// ReleaseDiff is a release whose versions differ, or that is only enabled in one of the two environments
+type ReleaseDiff struct {
+	Name string `json:"name" yaml:"name"`
+	// OnlyIn is the name of the environment the release is enabled in, if it's missing from the other
+	OnlyIn string        `json:"onlyIn,omitempty" yaml:"onlyIn,omitempty"`
+	Fields []VersionDiff `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+type VersionDiff struct {
+	Field   string `json:"field" yaml:"field"`
+	Bee     string `json:"bee" yaml:"bee"`
+	Against string `json:"against" yaml:"against"`
+}
+
+// DiffBeeEnv lines up the releases in bee and against by name and reports those that differ
+func DiffBeeEnv(bee terra.Environment, against terra.Environment) BeeDiff {
+	view := BeeDiff{Bee: bee.Name(), Against: against.Name()}
+	for _, change := range diff.ReleasesIn(against, bee) {
+		release := ReleaseDiff{Name: change.Name}
+		switch change.Change {
+		case diff.Added:
+			release.OnlyIn = bee.Name()
+		case diff.Removed:
+			release.OnlyIn = against.Name()
+		default:
+			for _, field := range change.Fields {
+				release.Fields = append(release.Fields, VersionDiff{Field: field.Field, Bee: field.New, Against: field.Old})
+			}
+		}
+		view.Releases = append(view.Releases, release)
+	}
+	return view
+}
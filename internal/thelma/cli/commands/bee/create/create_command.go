@@ -9,6 +9,7 @@ import (
 	"github.com/broadinstitute/thelma/internal/thelma/cli"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/pinflags"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/scheduleflags"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/seedflags"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/views"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/validate"
@@ -23,6 +24,33 @@ Examples:
 thelma bee create \
   --name=swat-grungy-puma \
   --template=swatomation \
+
+# Create a BEE with only the releases in the "minimal" profile enabled
+thelma bee create \
+  --name=swat-grungy-puma \
+  --profile=minimal
+
+Profiles are defined in Thelma config (bee.profiles.<name>.releases) or in
+$THELMA_HOME/etc/bee-profiles.yaml, eg:
+
+minimal:
+  description: Just enough to log in and create a workspace
+  releases: [sam, rawls, leonardo]
+
+Creation fails if it would exceed a BEE quota from Thelma config
+(bee.quotas.maxPerOwner, bee.quotas.maxPerTemplate). The BEE counts against
+the quota of the Sherlock user running Thelma, and of --owner if that names
+someone else. Users
+listed in bee.quotas.overrideUsers or holding a Sherlock role in
+bee.quotas.overrideRoles (eg. CI service accounts) can pass --ignore-quotas
+to skip the check.
+
+# Create a BEE that stops at 19:00 Mon-Thu and 15:00 Fri, and starts at 07:00 on weekdays
+thelma bee create \
+  --name=swat-grungy-puma \
+  --schedule-time-zone=America/New_York \
+  --stop-weekly=mon-thu=19:00,fri=15:00 \
+  --start-cron="0 7 * * mon-fri"
 `
 
 // flagNames the names of all this command's CLI flags are kept in a struct so they can be easily referenced in error messages
@@ -30,6 +58,7 @@ var flagNames = struct {
 	name                      string
 	owner                     string
 	template                  string
+	profile                   string
 	generatorOnly             string
 	waitHealthy               string
 	waitHealthyTimeoutSeconds string
@@ -38,13 +67,12 @@ var flagNames = struct {
 	notify                    string
 	exportLogsOnFailure       string
 	deleteAfter               string
-	dailyStopTime             string
-	dailyStartTime            string
-	dailyStartWeekends        string
+	ignoreQuotas              string
 }{
 	name:                      "name",
 	owner:                     "owner",
 	template:                  "template",
+	profile:                   "profile",
 	generatorOnly:             "generator-only",
 	waitHealthy:               "wait-healthy",
 	waitHealthyTimeoutSeconds: "wait-healthy-timeout-seconds",
@@ -53,28 +81,26 @@ var flagNames = struct {
 	notify:                    "notify",
 	exportLogsOnFailure:       "export-logs-on-failure",
 	deleteAfter:               "delete-after",
-	dailyStopTime:             "daily-stop-time",
-	dailyStartTime:            "daily-start-time",
-	dailyStartWeekends:        "daily-start-weekends",
+	ignoreQuotas:              "ignore-quotas",
 }
 
 type options struct {
 	bee.CreateOptions
-	deleteAfter        time.Duration
-	dailyStopTime      string
-	dailyStartTime     string
-	dailyStartWeekends bool
+	deleteAfter time.Duration
+	profile     string
 }
 
 type createCommand struct {
-	options   options
-	pinFlags  pinflags.PinFlags
-	seedFlags seedflags.SeedFlags
+	options       options
+	scheduleFlags scheduleflags.ScheduleFlags
+	pinFlags      pinflags.PinFlags
+	seedFlags     seedflags.SeedFlags
 }
 
 func NewBeeCreateCommand() cli.ThelmaCommand {
 	return &createCommand{
-		pinFlags: pinflags.NewPinFlags(),
+		scheduleFlags: scheduleflags.NewScheduleFlags(),
+		pinFlags:      pinflags.NewPinFlags(),
 		seedFlags: seedflags.NewSeedFlags(func(options *seedflags.Options) {
 			options.Prefix = "seed-"
 			options.NoShortHand = true // default short-hand flags could conflict with others in create command
@@ -91,6 +117,7 @@ func (cmd *createCommand) ConfigureCobra(cobraCommand *cobra.Command) {
 	cobraCommand.Flags().StringVarP(&cmd.options.Name, flagNames.name, "n", "", "Name for this BEE. If not given, a name will be generated")
 	cobraCommand.Flags().StringVarP(&cmd.options.Owner, flagNames.owner, "o", "", "Email address of the owner of the BEE")
 	cobraCommand.Flags().StringVarP(&cmd.options.Template, flagNames.template, "t", "swatomation", "Template to use for this BEE")
+	cobraCommand.Flags().StringVar(&cmd.options.profile, flagNames.profile, "", "Release profile to apply; releases not in the profile are disabled")
 	cobraCommand.Flags().BoolVar(&cmd.options.SyncGeneratorOnly, flagNames.generatorOnly, false, "Sync the BEE generator but not the BEE's Argo apps")
 	cobraCommand.Flags().BoolVar(&cmd.options.WaitHealthy, flagNames.waitHealthy, true, "Wait for BEE's Argo apps to become healthy after syncing")
 	cobraCommand.Flags().IntVar(&cmd.options.WaitHealthTimeoutSeconds, flagNames.waitHealthyTimeoutSeconds, 1800, "How long to wait for BEE's Argo apps to become healthy after syncing")
@@ -98,11 +125,9 @@ func (cmd *createCommand) ConfigureCobra(cobraCommand *cobra.Command) {
 	cobraCommand.Flags().BoolVar(&cmd.options.ExportLogsOnFailure, flagNames.exportLogsOnFailure, true, `Export container logs to GCS if BEE creation fails)`)
 	cobraCommand.Flags().BoolVar(&cmd.options.Notify, flagNames.notify, true, "Attempt to notify the owner via Slack upon success")
 	cobraCommand.Flags().DurationVar(&cmd.options.deleteAfter, flagNames.deleteAfter, 0, "Automatically delete this BEE after a period of time (eg. 4h)")
+	cobraCommand.Flags().BoolVar(&cmd.options.IgnoreQuotas, flagNames.ignoreQuotas, false, "Admin override: create the BEE even if it would exceed a quota (only for users in bee.quotas.overrideUsers or bee.quotas.overrideRoles)")
 
-	cobraCommand.Flags().StringVar(&cmd.options.dailyStopTime, flagNames.dailyStopTime, "", "An ISO-8601 time (repeating daily) to stop the BEE.")
-	cobraCommand.Flags().StringVar(&cmd.options.dailyStartTime, flagNames.dailyStartTime, "", "An ISO-8601 time (repeating weekdays) to stop the BEE.")
-	cobraCommand.Flags().BoolVar(&cmd.options.dailyStartWeekends, flagNames.dailyStartWeekends, false, "If the daily start time should also apply on weekend days.")
-
+	cmd.scheduleFlags.AddFlags(cobraCommand)
 	cmd.pinFlags.AddFlags(cobraCommand)
 	cmd.seedFlags.AddFlags(cobraCommand)
 }
@@ -120,35 +145,38 @@ func (cmd *createCommand) PreRun(thelmaApp app.ThelmaApp, ctx cli.RunContext) er
 		cmd.options.AutoDelete.After = time.Now().Add(cmd.options.deleteAfter)
 	}
 
-	if ctx.CobraCommand().Flags().Changed(flagNames.dailyStopTime) {
-		cmd.options.StopSchedule.Enabled = true
-		t, err := time.Parse(time.RFC3339, cmd.options.dailyStopTime)
-		if err != nil {
-			return errors.Errorf("%s was an invalid time: %v", cmd.options.dailyStopTime, err)
-		}
-		cmd.options.StopSchedule.RepeatingTime = t
-	}
-
-	if ctx.CobraCommand().Flags().Changed(flagNames.dailyStartTime) {
-		cmd.options.StartSchedule.Enabled = true
-		t, err := time.Parse(time.RFC3339, cmd.options.dailyStartTime)
-		if err != nil {
-			return errors.Errorf("%s was an invalid time: %v", cmd.options.dailyStartTime, err)
-		}
-		cmd.options.StartSchedule.RepeatingTime = t
-		cmd.options.StartSchedule.Weekends = cmd.options.dailyStartWeekends
+	scheduleOptions, err := cmd.scheduleFlags.GetScheduleOptions(ctx.CobraCommand(), time.Now())
+	if err != nil {
+		return err
 	}
+	cmd.options.ScheduleOptions = scheduleOptions
 
 	// validate --template
 	bees, err := builders.NewBees(thelmaApp)
 	if err != nil {
 		return err
 	}
-	_, err = bees.GetTemplate(cmd.options.Template)
+	template, err := bees.GetTemplate(cmd.options.Template)
 	if err != nil {
 		return errors.Errorf("--%s: %v", flagNames.template, err)
 	}
 
+	// validate --profile
+	if ctx.CobraCommand().Flags().Changed(flagNames.profile) {
+		_profiles, err := builders.NewProfiles(thelmaApp)
+		if err != nil {
+			return err
+		}
+		profile, err := _profiles.Get(cmd.options.profile)
+		if err != nil {
+			return errors.Errorf("--%s: %v", flagNames.profile, err)
+		}
+		if _, err = profile.Disabled(template); err != nil {
+			return errors.Errorf("--%s: %v", flagNames.profile, err)
+		}
+		cmd.options.Profile = &profile
+	}
+
 	// validate/load pin and seed options
 	pinOptions, err := cmd.pinFlags.GetPinOptions(thelmaApp, ctx)
 	if err != nil {
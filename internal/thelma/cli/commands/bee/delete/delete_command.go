@@ -66,6 +66,13 @@ func (cmd *deleteCommand) Run(app app.ThelmaApp, rc cli.RunContext) error {
 	if err != nil {
 		return err
 	}
+	// checkpoints are removed from the artifact bucket too, in case provisioning uploaded them
+	if cmd.options.Checkpoints, err = builders.NewCheckpointStore(app, bees, cmd.name, true); err != nil {
+		return err
+	}
+	if cmd.options.Hibernation, err = builders.NewHibernatedStore(app, bees, cmd.name); err != nil {
+		return err
+	}
 	_bee, err := bees.DeleteWith(cmd.name, cmd.options)
 	if _bee != nil {
 		rc.SetOutput(views.DescribeBee(_bee))
@@ -62,9 +62,7 @@ func (cmd *describeCommand) Run(app app.ThelmaApp, rc cli.RunContext) error {
 		return err
 	}
 
-	view := views.DescribeBeeEnv(bee)
-
-	rc.SetOutput(view)
+	rc.SetOutput(views.DescribeBeeEnv(bee))
 
 	return nil
 }
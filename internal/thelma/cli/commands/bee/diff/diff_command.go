@@ -0,0 +1,106 @@
+package diff
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/views"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Report version drift between a BEE and another environment
+
+Releases are lined up by name, and differences in chart version, app version,
+and terra-helmfile ref are reported, along with releases that are enabled in
+one environment but missing from the other. By default the BEE is compared
+against its template.
+
+Examples:
+
+# Compare a BEE against its template
+thelma bee diff -n <name>
+
+# Compare a BEE against dev
+thelma bee diff -n <name> --against=dev
+`
+
+type options struct {
+	name    string
+	against string
+}
+
+// flagNames the names of all this command's CLI flags are kept in a struct so they can be easily referenced in error messages
+var flagNames = struct {
+	name    string
+	against string
+}{
+	name:    "name",
+	against: "against",
+}
+
+type diffCommand struct {
+	options options
+}
+
+func NewBeeDiffCommand() cli.ThelmaCommand {
+	return &diffCommand{}
+}
+
+func (cmd *diffCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "diff"
+	cobraCommand.Short = "Report version drift between a BEE and another environment"
+	cobraCommand.Long = helpMessage
+	cobraCommand.Flags().StringVarP(&cmd.options.name, flagNames.name, "n", "NAME", "Required. Name of the BEE to compare")
+	cobraCommand.Flags().StringVar(&cmd.options.against, flagNames.against, "", "Name of the environment or template to compare against (defaults to the BEE's template)")
+}
+
+func (cmd *diffCommand) PreRun(_ app.ThelmaApp, rc cli.RunContext) error {
+	// validate --name
+	if !rc.CobraCommand().Flags().Changed(flagNames.name) {
+		return errors.Errorf("no environment name specified; --%s is required", flagNames.name)
+	}
+	return nil
+}
+
+func (cmd *diffCommand) Run(app app.ThelmaApp, rc cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+
+	bee, err := bees.GetBee(cmd.options.name)
+	if err != nil {
+		return err
+	}
+
+	againstName := cmd.options.against
+	if againstName == "" {
+		if bee.Template() == "" {
+			return errors.Errorf("BEE %s has no template; specify an environment to compare against with --%s", bee.Name(), flagNames.against)
+		}
+		againstName = bee.Template()
+	}
+
+	state, err := app.State()
+	if err != nil {
+		return err
+	}
+	against, err := state.Environments().Get(againstName)
+	if err != nil {
+		return err
+	}
+
+	view := views.DiffBeeEnv(bee, against)
+	if len(view.Releases) == 0 {
+		log.Info().Msgf("%s has the same release versions as %s", bee.Name(), against.Name())
+	}
+	rc.SetOutput(view)
+
+	return nil
+}
+
+func (cmd *diffCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
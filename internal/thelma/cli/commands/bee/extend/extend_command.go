@@ -0,0 +1,77 @@
+package extend
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/views"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Push back a BEE's automatic deletion time
+
+Examples:
+
+# Keep a BEE around for another day
+thelma bee extend --name=swat-grungy-puma --by=24h
+`
+
+var flagNames = struct {
+	name string
+	by   string
+}{
+	name: "name",
+	by:   "by",
+}
+
+type options struct {
+	name string
+	by   time.Duration
+}
+
+type extendCommand struct {
+	options options
+}
+
+func NewBeeExtendCommand() cli.ThelmaCommand {
+	return &extendCommand{}
+}
+
+func (cmd *extendCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "extend [options]"
+	cobraCommand.Short = "Push back a BEE's automatic deletion time"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().StringVarP(&cmd.options.name, flagNames.name, "n", "", "Required. Name of the BEE to extend")
+	cobraCommand.Flags().DurationVar(&cmd.options.by, flagNames.by, 24*time.Hour, "How long to push back the BEE's automatic deletion time (eg. 24h)")
+}
+
+func (cmd *extendCommand) PreRun(_ app.ThelmaApp, ctx cli.RunContext) error {
+	if !ctx.CobraCommand().Flags().Changed(flagNames.name) {
+		return errors.Errorf("no environment name specified; --%s is required", flagNames.name)
+	}
+	if cmd.options.by <= 0 {
+		return errors.Errorf("--%s must be greater than zero", flagNames.by)
+	}
+	return nil
+}
+
+func (cmd *extendCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+
+	_bee, err := bees.Extend(cmd.options.name, cmd.options.by)
+	if _bee != nil {
+		ctx.SetOutput(views.DescribeBee(_bee))
+	}
+	return err
+}
+
+func (cmd *extendCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
@@ -19,6 +19,12 @@ const helpMessage = `Provision the resources for a newly created BEE (Branch Eng
 Examples:
 
 thelma bee provision --name=bee-swat-ecstatic-spider
+
+# Resume a failed provision, skipping phases and seed steps that already completed
+thelma bee provision --name=bee-swat-ecstatic-spider --resume
+
+# Show which provisioning phases have completed
+thelma bee provision --name=bee-swat-ecstatic-spider --status
 `
 
 var flagNames = struct {
@@ -29,6 +35,9 @@ var flagNames = struct {
 	seed                      string
 	notify                    string
 	exportLogsOnFailure       string
+	resume                    string
+	status                    string
+	uploadCheckpoints         string
 }{
 	name:                      "name",
 	generatorOnly:             "generator-only",
@@ -37,13 +46,18 @@ var flagNames = struct {
 	seed:                      "seed",
 	notify:                    "notify",
 	exportLogsOnFailure:       "export-logs-on-failure",
+	resume:                    "resume",
+	status:                    "status",
+	uploadCheckpoints:         "upload-checkpoints",
 }
 
 type provisionCommand struct {
-	name      string
-	options   bee.ProvisionOptions
-	pinFlags  pinflags.PinFlags
-	seedFlags seedflags.SeedFlags
+	name              string
+	status            bool
+	uploadCheckpoints bool
+	options           bee.ProvisionOptions
+	pinFlags          pinflags.PinFlags
+	seedFlags         seedflags.SeedFlags
 }
 
 func NewBeeProvisionCommand() cli.ThelmaCommand {
@@ -69,6 +83,9 @@ func (cmd *provisionCommand) ConfigureCobra(cobraCommand *cobra.Command) {
 	cobraCommand.Flags().BoolVar(&cmd.options.Seed, flagNames.seed, true, `Seed BEE after creation (run "thelma bee seed -h" for more info)`)
 	cobraCommand.Flags().BoolVar(&cmd.options.ExportLogsOnFailure, flagNames.exportLogsOnFailure, true, `Export container logs to GCS if BEE creation fails)`)
 	cobraCommand.Flags().BoolVar(&cmd.options.Notify, flagNames.notify, true, "Attempt to notify the owner via Slack upon success")
+	cobraCommand.Flags().BoolVar(&cmd.options.Resume, flagNames.resume, false, "Skip provisioning phases and seed steps that completed in a previous attempt")
+	cobraCommand.Flags().BoolVar(&cmd.status, flagNames.status, false, "Print which provisioning phases have completed, without provisioning")
+	cobraCommand.Flags().BoolVar(&cmd.uploadCheckpoints, flagNames.uploadCheckpoints, false, "Also save provisioning progress to the cluster artifact bucket, so it can be resumed from another machine")
 
 	cmd.pinFlags.AddFlags(cobraCommand)
 	cmd.seedFlags.AddFlags(cobraCommand)
@@ -103,6 +120,24 @@ func (cmd *provisionCommand) Run(thelmaApp app.ThelmaApp, ctx cli.RunContext) er
 	if err != nil {
 		return err
 	}
+	store, err := builders.NewCheckpointStore(thelmaApp, bees, cmd.name, cmd.uploadCheckpoints)
+	if err != nil {
+		return err
+	}
+
+	if cmd.status {
+		cp, err := store.Load(cmd.name)
+		if err != nil {
+			return err
+		}
+		if cp == nil {
+			return errors.Errorf("no provisioning checkpoint found for %s", cmd.name)
+		}
+		ctx.SetOutput(cp)
+		return nil
+	}
+
+	cmd.options.Checkpoints = store
 	_bee, err := bees.ProvisionWith(cmd.name, cmd.options)
 	if _bee != nil {
 		ctx.SetOutput(views.DescribeBee(_bee))
@@ -0,0 +1,19 @@
+package schedule
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app/builder"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_BeeScheduleHelp(t *testing.T) {
+	_cli := cli.New(func(options *cli.Options) {
+		options.AddCommand("schedule", NewBeeScheduleCommand())
+		options.ConfigureThelma(func(thelmaBuilder builder.ThelmaBuilder) {
+			thelmaBuilder.WithTestDefaults(t)
+		})
+		options.SetArgs([]string{"schedule", "--help"})
+	})
+	assert.NoError(t, _cli.Execute(), "--help should execute successfully")
+}
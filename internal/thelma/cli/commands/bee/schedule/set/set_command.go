@@ -0,0 +1,111 @@
+package set
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/scheduleflags"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/views"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Replace a BEE's start/stop schedule
+
+The new schedule replaces the BEE's existing one entirely, so
+this can also be used to repair schedules that
+"thelma bees apply-schedule" or "thelma state lint" report as
+invalid.
+
+Examples:
+
+# Stop at 19:00 Mon-Thu and 15:00 Fri, and start at 07:00 on weekdays
+thelma bee schedule set \
+  --name=swat-grungy-puma \
+  --schedule-time-zone=America/New_York \
+  --stop-weekly=mon-thu=19:00,fri=15:00 \
+  --start-cron="0 7 * * mon-fri"
+
+# Remove a BEE's schedule
+thelma bee schedule set --name=swat-grungy-puma --clear
+`
+
+var flagNames = struct {
+	name  string
+	clear string
+}{
+	name:  "name",
+	clear: "clear",
+}
+
+type options struct {
+	name     string
+	clear    bool
+	schedule terra.ScheduleOptions
+}
+
+type setCommand struct {
+	options       options
+	scheduleFlags scheduleflags.ScheduleFlags
+}
+
+func NewBeeScheduleSetCommand() cli.ThelmaCommand {
+	return &setCommand{
+		scheduleFlags: scheduleflags.NewScheduleFlags(),
+	}
+}
+
+func (cmd *setCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "set [options]"
+	cobraCommand.Short = "Replace a BEE's start/stop schedule"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().StringVarP(&cmd.options.name, flagNames.name, "n", "", "Required. Name of the BEE to update")
+	cobraCommand.Flags().BoolVar(&cmd.options.clear, flagNames.clear, false, "Remove the BEE's schedule entirely")
+
+	cmd.scheduleFlags.AddFlags(cobraCommand)
+}
+
+func (cmd *setCommand) PreRun(_ app.ThelmaApp, ctx cli.RunContext) error {
+	if !ctx.CobraCommand().Flags().Changed(flagNames.name) {
+		return errors.Errorf("no environment name specified; --%s is required", flagNames.name)
+	}
+
+	changed := cmd.scheduleFlags.Changed(ctx.CobraCommand())
+	if cmd.options.clear && changed {
+		return errors.Errorf("--%s cannot be combined with schedule flags", flagNames.clear)
+	}
+	if !cmd.options.clear && !changed {
+		return errors.Errorf("no schedule specified; pass schedule flags or --%s", flagNames.clear)
+	}
+	if cmd.options.clear {
+		return nil
+	}
+
+	scheduleOptions, err := cmd.scheduleFlags.GetScheduleOptions(ctx.CobraCommand(), time.Now())
+	if err != nil {
+		return err
+	}
+	cmd.options.schedule = scheduleOptions
+	return nil
+}
+
+func (cmd *setCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+
+	_bee, err := bees.SetSchedule(cmd.options.name, cmd.options.schedule)
+	if _bee != nil {
+		ctx.SetOutput(views.DescribeBee(_bee))
+	}
+	return err
+}
+
+func (cmd *setCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
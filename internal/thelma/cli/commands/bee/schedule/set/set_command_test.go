@@ -0,0 +1,23 @@
+package set
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app/builder"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/schedule"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_ScheduleSetHelp(t *testing.T) {
+	_cli := cli.New(func(options *cli.Options) {
+		options.AddCommand("bee", bee.NewBeeCommand())
+		options.AddCommand("bee schedule", schedule.NewBeeScheduleCommand())
+		options.AddCommand("bee schedule set", NewBeeScheduleSetCommand())
+		options.ConfigureThelma(func(thelmaBuilder builder.ThelmaBuilder) {
+			thelmaBuilder.WithTestDefaults(t)
+		})
+		options.SetArgs([]string{"bee", "schedule", "set", "--help"})
+	})
+	assert.NoError(t, _cli.Execute(), "--help should execute successfully")
+}
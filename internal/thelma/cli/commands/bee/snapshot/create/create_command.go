@@ -0,0 +1,86 @@
+package create
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/bee"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/hibernation"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Snapshot a BEE's persistent volumes
+
+Every persistent volume claim belonging to the BEE's releases is snapshotted
+with a Kubernetes VolumeSnapshot labeled with the environment, release and
+snapshot name.
+
+By default, the BEE's workloads are scaled down while snapshots are taken so
+that they are consistent, and synced back up afterwards.
+
+Examples:
+
+# Capture a freshly-seeded BEE
+thelma bee snapshot create --name=swat-grungy-puma --snapshot=seeded
+`
+
+var flagNames = struct {
+	name          string
+	snapshot      string
+	snapshotClass string
+	quiesce       string
+}{
+	name:          "name",
+	snapshot:      "snapshot",
+	snapshotClass: "snapshot-class",
+	quiesce:       "quiesce",
+}
+
+type options struct {
+	name string
+	bee.SnapshotOptions
+}
+
+type createCommand struct {
+	options options
+}
+
+func NewBeeSnapshotCreateCommand() cli.ThelmaCommand {
+	return &createCommand{}
+}
+
+func (cmd *createCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "create [options]"
+	cobraCommand.Short = "Snapshot a BEE's persistent volumes"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().StringVarP(&cmd.options.name, flagNames.name, "n", "", "Required. Name of the BEE to snapshot")
+	cobraCommand.Flags().StringVar(&cmd.options.Name, flagNames.snapshot, "", "Name of the snapshot (defaults to a timestamp)")
+	cobraCommand.Flags().StringVar(&cmd.options.SnapshotClass, flagNames.snapshotClass, hibernation.DefaultSnapshotClass, "VolumeSnapshotClass to use for the snapshot")
+	cobraCommand.Flags().BoolVar(&cmd.options.Quiesce, flagNames.quiesce, true, "Scale the BEE's workloads down while snapshots are taken")
+}
+
+func (cmd *createCommand) PreRun(_ app.ThelmaApp, ctx cli.RunContext) error {
+	if !ctx.CobraCommand().Flags().Changed(flagNames.name) {
+		return errors.Errorf("no environment name specified; --%s is required", flagNames.name)
+	}
+	return nil
+}
+
+func (cmd *createCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+
+	created, err := bees.CreateSnapshot(cmd.options.name, cmd.options.SnapshotOptions)
+	if len(created) > 0 {
+		ctx.SetOutput(created)
+	}
+	return err
+}
+
+func (cmd *createCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
@@ -0,0 +1,68 @@
+package list
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `List snapshots of a BEE's persistent volumes
+
+Examples:
+
+thelma bee snapshot list --name=swat-grungy-puma
+`
+
+var flagNames = struct {
+	name string
+}{
+	name: "name",
+}
+
+type listCommand struct {
+	name string
+}
+
+func NewBeeSnapshotListCommand() cli.ThelmaCommand {
+	return &listCommand{}
+}
+
+func (cmd *listCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "list [options]"
+	cobraCommand.Short = "List snapshots of a BEE's persistent volumes"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().StringVarP(&cmd.name, flagNames.name, "n", "", "Required. Name of the BEE")
+}
+
+func (cmd *listCommand) PreRun(_ app.ThelmaApp, ctx cli.RunContext) error {
+	if !ctx.CobraCommand().Flags().Changed(flagNames.name) {
+		return errors.Errorf("no environment name specified; --%s is required", flagNames.name)
+	}
+	return nil
+}
+
+func (cmd *listCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+
+	listed, err := bees.ListSnapshots(cmd.name)
+	if err != nil {
+		return err
+	}
+	if len(listed) == 0 {
+		log.Info().Msgf("%s has no snapshots", cmd.name)
+		return nil
+	}
+	ctx.SetOutput(listed)
+	return nil
+}
+
+func (cmd *listCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
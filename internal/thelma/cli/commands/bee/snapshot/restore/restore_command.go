@@ -0,0 +1,75 @@
+package restore
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/views"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Roll a BEE's persistent volumes back to a snapshot
+
+The BEE's workloads are scaled down, its persistent volume claims are replaced
+with claims restored from the snapshot, and then ArgoCD is synced to bring
+services back up.
+
+Examples:
+
+thelma bee snapshot restore --name=swat-grungy-puma --snapshot=seeded
+`
+
+var flagNames = struct {
+	name     string
+	snapshot string
+}{
+	name:     "name",
+	snapshot: "snapshot",
+}
+
+type restoreCommand struct {
+	name     string
+	snapshot string
+}
+
+func NewBeeSnapshotRestoreCommand() cli.ThelmaCommand {
+	return &restoreCommand{}
+}
+
+func (cmd *restoreCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "restore [options]"
+	cobraCommand.Short = "Roll a BEE's persistent volumes back to a snapshot"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().StringVarP(&cmd.name, flagNames.name, "n", "", "Required. Name of the BEE to restore")
+	cobraCommand.Flags().StringVar(&cmd.snapshot, flagNames.snapshot, "", "Required. Name of the snapshot to restore")
+}
+
+func (cmd *restoreCommand) PreRun(_ app.ThelmaApp, ctx cli.RunContext) error {
+	flags := ctx.CobraCommand().Flags()
+	if !flags.Changed(flagNames.name) {
+		return errors.Errorf("no environment name specified; --%s is required", flagNames.name)
+	}
+	if !flags.Changed(flagNames.snapshot) {
+		return errors.Errorf("no snapshot specified; --%s is required", flagNames.snapshot)
+	}
+	return nil
+}
+
+func (cmd *restoreCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+
+	_bee, err := bees.RestoreSnapshot(cmd.name, cmd.snapshot)
+	if _bee != nil {
+		ctx.SetOutput(views.DescribeBee(_bee))
+	}
+	return err
+}
+
+func (cmd *restoreCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
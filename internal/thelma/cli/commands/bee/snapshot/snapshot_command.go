@@ -0,0 +1,38 @@
+package snapshot
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Capture and restore snapshots of a BEE's persistent volumes
+
+Snapshots let you roll a BEE back to a known-good state (for example, right
+after it was seeded) instead of resetting it and re-seeding from scratch.`
+
+type command struct{}
+
+func NewBeeSnapshotCommand() cli.ThelmaCommand {
+	return &command{}
+}
+
+func (cmd *command) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "snapshot"
+	cobraCommand.Short = "Capture and restore snapshots of a BEE's persistent volumes"
+	cobraCommand.Long = helpMessage
+}
+
+func (cmd *command) PreRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	// nothing to do yet
+	return nil
+}
+
+func (cmd *command) Run(_ app.ThelmaApp, _ cli.RunContext) error {
+	panic("Run() is only executed for leaf commands")
+}
+
+func (cmd *command) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	// nothing to do yet
+	return nil
+}
@@ -16,6 +16,10 @@ Examples:
 
 # Start an existing BEE
 thelma bee start --name=swat-grungy-puma
+
+If the BEE was stopped with --hibernate, its persistent volumes are restored
+from snapshots before services are brought back up, and the snapshots are
+deleted once they are.
 `
 
 var flagNames = struct {
@@ -67,6 +71,11 @@ func (cmd *startCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
 		return err
 	}
 
+	cmd.options.Hibernation, err = builders.NewHibernatedStore(app, bees, cmd.options.name)
+	if err != nil {
+		return err
+	}
+
 	_bee, err := bees.StartStopWith(cmd.options.name, false, cmd.options.StartStopOptions)
 	if _bee != nil {
 		ctx.SetOutput(views.DescribeBee(_bee))
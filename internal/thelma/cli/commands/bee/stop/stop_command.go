@@ -3,6 +3,7 @@ package stop
 import (
 	"github.com/broadinstitute/thelma/internal/thelma/app"
 	"github.com/broadinstitute/thelma/internal/thelma/bee"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/hibernation"
 	"github.com/broadinstitute/thelma/internal/thelma/cli"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/views"
@@ -16,16 +17,25 @@ Examples:
 
 # Stop an existing BEE
 thelma bee stop --name=swat-grungy-puma
+
+# Stop an existing BEE and release its persistent disks until it is started again.
+# The volumes are captured in a "hibernate-<timestamp>" snapshot, which shows up in
+# "thelma bee snapshot list" until the BEE is started.
+thelma bee stop --name=swat-grungy-puma --hibernate
 `
 
 var flagNames = struct {
-	name   string
-	notify string
-	sync   string
+	name          string
+	notify        string
+	sync          string
+	hibernate     string
+	snapshotClass string
 }{
-	name:   "name",
-	notify: "notify",
-	sync:   "string",
+	name:          "name",
+	notify:        "notify",
+	sync:          "string",
+	hibernate:     "hibernate",
+	snapshotClass: "snapshot-class",
 }
 
 type options struct {
@@ -49,6 +59,8 @@ func (cmd *stopCommand) ConfigureCobra(cobraCommand *cobra.Command) {
 	cobraCommand.Flags().StringVarP(&cmd.options.name, flagNames.name, "n", "", "Required. Name of the BEE to stop.")
 	cobraCommand.Flags().BoolVar(&cmd.options.Notify, flagNames.notify, true, "If the BEE owner should be notified upon stop.")
 	cobraCommand.Flags().BoolVar(&cmd.options.Sync, flagNames.sync, true, "If the BEE should be ArgoCD synced to immediately stop all chart instances.")
+	cobraCommand.Flags().BoolVar(&cmd.options.Hibernate, flagNames.hibernate, false, "Snapshot the BEE's persistent volumes and delete its PVCs; data is restored on the next start.")
+	cobraCommand.Flags().StringVar(&cmd.options.SnapshotClass, flagNames.snapshotClass, hibernation.DefaultSnapshotClass, "VolumeSnapshotClass to use with --hibernate.")
 }
 
 func (cmd *stopCommand) PreRun(_ app.ThelmaApp, ctx cli.RunContext) error {
@@ -67,6 +79,13 @@ func (cmd *stopCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
 		return err
 	}
 
+	if cmd.options.Hibernate {
+		cmd.options.Hibernation, err = builders.NewHibernationStore(app, bees, cmd.options.name)
+		if err != nil {
+			return err
+		}
+	}
+
 	_bee, err := bees.StartStopWith(cmd.options.name, true, cmd.options.StartStopOptions)
 	if _bee != nil {
 		ctx.SetOutput(views.DescribeBee(_bee))
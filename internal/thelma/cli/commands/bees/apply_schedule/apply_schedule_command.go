@@ -3,6 +3,7 @@ package apply_schedule
 import (
 	"fmt"
 	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/bee"
 	"github.com/broadinstitute/thelma/internal/thelma/cli"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/filterflags"
@@ -14,11 +15,19 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"strings"
 	"sync"
 	"time"
 )
 
-const helpMessage = `Start and stop BEEs as defined by their schedule`
+const helpMessage = `Start and stop BEEs as defined by their schedule
+
+Start transitions are skipped on holidays listed in Thelma config
+(bee.holidays.ics and bee.holidays.dates), unless the BEE was created
+with --start-on-holidays.
+
+BEEs that were stopped with --hibernate have their volumes restored from
+snapshots when they're started, the same as with "thelma bee start".`
 
 type options struct {
 	dryRun         bool
@@ -64,7 +73,7 @@ func (cmd *command) ConfigureCobra(cobraCommand *cobra.Command) {
 	cobraCommand.Flags().BoolVar(&cmd.options.dryRun, flagNames.dryRun, true, "Print the actions that would be taken instead of also doing them")
 	cobraCommand.Flags().BoolVar(&cmd.options.start, flagNames.start, true, "If start schedules should be applied")
 	cobraCommand.Flags().BoolVar(&cmd.options.stop, flagNames.stop, true, "if stop schedules should be applied")
-	cobraCommand.Flags().DurationVar(&cmd.options.fromPast, flagNames.fromPast, 20*time.Minute, "How far back to look for schedule transitions to apply (e.g. 5m, 1h, 30s)")
+	cobraCommand.Flags().DurationVar(&cmd.options.fromPast, flagNames.fromPast, schedule.DefaultApplyWindow, "How far back to look for schedule transitions to apply (e.g. 5m, 1h, 30s)")
 	cobraCommand.Flags().DurationVar(&cmd.options.creationBuffer, flagNames.creationBuffer, 20*time.Minute, "Ignore BEEs created in the past duration to allow uninterrupted seeding (e.g. 5m, 1h, 30s)")
 	cobraCommand.Flags().IntVar(&cmd.options.maxParallel, flagNames.maxParallel, 3, "Number of BEEs to apply schedules for in parallel")
 
@@ -76,6 +85,15 @@ func (cmd *command) PreRun(_ app.ThelmaApp, _ cli.RunContext) error {
 }
 
 func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
+	// BEEs are started and stopped based on state, so make sure it's current rather than cached
+	stateLoader, err := app.StateLoader()
+	if err != nil {
+		return err
+	}
+	if _, err = stateLoader.Reload(); err != nil {
+		return errors.Errorf("error reloading state: %v", err)
+	}
+
 	bees, err := builders.NewBees(app)
 	if err != nil {
 		return err
@@ -96,11 +114,19 @@ func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
 		return nil
 	}
 
+	holidays, err := builders.NewHolidays(app)
+	if err != nil {
+		return err
+	}
+
 	now := time.Now()
 	// Negative Add() since it works with durations; Sub() only works with times
 	since := now.Add(-cmd.options.fromPast)
 
 	var beesToFlip []terra.Environment
+	// names of BEEs whose schedules couldn't be applied because they're invalid; reported as an error once the
+	// other BEEs have been handled
+	var invalidSchedules []string
 	for _, matchingBee := range matchingBees {
 
 		// If this BEE was created within the buffer time, ignore it
@@ -109,21 +135,44 @@ func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
 			continue
 		}
 
-		wouldStop := cmd.options.stop &&
-			matchingBee.OfflineScheduleBeginEnabled() &&
-			schedule.CheckDailyScheduleMatch(matchingBee.OfflineScheduleBeginTime(), since, now)
-
-		// We care about the day of the week for wouldStart, so let's keep server's local time as the default but
-		// try to use the timezone that the schedule was defined in. We do this because it's possible that it might
-		// be another day of the week in another timezone right now.
+		var wouldStop, wouldStart bool
+		// location that start transitions are evaluated in, for checking holidays
 		location := time.Local
-		if matchingBee.OfflineScheduleEndEnabled() && matchingBee.OfflineScheduleEndTime().Location() != nil {
-			location = matchingBee.OfflineScheduleEndTime().Location()
+		if offlineSchedule := matchingBee.OfflineSchedule(); offlineSchedule != nil {
+			// Cron and weekly schedules carry their own time zone, so they can be evaluated directly
+			if err := offlineSchedule.Validate(); err != nil {
+				log.Error().Msgf("skipping %s since its schedule is invalid: %v", matchingBee.Name(), err)
+				invalidSchedules = append(invalidSchedules, matchingBee.Name())
+				continue
+			}
+			wouldStop = cmd.options.stop && offlineSchedule.StopMatches(since, now)
+			wouldStart = cmd.options.start && offlineSchedule.StartMatches(since, now)
+			if location, err = time.LoadLocation(offlineSchedule.TimeZone); err != nil {
+				return err
+			}
+		} else {
+			wouldStop = cmd.options.stop &&
+				matchingBee.OfflineScheduleBeginEnabled() &&
+				schedule.CheckDailyScheduleMatch(matchingBee.OfflineScheduleBeginTime(), since, now)
+
+			// We care about the day of the week for wouldStart, so let's keep server's local time as the default but
+			// try to use the timezone that the schedule was defined in. We do this because it's possible that it might
+			// be another day of the week in another timezone right now.
+			if matchingBee.OfflineScheduleEndEnabled() && matchingBee.OfflineScheduleEndTime().Location() != nil {
+				location = matchingBee.OfflineScheduleEndTime().Location()
+			}
+			wouldStart = cmd.options.start &&
+				matchingBee.OfflineScheduleEndEnabled() &&
+				schedule.CheckDailyScheduleMatch(matchingBee.OfflineScheduleEndTime(), since, now) &&
+				(!schedule.IsWeekendDay(since.In(location)) || !schedule.IsWeekendDay(now.In(location)) || matchingBee.OfflineScheduleEndWeekends())
+		}
+
+		// Holidays are skipped the same way as weekends: only if the whole window falls on one
+		if wouldStart && holidays.IsHoliday(since.In(location)) && holidays.IsHoliday(now.In(location)) &&
+			!matchingBee.StartOnHolidays() {
+			log.Info().Msgf("skipping start for %s since it's a holiday (%s)", matchingBee.Name(), holidays.Name(now.In(location)))
+			wouldStart = false
 		}
-		wouldStart := cmd.options.start &&
-			matchingBee.OfflineScheduleEndEnabled() &&
-			schedule.CheckDailyScheduleMatch(matchingBee.OfflineScheduleEndTime(), since, now) &&
-			(!schedule.IsWeekendDay(since.In(location)) || !schedule.IsWeekendDay(now.In(location)) || matchingBee.OfflineScheduleEndWeekends())
 
 		if wouldStop && wouldStart {
 			log.Warn().Msgf("%s would've been both stopped and started right now", matchingBee.Name())
@@ -134,8 +183,8 @@ func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
 			} else if cmd.options.dryRun {
 				log.Debug().Msgf("Skipped sending slack message due to this being a dry run")
 			} else {
-				markdown := fmt.Sprintf("Hey there, your <https://broad.io/beehive/r/environment/%s|%s> BEE has a start/stop schedule with times too close together, so Thelma skipped applying the schedule just now. Thelma was using a range of %s, so try making the start and stop times at least that far apart.",
-					matchingBee.Name(), matchingBee.Name(), cmd.options.fromPast.String())
+				markdown := fmt.Sprintf("Hey there, your <https://broad.io/beehive/r/environment/%s|%s> BEE has a start/stop schedule with times too close together, so Thelma skipped applying the schedule just now. Thelma was using a range of %s, so try making the start and stop times at least that far apart. You can check a schedule with `thelma bees schedule preview --name-includes=%s`.",
+					matchingBee.Name(), matchingBee.Name(), cmd.options.fromPast.String(), matchingBee.Name())
 				if err := slack.SendDirectMessage(matchingBee.Owner(), markdown); err != nil {
 					log.Debug().Msgf("Couldn't send a slack message to %s: %v", matchingBee.Owner(), err)
 					if err := slack.SendDevopsAlert("Conflicting Bee Schedule", fmt.Sprintf("BEE %s had conflicting start/stop times and an non-Slackable owner; try making the times at least %s apart", matchingBee.Name(), cmd.options.fromPast), false); err != nil {
@@ -154,7 +203,7 @@ func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
 
 	if len(beesToFlip) == 0 {
 		log.Info().Msg("Found no matching BEEs with scheduling that needed applying")
-		return nil
+		return invalidSchedulesError(invalidSchedules)
 	}
 
 	state, err := app.State()
@@ -163,11 +212,24 @@ func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
 	}
 
 	var successfullyFlippedEnvNames []string
+	var failedHibernatedStarts []string
 	var mutex sync.Mutex
 
 	var jobs []pool.Job
 	for _, unsafe := range beesToFlip {
 		env := unsafe
+		if env.Offline() {
+			hibernated, err := cmd.startIfHibernated(app, bees, env)
+			if err != nil {
+				log.Warn().Msgf("Failed to start hibernated BEE %s: %v", env.Name(), err)
+				failedHibernatedStarts = append(failedHibernatedStarts, fmt.Sprintf("%s (%v)", env.Name(), err))
+				continue
+			}
+			if hibernated {
+				successfullyFlippedEnvNames = append(successfullyFlippedEnvNames, env.Name())
+				continue
+			}
+		}
 		if cmd.options.dryRun {
 			log.Info().Msgf("Would've flipped %s target state to offline=%t but dry run was enabled", env.Name(), !env.Offline())
 		} else if err := state.Environments().SetOffline(env.Name(), !env.Offline()); err != nil {
@@ -215,10 +277,6 @@ func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
 		return err
 	}
 
-	stateLoader, err := app.StateLoader()
-	if err != nil {
-		return err
-	}
 	state, err = stateLoader.Reload()
 	if err != nil {
 		return errors.Errorf("flipped BEEs but couldn't reload state: %v", err)
@@ -241,9 +299,57 @@ func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
 	view := views.SummarizeBees(successfullyFlippedEnvs)
 	rc.SetOutput(view)
 
-	return nil
+	if err := hibernatedStartsError(failedHibernatedStarts); err != nil {
+		if invalidErr := invalidSchedulesError(invalidSchedules); invalidErr != nil {
+			return errors.Errorf("%v; %v", err, invalidErr)
+		}
+		return err
+	}
+	return invalidSchedulesError(invalidSchedules)
+}
+
+// startIfHibernated starts the BEE through the same path as "thelma bee start" if it was hibernated, so its volumes are
+// restored from snapshots instead of coming back up empty. Returns false if the BEE isn't hibernated. Hibernated BEEs
+// are started one at a time, outside the worker pool, since starting them reloads state.
+func (cmd *command) startIfHibernated(app app.ThelmaApp, bees bee.Bees, env terra.Environment) (bool, error) {
+	store, err := builders.NewHibernatedStore(app, bees, env.Name())
+	if err != nil {
+		return false, err
+	}
+	if store == nil {
+		return false, nil
+	}
+	if cmd.options.dryRun {
+		log.Info().Msgf("Would've started %s and restored its volumes from hibernation but dry run was enabled", env.Name())
+		return true, nil
+	}
+	log.Info().Msgf("Starting %s and restoring its volumes from hibernation", env.Name())
+	_, err = bees.StartStopWith(env.Name(), false, bee.StartStopOptions{
+		Sync:            true,
+		SkipWaitHealthy: true, // match the pooled syncs, which don't wait either
+		Hibernation:     store,
+	})
+	return err == nil, err
 }
 
 func (cmd *command) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
 	return nil
 }
+
+// invalidSchedulesError returns an error listing BEEs with invalid schedules, so that they don't silently stop being
+// started and stopped
+func invalidSchedulesError(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	return errors.Errorf("%d BEE(s) have invalid schedules and were skipped: %s; fix them with `thelma bee schedule set`", len(names), strings.Join(names, ", "))
+}
+
+// hibernatedStartsError returns an error listing hibernated BEEs that failed to start, so that they aren't left offline
+// without anyone noticing
+func hibernatedStartsError(failures []string) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return errors.Errorf("%d hibernated BEE(s) failed to start: %s", len(failures), strings.Join(failures, ", "))
+}
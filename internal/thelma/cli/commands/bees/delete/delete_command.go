@@ -117,9 +117,19 @@ func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
 			Name: env.Name(),
 			Run: func(_ pool.StatusReporter) error {
 				log.Info().Msgf("Deleting %s", env.Name())
-				_, err := bees.DeleteWith(env.Name(), bee.DeleteOptions{
-					Unseed:     true,
-					ExportLogs: true,
+				checkpoints, err := builders.NewCheckpointStore(app, bees, env.Name(), true)
+				if err != nil {
+					return err
+				}
+				hibernated, err := builders.NewHibernatedStore(app, bees, env.Name())
+				if err != nil {
+					return err
+				}
+				_, err = bees.DeleteWith(env.Name(), bee.DeleteOptions{
+					Unseed:      true,
+					ExportLogs:  true,
+					Checkpoints: checkpoints,
+					Hibernation: hibernated,
 				})
 
 				sendSlackMessage(slackClient, env.Name(), err)
@@ -0,0 +1,91 @@
+package doctor
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/bee"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/filterflags"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Check the health of every BEE matching a filter
+
+For each running BEE, reads the status of every Argo app and reports apps
+that are degraded, missing, or out of sync, along with a one-line
+explanation. Stopped BEEs are skipped.
+
+Examples:
+
+# Report unhealthy BEEs created from the swatomation template
+thelma bees doctor --template=swatomation
+
+# Re-sync generators for BEEs with missing or out-of-sync apps, DM their
+# owners, and post a digest to the DevOps alert channel
+thelma bees doctor --remediate --notify --alert
+`
+
+var flagNames = struct {
+	notify    string
+	alert     string
+	remediate string
+}{
+	notify:    "notify",
+	alert:     "alert",
+	remediate: "remediate",
+}
+
+type command struct {
+	options bee.DoctorOptions
+	fflags  filterflags.FilterFlags
+}
+
+func NewBeesDoctorCommand() cli.ThelmaCommand {
+	return &command{
+		fflags: filterflags.NewFilterFlags(),
+	}
+}
+
+func (cmd *command) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "doctor"
+	cobraCommand.Short = "Check the health of every BEE matching a filter"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().BoolVar(&cmd.options.Notify, flagNames.notify, false, "Notify owners of unhealthy BEEs via Slack")
+	cobraCommand.Flags().BoolVar(&cmd.options.Alert, flagNames.alert, false, "Post a digest of unhealthy BEEs to the DevOps alert channel")
+	cobraCommand.Flags().BoolVar(&cmd.options.Remediate, flagNames.remediate, false, "Re-sync the generator for BEEs with missing or out-of-sync apps")
+
+	cmd.fflags.AddFlags(cobraCommand)
+}
+
+func (cmd *command) PreRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
+
+func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+
+	beeFilter, err := cmd.fflags.GetFilter(app)
+	if err != nil {
+		return err
+	}
+
+	report, err := bees.Doctor(beeFilter, cmd.options)
+	if err != nil {
+		return err
+	}
+	if len(report.Bees) == 0 {
+		log.Info().Msg("found no matching BEEs to check")
+		return nil
+	}
+	rc.SetOutput(report)
+	return nil
+}
+
+func (cmd *command) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
@@ -0,0 +1,75 @@
+package gc
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/bee"
+	"github.com/broadinstitute/thelma/internal/thelma/bee/cleanup"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Delete cloud resources left behind by deleted BEEs
+
+Finds GCS buckets, service accounts and Cloud SQL databases in BEE projects
+that follow BEE naming conventions (a unique resource prefix, then the name of
+a release from a BEE template) but whose prefix doesn't belong to any live
+environment. Buckets created within the grace period are skipped.
+
+By default this is a dry run that only reports what would be deleted; review
+the report before re-running with --dry-run=false.
+
+Examples:
+
+# List orphaned resources
+thelma bees gc
+
+# Delete orphaned resources
+thelma bees gc --dry-run=false
+`
+
+var flagNames = struct {
+	dryRun      string
+	gracePeriod string
+}{
+	dryRun:      "dry-run",
+	gracePeriod: "grace-period",
+}
+
+type command struct {
+	options bee.GCOptions
+}
+
+func NewBeesGCCommand() cli.ThelmaCommand {
+	return &command{}
+}
+
+func (cmd *command) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "gc"
+	cobraCommand.Short = "Delete cloud resources left behind by deleted BEEs"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().BoolVar(&cmd.options.DryRun, flagNames.dryRun, true, "Report orphaned resources without deleting them")
+	cobraCommand.Flags().DurationVar(&cmd.options.GracePeriod, flagNames.gracePeriod, cleanup.DefaultGracePeriod, "Ignore resources created more recently than this")
+}
+
+func (cmd *command) PreRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
+
+func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+
+	report, err := bees.GC(cmd.options)
+	if report != nil {
+		rc.SetOutput(report)
+	}
+	return err
+}
+
+func (cmd *command) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
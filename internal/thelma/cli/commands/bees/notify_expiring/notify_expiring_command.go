@@ -0,0 +1,88 @@
+package notify_expiring
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/bee"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Notify owners of BEEs that will soon be automatically deleted
+
+Owners are sent a Slack DM including the exact deletion time and instructions
+for extending it with "thelma bee extend". Each owner is notified once per
+deletion time, so this is safe to run on a frequent schedule; extending a BEE
+changes its deletion time, so its owner will be notified again.
+
+Examples:
+
+# Notify owners of BEEs that will be deleted in the next 24 hours
+thelma bees notify-expiring --within=24h
+
+# List BEEs that will be deleted in the next 2 days without notifying anyone
+thelma bees notify-expiring --within=48h --dry-run
+`
+
+var flagNames = struct {
+	within string
+	dryRun string
+}{
+	within: "within",
+	dryRun: "dry-run",
+}
+
+type options struct {
+	within time.Duration
+	bee.NotifyExpiringOptions
+}
+
+type command struct {
+	options options
+}
+
+func NewBeesNotifyExpiringCommand() cli.ThelmaCommand {
+	return &command{}
+}
+
+func (cmd *command) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "notify-expiring"
+	cobraCommand.Short = "Notify owners of BEEs that will soon be automatically deleted"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().DurationVar(&cmd.options.within, flagNames.within, 24*time.Hour, "Notify owners of BEEs that will be deleted within this long")
+	cobraCommand.Flags().BoolVar(&cmd.options.DryRun, flagNames.dryRun, false, "Print expiring BEEs without notifying their owners")
+}
+
+func (cmd *command) PreRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	if cmd.options.within <= 0 {
+		return errors.Errorf("--%s must be greater than zero", flagNames.within)
+	}
+	return nil
+}
+
+func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+
+	notices, err := bees.NotifyExpiring(cmd.options.within, cmd.options.NotifyExpiringOptions)
+	if err != nil {
+		return err
+	}
+	if len(notices) == 0 {
+		log.Info().Msgf("found no BEEs that will be deleted within %s", cmd.options.within)
+		return nil
+	}
+	rc.SetOutput(notices)
+	return nil
+}
+
+func (cmd *command) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
@@ -0,0 +1,208 @@
+package preview
+
+import (
+	"sort"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/builders"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/common/filterflags"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Preview upcoming start/stop transitions for BEEs
+
+For each matching BEE with a schedule, lists the next transitions in the
+schedule's time zone and flags any stop and start that are closer together
+than --from-past. "thelma bees apply-schedule" skips both transitions of
+such a pair, so the BEE is left as it was. Start transitions on holidays
+from Thelma config are marked as skipped, unless the BEE starts on holidays.
+
+Examples:
+
+# Preview the next 10 transitions for all BEEs owned by someone
+thelma bees schedule preview --owner=someone@broadinstitute.org
+
+# Check a week's worth of transitions against a 30 minute apply-schedule window
+thelma bees schedule preview --count=20 --from-past=30m
+`
+
+// timeFormat includes the weekday, since weekly schedules are easier to check that way
+const timeFormat = "Mon 2006-01-02 15:04 MST"
+
+var flagNames = struct {
+	count    string
+	fromPast string
+}{
+	count:    "count",
+	fromPast: "from-past",
+}
+
+type options struct {
+	count    int
+	fromPast time.Duration
+}
+
+type command struct {
+	options options
+	fflags  filterflags.FilterFlags
+}
+
+// beePreview is the output for a single BEE
+type beePreview struct {
+	Name        string       `json:"name" yaml:"name"`
+	TimeZone    string       `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
+	Transitions []transition `json:"transitions,omitempty" yaml:"transitions,omitempty"`
+	Conflicts   []string     `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
+	Error       string       `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type transition struct {
+	Action schedule.Action `json:"action" yaml:"action"`
+	Time   string          `json:"time" yaml:"time"`
+	// Skipped is the reason apply-schedule will skip the transition, if it will
+	Skipped string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+}
+
+func NewBeesSchedulePreviewCommand() cli.ThelmaCommand {
+	return &command{
+		fflags: filterflags.NewFilterFlags(),
+	}
+}
+
+func (cmd *command) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "preview"
+	cobraCommand.Short = "Preview upcoming start/stop transitions for BEEs"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().IntVar(&cmd.options.count, flagNames.count, 10, "Number of upcoming transitions to show per BEE")
+	cobraCommand.Flags().DurationVar(&cmd.options.fromPast, flagNames.fromPast, schedule.DefaultApplyWindow, "apply-schedule's --from-past window; stops and starts closer together than this are flagged (e.g. 5m, 1h, 30s)")
+
+	cmd.fflags.AddFlags(cobraCommand)
+}
+
+func (cmd *command) PreRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	if cmd.options.count < 1 {
+		return errors.Errorf("--%s must be at least 1", flagNames.count)
+	}
+	return nil
+}
+
+func (cmd *command) Run(app app.ThelmaApp, rc cli.RunContext) error {
+	bees, err := builders.NewBees(app)
+	if err != nil {
+		return err
+	}
+
+	beeFilter, err := cmd.fflags.GetFilter(app)
+	if err != nil {
+		return err
+	}
+
+	matchingBees, err := bees.FilterBees(beeFilter)
+	if err != nil {
+		return err
+	}
+
+	holidays, err := builders.NewHolidays(app)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(matchingBees, func(i, j int) bool {
+		return matchingBees[i].Name() < matchingBees[j].Name()
+	})
+
+	now := time.Now()
+	var previews []beePreview
+	for _, matchingBee := range matchingBees {
+		p, hasSchedule := previewBee(matchingBee, now, cmd.options.count, cmd.options.fromPast, holidays)
+		if !hasSchedule {
+			continue
+		}
+		if p.Error != "" {
+			log.Warn().Msgf("%s has an invalid schedule: %s", p.Name, p.Error)
+		}
+		for _, conflict := range p.Conflicts {
+			log.Warn().Msgf("%s has conflicting transitions: %s", p.Name, conflict)
+		}
+		previews = append(previews, p)
+	}
+
+	if len(previews) == 0 {
+		log.Info().Msg("Found no matching BEEs with schedules to preview")
+		return nil
+	}
+	rc.SetOutput(previews)
+	return nil
+}
+
+func (cmd *command) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
+
+// previewBee computes the next count transitions for the BEE's schedule, marks starts on holidays as skipped, and
+// flags adjacent stops and starts closer together than window. Returns false if the BEE has no schedule.
+func previewBee(env terra.Environment, now time.Time, count int, window time.Duration, holidays schedule.Holidays) (beePreview, bool) {
+	p := beePreview{Name: env.Name()}
+
+	var transitions []schedule.Transition
+	var location *time.Location
+	if offlineSchedule := env.OfflineSchedule(); offlineSchedule != nil {
+		p.TimeZone = offlineSchedule.TimeZone
+		if err := offlineSchedule.Validate(); err != nil {
+			p.Error = err.Error()
+			return p, true
+		}
+		var err error
+		if transitions, err = offlineSchedule.Upcoming(now, count); err != nil {
+			p.Error = err.Error()
+			return p, true
+		}
+		location, _ = time.LoadLocation(offlineSchedule.TimeZone)
+	} else {
+		var daily schedule.Daily
+		if env.OfflineScheduleBeginEnabled() {
+			stop := env.OfflineScheduleBeginTime()
+			daily.Stop = &stop
+			location = stop.Location()
+		}
+		if env.OfflineScheduleEndEnabled() {
+			start := env.OfflineScheduleEndTime()
+			daily.Start = &start
+			daily.StartWeekends = env.OfflineScheduleEndWeekends()
+			if location == nil {
+				location = start.Location()
+			}
+		}
+		if daily.Stop == nil && daily.Start == nil {
+			return p, false
+		}
+		p.TimeZone = location.String()
+		transitions = daily.Upcoming(now, count)
+	}
+
+	var applied []schedule.Transition
+	for _, t := range transitions {
+		view := transition{Action: t.Action, Time: t.Time.In(location).Format(timeFormat)}
+		// transitions are already in the location their schedule is evaluated in
+		if t.Action == schedule.Start && holidays.IsHoliday(t.Time) && !env.StartOnHolidays() {
+			view.Skipped = "holiday"
+			if name := holidays.Name(t.Time); name != "" {
+				view.Skipped += ": " + name
+			}
+		} else {
+			applied = append(applied, t)
+		}
+		p.Transitions = append(p.Transitions, view)
+	}
+	for _, conflict := range schedule.Conflicts(applied, window) {
+		p.Conflicts = append(p.Conflicts, conflict.String())
+	}
+	return p, true
+}
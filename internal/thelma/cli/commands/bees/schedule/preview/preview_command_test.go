@@ -0,0 +1,112 @@
+package preview
+
+import (
+	"testing"
+	"time"
+
+	statemocks "github.com/broadinstitute/thelma/internal/thelma/state/api/terra/mocks"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_previewBee(t *testing.T) {
+	// Thursday afternoon
+	now, err := time.Parse(time.RFC3339, "2023-02-23T12:00:00-05:00")
+	require.NoError(t, err)
+
+	t.Run("cron and weekly schedule", func(t *testing.T) {
+		env := statemocks.NewEnvironment(t)
+		env.EXPECT().Name().Return("my-bee")
+		env.EXPECT().OfflineSchedule().Return(&schedule.Schedule{
+			TimeZone: "America/New_York",
+			Stop:     &schedule.Rule{Weekly: map[string]string{"thu": "19:00", "fri": "07:10"}},
+			Start:    &schedule.Rule{Cron: "0 7 * * mon-fri"},
+		})
+
+		p, hasSchedule := previewBee(env, now, 3, schedule.DefaultApplyWindow, nil)
+		assert.True(t, hasSchedule)
+		assert.Equal(t, "America/New_York", p.TimeZone)
+		assert.Empty(t, p.Error)
+		assert.Equal(t, []transition{
+			{Action: schedule.Stop, Time: "Thu 2023-02-23 19:00 EST"},
+			{Action: schedule.Start, Time: "Fri 2023-02-24 07:00 EST"},
+			{Action: schedule.Stop, Time: "Fri 2023-02-24 07:10 EST"},
+		}, p.Transitions)
+		assert.Equal(t, []string{
+			"start at 2023-02-24T07:00:00-05:00 and stop at 2023-02-24T07:10:00-05:00 are only 10m0s apart",
+		}, p.Conflicts)
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		env := statemocks.NewEnvironment(t)
+		env.EXPECT().Name().Return("my-bee")
+		env.EXPECT().OfflineSchedule().Return(&schedule.Schedule{Stop: &schedule.Rule{Cron: "0 19 * * *"}})
+
+		p, hasSchedule := previewBee(env, now, 3, schedule.DefaultApplyWindow, nil)
+		assert.True(t, hasSchedule)
+		assert.Contains(t, p.Error, "time zone")
+		assert.Empty(t, p.Transitions)
+	})
+
+	t.Run("daily schedule", func(t *testing.T) {
+		location, err := time.LoadLocation("America/Chicago")
+		require.NoError(t, err)
+		env := statemocks.NewEnvironment(t)
+		env.EXPECT().Name().Return("my-bee")
+		env.EXPECT().OfflineSchedule().Return(nil)
+		env.EXPECT().OfflineScheduleBeginEnabled().Return(true)
+		env.EXPECT().OfflineScheduleBeginTime().Return(time.Date(2023, 1, 1, 18, 0, 0, 0, location))
+		env.EXPECT().OfflineScheduleEndEnabled().Return(false)
+
+		p, hasSchedule := previewBee(env, now, 2, schedule.DefaultApplyWindow, nil)
+		assert.True(t, hasSchedule)
+		assert.Equal(t, "America/Chicago", p.TimeZone)
+		assert.Equal(t, []transition{
+			{Action: schedule.Stop, Time: "Thu 2023-02-23 18:00 CST"},
+			{Action: schedule.Stop, Time: "Fri 2023-02-24 18:00 CST"},
+		}, p.Transitions)
+		assert.Empty(t, p.Conflicts)
+	})
+
+	t.Run("no schedule", func(t *testing.T) {
+		env := statemocks.NewEnvironment(t)
+		env.EXPECT().Name().Return("my-bee")
+		env.EXPECT().OfflineSchedule().Return(nil)
+		env.EXPECT().OfflineScheduleBeginEnabled().Return(false)
+		env.EXPECT().OfflineScheduleEndEnabled().Return(false)
+
+		_, hasSchedule := previewBee(env, now, 2, schedule.DefaultApplyWindow, nil)
+		assert.False(t, hasSchedule)
+	})
+
+	t.Run("holidays", func(t *testing.T) {
+		holidays, err := schedule.NewHolidays([]schedule.Holiday{{Date: "2023-02-24", Name: "Institute Holiday"}})
+		require.NoError(t, err)
+		weekly := &schedule.Schedule{
+			TimeZone: "America/New_York",
+			Stop:     &schedule.Rule{Cron: "0 19 * * mon-fri"},
+			Start:    &schedule.Rule{Cron: "0 7 * * mon-fri"},
+		}
+
+		env := statemocks.NewEnvironment(t)
+		env.EXPECT().Name().Return("my-bee")
+		env.EXPECT().OfflineSchedule().Return(weekly)
+		env.EXPECT().StartOnHolidays().Return(false)
+
+		p, _ := previewBee(env, now, 3, schedule.DefaultApplyWindow, holidays)
+		assert.Equal(t, []transition{
+			{Action: schedule.Stop, Time: "Thu 2023-02-23 19:00 EST"},
+			{Action: schedule.Start, Time: "Fri 2023-02-24 07:00 EST", Skipped: "holiday: Institute Holiday"},
+			{Action: schedule.Stop, Time: "Fri 2023-02-24 19:00 EST"},
+		}, p.Transitions)
+
+		optOut := statemocks.NewEnvironment(t)
+		optOut.EXPECT().Name().Return("my-bee")
+		optOut.EXPECT().OfflineSchedule().Return(weekly)
+		optOut.EXPECT().StartOnHolidays().Return(true)
+
+		p, _ = previewBee(optOut, now, 3, schedule.DefaultApplyWindow, holidays)
+		assert.Empty(t, p.Transitions[1].Skipped)
+	})
+}
@@ -0,0 +1,34 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	sherlock_client "github.com/broadinstitute/thelma/internal/thelma/clients/sherlock"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const prodSherlockHostName = "sherlock.dsp-devops-prod.broadinstitute.org"
+
+// ErrDestinationForbidden is returned when a user tries to write state to production Sherlock
+var ErrDestinationForbidden = errors.Errorf("state export to production sherlock: %s is not allowed", prodSherlockHostName)
+
+// NewDestinationSherlockClient constructs a Sherlock client for writing state to the given URL. This is different
+// than the app-level Sherlock client, to support use cases such as copying state from prod to a local Sherlock
+// for debugging. Returns ErrDestinationForbidden if the URL points at production Sherlock.
+func NewDestinationSherlockClient(thelmaApp app.ThelmaApp, destinationURL string) (sherlock_client.Client, error) {
+	// check to make sure destination is not prod sherlock, this should not be allowed
+	if strings.Contains(destinationURL, prodSherlockHostName) {
+		log.Warn().Msgf("exporting to destination: %s is forbidden", prodSherlockHostName)
+		return nil, ErrDestinationForbidden
+	}
+
+	client, err := thelmaApp.Clients().Sherlock(func(options *sherlock_client.Options) {
+		options.Addr = destinationURL
+	})
+	if err != nil {
+		return nil, errors.Errorf("error building exporter sherlock client: %v", err)
+	}
+	return client, nil
+}
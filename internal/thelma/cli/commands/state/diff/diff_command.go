@@ -0,0 +1,99 @@
+package diff
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	terradiff "github.com/broadinstitute/thelma/internal/thelma/state/api/terra/diff"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Reports differences between two state snapshots, or between a snapshot and live state
+
+Snapshots are YAML or JSON files created by "thelma state export --format yaml|json".
+Added, removed, and changed clusters, environments, and releases are reported,
+including field-level changes such as chart version, app version, terra-helmfile ref,
+offline status, and start/stop schedules.
+
+Use --output-format text for a human-readable summary, or yaml/json for machine-readable output.
+
+Examples:
+
+# Compare a snapshot to live state
+thelma state diff --from state.yaml --output-format text
+
+# Compare two snapshots
+thelma state diff --from yesterday.yaml --to today.yaml --output-format json
+`
+
+type options struct {
+	from string
+	to   string
+}
+
+var flagNames = struct {
+	from string
+	to   string
+}{
+	from: "from",
+	to:   "to",
+}
+
+type diffCommand struct {
+	options *options
+}
+
+func NewStateDiffCommand() cli.ThelmaCommand {
+	return &diffCommand{
+		options: &options{},
+	}
+}
+
+func (cmd *diffCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "diff [options]"
+	cobraCommand.Short = "reports differences between state snapshots"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().StringVar(&cmd.options.from, flagNames.from, "", "Required. Path to the YAML or JSON state snapshot to compare from")
+	cobraCommand.Flags().StringVar(&cmd.options.to, flagNames.to, "", "Path to the YAML or JSON state snapshot to compare to (defaults to live state)")
+}
+
+func (cmd *diffCommand) PreRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	if cmd.options.from == "" {
+		return errors.Errorf("no snapshot specified; --%s is required", flagNames.from)
+	}
+	return nil
+}
+
+func (cmd *diffCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
+	from, err := file.NewStateLoader(cmd.options.from).Load()
+	if err != nil {
+		return errors.Errorf("error loading state snapshot %s: %v", cmd.options.from, err)
+	}
+
+	var to terra.State
+	if cmd.options.to == "" {
+		to, err = app.State()
+		if err != nil {
+			return errors.Errorf("error retrieving Thelma state: %v", err)
+		}
+	} else {
+		to, err = file.NewStateLoader(cmd.options.to).Load()
+		if err != nil {
+			return errors.Errorf("error loading state snapshot %s: %v", cmd.options.to, err)
+		}
+	}
+
+	d, err := terradiff.States(from, to)
+	if err != nil {
+		return errors.Errorf("error comparing state: %v", err)
+	}
+	ctx.SetOutput(d)
+	return nil
+}
+
+func (cmd *diffCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
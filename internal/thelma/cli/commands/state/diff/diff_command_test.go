@@ -0,0 +1,63 @@
+package diff
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/builder"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	states "github.com/broadinstitute/thelma/internal/thelma/cli/commands/state"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const snapshot = `
+clusters:
+  - name: terra-dev
+    base: terra
+    address: https://35.238.186.116
+    project: broad-dsde-dev
+environments:
+  - name: dev
+    base: live
+    lifecycle: static
+    defaultCluster: terra-dev
+releases:
+  - name: sam
+    chart: sam
+    environment: dev
+    cluster: terra-dev
+    namespace: terra-dev
+    chartVersion: 0.34.0
+    appVersion: 1.2.3
+`
+
+func Test_DiffAgainstLiveState(t *testing.T) {
+	dir := t.TempDir()
+	snapshotFile := path.Join(dir, "snapshot.yaml")
+	require.NoError(t, os.WriteFile(snapshotFile, []byte(snapshot), 0644))
+
+	liveStateFile := path.Join(dir, "live.yaml")
+	live, err := file.ParseDocument([]byte(snapshot), false)
+	require.NoError(t, err)
+	live.Releases[0].AppVersion = "1.2.4"
+	require.NoError(t, file.WriteDocument(liveStateFile, live))
+
+	var out bytes.Buffer
+	_cli := cli.New(func(options *cli.Options) {
+		options.AddCommand("state", states.NewStateCommand())
+		options.AddCommand("state diff", NewStateDiffCommand())
+		options.ConfigureThelma(func(thelmaBuilder builder.ThelmaBuilder) {
+			thelmaBuilder.WithTestDefaults(t)
+			thelmaBuilder.UseCustomStateLoader(file.NewStateLoader(liveStateFile))
+		})
+		options.SetOut(&out)
+		options.SetArgs([]string{"state", "diff", "--from", snapshotFile, "--output-format", "text"})
+	})
+	require.NoError(t, _cli.Execute())
+
+	assert.Equal(t, "~ release sam in dev\n    appVersion: 1.2.3 -> 1.2.4\n", out.String())
+}
@@ -1,30 +1,76 @@
 package export
 
 import (
-	"github.com/pkg/errors"
-	"strings"
+	"os"
 
 	"github.com/broadinstitute/thelma/internal/thelma/app"
 	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/state/common"
 	sherlock_client "github.com/broadinstitute/thelma/internal/thelma/clients/sherlock"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
 	"github.com/broadinstitute/thelma/internal/thelma/state/providers/sherlock"
+	"github.com/broadinstitute/thelma/internal/thelma/state/testing/statefixtures"
+	"github.com/broadinstitute/thelma/internal/thelma/utils"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
-const helpMessage = `Exports thelma's internal state to a destination`
-const prodSherlockHostName = "sherlock.dsp-devops-prod.broadinstitute.org"
+const helpMessage = `Exports thelma's internal state to a destination
+
+By default, state is exported to another Sherlock instance (see --destination).
+
+With --format yaml or --format json, a complete snapshot of state (clusters, environments, and
+releases) is written to a file instead, in the format understood by Thelma's "file" state provider.
+Snapshots can be loaded back into Sherlock with "thelma state import".
+
+With --format fixture, state is written in the format understood by the statefixtures test package,
+so that unit test fixtures can be regenerated from real state. Use --anonymize to replace environment
+owners and unique resource prefixes with generated values.
+
+Examples:
 
-var ErrExportDestinationForbidden = errors.Errorf("state export to production sherlock: %s is not allowed", prodSherlockHostName)
+# Export state to a local Sherlock
+thelma state export --destination http://localhost:8080
+
+# Snapshot state to a YAML file
+thelma state export --format yaml --file state.yaml
+
+# Regenerate a unit test fixture
+thelma state export --format fixture --anonymize --file testdata/statefixture.yaml
+`
+
+// ErrExportDestinationForbidden is returned when a user tries to export state to production Sherlock
+var ErrExportDestinationForbidden = common.ErrDestinationForbidden
+
+// export formats
+const (
+	sherlockFormat = "sherlock"
+	yamlFormat     = "yaml"
+	jsonFormat     = "json"
+	fixtureFormat  = "fixture"
+)
+
+var supportedFormats = []string{sherlockFormat, yamlFormat, jsonFormat, fixtureFormat}
 
 type options struct {
 	destinationURL string
+	format         string
+	file           string
+	anonymize      bool
 }
 
 var flagNames = struct {
 	destinationURL string
+	format         string
+	file           string
+	anonymize      string
 }{
 	destinationURL: "destination",
+	format:         "format",
+	file:           "file",
+	anonymize:      "anonymize",
 }
 
 type exportCommand struct {
@@ -44,36 +90,51 @@ func (cmd *exportCommand) ConfigureCobra(cobraCommand *cobra.Command) {
 	cobraCommand.Long = helpMessage
 
 	cobraCommand.Flags().StringVar(&cmd.options.destinationURL, flagNames.destinationURL, "http://localhost:8080", "destination to export state to")
+	cobraCommand.Flags().StringVar(&cmd.options.format, flagNames.format, sherlockFormat, "One of: "+utils.QuoteJoin(supportedFormats))
+	cobraCommand.Flags().StringVar(&cmd.options.file, flagNames.file, "", "File to write snapshot to, if --format is yaml, json, or fixture (defaults to stdout)")
+	cobraCommand.Flags().BoolVar(&cmd.options.anonymize, flagNames.anonymize, false, "Replace environment owners and unique resource prefixes with generated values, if --format is fixture")
 }
 
 func (cmd *exportCommand) PreRun(app app.ThelmaApp, ctx cli.RunContext) error {
-	// construct a sherlock client to export state to, this is different than the app level
-	// sherlock client to support use cases such as exporting state from prod to a local sherlock for debugging
-
-	client, err := app.Clients().Sherlock(func(options *sherlock_client.Options) {
-		options.Addr = cmd.options.destinationURL
-	})
-	if err != nil {
-		return errors.Errorf("error building exporter sherlock client")
+	if cmd.options.anonymize && cmd.options.format != fixtureFormat {
+		return errors.Errorf("--%s can only be used with --%s %s", flagNames.anonymize, flagNames.format, fixtureFormat)
 	}
-
-	// check to make sure destination is not prod sherlock, this should not be allowed
-	if strings.Contains(cmd.options.destinationURL, prodSherlockHostName) {
-		log.Warn().Msgf("exporting to destination: %s is forbidden", prodSherlockHostName)
-		return ErrExportDestinationForbidden
+	switch cmd.options.format {
+	case sherlockFormat:
+		if ctx.CobraCommand().Flags().Changed(flagNames.file) {
+			return errors.Errorf("--%s can't be used with --%s %s", flagNames.file, flagNames.format, sherlockFormat)
+		}
+	case yamlFormat, jsonFormat, fixtureFormat:
+		if ctx.CobraCommand().Flags().Changed(flagNames.destinationURL) {
+			return errors.Errorf("--%s can't be used with --%s %s", flagNames.destinationURL, flagNames.format, cmd.options.format)
+		}
+		return nil
+	default:
+		return errors.Errorf("--%s must be one of %s; got %q", flagNames.format, utils.QuoteJoin(supportedFormats), cmd.options.format)
 	}
 
+	client, err := common.NewDestinationSherlockClient(app, cmd.options.destinationURL)
+	if err != nil {
+		return err
+	}
 	cmd.sherlockClient = client
 	return nil
 }
 
 func (cmd *exportCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
-	log.Info().Msgf("exporting state to: %s", cmd.options.destinationURL)
 	state, err := app.State()
 	if err != nil {
 		return errors.Errorf("error retrieving Thelma state: %v", err)
 	}
 
+	if cmd.options.format == fixtureFormat {
+		return cmd.writeFixture(state)
+	}
+	if cmd.options.format != sherlockFormat {
+		return cmd.writeSnapshot(state)
+	}
+
+	log.Info().Msgf("exporting state to: %s", cmd.options.destinationURL)
 	stateExporter := sherlock.NewSherlockStateWriter(state, cmd.sherlockClient)
 
 	if err := stateExporter.WriteClusters(); err != nil {
@@ -90,3 +151,42 @@ func (cmd *exportCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
 func (cmd *exportCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
 	return nil
 }
+
+func (cmd *exportCommand) writeSnapshot(state terra.State) error {
+	doc, err := file.NewDocument(state)
+	if err != nil {
+		return errors.Errorf("error building state snapshot: %v", err)
+	}
+	content, err := file.MarshalDocument(doc, cmd.options.format == jsonFormat)
+	if err != nil {
+		return errors.Errorf("error serializing state snapshot: %v", err)
+	}
+	return cmd.writeOutput(content, len(doc.Clusters), len(doc.Environments), len(doc.Releases))
+}
+
+func (cmd *exportCommand) writeFixture(state terra.State) error {
+	data, err := statefixtures.FromState(state, func(options *statefixtures.ExportOptions) {
+		options.Anonymize = cmd.options.anonymize
+	})
+	if err != nil {
+		return errors.Errorf("error building state fixture: %v", err)
+	}
+	content, err := data.Marshal()
+	if err != nil {
+		return errors.Errorf("error serializing state fixture: %v", err)
+	}
+	return cmd.writeOutput(content, len(data.Clusters), len(data.Environments), len(data.Releases))
+}
+
+func (cmd *exportCommand) writeOutput(content []byte, clusters int, environments int, releases int) error {
+	if cmd.options.file == "" {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+
+	if err := os.WriteFile(cmd.options.file, content, 0644); err != nil {
+		return errors.Errorf("error writing %s to %s: %v", cmd.options.format, cmd.options.file, err)
+	}
+	log.Info().Msgf("Wrote %d clusters, %d environments, and %d releases to %s (format: %s)", clusters, environments, releases, cmd.options.file, cmd.options.format)
+	return nil
+}
@@ -0,0 +1,101 @@
+package export
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/builder"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	states "github.com/broadinstitute/thelma/internal/thelma/cli/commands/state"
+	_import "github.com/broadinstitute/thelma/internal/thelma/cli/commands/state/import"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/broadinstitute/thelma/internal/thelma/state/testing/statefixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testState = `
+clusters:
+  - name: terra-dev
+    base: terra
+    address: https://35.238.186.116
+    project: broad-dsde-dev
+environments:
+  - name: dev
+    base: live
+    lifecycle: static
+    defaultCluster: terra-dev
+releases:
+  - name: sam
+    chart: sam
+    environment: dev
+    cluster: terra-dev
+    namespace: terra-dev
+    chartVersion: 0.34.0
+    appVersion: 1.2.3
+`
+
+func runStateCommand(t *testing.T, stateFile string, args ...string) error {
+	_cli := cli.New(func(options *cli.Options) {
+		options.AddCommand("state", states.NewStateCommand())
+		options.AddCommand("state export", NewStateExportCommand())
+		options.AddCommand("state import", _import.NewStateImportCommand())
+		options.ConfigureThelma(func(thelmaBuilder builder.ThelmaBuilder) {
+			thelmaBuilder.WithTestDefaults(t)
+			thelmaBuilder.UseCustomStateLoader(file.NewStateLoader(stateFile))
+		})
+		options.SetArgs(args)
+	})
+	return _cli.Execute()
+}
+
+func Test_ExportAndImportSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	stateFile := path.Join(dir, "state.yaml")
+	require.NoError(t, os.WriteFile(stateFile, []byte(testState), 0644))
+
+	snapshotFile := path.Join(dir, "snapshot.json")
+	require.NoError(t, runStateCommand(t, stateFile, "state", "export", "--format", "json", "--file", snapshotFile))
+
+	snapshot, err := file.ReadDocument(snapshotFile)
+	require.NoError(t, err)
+	assert.Equal(t, file.DocumentVersion, snapshot.Version)
+	assert.Len(t, snapshot.Clusters, 1)
+	assert.Len(t, snapshot.Environments, 1)
+	require.Len(t, snapshot.Releases, 1)
+	assert.Equal(t, "1.2.3", snapshot.Releases[0].AppVersion)
+
+	importedFile := path.Join(dir, "imported.yaml")
+	require.NoError(t, runStateCommand(t, stateFile, "state", "import", "--file", snapshotFile, "--destination-file", importedFile))
+
+	imported, err := file.ReadDocument(importedFile)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.Clusters, imported.Clusters)
+	assert.Equal(t, snapshot.Environments, imported.Environments)
+	assert.Equal(t, snapshot.Releases, imported.Releases)
+}
+
+func Test_ExportFixture(t *testing.T) {
+	dir := t.TempDir()
+	stateFile := path.Join(dir, "state.yaml")
+	require.NoError(t, os.WriteFile(stateFile, []byte(testState), 0644))
+
+	fixtureFile := path.Join(dir, "fixture.yaml")
+	require.NoError(t, runStateCommand(t, stateFile, "state", "export", "--format", "fixture", "--anonymize", "--file", fixtureFile))
+
+	fixture, err := statefixtures.LoadFixtureFromFile(fixtureFile)
+	require.NoError(t, err)
+	assert.Equal(t, "terra-dev", fixture.Environment("dev").DefaultCluster().Name())
+	assert.Equal(t, "1.2.3", fixture.Release("sam", "dev").AppVersion())
+}
+
+func Test_ExportFlagValidation(t *testing.T) {
+	stateFile := path.Join(t.TempDir(), "state.yaml")
+	require.NoError(t, os.WriteFile(stateFile, []byte(testState), 0644))
+
+	assert.ErrorContains(t, runStateCommand(t, stateFile, "state", "export", "--format", "xml"), "--format must be one of")
+	assert.ErrorContains(t, runStateCommand(t, stateFile, "state", "export", "--file", "out.yaml"), "--file can't be used with --format sherlock")
+	assert.ErrorContains(t, runStateCommand(t, stateFile, "state", "export", "--format", "yaml", "--anonymize"), "--anonymize can only be used with --format fixture")
+	assert.ErrorContains(t, runStateCommand(t, stateFile, "state", "export", "--destination", "https://sherlock.dsp-devops-prod.broadinstitute.org"), ErrExportDestinationForbidden.Error())
+}
@@ -0,0 +1,107 @@
+package _import
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/state/common"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/sherlock"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Imports a state snapshot created by "thelma state export --format yaml|json"
+
+The snapshot's clusters, environments, and releases are replayed into a local Sherlock
+(see --destination) or into another state file (see --destination-file).
+
+Examples:
+
+# Seed a local Sherlock from a snapshot
+thelma state import --file state.yaml --destination http://localhost:8080
+
+# Merge a snapshot into a state file used by the "file" state provider
+thelma state import --file state.yaml --destination-file ~/my-state.yaml
+`
+
+type options struct {
+	file            string
+	destinationURL  string
+	destinationFile string
+}
+
+var flagNames = struct {
+	file            string
+	destinationURL  string
+	destinationFile string
+}{
+	file:            "file",
+	destinationURL:  "destination",
+	destinationFile: "destination-file",
+}
+
+type importCommand struct {
+	options     *options
+	stateWriter terra.StateWriter
+}
+
+func NewStateImportCommand() cli.ThelmaCommand {
+	return &importCommand{
+		options: &options{},
+	}
+}
+
+func (cmd *importCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "import [options]"
+	cobraCommand.Short = "imports a state snapshot"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().StringVar(&cmd.options.file, flagNames.file, "", "Required. Path to a YAML or JSON state snapshot")
+	cobraCommand.Flags().StringVar(&cmd.options.destinationURL, flagNames.destinationURL, "http://localhost:8080", "Sherlock instance to import state into")
+	cobraCommand.Flags().StringVar(&cmd.options.destinationFile, flagNames.destinationFile, "", "Import state into this state file instead of Sherlock")
+	cobraCommand.MarkFlagsMutuallyExclusive(flagNames.destinationURL, flagNames.destinationFile)
+}
+
+func (cmd *importCommand) PreRun(app app.ThelmaApp, ctx cli.RunContext) error {
+	if cmd.options.file == "" {
+		return errors.Errorf("no snapshot specified; --%s is required", flagNames.file)
+	}
+
+	if cmd.options.destinationFile != "" {
+		cmd.stateWriter = file.NewStateWriter(cmd.options.destinationFile)
+		return nil
+	}
+
+	client, err := common.NewDestinationSherlockClient(app, cmd.options.destinationURL)
+	if err != nil {
+		return err
+	}
+	cmd.stateWriter = client
+	return nil
+}
+
+func (cmd *importCommand) Run(_ app.ThelmaApp, _ cli.RunContext) error {
+	snapshot, err := file.NewStateLoader(cmd.options.file).Load()
+	if err != nil {
+		return errors.Errorf("error loading state snapshot: %v", err)
+	}
+
+	log.Info().Msgf("importing state from %s", cmd.options.file)
+	stateImporter := sherlock.NewSherlockStateWriter(snapshot, cmd.stateWriter)
+
+	if err = stateImporter.WriteClusters(); err != nil {
+		return errors.Errorf("error importing clusters: %v", err)
+	}
+
+	if err = stateImporter.WriteEnvironments(); err != nil {
+		return errors.Errorf("error importing environments: %v", err)
+	}
+
+	return nil
+}
+
+func (cmd *importCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	terralint "github.com/broadinstitute/thelma/internal/thelma/state/api/terra/lint"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Checks Thelma state for invariant violations
+
+Reports problems such as invalid environment names, duplicate unique resource prefixes,
+dynamic environments whose template no longer exists, clusters without an artifact bucket,
+releases that reference unknown clusters, and start/stop schedules that are closer together
+than the apply-schedule window.
+
+Each violation has a severity ("error" or "warning"). The command exits non-zero if any
+violations at or above the --fail-on severity are found, so it can be used to gate CI.
+
+Examples:
+
+# Lint live state, printing a human-readable summary
+thelma state lint --output-format text
+
+# Lint live state in CI, failing on warnings as well as errors
+thelma state lint --fail-on warning --output-format json
+`
+
+// failOnNone disables failing on violations
+const failOnNone = "none"
+
+type options struct {
+	failOn         string
+	scheduleWindow time.Duration
+}
+
+var flagNames = struct {
+	failOn         string
+	scheduleWindow string
+}{
+	failOn:         "fail-on",
+	scheduleWindow: "schedule-window",
+}
+
+type lintCommand struct {
+	options *options
+}
+
+func NewStateLintCommand() cli.ThelmaCommand {
+	return &lintCommand{
+		options: &options{},
+	}
+}
+
+func (cmd *lintCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "lint [options]"
+	cobraCommand.Short = "checks state for invariant violations"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().StringVar(&cmd.options.failOn, flagNames.failOn, string(terralint.Error), `Exit non-zero if violations at or above this severity are found ("error", "warning", or "none")`)
+	cobraCommand.Flags().DurationVar(&cmd.options.scheduleWindow, flagNames.scheduleWindow, terralint.DefaultScheduleWindow, "Warn about environments with stop and start schedules closer together than this")
+}
+
+func (cmd *lintCommand) PreRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	if cmd.options.failOn == failOnNone {
+		return nil
+	}
+	if _, err := terralint.ParseSeverity(cmd.options.failOn); err != nil {
+		return errors.Errorf("--%s: %v", flagNames.failOn, err)
+	}
+	return nil
+}
+
+func (cmd *lintCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
+	state, err := app.State()
+	if err != nil {
+		return errors.Errorf("error retrieving Thelma state: %v", err)
+	}
+
+	report, err := terralint.State(state, func(options *terralint.Options) {
+		options.ScheduleWindow = cmd.options.scheduleWindow
+	})
+	if err != nil {
+		return errors.Errorf("error linting state: %v", err)
+	}
+	ctx.SetOutput(report)
+
+	if cmd.options.failOn == failOnNone {
+		return nil
+	}
+	severity, err := terralint.ParseSeverity(cmd.options.failOn)
+	if err != nil {
+		return err
+	}
+	if count := report.Count(severity); count > 0 {
+		return errors.Errorf("found %d violation(s) with severity %s or higher", count, severity)
+	}
+	return nil
+}
+
+func (cmd *lintCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
@@ -0,0 +1,101 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/builder"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	states "github.com/broadinstitute/thelma/internal/thelma/cli/commands/state"
+	terralint "github.com/broadinstitute/thelma/internal/thelma/state/api/terra/lint"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const stateWithWarning = `
+clusters:
+  - name: terra-dev
+    base: terra
+    address: https://35.238.186.116
+    project: broad-dsde-dev
+environments:
+  - name: dev
+    base: live
+    lifecycle: static
+    defaultCluster: terra-dev
+    offlineSchedule:
+      begin:
+        enabled: true
+        time: 2023-01-01T20:00:00Z
+      end:
+        enabled: true
+        time: 2023-01-01T20:10:00Z
+`
+
+func Test_Lint(t *testing.T) {
+	testCases := []struct {
+		name        string
+		args        []string
+		expectErr   string
+		expectCount int
+	}{
+		{
+			name:        "warnings do not fail by default",
+			args:        []string{},
+			expectCount: 1,
+		},
+		{
+			name:        "fail on warning",
+			args:        []string{"--fail-on", "warning"},
+			expectErr:   "found 1 violation(s) with severity warning or higher",
+			expectCount: 1,
+		},
+		{
+			name:        "smaller schedule window",
+			args:        []string{"--fail-on", "warning", "--schedule-window", "5m"},
+			expectCount: 0,
+		},
+		{
+			name:      "invalid severity",
+			args:      []string{"--fail-on", "fatal"},
+			expectErr: `--fail-on: unknown severity "fatal"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stateFile := path.Join(t.TempDir(), "state.yaml")
+			require.NoError(t, os.WriteFile(stateFile, []byte(stateWithWarning), 0644))
+
+			var out bytes.Buffer
+			_cli := cli.New(func(options *cli.Options) {
+				options.AddCommand("state", states.NewStateCommand())
+				options.AddCommand("state lint", NewStateLintCommand())
+				options.ConfigureThelma(func(thelmaBuilder builder.ThelmaBuilder) {
+					thelmaBuilder.WithTestDefaults(t)
+					thelmaBuilder.UseCustomStateLoader(file.NewStateLoader(stateFile))
+				})
+				options.SetOut(&out)
+				options.SetArgs(append([]string{"state", "lint", "--output-format", "json"}, tc.args...))
+			})
+			err := _cli.Execute()
+			if tc.expectErr != "" {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, tc.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			if out.Len() == 0 {
+				return
+			}
+
+			var report terralint.Report
+			require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+			assert.Len(t, report.Violations, tc.expectCount)
+		})
+	}
+}
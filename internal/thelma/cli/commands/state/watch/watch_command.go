@@ -0,0 +1,129 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/watch"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const helpMessage = `Watches Thelma state for changes and emits an event for each one
+
+State is reloaded every --interval and compared to the previous snapshot. Changes are
+emitted as typed events, such as EnvironmentCreated, EnvironmentOfflineChanged,
+and ReleaseVersionChanged, either as JSON lines on stdout or as JSON POSTs to a --webhook.
+
+Failed reloads and deliveries are retried with exponential backoff, up to --max-backoff.
+Up to --max-pending undelivered events are held for retry; beyond that, the oldest are dropped.
+
+Examples:
+
+# Print events as they happen
+thelma state watch
+
+# Send events to a webhook, polling every 5 minutes
+thelma state watch --interval 5m --webhook https://example.com/hooks/thelma
+
+# Watch for an hour, then exit
+thelma state watch --duration 1h
+`
+
+// webhookTimeout is the timeout for a single webhook request
+const webhookTimeout = 30 * time.Second
+
+type options struct {
+	interval   time.Duration
+	maxBackoff time.Duration
+	maxPending int
+	webhook    string
+	duration   time.Duration
+}
+
+var flagNames = struct {
+	interval   string
+	maxBackoff string
+	maxPending string
+	webhook    string
+	duration   string
+}{
+	interval:   "interval",
+	maxBackoff: "max-backoff",
+	maxPending: "max-pending",
+	webhook:    "webhook",
+	duration:   "duration",
+}
+
+type watchCommand struct {
+	options *options
+}
+
+func NewStateWatchCommand() cli.ThelmaCommand {
+	return &watchCommand{
+		options: &options{},
+	}
+}
+
+func (cmd *watchCommand) ConfigureCobra(cobraCommand *cobra.Command) {
+	cobraCommand.Use = "watch [options]"
+	cobraCommand.Short = "emits events when state changes"
+	cobraCommand.Long = helpMessage
+
+	cobraCommand.Flags().DurationVar(&cmd.options.interval, flagNames.interval, time.Minute, "How often to poll for state changes")
+	cobraCommand.Flags().DurationVar(&cmd.options.maxBackoff, flagNames.maxBackoff, 10*time.Minute, "Maximum poll interval after failed reloads or webhook deliveries")
+	cobraCommand.Flags().IntVar(&cmd.options.maxPending, flagNames.maxPending, 1000, "Maximum number of undelivered events to hold for retry before dropping the oldest")
+	cobraCommand.Flags().StringVar(&cmd.options.webhook, flagNames.webhook, "", "URL to POST events to as JSON (defaults to writing JSON lines to stdout)")
+	cobraCommand.Flags().DurationVar(&cmd.options.duration, flagNames.duration, 0, "Stop watching after this long (defaults to watching until interrupted)")
+}
+
+func (cmd *watchCommand) PreRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	if cmd.options.interval <= 0 {
+		return errors.Errorf("--%s must be greater than zero", flagNames.interval)
+	}
+	if cmd.options.maxBackoff < cmd.options.interval {
+		return errors.Errorf("--%s must be greater than or equal to --%s", flagNames.maxBackoff, flagNames.interval)
+	}
+	if cmd.options.maxPending <= 0 {
+		return errors.Errorf("--%s must be greater than zero", flagNames.maxPending)
+	}
+	return nil
+}
+
+func (cmd *watchCommand) Run(app app.ThelmaApp, ctx cli.RunContext) error {
+	stateLoader, err := app.StateLoader()
+	if err != nil {
+		return errors.Errorf("error retrieving Thelma state loader: %v", err)
+	}
+
+	var sink watch.Sink
+	if cmd.options.webhook != "" {
+		sink = watch.NewWebhookSink(cmd.options.webhook, webhookTimeout)
+	} else {
+		sink = watch.NewJSONLinesSink(ctx.CobraCommand().OutOrStdout())
+	}
+
+	watchCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if cmd.options.duration > 0 {
+		var cancel context.CancelFunc
+		watchCtx, cancel = context.WithTimeout(watchCtx, cmd.options.duration)
+		defer cancel()
+	}
+
+	watcher := watch.New(stateLoader, sink, func(options *watch.Options) {
+		options.Interval = cmd.options.interval
+		options.MaxBackoff = cmd.options.maxBackoff
+		options.MaxPending = cmd.options.maxPending
+	})
+	return watcher.Watch(watchCtx)
+}
+
+func (cmd *watchCommand) PostRun(_ app.ThelmaApp, _ cli.RunContext) error {
+	return nil
+}
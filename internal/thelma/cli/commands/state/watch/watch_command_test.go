@@ -0,0 +1,48 @@
+package watch
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/builder"
+	"github.com/broadinstitute/thelma/internal/thelma/cli"
+	states "github.com/broadinstitute/thelma/internal/thelma/cli/commands/state"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const emptyState = `
+clusters: []
+environments: []
+releases: []
+`
+
+func Test_WatchValidatesFlags(t *testing.T) {
+	err := runWatch(t, "--interval", "5m", "--max-backoff", "1m")
+	assert.ErrorContains(t, err, "--max-backoff must be greater than or equal to --interval")
+}
+
+func Test_WatchStopsAfterDuration(t *testing.T) {
+	require.NoError(t, runWatch(t, "--interval", "1ms", "--max-backoff", "1ms", "--duration", "20ms"))
+}
+
+func runWatch(t *testing.T, args ...string) error {
+	stateFile := path.Join(t.TempDir(), "state.yaml")
+	require.NoError(t, os.WriteFile(stateFile, []byte(emptyState), 0644))
+
+	var out bytes.Buffer
+	_cli := cli.New(func(options *cli.Options) {
+		options.AddCommand("state", states.NewStateCommand())
+		options.AddCommand("state watch", NewStateWatchCommand())
+		options.ConfigureThelma(func(thelmaBuilder builder.ThelmaBuilder) {
+			thelmaBuilder.WithTestDefaults(t)
+			thelmaBuilder.UseCustomStateLoader(file.NewStateLoader(stateFile))
+		})
+		options.SetOut(&out)
+		options.SetArgs(append([]string{"state", "watch"}, args...))
+	})
+	return _cli.Execute()
+}
@@ -16,15 +16,25 @@ import (
 	auth_vault "github.com/broadinstitute/thelma/internal/thelma/cli/commands/auth/vault"
 
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee"
+	bee_apply "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/apply"
+	bee_clone "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/clone"
 	bee_create "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/create"
 	bee_delete "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/delete"
 	bee_describe "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/describe"
+	bee_diff "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/diff"
+	bee_extend "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/extend"
 	bee_list "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/list"
 	bee_pin "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/pin"
 	bee_provision "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/provision"
 	bee_reset "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/reset"
+	bee_schedule "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/schedule"
+	bee_schedule_set "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/schedule/set"
 	bee_seed "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/seed/seed"
 	bee_unseed "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/seed/unseed"
+	bee_snapshot "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/snapshot"
+	bee_snapshot_create "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/snapshot/create"
+	bee_snapshot_list "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/snapshot/list"
+	bee_snapshot_restore "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/snapshot/restore"
 	bee_start "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/start"
 	bee_stop "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/stop"
 	bee_sync "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bee/sync"
@@ -34,6 +44,11 @@ import (
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/bees"
 	bees_apply_schedule "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bees/apply_schedule"
 	bees_delete "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bees/delete"
+	bees_doctor "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bees/doctor"
+	bees_gc "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bees/gc"
+	bees_notify_expiring "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bees/notify_expiring"
+	bees_schedule "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bees/schedule"
+	bees_schedule_preview "github.com/broadinstitute/thelma/internal/thelma/cli/commands/bees/schedule/preview"
 
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/charts"
 	charts_deploy "github.com/broadinstitute/thelma/internal/thelma/cli/commands/charts/deploy"
@@ -50,7 +65,11 @@ import (
 	sql_proxy "github.com/broadinstitute/thelma/internal/thelma/cli/commands/sql/proxy"
 
 	states "github.com/broadinstitute/thelma/internal/thelma/cli/commands/state"
+	state_diff "github.com/broadinstitute/thelma/internal/thelma/cli/commands/state/diff"
 	state_export "github.com/broadinstitute/thelma/internal/thelma/cli/commands/state/export"
+	state_import "github.com/broadinstitute/thelma/internal/thelma/cli/commands/state/import"
+	state_lint "github.com/broadinstitute/thelma/internal/thelma/cli/commands/state/lint"
+	state_watch "github.com/broadinstitute/thelma/internal/thelma/cli/commands/state/watch"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/status"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/commands/version"
 )
@@ -76,14 +95,24 @@ func withCommands(opts *cli.Options) {
 	opts.AddCommand("auth vault", auth_vault.NewAuthVaultCommand())
 
 	opts.AddCommand("bee", bee.NewBeeCommand())
+	opts.AddCommand("bee apply", bee_apply.NewBeeApplyCommand())
+	opts.AddCommand("bee clone", bee_clone.NewBeeCloneCommand())
 	opts.AddCommand("bee create", bee_create.NewBeeCreateCommand())
 	opts.AddCommand("bee provision", bee_provision.NewBeeProvisionCommand())
 	opts.AddCommand("bee delete", bee_delete.NewBeeDeleteCommand())
 	opts.AddCommand("bee describe", bee_describe.NewBeeDescribeCommand())
+	opts.AddCommand("bee diff", bee_diff.NewBeeDiffCommand())
+	opts.AddCommand("bee extend", bee_extend.NewBeeExtendCommand())
 	opts.AddCommand("bee list", bee_list.NewBeeListCommand())
 	opts.AddCommand("bee pin", bee_pin.NewBeePinCommand())
 	opts.AddCommand("bee reset", bee_reset.NewBeeResetCommand())
+	opts.AddCommand("bee schedule", bee_schedule.NewBeeScheduleCommand())
+	opts.AddCommand("bee schedule set", bee_schedule_set.NewBeeScheduleSetCommand())
 	opts.AddCommand("bee seed", bee_seed.NewBeeSeedCommand())
+	opts.AddCommand("bee snapshot", bee_snapshot.NewBeeSnapshotCommand())
+	opts.AddCommand("bee snapshot create", bee_snapshot_create.NewBeeSnapshotCreateCommand())
+	opts.AddCommand("bee snapshot list", bee_snapshot_list.NewBeeSnapshotListCommand())
+	opts.AddCommand("bee snapshot restore", bee_snapshot_restore.NewBeeSnapshotRestoreCommand())
 	opts.AddCommand("bee start", bee_start.NewBeeStartCommand())
 	opts.AddCommand("bee stop", bee_stop.NewBeeStopCommand())
 	opts.AddCommand("bee sync", bee_sync.NewBeeSyncCommand())
@@ -94,6 +123,11 @@ func withCommands(opts *cli.Options) {
 	opts.AddCommand("bees", bees.NewBeesCommand())
 	opts.AddCommand("bees delete", bees_delete.NewBeesDeleteCommand())
 	opts.AddCommand("bees apply-schedule", bees_apply_schedule.NewBeesApplyScheduleCommand())
+	opts.AddCommand("bees gc", bees_gc.NewBeesGCCommand())
+	opts.AddCommand("bees doctor", bees_doctor.NewBeesDoctorCommand())
+	opts.AddCommand("bees notify-expiring", bees_notify_expiring.NewBeesNotifyExpiringCommand())
+	opts.AddCommand("bees schedule", bees_schedule.NewBeesScheduleCommand())
+	opts.AddCommand("bees schedule preview", bees_schedule_preview.NewBeesSchedulePreviewCommand())
 
 	opts.AddCommand("charts", charts.NewChartsCommand())
 	opts.AddCommand("charts import", charts_import.NewChartsImportCommand())
@@ -115,7 +149,11 @@ func withCommands(opts *cli.Options) {
 	opts.AddCommand("sql proxy", sql_proxy.NewSqlProxyCommand())
 
 	opts.AddCommand("state", states.NewStateCommand())
+	opts.AddCommand("state diff", state_diff.NewStateDiffCommand())
 	opts.AddCommand("state export", state_export.NewStateExportCommand())
+	opts.AddCommand("state import", state_import.NewStateImportCommand())
+	opts.AddCommand("state lint", state_lint.NewStateLintCommand())
+	opts.AddCommand("state watch", state_watch.NewStateWatchCommand())
 
 	opts.AddCommand("status", status.NewStatusCommand())
 
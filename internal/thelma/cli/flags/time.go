@@ -0,0 +1,50 @@
+package flags
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"time"
+)
+
+// dateFormat is accepted by time flags in addition to RFC3339, and is interpreted as midnight UTC
+const dateFormat = "2006-01-02"
+
+// TimeVar defines a time.Time flag with specified name and usage string. Values may be supplied in RFC3339 format
+// (eg. "2023-01-02T15:04:05Z") or as a date (eg. "2023-01-02", meaning midnight UTC).
+func TimeVar(flags *pflag.FlagSet, p *time.Time, name string, usage string) {
+	flags.Var((*timeValue)(p), name, usage)
+}
+
+// timeValue implements pflag.Value for time.Time
+type timeValue time.Time
+
+func (t *timeValue) String() string {
+	if time.Time(*t).IsZero() {
+		return ""
+	}
+	return time.Time(*t).Format(time.RFC3339)
+}
+
+func (t *timeValue) Set(s string) error {
+	parsed, err := ParseTime(s)
+	if err != nil {
+		return err
+	}
+	*t = timeValue(parsed)
+	return nil
+}
+
+func (t *timeValue) Type() string {
+	return "time"
+}
+
+// ParseTime parses a time in RFC3339 or YYYY-MM-DD format
+func ParseTime(s string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, s); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse(dateFormat, s); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, errors.Errorf("invalid time %q: expected RFC3339 (eg. 2006-01-02T15:04:05Z) or date (eg. 2006-01-02)", s)
+}
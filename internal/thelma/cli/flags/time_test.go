@@ -0,0 +1,51 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_TimeVar(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    time.Time
+		expectedErr string
+	}{
+		{
+			input:    "2023-01-02T15:04:05Z",
+			expected: time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "2023-01-02T10:04:05-05:00",
+			expected: time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			input:    "2023-01-02",
+			expected: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			input:       "yesterday",
+			expectedErr: `invalid time "yesterday"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			var value time.Time
+			flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			TimeVar(flagSet, &value, "since", "a time")
+
+			err := flagSet.Parse([]string{"--since", tc.input})
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tc.expected.Equal(value), "expected %s, got %s", tc.expected, value)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package format
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 	"io"
@@ -35,6 +36,9 @@ const (
 	None
 	// PrettyYaml format prints output in colored YAML
 	PrettyYaml
+	// Text format prints output in a human-readable form, for data that implements fmt.Stringer.
+	// Other data is printed in YAML.
+	Text
 )
 
 // Format will write formatted data to the given writer
@@ -58,6 +62,9 @@ func (f *Format) FromString(value string) error {
 	case "none":
 		*f = None
 		return nil
+	case "text":
+		*f = Text
+		return nil
 	}
 	return errors.Errorf("unknown format: %q", value)
 }
@@ -73,6 +80,8 @@ func (f Format) String() string {
 		return "none"
 	case PrettyYaml:
 		return "pretty-yaml"
+	case Text:
+		return "text"
 	}
 	return "unknown"
 }
@@ -86,6 +95,7 @@ var formats = map[Format]formatFn{
 	PrettyYaml: formatPrettyYaml,
 	Json:       formatJson,
 	None:       formatNone,
+	Text:       formatText,
 }
 
 func formatJson(data interface{}, w io.Writer) error {
@@ -103,3 +113,16 @@ func formatYaml(data interface{}, w io.Writer) error {
 func formatNone(_ interface{}, w io.Writer) error {
 	return nil
 }
+
+func formatText(data interface{}, w io.Writer) error {
+	stringer, ok := data.(fmt.Stringer)
+	if !ok {
+		return formatYaml(data, w)
+	}
+	text := stringer.String()
+	if len(text) > 0 && text[len(text)-1] != '\n' {
+		text += "\n"
+	}
+	_, err := io.WriteString(w, text)
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 )
 
@@ -34,6 +35,11 @@ func TestFormat(t *testing.T) {
 			format:   None,
 			expected: "",
 		},
+		{
+			name:     "text falls back to yaml",
+			format:   Text,
+			expected: "foo: bar\n",
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -54,3 +60,15 @@ func TestIsSupported(t *testing.T) {
 	assert.True(t, IsSupported("yaml"))
 	assert.False(t, IsSupported("foo"))
 }
+
+type stringerData struct{}
+
+func (stringerData) String() string {
+	return strings.Join([]string{"foo", "bar"}, "\n")
+}
+
+func TestText(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, Text.Format(stringerData{}, &b))
+	assert.Equal(t, "foo\nbar\n", b.String())
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/broadinstitute/thelma/internal/thelma/cli/environmentflags"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/printing"
 	"github.com/broadinstitute/thelma/internal/thelma/cli/printing/format"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"os"
@@ -18,9 +19,13 @@ const thelmaCommandName = name.Name
 // globalUsage common usage string printed for all subcommands
 const globalUsage = `CLI tools for interacting with Terra's Helm charts`
 
+// refreshStateFlagName is the name of the global flag that forces Thelma to reload state instead of using its cache
+const refreshStateFlagName = "refresh-state"
+
 // rootCommand is the root command for Thelma. It configures global flags and their related features.
 type rootCommand struct {
-	printer printing.Printer
+	printer      printing.Printer
+	refreshState bool
 }
 
 func newRootCommand() ThelmaCommand {
@@ -43,14 +48,27 @@ func (r *rootCommand) ConfigureCobra(cobraCommand *cobra.Command) {
 	// Add flag for control if other flags should be read from the environment (e.g. "--flags-from-environment-prefix=PARAM_")
 	// See execution.setFlagsFromEnvironment for where this option gets picked up (and why there, rather than in PreRun here)
 	environmentflags.AddFlag(cobraCommand.PersistentFlags())
+
+	// Add flag for bypassing the on-disk state cache (e.g. "--refresh-state")
+	cobraCommand.PersistentFlags().BoolVar(&r.refreshState, refreshStateFlagName, false, "Reload state from Sherlock instead of using cached state")
 }
 
-func (r *rootCommand) PreRun(_ app.ThelmaApp, _ RunContext) error {
+func (r *rootCommand) PreRun(thelmaApp app.ThelmaApp, _ RunContext) error {
 	log.Debug().Strs("argv", os.Args).Msgf("Starting new thelma run")
 	// check that output format flags were used correctly
 	if err := r.printer.VerifyFlags(); err != nil {
 		return err
 	}
+	if r.refreshState {
+		// reload state now, so that subsequent calls to app.State() return fresh state instead of cached state
+		stateLoader, err := thelmaApp.StateLoader()
+		if err != nil {
+			return err
+		}
+		if _, err = stateLoader.Reload(); err != nil {
+			return errors.Errorf("--%s: error reloading state: %v", refreshStateFlagName, err)
+		}
+	}
 	return nil
 }
 
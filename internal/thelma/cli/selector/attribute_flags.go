@@ -0,0 +1,144 @@
+package selector
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/charts/semver"
+	"github.com/broadinstitute/thelma/internal/thelma/cli/flags"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/filter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// releaseAttributeFlags processes flags that filter releases by attributes like chart version and app version
+type releaseAttributeFlags struct {
+	chartVersion     string
+	appVersion       string
+	terraHelmfileRef []string
+}
+
+func newReleaseAttributeFlags() *releaseAttributeFlags {
+	return &releaseAttributeFlags{}
+}
+
+func releaseAttributeFlagNames() []string {
+	return []string{flagNames.releaseType, flagNames.chartVersion, flagNames.appVersion, flagNames.terraHelmfileRef}
+}
+
+func (r *releaseAttributeFlags) addToCobraCommand(cobraCommand *cobra.Command) {
+	cobraCommand.Flags().StringVar(&r.chartVersion, flagNames.chartVersion, "", `Only include releases with a chart version in the given semver range (eg. ">=0.34.0 <1.0.0", "^0.34.0")`)
+	cobraCommand.Flags().StringVar(&r.appVersion, flagNames.appVersion, "", `Only include releases with an app version in the given semver range (eg. "~1.2.0")`)
+	cobraCommand.Flags().StringSliceVar(&r.terraHelmfileRef, flagNames.terraHelmfileRef, []string{}, "Only include releases pinned to the given terra-helmfile ref(s)")
+}
+
+func (r *releaseAttributeFlags) processInput(f *filterBuilder, _ terra.State, _ []string, pflags *pflag.FlagSet) error {
+	if pflags.Changed(flagNames.chartVersion) {
+		versionRange, err := semver.ParseRange(r.chartVersion)
+		if err != nil {
+			return errors.Errorf("--%s: %v", flagNames.chartVersion, err)
+		}
+		f.addAttributeFilter(filter.Releases().ChartVersionInRange(versionRange))
+	}
+	if pflags.Changed(flagNames.appVersion) {
+		versionRange, err := semver.ParseRange(r.appVersion)
+		if err != nil {
+			return errors.Errorf("--%s: %v", flagNames.appVersion, err)
+		}
+		f.addAttributeFilter(filter.Releases().AppVersionInRange(versionRange))
+	}
+	if pflags.Changed(flagNames.terraHelmfileRef) {
+		refs := collateSelectorValues(r.terraHelmfileRef)
+		if refs.Empty() {
+			return errors.Errorf("--%s: at least one ref must be specified", flagNames.terraHelmfileRef)
+		}
+		f.addAttributeFilter(filter.Releases().HasTerraHelmfileRef(refs.Elements()...))
+	}
+	return nil
+}
+
+// environmentAttributeFlags processes flags that filter releases by attributes of their environment, like owner
+// and creation time
+type environmentAttributeFlags struct {
+	owner            []string
+	createdBefore    time.Time
+	createdAfter     time.Time
+	offline          bool
+	autoDeleteWithin time.Duration
+	hasStartSchedule bool
+	hasStopSchedule  bool
+}
+
+func newEnvironmentAttributeFlags() *environmentAttributeFlags {
+	return &environmentAttributeFlags{}
+}
+
+func environmentAttributeFlagNames() []string {
+	return []string{
+		flagNames.environmentOwner,
+		flagNames.environmentCreatedBefore,
+		flagNames.environmentCreatedAfter,
+		flagNames.environmentOffline,
+		flagNames.environmentAutoDeleteWithin,
+		flagNames.environmentHasStartSchedule,
+		flagNames.environmentHasStopSchedule,
+	}
+}
+
+func (e *environmentAttributeFlags) addToCobraCommand(cobraCommand *cobra.Command) {
+	cobraCommand.Flags().StringSliceVar(&e.owner, flagNames.environmentOwner, []string{}, "Run for environments owned by the given user(s)")
+	flags.TimeVar(cobraCommand.Flags(), &e.createdBefore, flagNames.environmentCreatedBefore, "Run for environments created before the given time (RFC3339 or YYYY-MM-DD)")
+	flags.TimeVar(cobraCommand.Flags(), &e.createdAfter, flagNames.environmentCreatedAfter, "Run for environments created after the given time (RFC3339 or YYYY-MM-DD)")
+	cobraCommand.Flags().BoolVar(&e.offline, flagNames.environmentOffline, false, "Run for environments that are currently offline (or online, if set to false)")
+	cobraCommand.Flags().DurationVar(&e.autoDeleteWithin, flagNames.environmentAutoDeleteWithin, 0, "Run for environments that will be automatically deleted within the given duration")
+	cobraCommand.Flags().BoolVar(&e.hasStartSchedule, flagNames.environmentHasStartSchedule, false, "Run for environments with a start schedule (or without one, if set to false)")
+	cobraCommand.Flags().BoolVar(&e.hasStopSchedule, flagNames.environmentHasStopSchedule, false, "Run for environments with a stop schedule (or without one, if set to false)")
+}
+
+func (e *environmentAttributeFlags) processInput(f *filterBuilder, _ terra.State, _ []string, pflags *pflag.FlagSet) error {
+	if pflags.Changed(flagNames.environmentOwner) {
+		owners := collateSelectorValues(e.owner)
+		if owners.Empty() {
+			return errors.Errorf("--%s: at least one owner must be specified", flagNames.environmentOwner)
+		}
+		f.addEnvironmentFilter(filter.Environments().HasOwner(owners.Elements()...))
+	}
+	if pflags.Changed(flagNames.environmentCreatedBefore) {
+		f.addEnvironmentFilter(filter.Environments().CreatedBefore(e.createdBefore))
+	}
+	if pflags.Changed(flagNames.environmentCreatedAfter) {
+		f.addEnvironmentFilter(filter.Environments().CreatedAfter(e.createdAfter))
+	}
+	if pflags.Changed(flagNames.environmentOffline) {
+		f.addEnvironmentFilter(negateUnless(e.offline, filter.Environments().IsOffline()))
+	}
+	if pflags.Changed(flagNames.environmentAutoDeleteWithin) {
+		f.addEnvironmentFilter(filter.Environments().AutoDeleteDueWithin(e.autoDeleteWithin))
+	}
+	if pflags.Changed(flagNames.environmentHasStartSchedule) {
+		f.addEnvironmentFilter(negateUnless(e.hasStartSchedule, filter.Environments().HasStartSchedule()))
+	}
+	if pflags.Changed(flagNames.environmentHasStopSchedule) {
+		f.addEnvironmentFilter(negateUnless(e.hasStopSchedule, filter.Environments().HasStopSchedule()))
+	}
+	return nil
+}
+
+// negateUnless returns the filter if the boolean flag value is true, or its negation if false
+func negateUnless(value bool, f terra.EnvironmentFilter) terra.EnvironmentFilter {
+	if value {
+		return f
+	}
+	return f.Negate()
+}
+
+// anyChanged returns true if any of the given flags were set by the user
+func anyChanged(pflags *pflag.FlagSet, names []string) bool {
+	for _, name := range names {
+		if pflags.Changed(name) {
+			return true
+		}
+	}
+	return false
+}
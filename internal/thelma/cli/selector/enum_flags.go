@@ -25,9 +25,10 @@ func newReleasesFlag() *enumFlag {
 				return flagValues, nil
 			} else if len(args) > 0 {
 				return []string{args[0]}, nil
-			} else if pflags.Changed(flagNames.exactRelease) || pflags.Changed(flagNames.changedFilesList) {
-				// If there's no releases specified but there are exact releases specified, or a changed file list, act
-				// as if this flag had been set to ALL so we don't filter on it
+			} else if pflags.Changed(flagNames.exactRelease) || pflags.Changed(flagNames.changedFilesList) || pflags.Changed(flagNames.where) || anyChanged(pflags, releaseAttributeFlagNames()) {
+				// If there's no releases specified but there are exact releases specified, a changed file list, a
+				// --where expression, or release attribute flags like --chart-version, act as if this flag had been set
+				// to ALL so we don't filter on it
 				return []string{allSelector}, nil
 			} else {
 				// We have a lot of releases, and most developers want to render for a specific service,
@@ -209,6 +210,27 @@ func newEnvironmentLifecyclesFlag() *enumFlag {
 	}
 }
 
+// --release-type flag
+func newReleaseTypesFlag() *enumFlag {
+	return &enumFlag{
+		flagName:      flagNames.releaseType,
+		defaultValues: []string{allSelector},
+		usageMessage:  `Run for releases of a specific type (eg. "app", "cluster")`,
+
+		validValues: func(_ terra.State) (set.StringSet, error) {
+			s := set.NewStringSet()
+			for _, releaseType := range terra.ReleaseTypes() {
+				s.Add(releaseType.String())
+			}
+			return s, nil
+		},
+
+		buildFilter: func(f *filterBuilder, uniqueValues []string) {
+			f.addAttributeFilter(filter.Releases().OfTypeName(uniqueValues...))
+		},
+	}
+}
+
 func namesSet[T terra.Named](named []T) set.StringSet {
 	names := set.NewStringSet()
 	for _, n := range named {
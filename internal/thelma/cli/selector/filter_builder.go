@@ -21,6 +21,9 @@ type filterBuilder struct {
 	environmentFilters []terra.EnvironmentFilter
 	// holds union filters (any can be matched). Used for -e / -c flags, which are additive
 	destinationIncludes []terra.DestinationFilter
+	// holds release attribute filters (intersected). Unlike releaseFilters, these narrow the selection without scoping
+	// it to specific releases. Used for --where, --chart-version & friends
+	attributeFilters []terra.ReleaseFilter
 }
 
 // combine aggregates all registered filters into a single terra.ReleaseFilter
@@ -48,6 +51,7 @@ func (f *filterBuilder) combine() terra.ReleaseFilter {
 
 	var releaseFilters []terra.ReleaseFilter
 	releaseFilters = append(releaseFilters, f.releaseFilters...)
+	releaseFilters = append(releaseFilters, f.attributeFilters...)
 
 	// aggregate destination filters with And, then convert the aggregated filter into a release filter
 	if len(destFilters) > 0 {
@@ -76,6 +80,10 @@ func (f *filterBuilder) addReleaseFilter(filter terra.ReleaseFilter) {
 	f.releaseFilters = append(f.releaseFilters, filter)
 }
 
+func (f *filterBuilder) addAttributeFilter(filter terra.ReleaseFilter) {
+	f.attributeFilters = append(f.attributeFilters, filter)
+}
+
 func (f *filterBuilder) addDestinationInclude(filter terra.DestinationFilter) {
 	f.destinationIncludes = append(f.destinationIncludes, filter)
 }
@@ -9,9 +9,12 @@ import (
 )
 
 type RenderSelector struct {
-	enumFlags        []*enumFlag
-	changedFilesFlag *changedFilesListFlag
-	filterBuilder    *filterBuilder
+	enumFlags             []*enumFlag
+	changedFilesFlag      *changedFilesListFlag
+	whereFlag             *whereFlag
+	releaseAttributes     *releaseAttributeFlags
+	environmentAttributes *environmentAttributeFlags
+	filterBuilder         *filterBuilder
 }
 
 // RenderSelection describes the set of releases that match user-supplied CLI flags for a render selector
@@ -36,12 +39,16 @@ func NewRenderSelector() *RenderSelector {
 		newDestinationBasesFlag(),
 		newEnvironmentTemplatesFlag(),
 		newEnvironmentLifecyclesFlag(),
+		newReleaseTypesFlag(),
 	}
 
 	return &RenderSelector{
-		enumFlags:        enumFlags,
-		changedFilesFlag: newChangedFilesList(),
-		filterBuilder:    newFilterBuilder(),
+		enumFlags:             enumFlags,
+		changedFilesFlag:      newChangedFilesList(),
+		whereFlag:             newWhereFlag(),
+		releaseAttributes:     newReleaseAttributeFlags(),
+		environmentAttributes: newEnvironmentAttributeFlags(),
+		filterBuilder:         newFilterBuilder(),
 	}
 }
 
@@ -51,6 +58,9 @@ func (s *RenderSelector) AddFlags(cobraCommand *cobra.Command) {
 		flag.addToCobraCommand(cobraCommand)
 	}
 	s.changedFilesFlag.addToCobraCommand(cobraCommand)
+	s.whereFlag.addToCobraCommand(cobraCommand)
+	s.releaseAttributes.addToCobraCommand(cobraCommand)
+	s.environmentAttributes.addToCobraCommand(cobraCommand)
 }
 
 func (s *RenderSelector) GetSelection(state terra.State, chartsDir source.ChartsDir, pflags *pflag.FlagSet, args []string) (*RenderSelection, error) {
@@ -69,6 +79,15 @@ func (s *RenderSelector) GetSelection(state terra.State, chartsDir source.Charts
 	if err := s.changedFilesFlag.processInput(s.filterBuilder, state, chartsDir, args, pflags); err != nil {
 		return nil, err
 	}
+	if err := s.whereFlag.processInput(s.filterBuilder, state, args, pflags); err != nil {
+		return nil, err
+	}
+	if err := s.releaseAttributes.processInput(s.filterBuilder, state, args, pflags); err != nil {
+		return nil, err
+	}
+	if err := s.environmentAttributes.processInput(s.filterBuilder, state, args, pflags); err != nil {
+		return nil, err
+	}
 
 	releaseFilter := s.filterBuilder.combine()
 	releases, err := applyFilter(state, releaseFilter)
@@ -111,6 +111,51 @@ func Test_RenderSelector(t *testing.T) {
 			args:           "--environment-template=swatomation --environment-lifecycle=dynamic --destination-type=environment ALL",
 			expectReleases: []string{"cromwell-my-bee"},
 		},
+		{
+			name:           "environment owner",
+			args:           "--environment-owner=jdoe@broadinstitute.org --environment-lifecycle=dynamic --destination-type=environment ALL",
+			expectReleases: []string{"cromwell-my-bee"},
+		},
+		{
+			name:           "environment offline",
+			args:           "--environment-offline --destination-type=environment ALL",
+			expectReleases: []string{"workspacemanager-swatomation"},
+		},
+		{
+			name:           "environment online",
+			args:           "--environment-offline=false --destination-type=environment ALL",
+			expectReleases: []string{"agora-dev", "rawls-staging", "sam-dev", "sam-staging"},
+		},
+		{
+			name:           "environment created after",
+			args:           "--environment-created-after=2023-01-01 --environment-lifecycle=template,dynamic --destination-type=environment ALL",
+			expectReleases: []string{"cromwell-my-bee"},
+		},
+		{
+			name:           "environment created before",
+			args:           "--environment-created-before=2023-01-01T00:00:00Z --environment-lifecycle=template,dynamic --destination-type=environment ALL",
+			expectReleases: []string{"workspacemanager-swatomation"},
+		},
+		{
+			name:           "environment has stop schedule",
+			args:           "--environment-has-stop-schedule --environment-lifecycle=template,dynamic --destination-type=environment ALL",
+			expectReleases: []string{"cromwell-my-bee"},
+		},
+		{
+			name:      "environment attribute flags cannot be combined with -e",
+			args:      "-e dev --environment-offline ALL",
+			expectErr: "--environment cannot be combined with --environment-offline",
+		},
+		{
+			name:           "release type implies all releases",
+			args:           "--release-type=cluster",
+			expectReleases: []string{"secrets-manager-terra-dev", "yale-terra-dev", "yale-terra-staging"},
+		},
+		{
+			name:           "chart version range",
+			args:           "--chart-version-range=^10.0.0",
+			expectReleases: []string{"yale-terra-dev", "yale-terra-staging"},
+		},
 	}
 
 	for _, tc := range testCases {
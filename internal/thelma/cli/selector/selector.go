@@ -18,30 +18,56 @@ const selectorSeparator = ","
 const ReleasesFlagName = "release"
 
 var flagNames = struct {
-	release              string
-	exactRelease         string
-	environment          string
-	cluster              string
-	environmentLifecycle string
-	environmentTemplate  string
-	destinationType      string
-	destinationBase      string
-	changedFilesList     string
+	release                     string
+	exactRelease                string
+	environment                 string
+	cluster                     string
+	environmentLifecycle        string
+	environmentTemplate         string
+	destinationType             string
+	destinationBase             string
+	changedFilesList            string
+	where                       string
+	releaseType                 string
+	chartVersion                string
+	appVersion                  string
+	terraHelmfileRef            string
+	environmentOwner            string
+	environmentCreatedBefore    string
+	environmentCreatedAfter     string
+	environmentOffline          string
+	environmentAutoDeleteWithin string
+	environmentHasStartSchedule string
+	environmentHasStopSchedule  string
 }{
-	release:              ReleasesFlagName,
-	exactRelease:         "exact-release",
-	environment:          "environment",
-	cluster:              "cluster",
-	environmentLifecycle: "environment-lifecycle",
-	environmentTemplate:  "environment-template",
-	destinationBase:      "destination-base",
-	destinationType:      "destination-type",
-	changedFilesList:     changedfiles.FlagName,
+	release:                     ReleasesFlagName,
+	exactRelease:                "exact-release",
+	environment:                 "environment",
+	cluster:                     "cluster",
+	environmentLifecycle:        "environment-lifecycle",
+	environmentTemplate:         "environment-template",
+	destinationBase:             "destination-base",
+	destinationType:             "destination-type",
+	changedFilesList:            changedfiles.FlagName,
+	where:                       "where",
+	releaseType:                 "release-type",
+	chartVersion:                "chart-version-range",
+	appVersion:                  "app-version-range",
+	terraHelmfileRef:            "terra-helmfile-ref",
+	environmentOwner:            "environment-owner",
+	environmentCreatedBefore:    "environment-created-before",
+	environmentCreatedAfter:     "environment-created-after",
+	environmentOffline:          "environment-offline",
+	environmentAutoDeleteWithin: "environment-auto-delete-within",
+	environmentHasStartSchedule: "environment-has-start-schedule",
+	environmentHasStopSchedule:  "environment-has-stop-schedule",
 }
 
 type Selector struct {
-	flags         []*enumFlag
-	filterBuilder *filterBuilder
+	flags          []*enumFlag
+	whereFlag      *whereFlag
+	attributeFlags *releaseAttributeFlags
+	filterBuilder  *filterBuilder
 }
 
 func NewSelector() *Selector {
@@ -50,11 +76,14 @@ func NewSelector() *Selector {
 		newExactReleasesFlag(),
 		newEnvironmentsFlag(),
 		newClustersFlag(),
+		newReleaseTypesFlag(),
 	}
 
 	return &Selector{
-		filterBuilder: newFilterBuilder(),
-		flags:         flags,
+		filterBuilder:  newFilterBuilder(),
+		flags:          flags,
+		whereFlag:      newWhereFlag(),
+		attributeFlags: newReleaseAttributeFlags(),
 	}
 }
 
@@ -63,6 +92,8 @@ func (s *Selector) AddFlags(cobraCommand *cobra.Command) {
 	for _, flag := range s.flags {
 		flag.addToCobraCommand(cobraCommand)
 	}
+	s.whereFlag.addToCobraCommand(cobraCommand)
+	s.attributeFlags.addToCobraCommand(cobraCommand)
 }
 
 func (s *Selector) GetSelection(state terra.State, pflags *pflag.FlagSet, args []string) ([]terra.Release, error) {
@@ -78,6 +109,12 @@ func (s *Selector) GetSelection(state terra.State, pflags *pflag.FlagSet, args [
 			return nil, err
 		}
 	}
+	if err := s.whereFlag.processInput(s.filterBuilder, state, args, pflags); err != nil {
+		return nil, err
+	}
+	if err := s.attributeFlags.processInput(s.filterBuilder, state, args, pflags); err != nil {
+		return nil, err
+	}
 
 	releaseFilter := s.filterBuilder.combine()
 	releases, err := applyFilter(state, releaseFilter)
@@ -89,10 +126,10 @@ func (s *Selector) GetSelection(state terra.State, pflags *pflag.FlagSet, args [
 }
 
 func (s *Selector) checkRequiredFlags(flags *pflag.FlagSet) error {
-	// "If -e isn't provided, and -c isn't provided, and the user hasn't passed just --exact-release instead of --r"
-	if !flags.Changed(flagNames.environment) && !flags.Changed(flagNames.cluster) &&
+	// "If -e isn't provided, and -c isn't provided, and --where isn't provided, and the user hasn't passed just --exact-release instead of --r"
+	if !flags.Changed(flagNames.environment) && !flags.Changed(flagNames.cluster) && !flags.Changed(flagNames.where) &&
 		!(flags.Changed(flagNames.exactRelease) && !flags.Changed(flagNames.release)) {
-		return errors.Errorf("please specify a target environment or cluster with the -e/-c flags, or specify only full Sherlock-style release names with --exact-release")
+		return errors.Errorf("please specify a target environment or cluster with the -e/-c flags or a --where expression, or specify only full Sherlock-style release names with --exact-release")
 	}
 	return nil
 }
@@ -101,6 +138,7 @@ func checkIncompatibleEnumFlags(flags *pflag.FlagSet) error {
 	unionFlags := []string{flagNames.environment, flagNames.cluster}
 
 	intersectFlags := []string{flagNames.environmentTemplate, flagNames.environmentLifecycle, flagNames.destinationBase, flagNames.destinationType}
+	intersectFlags = append(intersectFlags, environmentAttributeFlagNames()...)
 
 	for _, unf := range unionFlags {
 		if flags.Changed(unf) {
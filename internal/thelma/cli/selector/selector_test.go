@@ -69,6 +69,36 @@ func Test_Selector(t *testing.T) {
 			args:           "--environment dev,swatomation --release ALL",
 			expectReleases: []string{"agora-dev", "sam-dev", "workspacemanager-swatomation"},
 		},
+		{
+			name:           "where expression",
+			args:           "--where chart==sam",
+			expectReleases: []string{"sam-dev", "sam-staging"},
+		},
+		{
+			name:           "where expression combined with environment",
+			args:           "-e staging --where chart!=sam",
+			expectReleases: []string{"rawls-staging"},
+		},
+		{
+			name:           "chart version range",
+			args:           "-e staging --chart-version-range=^4.0.0",
+			expectReleases: []string{"sam-staging"},
+		},
+		{
+			name:           "release type",
+			args:           "-c terra-dev --release-type=cluster",
+			expectReleases: []string{"secrets-manager-terra-dev", "yale-terra-dev"},
+		},
+		{
+			name:      "invalid chart version range",
+			args:      "-e staging --chart-version-range=^banana",
+			expectErr: `--chart-version-range: `,
+		},
+		{
+			name:      "invalid where expression",
+			args:      "--where chart==",
+			expectErr: "invalid --where expression: unexpected end of expression, expected a value",
+		},
 	}
 
 	for _, tc := range testCases {
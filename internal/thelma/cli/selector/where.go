@@ -0,0 +1,633 @@
+package selector
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/filter"
+	"github.com/pkg/errors"
+)
+
+// This file implements the expression language used by the --where flag. Expressions look like:
+//
+//	chart in (sam, rawls) and env.lifecycle == dynamic and env.owner =~ "@broad"
+//
+// Grammar:
+//
+//	expression := and ( "or" and )*
+//	and        := unary ( "and" unary )*
+//	unary      := "not" unary | "(" expression ")" | comparison
+//	comparison := field ( "==" | "!=" | "=~" | "!~" ) value
+//	            | field [ "not" ] "in" "(" value ( "," value )* ")"
+//	value      := word | "quoted string"
+//
+// =~ and !~ match against a regular expression (RE2 syntax, unanchored).
+
+// envFieldPrefix is the prefix for fields that refer to a release's environment
+const envFieldPrefix = "env."
+
+// comparison operators
+const (
+	opEquals     = "=="
+	opNotEquals  = "!="
+	opMatches    = "=~"
+	opNotMatches = "!~"
+	opIn         = "in"
+	opNotIn      = "not in"
+)
+
+// keywords
+const (
+	keywordAnd = "and"
+	keywordOr  = "or"
+	keywordNot = "not"
+	keywordIn  = "in"
+)
+
+// releaseFields are fields that can be used to filter releases
+var releaseFields = map[string]func(terra.Release) string{
+	"name":             terra.Release.Name,
+	"fullName":         terra.Release.FullName,
+	"chart":            terra.Release.ChartName,
+	"type":             func(r terra.Release) string { return r.Type().String() },
+	"chartVersion":     terra.Release.ChartVersion,
+	"appVersion":       terra.Release.AppVersion,
+	"terraHelmfileRef": terra.Release.TerraHelmfileRef,
+	"namespace":        terra.Release.Namespace,
+	"cluster":          terra.Release.ClusterName,
+	"destination":      func(r terra.Release) string { return r.Destination().Name() },
+}
+
+// environmentFields are fields that can be used to filter environments (prefixed with "env." in expressions)
+var environmentFields = map[string]func(terra.Environment) string{
+	"name":             terra.Environment.Name,
+	"base":             terra.Environment.Base,
+	"lifecycle":        func(e terra.Environment) string { return e.Lifecycle().String() },
+	"template":         terra.Environment.Template,
+	"owner":            terra.Environment.Owner,
+	"terraHelmfileRef": terra.Environment.TerraHelmfileRef,
+	"defaultCluster": func(e terra.Environment) string {
+		if e.DefaultCluster() == nil {
+			return ""
+		}
+		return e.DefaultCluster().Name()
+	},
+}
+
+// WhereExpression is a parsed --where expression
+type WhereExpression struct {
+	root whereNode
+}
+
+// ParseWhere parses a --where expression
+func ParseWhere(expression string) (*WhereExpression, error) {
+	p := &whereParser{input: expression}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorAt(tok, "unexpected %s, expected %q, %q, or end of expression", tok.describe(), keywordAnd, keywordOr)
+	}
+	return &WhereExpression{root: root}, nil
+}
+
+// String returns a normalized representation of the expression, which can be parsed again with ParseWhere
+func (w *WhereExpression) String() string {
+	return w.root.String()
+}
+
+// ReleaseFilter returns a release filter that matches releases satisfying the expression.
+// Conditions on env.* fields never match cluster releases.
+func (w *WhereExpression) ReleaseFilter() terra.ReleaseFilter {
+	return w.root.releaseFilter()
+}
+
+// EnvironmentFilter returns an environment filter that matches environments satisfying the expression.
+// Returns an error if the expression refers to release fields.
+func (w *WhereExpression) EnvironmentFilter() (terra.EnvironmentFilter, error) {
+	return w.root.environmentFilter()
+}
+
+// whereNode is a node in a parsed expression's syntax tree
+type whereNode interface {
+	String() string
+	releaseFilter() terra.ReleaseFilter
+	environmentFilter() (terra.EnvironmentFilter, error)
+}
+
+type andNode struct {
+	operands []whereNode
+}
+
+func (n andNode) String() string {
+	return joinNodes(n.operands, keywordAnd)
+}
+
+func (n andNode) releaseFilter() terra.ReleaseFilter {
+	var filters []terra.ReleaseFilter
+	for _, operand := range n.operands {
+		filters = append(filters, operand.releaseFilter())
+	}
+	return filter.Releases().And(filters...)
+}
+
+func (n andNode) environmentFilter() (terra.EnvironmentFilter, error) {
+	filters, err := environmentFilters(n.operands)
+	if err != nil {
+		return nil, err
+	}
+	return filter.Environments().And(filters...), nil
+}
+
+type orNode struct {
+	operands []whereNode
+}
+
+func (n orNode) String() string {
+	return joinNodes(n.operands, keywordOr)
+}
+
+func (n orNode) releaseFilter() terra.ReleaseFilter {
+	var filters []terra.ReleaseFilter
+	for _, operand := range n.operands {
+		filters = append(filters, operand.releaseFilter())
+	}
+	return filter.Releases().Or(filters...)
+}
+
+func (n orNode) environmentFilter() (terra.EnvironmentFilter, error) {
+	filters, err := environmentFilters(n.operands)
+	if err != nil {
+		return nil, err
+	}
+	return filter.Environments().Or(filters...), nil
+}
+
+type notNode struct {
+	operand whereNode
+}
+
+func (n notNode) String() string {
+	return keywordNot + " " + maybeParenthesize(n.operand)
+}
+
+func (n notNode) releaseFilter() terra.ReleaseFilter {
+	return n.operand.releaseFilter().Negate()
+}
+
+func (n notNode) environmentFilter() (terra.EnvironmentFilter, error) {
+	f, err := n.operand.environmentFilter()
+	if err != nil {
+		return nil, err
+	}
+	return f.Negate(), nil
+}
+
+// comparisonNode is a leaf node that compares a single field to one or more values
+type comparisonNode struct {
+	field    string
+	operator string
+	values   []string
+	regexp   *regexp.Regexp
+}
+
+func (n comparisonNode) String() string {
+	if n.operator == opIn || n.operator == opNotIn {
+		var quoted []string
+		for _, v := range n.values {
+			quoted = append(quoted, quoteWhereValue(v))
+		}
+		return fmt.Sprintf("%s %s (%s)", n.field, n.operator, strings.Join(quoted, ", "))
+	}
+	return fmt.Sprintf("%s %s %s", n.field, n.operator, quoteWhereValue(n.values[0]))
+}
+
+func (n comparisonNode) matches(value string) bool {
+	switch n.operator {
+	case opEquals:
+		return value == n.values[0]
+	case opNotEquals:
+		return value != n.values[0]
+	case opMatches:
+		return n.regexp.MatchString(value)
+	case opNotMatches:
+		return !n.regexp.MatchString(value)
+	case opIn, opNotIn:
+		for _, v := range n.values {
+			if value == v {
+				return n.operator == opIn
+			}
+		}
+		return n.operator == opNotIn
+	}
+	panic(errors.Errorf("unknown operator %q", n.operator))
+}
+
+func (n comparisonNode) releaseFilter() terra.ReleaseFilter {
+	if strings.HasPrefix(n.field, envFieldPrefix) {
+		envFilter := n.mustEnvironmentFilter()
+		return filter.Releases().DestinationMatches(filter.Destinations().IsEnvironmentMatching(envFilter))
+	}
+	getter := releaseFields[n.field]
+	return filter.Releases().Matching(n.String(), func(release terra.Release) bool {
+		return n.matches(getter(release))
+	})
+}
+
+func (n comparisonNode) environmentFilter() (terra.EnvironmentFilter, error) {
+	if !strings.HasPrefix(n.field, envFieldPrefix) {
+		return nil, errors.Errorf("%q is a release field and can't be used to filter environments (did you mean %q?)", n.field, envFieldPrefix+n.field)
+	}
+	return n.mustEnvironmentFilter(), nil
+}
+
+func (n comparisonNode) mustEnvironmentFilter() terra.EnvironmentFilter {
+	getter := environmentFields[strings.TrimPrefix(n.field, envFieldPrefix)]
+	return filter.Environments().Matching(n.String(), func(env terra.Environment) bool {
+		return n.matches(getter(env))
+	})
+}
+
+func environmentFilters(nodes []whereNode) ([]terra.EnvironmentFilter, error) {
+	var filters []terra.EnvironmentFilter
+	for _, node := range nodes {
+		f, err := node.environmentFilter()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+func joinNodes(nodes []whereNode, keyword string) string {
+	var parts []string
+	for _, node := range nodes {
+		parts = append(parts, maybeParenthesize(node))
+	}
+	return strings.Join(parts, " "+keyword+" ")
+}
+
+// maybeParenthesize wraps compound expressions in parentheses
+func maybeParenthesize(node whereNode) string {
+	switch node.(type) {
+	case andNode, orNode:
+		return "(" + node.String() + ")"
+	default:
+		return node.String()
+	}
+}
+
+// quoteWhereValue quotes a value if it can't be parsed as a bare word
+func quoteWhereValue(value string) string {
+	if value == "" || isKeyword(value) {
+		return strconv.Quote(value)
+	}
+	for _, r := range value {
+		if !isWordRune(r) {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
+
+// WhereParseError is returned when a --where expression can't be parsed
+type WhereParseError struct {
+	// Expression is the expression that failed to parse
+	Expression string
+	// Position is the byte offset in the expression where the error occurred
+	Position int
+	// Message describes the error
+	Message string
+}
+
+func (e *WhereParseError) Error() string {
+	return fmt.Sprintf("invalid --%s expression: %s at position %d\n  %s\n  %s^", flagNames.where, e.Message, e.Position+1, e.Expression, strings.Repeat(" ", e.Position))
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// describe returns a description of the token for use in error messages
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("string %s", strconv.Quote(t.value))
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && t.value == keyword
+}
+
+type whereParser struct {
+	input  string
+	tokens []token
+	index  int
+}
+
+func (p *whereParser) tokenize() error {
+	runes := []rune(p.input)
+	// track byte offsets so error positions line up with the input string
+	offsets := make([]int, len(runes)+1)
+	offset := 0
+	for i, r := range runes {
+		offsets[i] = offset
+		offset += len(string(r))
+	}
+	offsets[len(runes)] = offset
+
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		start := offsets[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			p.tokens = append(p.tokens, token{kind: tokenLeftParen, value: "(", pos: start})
+			i++
+		case r == ')':
+			p.tokens = append(p.tokens, token{kind: tokenRightParen, value: ")", pos: start})
+			i++
+		case r == ',':
+			p.tokens = append(p.tokens, token{kind: tokenComma, value: ",", pos: start})
+			i++
+		case r == '=' || r == '!':
+			if i+1 < len(runes) && (runes[i+1] == '=' || runes[i+1] == '~') {
+				p.tokens = append(p.tokens, token{kind: tokenOperator, value: string(runes[i : i+2]), pos: start})
+				i += 2
+			} else {
+				return p.errorAtPos(start, "unexpected %q, expected one of %q, %q, %q, %q", string(r), opEquals, opNotEquals, opMatches, opNotMatches)
+			}
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(runes) {
+				return p.errorAtPos(start, "unterminated string")
+			}
+			value, err := strconv.Unquote(string(runes[i : j+1]))
+			if err != nil {
+				return p.errorAtPos(start, "invalid string: %v", err)
+			}
+			p.tokens = append(p.tokens, token{kind: tokenString, value: value, pos: start})
+			i = j + 1
+		case isWordRune(r):
+			j := i
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+			p.tokens = append(p.tokens, token{kind: tokenWord, value: string(runes[i:j]), pos: start})
+			i = j
+		default:
+			return p.errorAtPos(start, "unexpected character %q", string(r))
+		}
+	}
+	p.tokens = append(p.tokens, token{kind: tokenEOF, pos: offset})
+	return nil
+}
+
+func (p *whereParser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *whereParser) next() token {
+	tok := p.tokens[p.index]
+	if tok.kind != tokenEOF {
+		p.index++
+	}
+	return tok
+}
+
+func (p *whereParser) parseExpression() (whereNode, error) {
+	var operands []whereNode
+	for {
+		operand, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, flatten(operand, orNode{})...)
+		if !p.peek().isKeyword(keywordOr) {
+			break
+		}
+		p.next()
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return orNode{operands: operands}, nil
+}
+
+func (p *whereParser) parseAnd() (whereNode, error) {
+	var operands []whereNode
+	for {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, flatten(operand, andNode{})...)
+		if !p.peek().isKeyword(keywordAnd) {
+			break
+		}
+		p.next()
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return andNode{operands: operands}, nil
+}
+
+func (p *whereParser) parseUnary() (whereNode, error) {
+	tok := p.peek()
+	switch {
+	case tok.isKeyword(keywordNot):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	case tok.kind == tokenLeftParen:
+		p.next()
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRightParen {
+			return nil, p.errorAt(closing, "unexpected %s, expected \")\"", closing.describe())
+		}
+		return expr, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *whereParser) parseComparison() (whereNode, error) {
+	fieldToken := p.next()
+	if fieldToken.kind != tokenWord || isKeyword(fieldToken.value) {
+		return nil, p.errorAt(fieldToken, "unexpected %s, expected a field name", fieldToken.describe())
+	}
+	if err := p.checkField(fieldToken); err != nil {
+		return nil, err
+	}
+	node := comparisonNode{field: fieldToken.value}
+
+	opToken := p.next()
+	switch {
+	case opToken.kind == tokenOperator:
+		node.operator = opToken.value
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		node.values = []string{value.value}
+		if node.operator == opMatches || node.operator == opNotMatches {
+			re, err := regexp.Compile(value.value)
+			if err != nil {
+				return nil, p.errorAt(value, "invalid regular expression: %v", err)
+			}
+			node.regexp = re
+		}
+		return node, nil
+	case opToken.isKeyword(keywordIn):
+		node.operator = opIn
+	case opToken.isKeyword(keywordNot) && p.peek().isKeyword(keywordIn):
+		p.next()
+		node.operator = opNotIn
+	default:
+		return nil, p.errorAt(opToken, "unexpected %s, expected an operator (one of %q, %q, %q, %q, %q, %q)", opToken.describe(), opEquals, opNotEquals, opMatches, opNotMatches, opIn, opNotIn)
+	}
+
+	values, err := p.parseValueList()
+	if err != nil {
+		return nil, err
+	}
+	node.values = values
+	return node, nil
+}
+
+func (p *whereParser) parseValueList() ([]string, error) {
+	if open := p.next(); open.kind != tokenLeftParen {
+		return nil, p.errorAt(open, "unexpected %s, expected \"(\"", open.describe())
+	}
+	var values []string
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value.value)
+
+		tok := p.next()
+		if tok.kind == tokenRightParen {
+			return values, nil
+		}
+		if tok.kind != tokenComma {
+			return nil, p.errorAt(tok, "unexpected %s, expected \",\" or \")\"", tok.describe())
+		}
+	}
+}
+
+func (p *whereParser) parseValue() (token, error) {
+	tok := p.next()
+	if tok.kind == tokenString || (tok.kind == tokenWord && !isKeyword(tok.value)) {
+		return tok, nil
+	}
+	return tok, p.errorAt(tok, "unexpected %s, expected a value", tok.describe())
+}
+
+func (p *whereParser) checkField(tok token) error {
+	if strings.HasPrefix(tok.value, envFieldPrefix) {
+		if _, exists := environmentFields[strings.TrimPrefix(tok.value, envFieldPrefix)]; exists {
+			return nil
+		}
+	} else if _, exists := releaseFields[tok.value]; exists {
+		return nil
+	}
+	return p.errorAt(tok, "unknown field %q (valid fields are: %s)", tok.value, strings.Join(validWhereFields(), ", "))
+}
+
+func (p *whereParser) errorAt(tok token, format string, args ...interface{}) error {
+	return p.errorAtPos(tok.pos, format, args...)
+}
+
+func (p *whereParser) errorAtPos(pos int, format string, args ...interface{}) error {
+	return &WhereParseError{
+		Expression: p.input,
+		Position:   pos,
+		Message:    fmt.Sprintf(format, args...),
+	}
+}
+
+// flatten returns the operands of node if it is the same type as kind (so "a and (b and c)" becomes a single
+// and node with three operands), otherwise returns node
+func flatten(node whereNode, kind whereNode) []whereNode {
+	switch n := node.(type) {
+	case andNode:
+		if _, ok := kind.(andNode); ok {
+			return n.operands
+		}
+	case orNode:
+		if _, ok := kind.(orNode); ok {
+			return n.operands
+		}
+	}
+	return []whereNode{node}
+}
+
+func validWhereFields() []string {
+	var fields []string
+	for f := range releaseFields {
+		fields = append(fields, f)
+	}
+	for f := range environmentFields {
+		fields = append(fields, envFieldPrefix+f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func isKeyword(word string) bool {
+	switch word {
+	case keywordAnd, keywordOr, keywordNot, keywordIn:
+		return true
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("._-@/:+", r)
+}
@@ -0,0 +1,39 @@
+package selector
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// whereFlag processes the --where flag, which filters releases with an expression (see where.go)
+type whereFlag struct {
+	expression string
+}
+
+func newWhereFlag() *whereFlag {
+	return &whereFlag{}
+}
+
+func (w *whereFlag) addToCobraCommand(cobraCommand *cobra.Command) {
+	cobraCommand.Flags().StringVar(
+		&w.expression,
+		flagNames.where,
+		"",
+		`Only include releases matching an expression, eg. 'chart in (sam, rawls) and env.lifecycle == dynamic and env.owner =~ "@broad"'`,
+	)
+}
+
+func (w *whereFlag) processInput(f *filterBuilder, _ terra.State, _ []string, pflags *pflag.FlagSet) error {
+	if !pflags.Changed(flagNames.where) {
+		return nil
+	}
+	expr, err := ParseWhere(w.expression)
+	if err != nil {
+		return err
+	}
+	log.Debug().Msgf("parsed --%s expression: %s", flagNames.where, expr.String())
+	f.addAttributeFilter(expr.ReleaseFilter())
+	return nil
+}
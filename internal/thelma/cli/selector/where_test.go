@@ -0,0 +1,210 @@
+package selector
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/testing/statefixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseWhere(t *testing.T) {
+	testCases := []struct {
+		name           string
+		expression     string
+		expectString   string
+		expectReleases []string
+		expectEnvs     []string
+		expectEnvErr   string
+	}{
+		{
+			name:           "equals",
+			expression:     "chart == sam",
+			expectString:   "chart == sam",
+			expectReleases: []string{"sam-dev", "sam-staging"},
+			expectEnvErr:   `"chart" is a release field and can't be used to filter environments (did you mean "env.chart"?)`,
+		},
+		{
+			name:           "in list",
+			expression:     "chart in (sam,rawls) and env.lifecycle == static",
+			expectString:   "chart in (sam, rawls) and env.lifecycle == static",
+			expectReleases: []string{"rawls-staging", "sam-dev", "sam-staging"},
+		},
+		{
+			name:           "not in list",
+			expression:     "chart not in (sam, rawls, yale, secrets-manager)",
+			expectString:   "chart not in (sam, rawls, yale, secrets-manager)",
+			expectReleases: []string{"agora-dev", "cromwell-my-bee", "workspacemanager-swatomation"},
+		},
+		{
+			name:           "regex on environment field",
+			expression:     `env.lifecycle == dynamic and env.owner =~ "@broad"`,
+			expectString:   "env.lifecycle == dynamic and env.owner =~ @broad",
+			expectReleases: []string{"cromwell-my-bee"},
+			expectEnvs:     []string{"my-bee"},
+		},
+		{
+			name:           "environment conditions never match cluster releases",
+			expression:     "env.name != dev and chart == yale",
+			expectString:   "env.name != dev and chart == yale",
+			expectReleases: nil,
+		},
+		{
+			name:           "precedence and parentheses",
+			expression:     "(chart == sam or chart == rawls) and not (env.name == dev)",
+			expectString:   "(chart == sam or chart == rawls) and not env.name == dev",
+			expectReleases: []string{"rawls-staging", "sam-staging"},
+		},
+		{
+			name:           "and binds tighter than or",
+			expression:     "env.name == dev and chart == sam or type == cluster and chart == yale",
+			expectString:   "(env.name == dev and chart == sam) or (type == cluster and chart == yale)",
+			expectReleases: []string{"sam-dev", "yale-terra-dev", "yale-terra-staging"},
+		},
+		{
+			name:         "quoted values",
+			expression:   `env.template == "" or env.name in ("my-bee", "and")`,
+			expectString: `env.template == "" or env.name in (my-bee, "and")`,
+			expectEnvs:   []string{"dev", "my-bee", "staging", "swatomation"},
+		},
+		{
+			name:         "nested or is flattened",
+			expression:   "env.base == live or (env.name == my-bee or env.name == swatomation)",
+			expectString: "env.base == live or env.name == my-bee or env.name == swatomation",
+			expectEnvs:   []string{"dev", "my-bee", "staging", "swatomation"},
+		},
+	}
+
+	statefixture, err := statefixtures.LoadFixtureFromFile("testdata/statefixture.yaml")
+	require.NoError(t, err)
+	state := statefixture.Mocks().State
+
+	allReleases, err := state.Releases().All()
+	require.NoError(t, err)
+	allEnvironments, err := state.Environments().All()
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := ParseWhere(tc.expression)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectString, expr.String())
+
+			// make sure String() round-trips
+			reparsed, err := ParseWhere(expr.String())
+			require.NoError(t, err)
+			assert.Equal(t, expr.String(), reparsed.String())
+
+			if tc.expectReleases != nil || tc.expectEnvs == nil {
+				var names []string
+				for _, r := range expr.ReleaseFilter().Filter(allReleases) {
+					names = append(names, r.FullName())
+				}
+				sort.Strings(names)
+				assert.Equal(t, tc.expectReleases, names)
+			}
+
+			envFilter, err := expr.EnvironmentFilter()
+			if tc.expectEnvErr != "" {
+				assert.ErrorContains(t, err, tc.expectEnvErr)
+				return
+			}
+			if tc.expectEnvs == nil {
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectEnvs, environmentNames(envFilter.Filter(allEnvironments)))
+		})
+	}
+}
+
+func Test_ParseWhereErrors(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expectErr  string
+	}{
+		{
+			expression: "",
+			expectErr:  "unexpected end of expression, expected a field name at position 1",
+		},
+		{
+			expression: "chart = sam",
+			expectErr:  `unexpected "=", expected one of "==", "!=", "=~", "!~" at position 7`,
+		},
+		{
+			expression: "chrat == sam",
+			expectErr:  `unknown field "chrat" (valid fields are: appVersion, chart, chartVersion,`,
+		},
+		{
+			expression: "env.color == blue",
+			expectErr:  `unknown field "env.color"`,
+		},
+		{
+			expression: "chart == sam rawls",
+			expectErr:  `unexpected "rawls", expected "and", "or", or end of expression at position 14`,
+		},
+		{
+			expression: "chart in (sam rawls)",
+			expectErr:  `unexpected "rawls", expected "," or ")" at position 15`,
+		},
+		{
+			expression: "chart in sam",
+			expectErr:  `unexpected "sam", expected "(" at position 10`,
+		},
+		{
+			expression: `chart == "sam`,
+			expectErr:  "unterminated string at position 10",
+		},
+		{
+			expression: "(chart == sam",
+			expectErr:  `unexpected end of expression, expected ")" at position 14`,
+		},
+		{
+			expression: "chart =~ sam(",
+			expectErr:  `unexpected "(", expected "and", "or", or end of expression`,
+		},
+		{
+			expression: `chart =~ "sam("`,
+			expectErr:  "invalid regular expression",
+		},
+		{
+			expression: "chart sam",
+			expectErr:  `unexpected "sam", expected an operator`,
+		},
+		{
+			expression: "chart == and",
+			expectErr:  `unexpected "and", expected a value`,
+		},
+		{
+			expression: "chart == sam & chart == rawls",
+			expectErr:  `unexpected character "&" at position 14`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			_, err := ParseWhere(tc.expression)
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tc.expectErr)
+		})
+	}
+}
+
+func Test_WhereParseErrorMessage(t *testing.T) {
+	_, err := ParseWhere("chart == sam rawls")
+	require.Error(t, err)
+	assert.Equal(t, `invalid --where expression: unexpected "rawls", expected "and", "or", or end of expression at position 14
+  chart == sam rawls
+               ^`, err.Error())
+}
+
+func environmentNames(envs []terra.Environment) []string {
+	var names []string
+	for _, e := range envs {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
@@ -3,6 +3,7 @@ package google
 import (
 	container "cloud.google.com/go/container/apiv1"
 	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/storage"
 	"context"
 	"github.com/broadinstitute/thelma/internal/thelma/app/config"
 	"github.com/broadinstitute/thelma/internal/thelma/clients/google/bucket"
@@ -13,6 +14,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"golang.org/x/oauth2"
 	oauth2google "golang.org/x/oauth2/google"
+	"google.golang.org/api/iam/v1"
 	"google.golang.org/api/iamcredentials/v1"
 	googleoauth "google.golang.org/api/oauth2/v2"
 	"google.golang.org/api/option"
@@ -33,6 +35,10 @@ type Clients interface {
 	ClusterManager() (*container.ClusterManagerClient, error)
 	// SqlAdmin returns a new google sql admin client
 	SqlAdmin() (sqladmin.Client, error)
+	// Storage returns a new google cloud storage client
+	Storage() (*storage.Client, error)
+	// Iam returns a new google iam client
+	Iam() (*iam.Service, error)
 	// TokenSource returns an oauth TokenSource for this client factory's configured identity
 	TokenSource() (oauth2.TokenSource, error)
 	// IdTokenGenerator returns a function suitable to be an issueFn for credentials.TokenProvider
@@ -321,6 +327,22 @@ func (c *clientsImpl) SqlAdmin() (sqladmin.Client, error) {
 	return sqladmin.New(client), nil
 }
 
+func (c *clientsImpl) Storage() (*storage.Client, error) {
+	clientOptions, err := c.googleClientOptions(false)
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewClient(context.Background(), clientOptions...)
+}
+
+func (c *clientsImpl) Iam() (*iam.Service, error) {
+	clientOptions, err := c.googleClientOptions(false)
+	if err != nil {
+		return nil, err
+	}
+	return iam.NewService(context.Background(), clientOptions...)
+}
+
 // IdTokenGenerator returns a function to generate ID tokens of a service account for a
 // given audience.
 //
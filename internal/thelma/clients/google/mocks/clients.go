@@ -10,8 +10,12 @@ import (
 
 	oauth2 "golang.org/x/oauth2"
 
+	iam "google.golang.org/api/iam/v1"
+
 	pubsub "cloud.google.com/go/pubsub"
 
+	storage "cloud.google.com/go/storage"
+
 	sqladmin "github.com/broadinstitute/thelma/internal/thelma/clients/google/sqladmin"
 
 	terraapi "github.com/broadinstitute/thelma/internal/thelma/clients/google/terraapi"
@@ -221,6 +225,59 @@ func (_c *Clients_IdTokenGenerator_Call) RunAndReturn(run func(string, ...string
 	return _c
 }
 
+// Iam provides a mock function with given fields:
+func (_m *Clients) Iam() (*iam.Service, error) {
+	ret := _m.Called()
+
+	var r0 *iam.Service
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*iam.Service, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *iam.Service); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.Service)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Clients_Iam_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Iam'
+type Clients_Iam_Call struct {
+	*mock.Call
+}
+
+// Iam is a helper method to define mock.On call
+func (_e *Clients_Expecter) Iam() *Clients_Iam_Call {
+	return &Clients_Iam_Call{Call: _e.mock.On("Iam")}
+}
+
+func (_c *Clients_Iam_Call) Run(run func()) *Clients_Iam_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Clients_Iam_Call) Return(_a0 *iam.Service, _a1 error) *Clients_Iam_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Clients_Iam_Call) RunAndReturn(run func() (*iam.Service, error)) *Clients_Iam_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PubSub provides a mock function with given fields: projectId
 func (_m *Clients) PubSub(projectId string) (*pubsub.Client, error) {
 	ret := _m.Called(projectId)
@@ -328,6 +385,59 @@ func (_c *Clients_SqlAdmin_Call) RunAndReturn(run func() (sqladmin.Client, error
 	return _c
 }
 
+// Storage provides a mock function with given fields:
+func (_m *Clients) Storage() (*storage.Client, error) {
+	ret := _m.Called()
+
+	var r0 *storage.Client
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*storage.Client, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *storage.Client); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.Client)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Clients_Storage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Storage'
+type Clients_Storage_Call struct {
+	*mock.Call
+}
+
+// Storage is a helper method to define mock.On call
+func (_e *Clients_Expecter) Storage() *Clients_Storage_Call {
+	return &Clients_Storage_Call{Call: _e.mock.On("Storage")}
+}
+
+func (_c *Clients_Storage_Call) Run(run func()) *Clients_Storage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Clients_Storage_Call) Return(_a0 *storage.Client, _a1 error) *Clients_Storage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Clients_Storage_Call) RunAndReturn(run func() (*storage.Client, error)) *Clients_Storage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Terra provides a mock function with given fields:
 func (_m *Clients) Terra() (terraapi.TerraClient, error) {
 	ret := _m.Called()
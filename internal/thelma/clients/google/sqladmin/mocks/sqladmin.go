@@ -64,6 +64,50 @@ func (_c *Client_AddUser_Call) RunAndReturn(run func(string, string, *sqladmin.U
 	return _c
 }
 
+// DeleteDatabase provides a mock function with given fields: project, instanceName, database
+func (_m *Client) DeleteDatabase(project string, instanceName string, database string) error {
+	ret := _m.Called(project, instanceName, database)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(project, instanceName, database)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_DeleteDatabase_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDatabase'
+type Client_DeleteDatabase_Call struct {
+	*mock.Call
+}
+
+// DeleteDatabase is a helper method to define mock.On call
+//   - project string
+//   - instanceName string
+//   - database string
+func (_e *Client_Expecter) DeleteDatabase(project interface{}, instanceName interface{}, database interface{}) *Client_DeleteDatabase_Call {
+	return &Client_DeleteDatabase_Call{Call: _e.mock.On("DeleteDatabase", project, instanceName, database)}
+}
+
+func (_c *Client_DeleteDatabase_Call) Run(run func(project string, instanceName string, database string)) *Client_DeleteDatabase_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteDatabase_Call) Return(_a0 error) *Client_DeleteDatabase_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_DeleteDatabase_Call) RunAndReturn(run func(string, string, string) error) *Client_DeleteDatabase_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteUser provides a mock function with given fields: project, instanceName, username
 func (_m *Client) DeleteUser(project string, instanceName string, username string) error {
 	ret := _m.Called(project, instanceName, username)
@@ -218,6 +262,115 @@ func (_c *Client_GetInstanceLocalUsers_Call) RunAndReturn(run func(string, strin
 	return _c
 }
 
+// ListDatabases provides a mock function with given fields: project, instanceName
+func (_m *Client) ListDatabases(project string, instanceName string) ([]string, error) {
+	ret := _m.Called(project, instanceName)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]string, error)); ok {
+		return rf(project, instanceName)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []string); ok {
+		r0 = rf(project, instanceName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(project, instanceName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ListDatabases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDatabases'
+type Client_ListDatabases_Call struct {
+	*mock.Call
+}
+
+// ListDatabases is a helper method to define mock.On call
+//   - project string
+//   - instanceName string
+func (_e *Client_Expecter) ListDatabases(project interface{}, instanceName interface{}) *Client_ListDatabases_Call {
+	return &Client_ListDatabases_Call{Call: _e.mock.On("ListDatabases", project, instanceName)}
+}
+
+func (_c *Client_ListDatabases_Call) Run(run func(project string, instanceName string)) *Client_ListDatabases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Client_ListDatabases_Call) Return(_a0 []string, _a1 error) *Client_ListDatabases_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ListDatabases_Call) RunAndReturn(run func(string, string) ([]string, error)) *Client_ListDatabases_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListInstances provides a mock function with given fields: project
+func (_m *Client) ListInstances(project string) ([]string, error) {
+	ret := _m.Called(project)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(project)
+	}
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(project)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(project)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ListInstances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListInstances'
+type Client_ListInstances_Call struct {
+	*mock.Call
+}
+
+// ListInstances is a helper method to define mock.On call
+//   - project string
+func (_e *Client_Expecter) ListInstances(project interface{}) *Client_ListInstances_Call {
+	return &Client_ListInstances_Call{Call: _e.mock.On("ListInstances", project)}
+}
+
+func (_c *Client_ListInstances_Call) Run(run func(project string)) *Client_ListInstances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Client_ListInstances_Call) Return(_a0 []string, _a1 error) *Client_ListInstances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ListInstances_Call) RunAndReturn(run func(string) ([]string, error)) *Client_ListInstances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PatchInstance provides a mock function with given fields: project, instanceName, patchRequest
 func (_m *Client) PatchInstance(project string, instanceName string, patchRequest *sqladmin.DatabaseInstance) error {
 	ret := _m.Called(project, instanceName, patchRequest)
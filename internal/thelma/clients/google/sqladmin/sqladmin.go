@@ -1,6 +1,7 @@
 package sqladmin
 
 import (
+	"context"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/api/sqladmin/v1"
@@ -21,6 +22,9 @@ type Client interface {
 	ResetPassword(project string, instanceName string, username string, password string) error
 	DeleteUser(project string, instanceName string, username string) error
 	AddUser(project string, instanceName string, user *sqladmin.User) error
+	ListInstances(project string) ([]string, error)
+	ListDatabases(project string, instanceName string) ([]string, error)
+	DeleteDatabase(project string, instanceName string, database string) error
 }
 
 func New(sqladminClient *sqladmin.Service) Client {
@@ -101,6 +105,42 @@ func (c client) AddUser(project string, instanceName string, user *sqladmin.User
 	return c.waitForOpToBeDone(op)
 }
 
+func (c client) ListInstances(project string) ([]string, error) {
+	var instances []string
+	err := c.sqladminClient.Instances.List(project).Pages(context.Background(), func(resp *sqladmin.InstancesListResponse) error {
+		for _, instance := range resp.Items {
+			instances = append(instances, instance.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Errorf("error listing instances in project %s: %v", project, err)
+	}
+	return instances, nil
+}
+
+func (c client) ListDatabases(project string, instanceName string) ([]string, error) {
+	resp, err := c.sqladminClient.Databases.List(project, instanceName).Do()
+	if err != nil {
+		return nil, errors.Errorf("error listing databases in instance %s: %v", instanceName, err)
+	}
+
+	var databases []string
+	for _, database := range resp.Items {
+		databases = append(databases, database.Name)
+	}
+	return databases, nil
+}
+
+func (c client) DeleteDatabase(project string, instanceName string, database string) error {
+	op, err := c.sqladminClient.Databases.Delete(project, instanceName, database).Do()
+	if err != nil {
+		return errors.Errorf("error deleting database %s from instance %s: %v", database, instanceName, err)
+	}
+
+	return c.waitForOpToBeDone(op)
+}
+
 func (c client) waitForOpToBeDone(op *sqladmin.Operation) error {
 	var err error
 
@@ -0,0 +1,27 @@
+package terraapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+)
+
+// AppClient makes arbitrary authenticated JSON requests against a Terra app, for cases where Thelma doesn't
+// have a purpose-built client (eg. seed steps declared in config)
+type AppClient interface {
+	// Request makes a request against the app; path is relative to the app's URL (eg. "/api/status").
+	// Like other Terra clients, a response with a status code above 299 is returned along with an error.
+	Request(method string, path string, body string) (*http.Response, string, error)
+}
+
+type appClient struct {
+	*terraClient
+	appRelease terra.AppRelease
+}
+
+func (c *appClient) Request(method string, path string, body string) (*http.Response, string, error) {
+	url := fmt.Sprintf("%s/%s", strings.TrimSuffix(c.appRelease.URL(), "/"), strings.TrimPrefix(path, "/"))
+	return c.doJsonRequest(method, url, strings.NewReader(body))
+}
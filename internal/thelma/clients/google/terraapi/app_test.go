@@ -0,0 +1,56 @@
+package terraapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/mocks"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	googleoauth "google.golang.org/api/oauth2/v2"
+)
+
+func Test_AppClientRequest(t *testing.T) {
+	fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer fake-token", r.Header.Get("Authorization"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		switch r.URL.Path {
+		case "/api/things":
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, `{"name":"thing"}`, string(body))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("Created"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("Not Found"))
+		}
+	}))
+	defer fakeServer.Close()
+
+	release := mocks.NewAppRelease(t)
+	release.On("URL").Return(fakeServer.URL + "/")
+
+	client := &appClient{
+		terraClient: &terraClient{
+			tokenSource: testutils.NewFakeTokenSource("fake-token"),
+			userInfo:    &googleoauth.Userinfo{},
+			httpClient:  *fakeServer.Client(),
+		},
+		appRelease: release,
+	}
+
+	resp, body, err := client.Request(http.MethodPost, "/api/things", `{"name":"thing"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "Created", body)
+
+	resp, _, err = client.Request(http.MethodGet, "api/missing", "")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "404")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -20,6 +20,8 @@ import (
 type TerraClient interface {
 	FirecloudOrch(release terra.AppRelease) FirecloudOrchClient
 	Sam(release terra.AppRelease) SamClient
+	// App returns a client for making arbitrary requests against any Terra app
+	App(release terra.AppRelease) AppClient
 	GoogleUserinfo() *googleoauth.Userinfo
 
 	// SetPoolStatusReporter makes the TerraClient play nice inside a pool.Job by
@@ -61,6 +63,13 @@ func (c *terraClient) Sam(appRelease terra.AppRelease) SamClient {
 	}
 }
 
+func (c *terraClient) App(appRelease terra.AppRelease) AppClient {
+	return &appClient{
+		terraClient: c,
+		appRelease:  appRelease,
+	}
+}
+
 func (c *terraClient) GoogleUserinfo() *googleoauth.Userinfo {
 	return c.userInfo
 }
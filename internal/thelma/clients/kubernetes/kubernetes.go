@@ -8,7 +8,9 @@ import (
 	"github.com/broadinstitute/thelma/internal/thelma/toolbox/kubectl"
 	"github.com/broadinstitute/thelma/internal/thelma/utils/lazy"
 	"github.com/broadinstitute/thelma/internal/thelma/utils/shell"
+	"k8s.io/client-go/dynamic"
 	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"path"
 )
@@ -22,6 +24,9 @@ type Clients interface {
 	ForRelease(release terra.Release) (k8s.Interface, error)
 	// ForKubectx returns an API client authenticated against the cluster referred to by the given Kubeconfig
 	ForKubectx(kubectx kubecfg.Kubectx) (k8s.Interface, error)
+	// DynamicForKubectx returns a dynamic API client, for custom resources that have no typed client (such as
+	// VolumeSnapshots), authenticated against the cluster referred to by the given Kubeconfig
+	DynamicForKubectx(kubectx kubecfg.Kubectx) (dynamic.Interface, error)
 	// Kubectl returns a new Kubectl
 	Kubectl() (kubectl.Kubectl, error)
 	// Kubecfg returns the Kubecfg instance used by this Clients
@@ -53,6 +58,22 @@ func (k *clients) ForRelease(release terra.Release) (k8s.Interface, error) {
 }
 
 func (k *clients) ForKubectx(kubectx kubecfg.Kubectx) (k8s.Interface, error) {
+	restConfig, err := k.restConfig(kubectx)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.NewForConfig(restConfig)
+}
+
+func (k *clients) DynamicForKubectx(kubectx kubecfg.Kubectx) (dynamic.Interface, error) {
+	restConfig, err := k.restConfig(kubectx)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(restConfig)
+}
+
+func (k *clients) restConfig(kubectx kubecfg.Kubectx) (*rest.Config, error) {
 	_kubecfg, err := k.kubecfg.Get()
 	if err != nil {
 		return nil, err
@@ -68,12 +89,7 @@ func (k *clients) ForKubectx(kubectx kubecfg.Kubectx) (k8s.Interface, error) {
 
 	clientConfig := clientcmd.NewDefaultClientConfig(*parsedKubecfg, &overrides)
 
-	restConfig, err := clientConfig.ClientConfig()
-	if err != nil {
-		return nil, err
-	}
-
-	return k8s.NewForConfig(restConfig)
+	return clientConfig.ClientConfig()
 }
 
 func (k *clients) Kubectl() (kubectl.Kubectl, error) {
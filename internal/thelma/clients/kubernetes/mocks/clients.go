@@ -6,6 +6,8 @@ import (
 	kubecfg "github.com/broadinstitute/thelma/internal/thelma/clients/kubernetes/kubecfg"
 	kubectl "github.com/broadinstitute/thelma/internal/thelma/toolbox/kubectl"
 
+	dynamic "k8s.io/client-go/dynamic"
+
 	kubernetes "k8s.io/client-go/kubernetes"
 
 	mock "github.com/stretchr/testify/mock"
@@ -26,6 +28,60 @@ func (_m *Clients) EXPECT() *Clients_Expecter {
 	return &Clients_Expecter{mock: &_m.Mock}
 }
 
+// DynamicForKubectx provides a mock function with given fields: kubectx
+func (_m *Clients) DynamicForKubectx(kubectx kubecfg.Kubectx) (dynamic.Interface, error) {
+	ret := _m.Called(kubectx)
+
+	var r0 dynamic.Interface
+	var r1 error
+	if rf, ok := ret.Get(0).(func(kubecfg.Kubectx) (dynamic.Interface, error)); ok {
+		return rf(kubectx)
+	}
+	if rf, ok := ret.Get(0).(func(kubecfg.Kubectx) dynamic.Interface); ok {
+		r0 = rf(kubectx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dynamic.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(kubecfg.Kubectx) error); ok {
+		r1 = rf(kubectx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Clients_DynamicForKubectx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DynamicForKubectx'
+type Clients_DynamicForKubectx_Call struct {
+	*mock.Call
+}
+
+// DynamicForKubectx is a helper method to define mock.On call
+//   - kubectx kubecfg.Kubectx
+func (_e *Clients_Expecter) DynamicForKubectx(kubectx interface{}) *Clients_DynamicForKubectx_Call {
+	return &Clients_DynamicForKubectx_Call{Call: _e.mock.On("DynamicForKubectx", kubectx)}
+}
+
+func (_c *Clients_DynamicForKubectx_Call) Run(run func(kubectx kubecfg.Kubectx)) *Clients_DynamicForKubectx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(kubecfg.Kubectx))
+	})
+	return _c
+}
+
+func (_c *Clients_DynamicForKubectx_Call) Return(_a0 dynamic.Interface, _a1 error) *Clients_DynamicForKubectx_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Clients_DynamicForKubectx_Call) RunAndReturn(run func(kubecfg.Kubectx) (dynamic.Interface, error)) *Clients_DynamicForKubectx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ForKubectx provides a mock function with given fields: kubectx
 func (_m *Clients) ForKubectx(kubectx kubecfg.Kubectx) (kubernetes.Interface, error) {
 	ret := _m.Called(kubectx)
@@ -0,0 +1,81 @@
+package sherlock
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/pkg/errors"
+)
+
+// metadataPrefix marks the line of an environment description that holds its EnvironmentMetadata
+const metadataPrefix = "thelma: "
+
+// legacySchedulePrefix and legacyStartOnHolidaysLine are description lines written by older versions of Thelma. They
+// are still read, but are replaced by a single metadata line whenever the description is updated.
+const legacySchedulePrefix = "thelma-schedule: "
+const legacyStartOnHolidaysLine = "thelma-start-on-holidays: true"
+
+// EnvironmentMetadata holds environment settings that Sherlock has no fields for. It's stored as a single JSON record on
+// one line of the environment's free-form description.
+type EnvironmentMetadata struct {
+	// Schedule the environment's cron or weekly stop/start schedule, if any
+	Schedule *schedule.Schedule `json:"schedule,omitempty"`
+	// StartOnHolidays if the environment's start schedule should still apply on holidays
+	StartOnHolidays bool `json:"startOnHolidays,omitempty"`
+	// Profile name of the release profile applied to the environment when it was created, if any
+	Profile string `json:"profile,omitempty"`
+	// DeleteAfterNotified the automatic deletion time the environment's owner was last notified about, if any
+	DeleteAfterNotified *time.Time `json:"deleteAfterNotified,omitempty"`
+}
+
+// EnvironmentMetadataFromDescription returns the EnvironmentMetadata stored in an environment description
+func EnvironmentMetadataFromDescription(description string) (EnvironmentMetadata, error) {
+	var metadata EnvironmentMetadata
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		if encoded, found := strings.CutPrefix(line, metadataPrefix); found {
+			var stored EnvironmentMetadata
+			if err := json.Unmarshal([]byte(encoded), &stored); err != nil {
+				return EnvironmentMetadata{}, errors.Errorf("error parsing Thelma metadata from environment description: %v", err)
+			}
+			return stored, nil
+		}
+		if encoded, found := strings.CutPrefix(line, legacySchedulePrefix); found {
+			var stored schedule.Schedule
+			if err := json.Unmarshal([]byte(encoded), &stored); err != nil {
+				return EnvironmentMetadata{}, errors.Errorf("error parsing schedule from environment description: %v", err)
+			}
+			metadata.Schedule = &stored
+		}
+		if line == legacyStartOnHolidaysLine {
+			metadata.StartOnHolidays = true
+		}
+	}
+	return metadata, nil
+}
+
+// WithDescription returns the description with this metadata stored in it, replacing any existing metadata. If the
+// metadata is empty, the metadata line is removed.
+func (m EnvironmentMetadata) WithDescription(description string) (string, error) {
+	var lines []string
+	for _, existing := range strings.Split(description, "\n") {
+		trimmed := strings.TrimSpace(existing)
+		if strings.HasPrefix(trimmed, metadataPrefix) || strings.HasPrefix(trimmed, legacySchedulePrefix) || trimmed == legacyStartOnHolidaysLine {
+			continue
+		}
+		lines = append(lines, existing)
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if m != (EnvironmentMetadata{}) {
+		encoded, err := json.Marshal(m)
+		if err != nil {
+			return "", errors.Errorf("error serializing Thelma metadata: %v", err)
+		}
+		lines = append(lines, metadataPrefix+string(encoded))
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,62 @@
+package sherlock
+
+import (
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentMetadata(t *testing.T) {
+	metadata := EnvironmentMetadata{
+		Schedule:        &schedule.Schedule{TimeZone: "UTC", Stop: &schedule.Rule{Cron: "0 19 * * *"}},
+		StartOnHolidays: true,
+	}
+
+	description, err := metadata.WithDescription("my BEE\n")
+	require.NoError(t, err)
+	assert.Equal(t, "my BEE\nthelma: {\"schedule\":{\"timeZone\":\"UTC\",\"stop\":{\"cron\":\"0 19 * * *\"}},\"startOnHolidays\":true}", description)
+
+	parsed, err := EnvironmentMetadataFromDescription(description)
+	require.NoError(t, err)
+	assert.Equal(t, metadata, parsed)
+
+	// updating replaces the existing record rather than adding another
+	metadata.StartOnHolidays = false
+	metadata.Profile = "minimal"
+	description, err = metadata.WithDescription(description)
+	require.NoError(t, err)
+	assert.Equal(t, "my BEE\nthelma: {\"schedule\":{\"timeZone\":\"UTC\",\"stop\":{\"cron\":\"0 19 * * *\"}},\"profile\":\"minimal\"}", description)
+
+	parsed, err = EnvironmentMetadataFromDescription(description)
+	require.NoError(t, err)
+	assert.Equal(t, metadata, parsed)
+
+	description, err = EnvironmentMetadata{}.WithDescription(description)
+	require.NoError(t, err)
+	assert.Equal(t, "my BEE", description)
+
+	parsed, err = EnvironmentMetadataFromDescription(description)
+	require.NoError(t, err)
+	assert.Equal(t, EnvironmentMetadata{}, parsed)
+
+	_, err = EnvironmentMetadataFromDescription("thelma: {")
+	assert.ErrorContains(t, err, "error parsing Thelma metadata")
+}
+
+func TestEnvironmentMetadata_Legacy(t *testing.T) {
+	description := "my BEE\nthelma-schedule: {\"timeZone\":\"UTC\",\"stop\":{\"cron\":\"0 19 * * *\"}}\nthelma-start-on-holidays: true"
+
+	parsed, err := EnvironmentMetadataFromDescription(description)
+	require.NoError(t, err)
+	assert.Equal(t, EnvironmentMetadata{
+		Schedule:        &schedule.Schedule{TimeZone: "UTC", Stop: &schedule.Rule{Cron: "0 19 * * *"}},
+		StartOnHolidays: true,
+	}, parsed)
+
+	// rewriting consolidates legacy lines into a single record
+	description, err = parsed.WithDescription(description)
+	require.NoError(t, err)
+	assert.Equal(t, "my BEE\nthelma: {\"schedule\":{\"timeZone\":\"UTC\",\"stop\":{\"cron\":\"0 19 * * *\"}},\"startOnHolidays\":true}", description)
+}
@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	terra "github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+
+	time "time"
 )
 
 // Client is an autogenerated mock type for the Client type
@@ -130,6 +132,57 @@ func (_c *Client_CreateEnvironmentFromTemplate_Call) RunAndReturn(run func(strin
 	return _c
 }
 
+// CurrentUser provides a mock function with given fields:
+func (_m *Client) CurrentUser() (sherlock.User, error) {
+	ret := _m.Called()
+
+	var r0 sherlock.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (sherlock.User, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() sherlock.User); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sherlock.User)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_CurrentUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CurrentUser'
+type Client_CurrentUser_Call struct {
+	*mock.Call
+}
+
+// CurrentUser is a helper method to define mock.On call
+func (_e *Client_Expecter) CurrentUser() *Client_CurrentUser_Call {
+	return &Client_CurrentUser_Call{Call: _e.mock.On("CurrentUser")}
+}
+
+func (_c *Client_CurrentUser_Call) Run(run func()) *Client_CurrentUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_CurrentUser_Call) Return(_a0 sherlock.User, _a1 error) *Client_CurrentUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_CurrentUser_Call) RunAndReturn(run func() (sherlock.User, error)) *Client_CurrentUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteEnvironments provides a mock function with given fields: _a0
 func (_m *Client) DeleteEnvironments(_a0 []terra.Environment) ([]string, error) {
 	ret := _m.Called(_a0)
@@ -590,6 +643,92 @@ func (_c *Client_ResetEnvironmentAndPinToDev_Call) RunAndReturn(run func(terra.E
 	return _c
 }
 
+// SetEnvironmentDeleteAfter provides a mock function with given fields: environmentName, after
+func (_m *Client) SetEnvironmentDeleteAfter(environmentName string, after time.Time) error {
+	ret := _m.Called(environmentName, after)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(environmentName, after)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_SetEnvironmentDeleteAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetEnvironmentDeleteAfter'
+type Client_SetEnvironmentDeleteAfter_Call struct {
+	*mock.Call
+}
+
+// SetEnvironmentDeleteAfter is a helper method to define mock.On call
+//   - environmentName string
+//   - after time.Time
+func (_e *Client_Expecter) SetEnvironmentDeleteAfter(environmentName interface{}, after interface{}) *Client_SetEnvironmentDeleteAfter_Call {
+	return &Client_SetEnvironmentDeleteAfter_Call{Call: _e.mock.On("SetEnvironmentDeleteAfter", environmentName, after)}
+}
+
+func (_c *Client_SetEnvironmentDeleteAfter_Call) Run(run func(environmentName string, after time.Time)) *Client_SetEnvironmentDeleteAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Client_SetEnvironmentDeleteAfter_Call) Return(_a0 error) *Client_SetEnvironmentDeleteAfter_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_SetEnvironmentDeleteAfter_Call) RunAndReturn(run func(string, time.Time) error) *Client_SetEnvironmentDeleteAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetEnvironmentDeleteAfterNotified provides a mock function with given fields: environmentName, after
+func (_m *Client) SetEnvironmentDeleteAfterNotified(environmentName string, after time.Time) error {
+	ret := _m.Called(environmentName, after)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(environmentName, after)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_SetEnvironmentDeleteAfterNotified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetEnvironmentDeleteAfterNotified'
+type Client_SetEnvironmentDeleteAfterNotified_Call struct {
+	*mock.Call
+}
+
+// SetEnvironmentDeleteAfterNotified is a helper method to define mock.On call
+//   - environmentName string
+//   - after time.Time
+func (_e *Client_Expecter) SetEnvironmentDeleteAfterNotified(environmentName interface{}, after interface{}) *Client_SetEnvironmentDeleteAfterNotified_Call {
+	return &Client_SetEnvironmentDeleteAfterNotified_Call{Call: _e.mock.On("SetEnvironmentDeleteAfterNotified", environmentName, after)}
+}
+
+func (_c *Client_SetEnvironmentDeleteAfterNotified_Call) Run(run func(environmentName string, after time.Time)) *Client_SetEnvironmentDeleteAfterNotified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Client_SetEnvironmentDeleteAfterNotified_Call) Return(_a0 error) *Client_SetEnvironmentDeleteAfterNotified_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_SetEnvironmentDeleteAfterNotified_Call) RunAndReturn(run func(string, time.Time) error) *Client_SetEnvironmentDeleteAfterNotified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetEnvironmentOffline provides a mock function with given fields: environmentName, offline
 func (_m *Client) SetEnvironmentOffline(environmentName string, offline bool) error {
 	ret := _m.Called(environmentName, offline)
@@ -633,6 +772,49 @@ func (_c *Client_SetEnvironmentOffline_Call) RunAndReturn(run func(string, bool)
 	return _c
 }
 
+// SetEnvironmentSchedule provides a mock function with given fields: environmentName, options
+func (_m *Client) SetEnvironmentSchedule(environmentName string, options terra.ScheduleOptions) error {
+	ret := _m.Called(environmentName, options)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, terra.ScheduleOptions) error); ok {
+		r0 = rf(environmentName, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_SetEnvironmentSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetEnvironmentSchedule'
+type Client_SetEnvironmentSchedule_Call struct {
+	*mock.Call
+}
+
+// SetEnvironmentSchedule is a helper method to define mock.On call
+//   - environmentName string
+//   - options terra.ScheduleOptions
+func (_e *Client_Expecter) SetEnvironmentSchedule(environmentName interface{}, options interface{}) *Client_SetEnvironmentSchedule_Call {
+	return &Client_SetEnvironmentSchedule_Call{Call: _e.mock.On("SetEnvironmentSchedule", environmentName, options)}
+}
+
+func (_c *Client_SetEnvironmentSchedule_Call) Run(run func(environmentName string, options terra.ScheduleOptions)) *Client_SetEnvironmentSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(terra.ScheduleOptions))
+	})
+	return _c
+}
+
+func (_c *Client_SetEnvironmentSchedule_Call) Return(_a0 error) *Client_SetEnvironmentSchedule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_SetEnvironmentSchedule_Call) RunAndReturn(run func(string, terra.ScheduleOptions) error) *Client_SetEnvironmentSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetTerraHelmfileRefForEntireEnvironment provides a mock function with given fields: environment, terraHelmfileRef
 func (_m *Client) SetTerraHelmfileRefForEntireEnvironment(environment terra.Environment, terraHelmfileRef string) error {
 	ret := _m.Called(environment, terraHelmfileRef)
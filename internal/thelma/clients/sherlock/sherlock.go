@@ -23,6 +23,7 @@ type Client interface {
 	StateWriter
 	ChartVersionUpdater
 	ChartReleaseStatusUpdater
+	Users
 	GetStatus() error
 	HttpClient() *http.Client
 }
@@ -42,6 +43,15 @@ type sherlockConfig struct {
 	OidcAddr string `default:"https://sherlock-oidc.dsp-devops-prod.broadinstitute.org"`
 }
 
+// ConfiguredAddr returns the address of the Sherlock instance that Thelma is configured to talk to
+func ConfiguredAddr(thelmaConfig config.Config) (string, error) {
+	var cfg sherlockConfig
+	if err := thelmaConfig.Unmarshal(configKey, &cfg); err != nil {
+		return "", err
+	}
+	return cfg.Addr, nil
+}
+
 // NewClient creates a Sherlock client, but you probably don't want to call it. You want to hit
 // clients.Clients.Sherlock() instead, which still accepts options but fills the all-important
 // authentication ones for you. Calling this directly is still useful for testing, though.
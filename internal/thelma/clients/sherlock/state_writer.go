@@ -10,6 +10,7 @@ import (
 	"github.com/broadinstitute/sherlock/sherlock-go-client/client/models"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
 	"github.com/broadinstitute/thelma/internal/thelma/utils"
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/strfmt"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -65,6 +66,23 @@ type StateWriter interface {
 	// Of note here is that calling this function doesn't touch Thelma's in-memory state, only Sherlock's state.
 	// Thelma's in-memory state will need to be reloaded to work with the mutated environment.
 	SetEnvironmentOffline(environmentName string, offline bool) error
+
+	// SetEnvironmentDeleteAfter enables automatic deletion for a given environment, after the given time.
+	// Of note here is that calling this function doesn't touch Thelma's in-memory state, only Sherlock's state.
+	// Thelma's in-memory state will need to be reloaded to work with the mutated environment.
+	SetEnvironmentDeleteAfter(environmentName string, after time.Time) error
+
+	// SetEnvironmentSchedule replaces the stop/start schedule settings of a given environment, disabling any
+	// schedules that aren't enabled in the options.
+	// Of note here is that calling this function doesn't touch Thelma's in-memory state, only Sherlock's state.
+	// Thelma's in-memory state will need to be reloaded to work with the mutated environment.
+	SetEnvironmentSchedule(environmentName string, options terra.ScheduleOptions) error
+
+	// SetEnvironmentDeleteAfterNotified records that the owner of a given environment was notified it will be
+	// automatically deleted after the given time.
+	// Of note here is that calling this function doesn't touch Thelma's in-memory state, only Sherlock's state.
+	// Thelma's in-memory state will need to be reloaded to work with the mutated environment.
+	SetEnvironmentDeleteAfterNotified(environmentName string, after time.Time) error
 }
 
 func (c *clientImpl) CreateEnvironmentFromTemplate(templateName string, options terra.CreateOptions) (string, error) {
@@ -90,6 +108,13 @@ func (c *clientImpl) CreateEnvironmentFromTemplate(templateName string, options
 		creatableEnvironment.OfflineScheduleEndTime = strfmt.DateTime(options.StartSchedule.RepeatingTime)
 		creatableEnvironment.OfflineScheduleEndWeekends = options.StartSchedule.Weekends
 	}
+	// Sherlock has no fields for cron or weekly schedules or profiles, so they're stored in the description
+	metadata := EnvironmentMetadata{Schedule: options.Schedule, StartOnHolidays: options.StartOnHolidays, Profile: options.Profile}
+	description, err := metadata.WithDescription(creatableEnvironment.Description)
+	if err != nil {
+		return "", err
+	}
+	creatableEnvironment.Description = description
 	created, err := c.client.Environments.PostAPIEnvironmentsV3(
 		environments.NewPostAPIEnvironmentsV3Params().WithEnvironment(creatableEnvironment))
 	if err != nil {
@@ -192,6 +217,97 @@ func (c *clientImpl) SetEnvironmentOffline(environmentName string, offline bool)
 	return err
 }
 
+func (c *clientImpl) SetEnvironmentDeleteAfter(environmentName string, after time.Time) error {
+	editableEnvironment := &models.SherlockEnvironmentV3Edit{
+		DeleteAfter: strfmt.DateTime(after),
+	}
+	_, err := c.client.Environments.PatchAPIEnvironmentsV3Selector(
+		environments.NewPatchAPIEnvironmentsV3SelectorParams().WithSelector(environmentName).WithEnvironment(editableEnvironment))
+	return err
+}
+
+func (c *clientImpl) SetEnvironmentSchedule(environmentName string, options terra.ScheduleOptions) error {
+	// Sherlock has no fields for cron or weekly schedules, so they're stored in the description. An unreadable record
+	// is replaced, since setting a new schedule is how it gets repaired.
+	description, err := c.updateEnvironmentMetadata(environmentName, true, func(metadata *EnvironmentMetadata) {
+		metadata.Schedule = options.Schedule
+		metadata.StartOnHolidays = options.StartOnHolidays
+	})
+	if err != nil {
+		return err
+	}
+
+	// The edit model omits false booleans and empty strings, so it can't disable a schedule or clear a description;
+	// send the fields as-is instead
+	fields := map[string]interface{}{
+		"description":                 description,
+		"offlineScheduleBeginEnabled": options.StopSchedule.Enabled,
+		"offlineScheduleEndEnabled":   options.StartSchedule.Enabled,
+		"offlineScheduleEndWeekends":  options.StartSchedule.Weekends,
+	}
+	if options.StopSchedule.Enabled {
+		fields["offlineScheduleBeginTime"] = strfmt.DateTime(options.StopSchedule.RepeatingTime)
+	}
+	if options.StartSchedule.Enabled {
+		fields["offlineScheduleEndTime"] = strfmt.DateTime(options.StartSchedule.RepeatingTime)
+	}
+	_, err = c.client.Environments.PatchAPIEnvironmentsV3Selector(
+		environments.NewPatchAPIEnvironmentsV3SelectorParams().WithSelector(environmentName),
+		withRawBody(environmentName, fields))
+	if err != nil {
+		return errors.Errorf("error from Sherlock setting schedule for environment %s: %v", environmentName, err)
+	}
+	return nil
+}
+
+func (c *clientImpl) SetEnvironmentDeleteAfterNotified(environmentName string, after time.Time) error {
+	// an unreadable record isn't replaced here, since that would silently wipe the schedule stored in it
+	description, err := c.updateEnvironmentMetadata(environmentName, false, func(metadata *EnvironmentMetadata) {
+		metadata.DeleteAfterNotified = &after
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.Environments.PatchAPIEnvironmentsV3Selector(
+		environments.NewPatchAPIEnvironmentsV3SelectorParams().WithSelector(environmentName),
+		withRawBody(environmentName, map[string]interface{}{"description": description}))
+	if err != nil {
+		return errors.Errorf("error from Sherlock recording expiry notification for environment %s: %v", environmentName, err)
+	}
+	return nil
+}
+
+// updateEnvironmentMetadata returns the environment's description with its EnvironmentMetadata modified by fn. An
+// unreadable metadata record is an error unless replaceUnreadable is set, in which case it's replaced.
+func (c *clientImpl) updateEnvironmentMetadata(environmentName string, replaceUnreadable bool, fn func(metadata *EnvironmentMetadata)) (string, error) {
+	environment, err := c.getEnvironment(environmentName)
+	if err != nil {
+		return "", err
+	}
+	metadata, err := EnvironmentMetadataFromDescription(environment.Description)
+	if err != nil {
+		if !replaceUnreadable {
+			return "", errors.Errorf("environment %s has unreadable Thelma metadata, fix it with `thelma bee schedule set`: %v", environmentName, err)
+		}
+		log.Warn().Msgf("replacing unreadable Thelma metadata for environment %s: %v", environmentName, err)
+	}
+	fn(&metadata)
+	return metadata.WithDescription(environment.Description)
+}
+
+// withRawBody replaces the body of a PATCH request to a selector endpoint, for fields the generated edit models can't
+// represent
+func withRawBody(selector string, body map[string]interface{}) func(*runtime.ClientOperation) {
+	return func(op *runtime.ClientOperation) {
+		op.Params = runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+			if err := r.SetPathParam("selector", selector); err != nil {
+				return err
+			}
+			return r.SetBodyParam(body)
+		})
+	}
+}
+
 // WriteEnvironments will take a list of terra.Environment interfaces them and issue POST requests
 // to write both the environment and any releases within that environment. 409 Conflict responses are ignored
 func (c *clientImpl) WriteEnvironments(envs []terra.Environment) ([]string, error) {
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/broadinstitute/sherlock/sherlock-go-client/client/environments"
 	"github.com/broadinstitute/sherlock/sherlock-go-client/client/models"
@@ -136,6 +137,110 @@ func (suite *sherlockStateWriterClientSuite) TestErrorOnDelete() {
 	suite.Assert().Error(err)
 }
 
+func (suite *sherlockStateWriterClientSuite) TestSetEnvironmentSchedule() {
+	var patched map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/environments/v3/my-bee", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			suite.Require().NoError(json.NewDecoder(r.Body).Decode(&patched))
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(&models.SherlockEnvironmentV3{
+			Name:        "my-bee",
+			Description: "my BEE\nthelma-schedule: {\"timeZone\":\"UTC\",\"stop\":{\"cron\":\"0 19 * * *\"}}",
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := sherlock.NewClient(func(options *sherlock.Options) {
+		options.Addr = server.URL
+		options.IapTokenProvider = &credentials.MockTokenProvider{ReturnString: testIapToken}
+		options.GhaOidcTokenProvider = &credentials.MockTokenProvider{ReturnNil: true}
+	})
+	suite.Require().NoError(err)
+
+	var opts terra.ScheduleOptions
+	opts.StopSchedule.Enabled = true
+	opts.StopSchedule.RepeatingTime = time.Date(2022, 1, 1, 19, 0, 0, 0, time.UTC)
+	opts.StartOnHolidays = true
+	suite.Require().NoError(client.SetEnvironmentSchedule("my-bee", opts))
+
+	// the cron schedule is removed, and disabled schedules are sent explicitly rather than omitted
+	suite.Assert().Equal("my BEE\nthelma: {\"startOnHolidays\":true}", patched["description"])
+	suite.Assert().Equal(true, patched["offlineScheduleBeginEnabled"])
+	suite.Assert().Equal("2022-01-01T19:00:00.000Z", patched["offlineScheduleBeginTime"])
+	suite.Assert().Equal(false, patched["offlineScheduleEndEnabled"])
+	suite.Assert().Equal(false, patched["offlineScheduleEndWeekends"])
+	suite.Assert().NotContains(patched, "offlineScheduleEndTime")
+}
+
+func (suite *sherlockStateWriterClientSuite) TestSetEnvironmentDeleteAfterNotified() {
+	var patched map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/environments/v3/my-bee", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			suite.Require().NoError(json.NewDecoder(r.Body).Decode(&patched))
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(&models.SherlockEnvironmentV3{
+			Name:        "my-bee",
+			Description: "my BEE\nthelma: {\"profile\":\"minimal\"}",
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := sherlock.NewClient(func(options *sherlock.Options) {
+		options.Addr = server.URL
+		options.IapTokenProvider = &credentials.MockTokenProvider{ReturnString: testIapToken}
+		options.GhaOidcTokenProvider = &credentials.MockTokenProvider{ReturnNil: true}
+	})
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(client.SetEnvironmentDeleteAfterNotified("my-bee", time.Date(2022, 1, 2, 15, 4, 0, 0, time.UTC)))
+
+	// other metadata is preserved
+	suite.Assert().Equal("my BEE\nthelma: {\"profile\":\"minimal\",\"deleteAfterNotified\":\"2022-01-02T15:04:00Z\"}", patched["description"])
+	suite.Assert().Len(patched, 1)
+}
+
+func (suite *sherlockStateWriterClientSuite) TestUnreadableEnvironmentMetadata() {
+	var patched map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/environments/v3/my-bee", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			suite.Require().NoError(json.NewDecoder(r.Body).Decode(&patched))
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(&models.SherlockEnvironmentV3{
+			Name:        "my-bee",
+			Description: "my BEE\nthelma: {\"schedule\":",
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := sherlock.NewClient(func(options *sherlock.Options) {
+		options.Addr = server.URL
+		options.IapTokenProvider = &credentials.MockTokenProvider{ReturnString: testIapToken}
+		options.GhaOidcTokenProvider = &credentials.MockTokenProvider{ReturnNil: true}
+	})
+	suite.Require().NoError(err)
+
+	// recording a notification doesn't wipe the broken record
+	err = client.SetEnvironmentDeleteAfterNotified("my-bee", time.Date(2022, 1, 2, 15, 4, 0, 0, time.UTC))
+	suite.Assert().ErrorContains(err, "unreadable Thelma metadata")
+	suite.Assert().Nil(patched)
+
+	// setting a schedule replaces it
+	suite.Require().NoError(client.SetEnvironmentSchedule("my-bee", terra.ScheduleOptions{StartOnHolidays: true}))
+	suite.Assert().Equal("my BEE\nthelma: {\"startOnHolidays\":true}", patched["description"])
+}
+
 func constructFakeState(t *testing.T) terra.State {
 	//nolint:staticcheck // SA1019
 	fixture, err := statefixtures.LoadFixture(statefixtures.Default)
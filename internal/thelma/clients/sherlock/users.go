@@ -0,0 +1,49 @@
+package sherlock
+
+import (
+	"github.com/broadinstitute/sherlock/sherlock-go-client/client/models"
+	"github.com/broadinstitute/sherlock/sherlock-go-client/client/users"
+	"github.com/pkg/errors"
+)
+
+// Users looks up Sherlock users
+type Users interface {
+	// CurrentUser returns the Sherlock user that Thelma is authenticated as
+	CurrentUser() (User, error)
+}
+
+// User is a Sherlock user
+type User struct {
+	// Email address of the user
+	Email string
+	// Roles names of the Sherlock roles assigned to the user; suspended assignments are not included
+	Roles []string
+}
+
+func (c *clientImpl) CurrentUser() (User, error) {
+	// Sherlock treats "self" as the authenticated user
+	response, err := c.client.Users.GetAPIUsersV3Selector(users.NewGetAPIUsersV3SelectorParams().WithSelector("self"))
+	if err != nil {
+		return User{}, errors.Errorf("error looking up current user in Sherlock: %v", err)
+	}
+	if response.Payload == nil {
+		return User{}, errors.Errorf("error reading Sherlock response, it didn't respond with an error but the client library couldn't parse a payload")
+	}
+	return fromSherlockUser(response.Payload), nil
+}
+
+func fromSherlockUser(user *models.SherlockUserV3) User {
+	result := User{Email: user.Email}
+	for _, assignment := range user.Assignments {
+		if assignment == nil || assignment.Suspended {
+			continue
+		}
+		// role info is an untyped nested role object
+		if role, ok := assignment.RoleInfo.(map[string]interface{}); ok {
+			if name, ok := role["name"].(string); ok && name != "" {
+				result.Roles = append(result.Roles, name)
+			}
+		}
+	}
+	return result
+}
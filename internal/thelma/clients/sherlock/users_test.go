@@ -0,0 +1,44 @@
+package sherlock_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/credentials"
+	"github.com/broadinstitute/thelma/internal/thelma/clients/sherlock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CurrentUser(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/users/v3/self", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{
+  "email": "someone@broadinstitute.org",
+  "assignments": [
+    {"roleInfo": {"name": "bee-admins"}},
+    {"roleInfo": {"name": "suspended-role"}, "suspended": true},
+    {"roleInfo": {"name": "all-users"}}
+  ]
+}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := sherlock.NewClient(func(options *sherlock.Options) {
+		options.Addr = server.URL
+		options.IapTokenProvider = &credentials.MockTokenProvider{ReturnString: testIapToken}
+		options.GhaOidcTokenProvider = &credentials.MockTokenProvider{ReturnNil: true}
+	})
+	require.NoError(t, err)
+
+	user, err := client.CurrentUser()
+	require.NoError(t, err)
+	assert.Equal(t, sherlock.User{
+		Email: "someone@broadinstitute.org",
+		Roles: []string{"bee-admins", "all-users"},
+	}, user)
+}
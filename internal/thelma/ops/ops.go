@@ -2,6 +2,7 @@
 package ops
 
 import (
+	"github.com/broadinstitute/thelma/internal/thelma/app/config"
 	"github.com/broadinstitute/thelma/internal/thelma/clients"
 	"github.com/broadinstitute/thelma/internal/thelma/ops/artifacts"
 	"github.com/broadinstitute/thelma/internal/thelma/ops/logs"
@@ -17,13 +18,15 @@ type Ops interface {
 	Sync() (sync.Sync, error)
 }
 
-func NewOps(clients clients.Clients) Ops {
+func NewOps(thelmaConfig config.Config, clients clients.Clients) Ops {
 	return &ops{
+		config:  thelmaConfig,
 		clients: clients,
 	}
 }
 
 type ops struct {
+	config  config.Config
 	clients clients.Clients
 }
 
@@ -58,5 +61,5 @@ func (o *ops) Sync() (sync.Sync, error) {
 	if err != nil {
 		return nil, err
 	}
-	return sync.New(argocd, statusReader, sherlock), nil
+	return sync.New(o.config, argocd, statusReader, sherlock)
 }
@@ -2,7 +2,9 @@ package sync
 
 import (
 	"fmt"
+	"github.com/broadinstitute/thelma/internal/thelma/app/config"
 	"github.com/broadinstitute/thelma/internal/thelma/app/metrics/labels"
+	"github.com/broadinstitute/thelma/internal/thelma/charts/dependency"
 	"github.com/broadinstitute/thelma/internal/thelma/clients/sherlock"
 	"github.com/broadinstitute/thelma/internal/thelma/ops/status"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
@@ -17,28 +19,97 @@ import (
 
 const waitHealthyPollingInterval = 30 * time.Second
 
+const configKey = "sync"
+
+type syncConfig struct {
+	// Dependencies maps chart names to the names of charts that must be synced and healthy first.
+	// Eg. {"sam": ["sam-postgres"]} means sam-postgres is synced before sam in the same sync.
+	Dependencies map[string][]string
+}
+
 type Sync interface {
 	// Sync will sync the Argo app(s) for a set of releases, wait for them to be healthy,
 	// and generate and return status reports (useful for understanding why a sync failed).
+	// If chart dependencies are configured, releases are synced in waves, so that each release's
+	// dependencies are synced (and healthy, if waiting for health) before it is.
 	Sync(releases []terra.Release, maxParallel int, options ...argocd.SyncOption) (map[terra.Release]*status.Status, error)
 }
 
-func New(argocd argocd.ArgoCD, statusReader status.Reader, sherlockUpdater sherlock.ChartReleaseStatusUpdater) Sync {
+func New(thelmaConfig config.Config, argocd argocd.ArgoCD, statusReader status.Reader, sherlockUpdater sherlock.ChartReleaseStatusUpdater) (Sync, error) {
+	var cfg syncConfig
+	if err := thelmaConfig.Unmarshal(configKey, &cfg); err != nil {
+		return nil, err
+	}
+	graph, err := buildDependencyGraph(cfg.Dependencies)
+	if err != nil {
+		return nil, errors.Errorf("invalid %s.dependencies config: %v", configKey, err)
+	}
 	return &syncer{
 		argocd:          argocd,
 		statusReader:    statusReader,
 		sherlockUpdater: sherlockUpdater,
-	}
+		dependencies:    graph,
+	}, nil
 }
 
 type syncer struct {
 	argocd          argocd.ArgoCD
 	statusReader    status.Reader
 	sherlockUpdater sherlock.ChartReleaseStatusUpdater
+	// dependencies is nil if no chart dependencies are configured
+	dependencies *dependency.Graph
 }
 
 // Sync a set of releases and return a status report indicating whether the release is healthy.
 func (s *syncer) Sync(releases []terra.Release, maxParallel int, options ...argocd.SyncOption) (map[terra.Release]*status.Status, error) {
+	statusMap := make(map[terra.Release]*status.Status)
+	var mutex sync.Mutex
+
+	waves := s.planWaves(releases)
+	for i, wave := range waves {
+		if len(waves) > 1 {
+			log.Info().Msgf("Syncing wave %d of %d (%d releases)", i+1, len(waves), len(wave))
+		}
+		if err := s.syncWave(wave, maxParallel, statusMap, &mutex, options...); err != nil {
+			if remaining := len(waves) - i - 1; remaining > 0 {
+				return statusMap, errors.Errorf("%v (skipped %d remaining wave(s) that depend on this one)", err, remaining)
+			}
+			return statusMap, err
+		}
+	}
+
+	return statusMap, nil
+}
+
+// planWaves groups releases into waves according to configured chart dependencies. If no dependencies are
+// configured, all releases are synced in a single wave.
+func (s *syncer) planWaves(releases []terra.Release) [][]terra.Release {
+	if s.dependencies == nil || len(releases) == 0 {
+		return [][]terra.Release{releases}
+	}
+
+	byChart := make(map[string][]terra.Release)
+	var chartNames []string
+	for _, release := range releases {
+		if _, exists := byChart[release.ChartName()]; !exists {
+			chartNames = append(chartNames, release.ChartName())
+		}
+		byChart[release.ChartName()] = append(byChart[release.ChartName()], release)
+	}
+
+	var waves [][]terra.Release
+	for _, chartWave := range s.dependencies.Waves(chartNames) {
+		var wave []terra.Release
+		for _, chartName := range chartWave {
+			wave = append(wave, byChart[chartName]...)
+		}
+		waves = append(waves, wave)
+	}
+	return waves
+}
+
+// syncWave syncs a set of releases in parallel, recording statuses in the status map
+func (s *syncer) syncWave(releases []terra.Release, maxParallel int, statusMap map[terra.Release]*status.Status, mutex *sync.Mutex, options ...argocd.SyncOption) error {
 	var jobs []pool.Job
 
 	waitHealthyTimeout := s.extractWaitHealthy(options)
@@ -49,9 +120,6 @@ func (s *syncer) Sync(releases []terra.Release, maxParallel int, options ...argo
 
 	destination, hasSingleDestination := checkIfSingleDestination(releases)
 
-	statusMap := make(map[terra.Release]*status.Status)
-	var mutex sync.Mutex
-
 	for _, unsafe := range releases {
 		release := unsafe
 
@@ -106,9 +174,7 @@ func (s *syncer) Sync(releases []terra.Release, maxParallel int, options ...argo
 		options.Metrics.PoolName = "ops_sync"
 	})
 
-	err := _pool.Execute()
-
-	return statusMap, err
+	return _pool.Execute()
 }
 
 // waitHealthy waits for a release's primary ArgoCD application to be healthy. If:
@@ -160,6 +226,25 @@ func updateStatus(status *status.Status, statusReporter pool.StatusReporter) {
 	statusReporter.Update(pool.Status{Message: status.Headline()})
 }
 
+// buildDependencyGraph builds a dependency graph from configured chart dependencies, returning nil if none
+// are configured
+func buildDependencyGraph(dependencies map[string][]string) (*dependency.Graph, error) {
+	if len(dependencies) == 0 {
+		return nil, nil
+	}
+	// every chart referenced as a dependency must be a node in the graph
+	all := make(map[string][]string)
+	for chartName, deps := range dependencies {
+		all[chartName] = deps
+		for _, dep := range deps {
+			if _, exists := all[dep]; !exists {
+				all[dep] = nil
+			}
+		}
+	}
+	return dependency.NewGraph(all)
+}
+
 func checkIfSingleDestination(releases []terra.Release) (terra.Destination, bool) {
 	var destination terra.Destination
 	for _, release := range releases {
@@ -0,0 +1,79 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/app/config"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	statemocks "github.com/broadinstitute/thelma/internal/thelma/state/api/terra/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_planWaves(t *testing.T) {
+	sam := mockRelease("sam")
+	samPostgres := mockRelease("sam-postgres")
+	rawls := mockRelease("rawls")
+	leonardo := mockRelease("leonardo")
+	releases := []terra.Release{leonardo, sam, rawls, samPostgres}
+
+	t.Run("no dependencies configured", func(t *testing.T) {
+		s := newTestSyncer(t, nil)
+		assert.Equal(t, [][]terra.Release{releases}, s.planWaves(releases))
+	})
+
+	t.Run("dependencies configured", func(t *testing.T) {
+		s := newTestSyncer(t, map[string]interface{}{
+			"sync.dependencies": map[string]interface{}{
+				"sam":      []string{"sam-postgres"},
+				"rawls":    []string{"sam"},
+				"leonardo": []string{"sam"},
+			},
+		})
+		assert.Equal(t, [][]terra.Release{
+			{samPostgres},
+			{sam},
+			{leonardo, rawls},
+		}, s.planWaves(releases))
+
+		// dependencies that aren't being synced don't add waves
+		assert.Equal(t, [][]terra.Release{
+			{samPostgres},
+			{rawls},
+		}, s.planWaves([]terra.Release{rawls, samPostgres}))
+	})
+}
+
+func Test_NewRejectsDependencyCycles(t *testing.T) {
+	thelmaConfig, err := config.NewTestConfig(t, map[string]interface{}{
+		"sync.dependencies": map[string]interface{}{
+			"sam":   []string{"rawls"},
+			"rawls": []string{"sam"},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = New(thelmaConfig, nil, nil, nil)
+	assert.ErrorContains(t, err, "invalid sync.dependencies config: cycle detected")
+}
+
+func newTestSyncer(t *testing.T, settings map[string]interface{}) *syncer {
+	var thelmaConfig config.Config
+	var err error
+	if settings == nil {
+		thelmaConfig, err = config.NewTestConfig(t)
+	} else {
+		thelmaConfig, err = config.NewTestConfig(t, settings)
+	}
+	require.NoError(t, err)
+
+	s, err := New(thelmaConfig, nil, nil, nil)
+	require.NoError(t, err)
+	return s.(*syncer)
+}
+
+func mockRelease(chartName string) terra.Release {
+	r := &statemocks.Release{}
+	r.EXPECT().ChartName().Return(chartName)
+	return r
+}
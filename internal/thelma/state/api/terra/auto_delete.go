@@ -8,4 +8,7 @@ type AutoDelete interface {
 	Enabled() bool
 	// After point in time after which the environment should be automatically deleted
 	After() time.Time
+	// NotifiedFor the deletion time the environment's owner was last notified about, or the zero time if they
+	// haven't been notified
+	NotifiedFor() time.Time
 }
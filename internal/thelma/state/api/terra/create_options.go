@@ -2,6 +2,8 @@ package terra
 
 import (
 	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
 )
 
 // CreateOptions options for creating a new dynamic environment
@@ -15,7 +17,14 @@ type CreateOptions struct {
 	}
 	// Owner optional - owner to assign to the environment
 	Owner string
+	// Profile optional - name of the release profile being applied to the environment, recorded in state
+	Profile string
+
+	ScheduleOptions
+}
 
+// ScheduleOptions when a dynamic environment should be stopped and started
+type ScheduleOptions struct {
 	// StopSchedule an optional daily time to stop the BEE
 	StopSchedule struct {
 		Enabled       bool
@@ -28,4 +37,24 @@ type CreateOptions struct {
 		RepeatingTime time.Time
 		Weekends      bool
 	}
+
+	// Schedule an optional cron or weekly schedule to stop and start the BEE, used instead of StopSchedule and
+	// StartSchedule
+	Schedule *schedule.Schedule
+
+	// StartOnHolidays if true, the start schedule still applies on holidays
+	StartOnHolidays bool
+}
+
+// ScheduleOptionsOf returns the schedule settings of an existing environment
+func ScheduleOptionsOf(env Environment) ScheduleOptions {
+	var opts ScheduleOptions
+	opts.StopSchedule.Enabled = env.OfflineScheduleBeginEnabled()
+	opts.StopSchedule.RepeatingTime = env.OfflineScheduleBeginTime()
+	opts.StartSchedule.Enabled = env.OfflineScheduleEndEnabled()
+	opts.StartSchedule.RepeatingTime = env.OfflineScheduleEndTime()
+	opts.StartSchedule.Weekends = env.OfflineScheduleEndWeekends()
+	opts.Schedule = env.OfflineSchedule()
+	opts.StartOnHolidays = env.StartOnHolidays()
+	return opts
 }
@@ -0,0 +1,426 @@
+package diff
+
+import (
+	"fmt"
+	gosort "sort"
+	"strings"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/compare"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/sort"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+)
+
+// ChangeType describes how an environment, cluster, or release differs between two states
+type ChangeType string
+
+const (
+	// Added means the entity exists in the new state but not the old
+	Added ChangeType = "added"
+	// Removed means the entity exists in the old state but not the new
+	Removed ChangeType = "removed"
+	// Changed means the entity exists in both states, but one or more of its fields differ
+	Changed ChangeType = "changed"
+)
+
+// symbol returns a short prefix used to identify this change type in text output
+func (c ChangeType) symbol() string {
+	switch c {
+	case Added:
+		return "+"
+	case Removed:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// Diff is the set of differences between two terra.State instances
+type Diff struct {
+	Clusters     []DestinationChange `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+	Environments []DestinationChange `json:"environments,omitempty" yaml:"environments,omitempty"`
+	Releases     []ReleaseChange     `json:"releases,omitempty" yaml:"releases,omitempty"`
+}
+
+// DestinationChange describes a cluster or environment that was added, removed, or changed
+type DestinationChange struct {
+	Name   string        `json:"name" yaml:"name"`
+	Change ChangeType    `json:"change" yaml:"change"`
+	Fields []FieldChange `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// ReleaseChange describes a release that was added, removed, or changed
+type ReleaseChange struct {
+	Name        string        `json:"name" yaml:"name"`
+	Destination string        `json:"destination" yaml:"destination"`
+	Change      ChangeType    `json:"change" yaml:"change"`
+	Fields      []FieldChange `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// FieldChange describes a single field that differs between two versions of the same entity
+type FieldChange struct {
+	Field string `json:"field" yaml:"field"`
+	Old   string `json:"old" yaml:"old"`
+	New   string `json:"new" yaml:"new"`
+}
+
+// States computes the differences between two states
+func States(old terra.State, new terra.State) (*Diff, error) {
+	var d Diff
+
+	oldClusters, err := clusterDestinations(old)
+	if err != nil {
+		return nil, err
+	}
+	newClusters, err := clusterDestinations(new)
+	if err != nil {
+		return nil, err
+	}
+	d.Clusters = diffDestinations(oldClusters, newClusters, func(o terra.Destination, n terra.Destination) []FieldChange {
+		return Clusters(o.(terra.Cluster), n.(terra.Cluster))
+	})
+
+	oldEnvironments, err := environmentDestinations(old)
+	if err != nil {
+		return nil, err
+	}
+	newEnvironments, err := environmentDestinations(new)
+	if err != nil {
+		return nil, err
+	}
+	d.Environments = diffDestinations(oldEnvironments, newEnvironments, func(o terra.Destination, n terra.Destination) []FieldChange {
+		return Environments(o.(terra.Environment), n.(terra.Environment))
+	})
+
+	oldReleases, err := old.Releases().All()
+	if err != nil {
+		return nil, err
+	}
+	newReleases, err := new.Releases().All()
+	if err != nil {
+		return nil, err
+	}
+	d.Releases = diffReleases(oldReleases, newReleases)
+
+	return &d, nil
+}
+
+// Empty returns true if there are no differences
+func (d *Diff) Empty() bool {
+	return len(d.Clusters) == 0 && len(d.Environments) == 0 && len(d.Releases) == 0
+}
+
+// String renders the diff in a human-readable format, with one line per added (+), removed (-), or changed (~)
+// entity, followed by changed fields. Eg.
+//
+//	~ release sam in dev
+//	    appVersion: 1.2.3 -> 1.2.4
+func (d *Diff) String() string {
+	if d.Empty() {
+		return "no differences"
+	}
+	var sb strings.Builder
+	for _, c := range d.Clusters {
+		writeChange(&sb, c.Change, fmt.Sprintf("cluster %s", c.Name), c.Fields)
+	}
+	for _, c := range d.Environments {
+		writeChange(&sb, c.Change, fmt.Sprintf("environment %s", c.Name), c.Fields)
+	}
+	for _, c := range d.Releases {
+		writeChange(&sb, c.Change, fmt.Sprintf("release %s in %s", c.Name, c.Destination), c.Fields)
+	}
+	return sb.String()
+}
+
+// Clusters returns field-level differences between two clusters
+func Clusters(old terra.Cluster, new terra.Cluster) []FieldChange {
+	var fields fieldChanges
+	fields.add("base", old.Base(), new.Base())
+	fields.add("address", old.Address(), new.Address())
+	fields.add("project", old.Project(), new.Project())
+	fields.add("location", old.Location(), new.Location())
+	fields.add("requiredRole", old.RequiredRole(), new.RequiredRole())
+	fields.add("terraHelmfileRef", old.TerraHelmfileRef(), new.TerraHelmfileRef())
+	return fields
+}
+
+// Environments returns field-level differences between two environments
+func Environments(old terra.Environment, new terra.Environment) []FieldChange {
+	var fields fieldChanges
+	fields.add("base", old.Base(), new.Base())
+	fields.add("lifecycle", old.Lifecycle().String(), new.Lifecycle().String())
+	fields.add("template", old.Template(), new.Template())
+	fields.add("defaultCluster", destinationName(old.DefaultCluster()), destinationName(new.DefaultCluster()))
+	fields.add("namespace", old.Namespace(), new.Namespace())
+	fields.add("owner", old.Owner(), new.Owner())
+	fields.add("requiredRole", old.RequiredRole(), new.RequiredRole())
+	fields.add("terraHelmfileRef", old.TerraHelmfileRef(), new.TerraHelmfileRef())
+	fields.add("preventDeletion", fmt.Sprint(old.PreventDeletion()), fmt.Sprint(new.PreventDeletion()))
+	fields.add("autoDelete", autoDelete(old), autoDelete(new))
+	fields.add("offline", fmt.Sprint(old.Offline()), fmt.Sprint(new.Offline()))
+	fields.add("stopSchedule", stopSchedule(old), stopSchedule(new))
+	fields.add("startSchedule", startSchedule(old), startSchedule(new))
+	return fields
+}
+
+// Releases returns field-level differences between two releases
+func Releases(old terra.Release, new terra.Release) []FieldChange {
+	var fields fieldChanges
+	fields.add("chart", old.ChartName(), new.ChartName())
+	fields.add("repo", old.Repo(), new.Repo())
+	fields.add("chartVersion", old.ChartVersion(), new.ChartVersion())
+	fields.add("appVersion", old.AppVersion(), new.AppVersion())
+	fields.add("terraHelmfileRef", old.TerraHelmfileRef(), new.TerraHelmfileRef())
+	fields.add("cluster", old.ClusterName(), new.ClusterName())
+	fields.add("namespace", old.Namespace(), new.Namespace())
+	return fields
+}
+
+// ReleasesIn compares the releases in two environments, lining them up by name rather than by destination, so that
+// eg. a BEE can be compared against its template or against dev. Only versions are compared, since cluster and
+// namespace always differ between environments. Releases only in new are Added; releases only in old are Removed.
+func ReleasesIn(old terra.Environment, new terra.Environment) []ReleaseChange {
+	oldByName := make(map[string]terra.Release)
+	for _, r := range old.Releases() {
+		oldByName[r.Name()] = r
+	}
+	newByName := make(map[string]terra.Release)
+	for _, r := range new.Releases() {
+		newByName[r.Name()] = r
+	}
+
+	var all []terra.Release
+	all = append(all, new.Releases()...)
+	for _, r := range old.Releases() {
+		if _, exists := newByName[r.Name()]; !exists {
+			all = append(all, r)
+		}
+	}
+	gosort.Slice(all, func(i, j int) bool {
+		return compare.Releases(all[i], all[j]) < 0
+	})
+
+	var changes []ReleaseChange
+	for _, r := range all {
+		o, inOld := oldByName[r.Name()]
+		n, inNew := newByName[r.Name()]
+		change := ReleaseChange{Name: r.Name(), Destination: r.Destination().Name()}
+		switch {
+		case !inOld:
+			change.Change = Added
+		case !inNew:
+			change.Change = Removed
+		default:
+			change.Change = Changed
+			change.Fields = releaseVersions(o, n)
+			if len(change.Fields) == 0 {
+				continue
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// releaseVersions returns differences in the version fields of two releases
+func releaseVersions(old terra.Release, new terra.Release) []FieldChange {
+	var fields fieldChanges
+	fields.add("chartVersion", old.ChartVersion(), new.ChartVersion())
+	fields.add("appVersion", old.AppVersion(), new.AppVersion())
+	fields.add("terraHelmfileRef", old.TerraHelmfileRef(), new.TerraHelmfileRef())
+	return fields
+}
+
+type fieldChanges []FieldChange
+
+func (f *fieldChanges) add(field string, old string, new string) {
+	if old == new {
+		return
+	}
+	*f = append(*f, FieldChange{Field: field, Old: old, New: new})
+}
+
+// diffDestinations compares two lists of destinations by name, returning changes in sorted order
+func diffDestinations(old []terra.Destination, new []terra.Destination, fieldsFn func(terra.Destination, terra.Destination) []FieldChange) []DestinationChange {
+	oldByName := make(map[string]terra.Destination)
+	for _, d := range old {
+		oldByName[d.Name()] = d
+	}
+	newByName := make(map[string]terra.Destination)
+	for _, d := range new {
+		newByName[d.Name()] = d
+	}
+
+	var all []terra.Destination
+	all = append(all, new...)
+	for _, d := range old {
+		if _, exists := newByName[d.Name()]; !exists {
+			all = append(all, d)
+		}
+	}
+	sort.Destinations(all)
+
+	var changes []DestinationChange
+	for _, d := range all {
+		o, inOld := oldByName[d.Name()]
+		n, inNew := newByName[d.Name()]
+		switch {
+		case !inOld:
+			changes = append(changes, DestinationChange{Name: d.Name(), Change: Added})
+		case !inNew:
+			changes = append(changes, DestinationChange{Name: d.Name(), Change: Removed})
+		default:
+			if fields := fieldsFn(o, n); len(fields) > 0 {
+				changes = append(changes, DestinationChange{Name: d.Name(), Change: Changed, Fields: fields})
+			}
+		}
+	}
+	return changes
+}
+
+// diffReleases compares two lists of releases by name and destination, returning changes in sorted order
+func diffReleases(old []terra.Release, new []terra.Release) []ReleaseChange {
+	oldByKey := make(map[string]terra.Release)
+	for _, r := range old {
+		oldByKey[releaseKey(r)] = r
+	}
+	newByKey := make(map[string]terra.Release)
+	for _, r := range new {
+		newByKey[releaseKey(r)] = r
+	}
+
+	var all []terra.Release
+	all = append(all, new...)
+	for _, r := range old {
+		if _, exists := newByKey[releaseKey(r)]; !exists {
+			all = append(all, r)
+		}
+	}
+	sort.Releases(all)
+
+	var changes []ReleaseChange
+	for _, r := range all {
+		key := releaseKey(r)
+		o, inOld := oldByKey[key]
+		n, inNew := newByKey[key]
+		change := ReleaseChange{Name: r.Name(), Destination: r.Destination().Name()}
+		switch {
+		case !inOld:
+			change.Change = Added
+		case !inNew:
+			change.Change = Removed
+		default:
+			change.Change = Changed
+			change.Fields = Releases(o, n)
+			if len(change.Fields) == 0 {
+				continue
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func releaseKey(r terra.Release) string {
+	return r.Name() + "/" + r.Destination().Name()
+}
+
+func clusterDestinations(state terra.State) ([]terra.Destination, error) {
+	clusters, err := state.Clusters().All()
+	if err != nil {
+		return nil, err
+	}
+	var result []terra.Destination
+	for _, c := range clusters {
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+func environmentDestinations(state terra.State) ([]terra.Destination, error) {
+	environments, err := state.Environments().All()
+	if err != nil {
+		return nil, err
+	}
+	var result []terra.Destination
+	for _, e := range environments {
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+func destinationName(d terra.Destination) string {
+	if d == nil {
+		return ""
+	}
+	return d.Name()
+}
+
+func autoDelete(env terra.Environment) string {
+	if env.AutoDelete() == nil || !env.AutoDelete().Enabled() {
+		return "disabled"
+	}
+	return env.AutoDelete().After().UTC().Format(time.RFC3339)
+}
+
+// stopSchedule renders an environment's daily stop schedule. Schedules repeat daily, so only the time of day
+// is significant.
+func stopSchedule(env terra.Environment) string {
+	if s := env.OfflineSchedule(); s != nil {
+		return formatRule(s.Stop, s.TimeZone)
+	}
+	if !env.OfflineScheduleBeginEnabled() {
+		return "disabled"
+	}
+	return formatTimeOfDay(env.OfflineScheduleBeginTime())
+}
+
+// startSchedule renders an environment's daily start schedule
+func startSchedule(env terra.Environment) string {
+	var s string
+	if offlineSchedule := env.OfflineSchedule(); offlineSchedule != nil {
+		if offlineSchedule.Start == nil {
+			return "disabled"
+		}
+		s = formatRule(offlineSchedule.Start, offlineSchedule.TimeZone)
+	} else {
+		if !env.OfflineScheduleEndEnabled() {
+			return "disabled"
+		}
+		s = formatTimeOfDay(env.OfflineScheduleEndTime())
+		if env.OfflineScheduleEndWeekends() {
+			s += " (including weekends)"
+		}
+	}
+	if env.StartOnHolidays() {
+		s += " (including holidays)"
+	}
+	return s
+}
+
+// formatRule renders a rule from a cron or weekly schedule
+func formatRule(rule *schedule.Rule, timeZone string) string {
+	if rule == nil {
+		return "disabled"
+	}
+	return fmt.Sprintf("%s %s", rule.String(), timeZone)
+}
+
+func formatTimeOfDay(t time.Time) string {
+	return t.UTC().Format("15:04 UTC")
+}
+
+func writeChange(sb *strings.Builder, change ChangeType, description string, fields []FieldChange) {
+	sb.WriteString(fmt.Sprintf("%s %s\n", change.symbol(), description))
+	for _, f := range fields {
+		sb.WriteString(fmt.Sprintf("    %s: %s -> %s\n", f.Field, quoteEmpty(f.Old), quoteEmpty(f.New)))
+	}
+}
+
+func quoteEmpty(s string) string {
+	if s == "" {
+		return `""`
+	}
+	return s
+}
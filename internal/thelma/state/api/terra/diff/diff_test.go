@@ -0,0 +1,213 @@
+package diff
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const oldState = `
+clusters:
+  - name: terra-dev
+    base: terra
+    address: https://10.0.0.1
+    project: broad-dsde-dev
+  - name: terra-perf
+    base: terra
+    address: https://10.0.0.2
+    project: broad-dsde-perf
+environments:
+  - name: dev
+    base: live
+    lifecycle: static
+    defaultCluster: terra-dev
+    offlineSchedule:
+      begin:
+        enabled: true
+        time: 2023-01-01T23:00:00Z
+releases:
+  - name: sam
+    chart: sam
+    environment: dev
+    cluster: terra-dev
+    namespace: terra-dev
+    chartVersion: 0.34.0
+    appVersion: 1.2.3
+  - name: rawls
+    chart: rawls
+    environment: dev
+    cluster: terra-dev
+    namespace: terra-dev
+    chartVersion: 0.10.0
+    appVersion: 4.5.6
+`
+
+const newState = `
+clusters:
+  - name: terra-dev
+    base: terra
+    address: https://10.0.0.3
+    project: broad-dsde-dev
+environments:
+  - name: dev
+    base: live
+    lifecycle: static
+    defaultCluster: terra-dev
+    offline: true
+    offlineSchedule:
+      begin:
+        enabled: true
+        time: 2023-02-05T22:30:00Z
+  - name: staging
+    base: live
+    lifecycle: static
+    defaultCluster: terra-dev
+releases:
+  - name: sam
+    chart: sam
+    environment: dev
+    cluster: terra-dev
+    namespace: terra-dev
+    chartVersion: 0.34.0
+    appVersion: 1.2.4
+    terraHelmfileRef: my-branch
+  - name: rawls
+    chart: rawls
+    environment: dev
+    cluster: terra-dev
+    namespace: terra-dev
+    chartVersion: 0.10.0
+    appVersion: 4.5.6
+`
+
+func Test_States(t *testing.T) {
+	d, err := States(loadState(t, oldState), loadState(t, newState))
+	require.NoError(t, err)
+
+	assert.Equal(t, []DestinationChange{
+		{Name: "terra-dev", Change: Changed, Fields: []FieldChange{{Field: "address", Old: "https://10.0.0.1", New: "https://10.0.0.3"}}},
+		{Name: "terra-perf", Change: Removed},
+	}, d.Clusters)
+
+	assert.Equal(t, []DestinationChange{
+		{Name: "dev", Change: Changed, Fields: []FieldChange{
+			{Field: "offline", Old: "false", New: "true"},
+			{Field: "stopSchedule", Old: "23:00 UTC", New: "22:30 UTC"},
+		}},
+		{Name: "staging", Change: Added},
+	}, d.Environments)
+
+	assert.Equal(t, []ReleaseChange{
+		{Name: "sam", Destination: "dev", Change: Changed, Fields: []FieldChange{
+			{Field: "appVersion", Old: "1.2.3", New: "1.2.4"},
+			{Field: "terraHelmfileRef", Old: "", New: "my-branch"},
+		}},
+	}, d.Releases)
+
+	assert.False(t, d.Empty())
+	assert.Equal(t, `~ cluster terra-dev
+    address: https://10.0.0.1 -> https://10.0.0.3
+- cluster terra-perf
+~ environment dev
+    offline: false -> true
+    stopSchedule: 23:00 UTC -> 22:30 UTC
++ environment staging
+~ release sam in dev
+    appVersion: 1.2.3 -> 1.2.4
+    terraHelmfileRef: "" -> my-branch
+`, d.String())
+}
+
+func Test_StatesIdentical(t *testing.T) {
+	d, err := States(loadState(t, oldState), loadState(t, oldState))
+	require.NoError(t, err)
+	assert.True(t, d.Empty())
+	assert.Equal(t, "no differences", d.String())
+}
+
+func loadState(t *testing.T, content string) terra.State {
+	stateFile := path.Join(t.TempDir(), "state.yaml")
+	require.NoError(t, os.WriteFile(stateFile, []byte(content), 0644))
+	state, err := file.NewStateLoader(stateFile).Load()
+	require.NoError(t, err)
+	return state
+}
+
+const beeState = `
+clusters:
+  - name: terra-qa-bees
+    base: terra
+    address: https://10.0.0.4
+environments:
+  - name: swatomation
+    base: bee
+    lifecycle: template
+    defaultCluster: terra-qa-bees
+  - name: my-bee
+    base: bee
+    lifecycle: dynamic
+    template: swatomation
+    defaultCluster: terra-qa-bees
+releases:
+  - name: sam
+    chart: sam
+    environment: swatomation
+    cluster: terra-qa-bees
+    chartVersion: 0.34.0
+    appVersion: 1.2.3
+  - name: rawls
+    chart: rawls
+    environment: swatomation
+    cluster: terra-qa-bees
+    chartVersion: 0.10.0
+    appVersion: 4.5.6
+  - name: leonardo
+    chart: leonardo
+    environment: swatomation
+    cluster: terra-qa-bees
+    chartVersion: 0.20.0
+    appVersion: 7.8.9
+  - name: sam
+    chart: sam
+    environment: my-bee
+    cluster: terra-qa-bees
+    chartVersion: 0.35.0
+    appVersion: 1.2.3
+    terraHelmfileRef: my-branch
+  - name: leonardo
+    chart: leonardo
+    environment: my-bee
+    cluster: terra-qa-bees
+    chartVersion: 0.20.0
+    appVersion: 7.8.9
+  - name: workspacemanager
+    chart: workspacemanager
+    environment: my-bee
+    cluster: terra-qa-bees
+    chartVersion: 0.1.0
+    appVersion: 0.0.1
+`
+
+func Test_ReleasesIn(t *testing.T) {
+	state := loadState(t, beeState)
+	template, err := state.Environments().Get("swatomation")
+	require.NoError(t, err)
+	bee, err := state.Environments().Get("my-bee")
+	require.NoError(t, err)
+
+	assert.Equal(t, []ReleaseChange{
+		{Name: "rawls", Destination: "swatomation", Change: Removed},
+		{Name: "sam", Destination: "my-bee", Change: Changed, Fields: []FieldChange{
+			{Field: "chartVersion", Old: "0.34.0", New: "0.35.0"},
+			{Field: "terraHelmfileRef", Old: "", New: "my-branch"},
+		}},
+		{Name: "workspacemanager", Destination: "my-bee", Change: Added},
+	}, ReleasesIn(template, bee))
+
+	assert.Empty(t, ReleasesIn(bee, bee))
+}
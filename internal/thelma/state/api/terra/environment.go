@@ -1,6 +1,10 @@
 package terra
 
-import "time"
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+)
 
 type Environment interface {
 	// DefaultCluster Returns the default cluster for this environment.
@@ -43,6 +47,14 @@ type Environment interface {
 	OfflineScheduleEndTime() time.Time
 	// OfflineScheduleEndWeekends indicates whether the start schedule should only apply on weekdays
 	OfflineScheduleEndWeekends() bool
+	// OfflineSchedule returns the environment's cron or weekly start/stop schedule, or nil if it doesn't have one.
+	// Environments with an OfflineSchedule don't use the daily OfflineScheduleBegin/End settings.
+	OfflineSchedule() *schedule.Schedule
+	// StartOnHolidays indicates whether the start schedule should still apply on holidays
+	StartOnHolidays() bool
+	// Profile returns the name of the release profile applied to this environment when it was created, or the empty
+	// string if none was
+	Profile() string
 	// EnableJanitor indicates whether the Janitor service should be used for this environment to help reduce cloud costs.
 	EnableJanitor() bool
 
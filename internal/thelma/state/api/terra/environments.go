@@ -1,5 +1,7 @@
 package terra
 
+import "time"
+
 // Environments is an interface for querying and updating environments
 type Environments interface {
 	// All returns a list of all environments
@@ -9,7 +11,7 @@ type Environments interface {
 	// Exists returns true if an environment by the given name exists
 	Exists(name string) (bool, error)
 	// CreateFromTemplate creates a new environment with the given options from the given template.
-	// Must panic if the template environment's lifecycle is not "template".
+	// Must return an error if the template environment's lifecycle is not "template".
 	CreateFromTemplate(template Environment, opts CreateOptions) (string, error)
 	// EnableRelease enables a release in an environment
 	// TODO this should move to Environment at some point
@@ -30,4 +32,11 @@ type Environments interface {
 	Delete(name string) error
 	// SetOffline controls whether an environment is meant to be online or offline.
 	SetOffline(name string, offline bool) error
+	// SetAutoDeleteAfter enables automatic deletion for an environment, scheduling it for deletion after the given time.
+	SetAutoDeleteAfter(name string, after time.Time) error
+	// SetAutoDeleteNotified records that an environment's owner was notified it will be deleted after the given time.
+	SetAutoDeleteNotified(name string, after time.Time) error
+	// SetSchedule replaces an environment's stop/start schedule settings with the given options. Schedules that
+	// aren't enabled in the options are removed.
+	SetSchedule(name string, options ScheduleOptions) error
 }
@@ -33,6 +33,24 @@ type EnvironmentFilters interface {
 	OlderThan(dur time.Duration) terra.EnvironmentFilter
 	// AutoDeletable returns environments that can be automatically deleted
 	AutoDeletable() terra.EnvironmentFilter
+	// AutoDeleteDueWithin returns environments that are scheduled to be automatically deleted within the given
+	// duration (including environments that are overdue for deletion)
+	AutoDeleteDueWithin(dur time.Duration) terra.EnvironmentFilter
+	// HasOwner returns environments owned by the given user(s)
+	HasOwner(owners ...string) terra.EnvironmentFilter
+	// CreatedBefore returns environments created before the given time
+	CreatedBefore(t time.Time) terra.EnvironmentFilter
+	// CreatedAfter returns environments created after the given time
+	CreatedAfter(t time.Time) terra.EnvironmentFilter
+	// IsOffline returns environments that are currently offline
+	IsOffline() terra.EnvironmentFilter
+	// HasStartSchedule returns environments with a start schedule (offline schedule end) enabled
+	HasStartSchedule() terra.EnvironmentFilter
+	// HasStopSchedule returns environments with a stop schedule (offline schedule begin) enabled
+	HasStopSchedule() terra.EnvironmentFilter
+	// Matching returns a filter that matches environments for which the given predicate returns true.
+	// The description is used as the filter's string representation.
+	Matching(description string, predicate func(terra.Environment) bool) terra.EnvironmentFilter
 	// Or returns a filter that matches environments that match _any_ of the given filters
 	Or(filters ...terra.EnvironmentFilter) terra.EnvironmentFilter
 	//And returns a filter that matches environments that match _all_ of the given filters
@@ -155,6 +173,91 @@ func (e environmentFilters) AutoDeletable() terra.EnvironmentFilter {
 	}
 }
 
+func (e environmentFilters) AutoDeleteDueWithin(dur time.Duration) terra.EnvironmentFilter {
+	return environmentFilter{
+		string: fmt.Sprintf("autoDeleteDueWithin(%s)", dur),
+		matcher: func(environment terra.Environment) bool {
+			return environment.AutoDelete() != nil &&
+				environment.AutoDelete().Enabled() &&
+				environment.AutoDelete().After().Before(time.Now().Add(dur))
+		},
+	}
+}
+
+func (e environmentFilters) HasOwner(owners ...string) terra.EnvironmentFilter {
+	return environmentFilter{
+		string: fmt.Sprintf("hasOwner(%s)", join(quote(owners)...)),
+		matcher: func(environment terra.Environment) bool {
+			for _, owner := range owners {
+				if environment.Owner() == owner {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+func (e environmentFilters) CreatedBefore(t time.Time) terra.EnvironmentFilter {
+	return environmentFilter{
+		string: fmt.Sprintf("createdBefore(%s)", t.Format(time.RFC3339)),
+		matcher: func(environment terra.Environment) bool {
+			return environment.CreatedAt().Before(t)
+		},
+	}
+}
+
+func (e environmentFilters) CreatedAfter(t time.Time) terra.EnvironmentFilter {
+	return environmentFilter{
+		string: fmt.Sprintf("createdAfter(%s)", t.Format(time.RFC3339)),
+		matcher: func(environment terra.Environment) bool {
+			return environment.CreatedAt().After(t)
+		},
+	}
+}
+
+func (e environmentFilters) IsOffline() terra.EnvironmentFilter {
+	return environmentFilter{
+		string: "isOffline()",
+		matcher: func(environment terra.Environment) bool {
+			return environment.Offline()
+		},
+	}
+}
+
+func (e environmentFilters) HasStartSchedule() terra.EnvironmentFilter {
+	return environmentFilter{
+		string: "hasStartSchedule()",
+		matcher: func(environment terra.Environment) bool {
+			if environment.OfflineScheduleEndEnabled() {
+				return true
+			}
+			s := environment.OfflineSchedule()
+			return s != nil && s.Start != nil
+		},
+	}
+}
+
+func (e environmentFilters) HasStopSchedule() terra.EnvironmentFilter {
+	return environmentFilter{
+		string: "hasStopSchedule()",
+		matcher: func(environment terra.Environment) bool {
+			if environment.OfflineScheduleBeginEnabled() {
+				return true
+			}
+			s := environment.OfflineSchedule()
+			return s != nil && s.Stop != nil
+		},
+	}
+}
+
+func (e environmentFilters) Matching(description string, predicate func(terra.Environment) bool) terra.EnvironmentFilter {
+	return environmentFilter{
+		string:  description,
+		matcher: predicate,
+	}
+}
+
 //
 // TODO [generics] Or and And functions are duplicated across all filter types, fix when generics are available
 
@@ -3,6 +3,7 @@ package filter
 import (
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/mocks"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -24,6 +25,11 @@ func TestEnvironmentFilters(t *testing.T) {
 	dev.EXPECT().CreatedAt().Return(time.Now().Add(-1 * 24 * 100 * time.Hour)) // 100 days old
 	dev.EXPECT().AutoDelete().Return(noAutoDelete)
 	dev.EXPECT().PreventDeletion().Return(true)
+	dev.EXPECT().Owner().Return("")
+	dev.EXPECT().Offline().Return(false)
+	dev.EXPECT().OfflineScheduleBeginEnabled().Return(false)
+	dev.EXPECT().OfflineScheduleEndEnabled().Return(false)
+	dev.EXPECT().OfflineSchedule().Return(nil)
 
 	swat := &mocks.Environment{}
 	swat.EXPECT().Name().Return("swatomation")
@@ -33,6 +39,11 @@ func TestEnvironmentFilters(t *testing.T) {
 	swat.EXPECT().CreatedAt().Return(time.Now().Add(-1 * 24 * 30 * time.Hour)) // 30 days old
 	swat.EXPECT().AutoDelete().Return(noAutoDelete)
 	swat.EXPECT().PreventDeletion().Return(false)
+	swat.EXPECT().Owner().Return("qa@broadinstitute.org")
+	swat.EXPECT().Offline().Return(true)
+	swat.EXPECT().OfflineScheduleBeginEnabled().Return(true)
+	swat.EXPECT().OfflineScheduleEndEnabled().Return(false)
+	swat.EXPECT().OfflineSchedule().Return(&schedule.Schedule{TimeZone: "UTC", Start: &schedule.Rule{Cron: "0 7 * * mon-fri"}})
 
 	bee := &mocks.Environment{}
 	bee.EXPECT().Name().Return("my-bee")
@@ -42,6 +53,10 @@ func TestEnvironmentFilters(t *testing.T) {
 	bee.EXPECT().CreatedAt().Return(time.Now().Add(-1 * 6 * time.Hour)) // 6 hours old
 	bee.EXPECT().AutoDelete().Return(autoDeleteAfter2HoursAgo)
 	bee.EXPECT().PreventDeletion().Return(false)
+	bee.EXPECT().Owner().Return("jdoe@broadinstitute.org")
+	bee.EXPECT().Offline().Return(false)
+	bee.EXPECT().OfflineScheduleBeginEnabled().Return(true)
+	bee.EXPECT().OfflineScheduleEndEnabled().Return(true)
 
 	testCases := []struct {
 		filter terra.EnvironmentFilter
@@ -147,6 +162,42 @@ func TestEnvironmentFilters(t *testing.T) {
 			filter: Environments().AutoDeletable().Negate(),
 			expect: []terra.Environment{dev, swat},
 		},
+		{
+			filter: Environments().AutoDeleteDueWithin(time.Hour),
+			expect: []terra.Environment{bee},
+		},
+		{
+			filter: Environments().HasOwner("jdoe@broadinstitute.org"),
+			expect: []terra.Environment{bee},
+		},
+		{
+			filter: Environments().HasOwner("jdoe@broadinstitute.org", "qa@broadinstitute.org"),
+			expect: []terra.Environment{swat, bee},
+		},
+		{
+			filter: Environments().CreatedBefore(time.Now().Add(-1 * 24 * time.Hour)),
+			expect: []terra.Environment{dev, swat},
+		},
+		{
+			filter: Environments().CreatedAfter(time.Now().Add(-1 * 24 * 60 * time.Hour)),
+			expect: []terra.Environment{swat, bee},
+		},
+		{
+			filter: Environments().IsOffline(),
+			expect: []terra.Environment{swat},
+		},
+		{
+			filter: Environments().HasStopSchedule(),
+			expect: []terra.Environment{swat, bee},
+		},
+		{
+			filter: Environments().HasStartSchedule(),
+			expect: []terra.Environment{swat, bee},
+		},
+		{
+			filter: Environments().Matching("custom", func(e terra.Environment) bool { return e.Base() == "bee" }),
+			expect: []terra.Environment{swat, bee},
+		},
 	}
 
 	for _, tc := range testCases {
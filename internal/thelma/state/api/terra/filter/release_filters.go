@@ -2,6 +2,7 @@ package filter
 
 import (
 	"fmt"
+	"github.com/broadinstitute/thelma/internal/thelma/charts/semver"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
 )
 
@@ -21,6 +22,20 @@ type ReleaseFilters interface {
 	DestinationMatches(filter terra.DestinationFilter) terra.ReleaseFilter
 	// BelongsToEnvironment returns a filter that matches releases in the given environment
 	BelongsToEnvironment(env terra.Environment) terra.ReleaseFilter
+	// OfType returns a filter that matches releases of the given type
+	OfType(releaseType terra.ReleaseType) terra.ReleaseFilter
+	// OfTypeName returns a filter that matches releases with the given type name(s) (eg. "app", "cluster")
+	OfTypeName(typeNames ...string) terra.ReleaseFilter
+	// HasTerraHelmfileRef returns a filter that matches releases pinned to the given terra-helmfile ref(s)
+	HasTerraHelmfileRef(refs ...string) terra.ReleaseFilter
+	// ChartVersionInRange returns a filter that matches releases with a chart version in the given range
+	ChartVersionInRange(versionRange semver.Range) terra.ReleaseFilter
+	// AppVersionInRange returns a filter that matches releases with an app version in the given range.
+	// Releases with app versions that are not semantic versions (eg. git shas) never match.
+	AppVersionInRange(versionRange semver.Range) terra.ReleaseFilter
+	// Matching returns a filter that matches releases for which the given predicate returns true.
+	// The description is used as the filter's string representation.
+	Matching(description string, predicate func(terra.Release) bool) terra.ReleaseFilter
 	// Or returns a filter that matches environments that match _any_ of the given filters
 	Or(filters ...terra.ReleaseFilter) terra.ReleaseFilter
 	//And returns a filter that matches environments that match _all_ of the given filters
@@ -108,6 +123,68 @@ func (r releaseFilters) BelongsToEnvironment(env terra.Environment) terra.Releas
 	return r.DestinationMatches(Destinations().IsEnvironment().And(Destinations().HasName(env.Name())))
 }
 
+func (r releaseFilters) OfType(releaseType terra.ReleaseType) terra.ReleaseFilter {
+	return releaseFilter{
+		string: fmt.Sprintf("ofType(%s)", releaseType.String()),
+		matcher: func(r terra.Release) bool {
+			return r.Type() == releaseType
+		},
+	}
+}
+
+func (r releaseFilters) OfTypeName(typeNames ...string) terra.ReleaseFilter {
+	return releaseFilter{
+		string: fmt.Sprintf("ofTypeName(%s)", join(quote(typeNames)...)),
+		matcher: func(r terra.Release) bool {
+			for _, typeName := range typeNames {
+				if r.Type().String() == typeName {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+func (r releaseFilters) HasTerraHelmfileRef(refs ...string) terra.ReleaseFilter {
+	return releaseFilter{
+		string: fmt.Sprintf("hasTerraHelmfileRef(%s)", join(quote(refs)...)),
+		matcher: func(r terra.Release) bool {
+			for _, ref := range refs {
+				if r.TerraHelmfileRef() == ref {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+func (r releaseFilters) ChartVersionInRange(versionRange semver.Range) terra.ReleaseFilter {
+	return releaseFilter{
+		string: fmt.Sprintf("chartVersionInRange(%q)", versionRange.String()),
+		matcher: func(r terra.Release) bool {
+			return versionRange.Contains(r.ChartVersion())
+		},
+	}
+}
+
+func (r releaseFilters) AppVersionInRange(versionRange semver.Range) terra.ReleaseFilter {
+	return releaseFilter{
+		string: fmt.Sprintf("appVersionInRange(%q)", versionRange.String()),
+		matcher: func(r terra.Release) bool {
+			return versionRange.Contains(r.AppVersion())
+		},
+	}
+}
+
+func (r releaseFilters) Matching(description string, predicate func(terra.Release) bool) terra.ReleaseFilter {
+	return releaseFilter{
+		string:  description,
+		matcher: predicate,
+	}
+}
+
 //
 // TODO [generics] Or and And functions are duplicated across all filter types, fix when generics are available
 
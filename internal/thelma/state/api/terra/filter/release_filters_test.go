@@ -0,0 +1,83 @@
+package filter
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/charts/semver"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestReleaseFilters(t *testing.T) {
+	sam := &mocks.Release{}
+	sam.EXPECT().Name().Return("sam")
+	sam.EXPECT().Type().Return(terra.AppReleaseType)
+	sam.EXPECT().ChartVersion().Return("0.34.1")
+	sam.EXPECT().AppVersion().Return("1.2.3")
+	sam.EXPECT().TerraHelmfileRef().Return("")
+
+	leonardo := &mocks.Release{}
+	leonardo.EXPECT().Name().Return("leonardo")
+	leonardo.EXPECT().Type().Return(terra.AppReleaseType)
+	leonardo.EXPECT().ChartVersion().Return("0.9.0")
+	leonardo.EXPECT().AppVersion().Return("2fe3a9b") // git sha, not a semantic version
+	leonardo.EXPECT().TerraHelmfileRef().Return("my-branch")
+
+	certManager := &mocks.Release{}
+	certManager.EXPECT().Name().Return("cert-manager")
+	certManager.EXPECT().Type().Return(terra.ClusterReleaseType)
+	certManager.EXPECT().ChartVersion().Return("1.10.0")
+	certManager.EXPECT().AppVersion().Return("1.10.0")
+	certManager.EXPECT().TerraHelmfileRef().Return("")
+
+	versionRange := func(s string) semver.Range {
+		r, err := semver.ParseRange(s)
+		require.NoError(t, err)
+		return r
+	}
+
+	testCases := []struct {
+		filter terra.ReleaseFilter
+		expect []terra.Release
+	}{
+		{
+			filter: Releases().OfType(terra.ClusterReleaseType),
+			expect: []terra.Release{certManager},
+		},
+		{
+			filter: Releases().OfTypeName("app"),
+			expect: []terra.Release{sam, leonardo},
+		},
+		{
+			filter: Releases().HasTerraHelmfileRef("my-branch"),
+			expect: []terra.Release{leonardo},
+		},
+		{
+			filter: Releases().ChartVersionInRange(versionRange("^0.34.0")),
+			expect: []terra.Release{sam},
+		},
+		{
+			filter: Releases().ChartVersionInRange(versionRange("<1.0.0")),
+			expect: []terra.Release{sam, leonardo},
+		},
+		{
+			filter: Releases().AppVersionInRange(versionRange(">=1.0.0")),
+			expect: []terra.Release{sam, certManager},
+		},
+		{
+			filter: Releases().AppVersionInRange(versionRange(">=1.0.0")).Negate(),
+			expect: []terra.Release{leonardo},
+		},
+		{
+			filter: Releases().Matching("custom", func(r terra.Release) bool { return r.Name() == "sam" }),
+			expect: []terra.Release{sam},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filter.String(), func(t *testing.T) {
+			assert.ElementsMatch(t, tc.expect, tc.filter.Filter([]terra.Release{sam, leonardo, certManager}))
+		})
+	}
+}
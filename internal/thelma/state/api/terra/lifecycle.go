@@ -66,6 +66,11 @@ func (l *Lifecycle) UnmarshalYAML(value *yaml.Node) error {
 	return errors.Errorf("unknown lifecycle type %v, supported lifecycles are %s", value.Value, strings.Join(supported, ", "))
 }
 
+// MarshalYAML is a custom marshaler so that lifecycles are serialized as strings like "static" or "dynamic"
+func (l Lifecycle) MarshalYAML() (interface{}, error) {
+	return l.String(), nil
+}
+
 // FromString will set the receiver's value to the one denoted by the given string
 func (l *Lifecycle) FromString(value string) error {
 	switch value {
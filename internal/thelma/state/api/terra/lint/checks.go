@@ -0,0 +1,167 @@
+package lint
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/validate"
+)
+
+// check is a single lint check
+type check struct {
+	// name identifies the check in violation output
+	name string
+	// severity of violations reported by this check
+	severity Severity
+	// fn reports violations via linter.report
+	fn func(l *linter)
+}
+
+// checks is the list of checks that are run, in order
+var checks = []check{
+	{
+		name:     "environment-name",
+		severity: Error,
+		fn: func(l *linter) {
+			for _, env := range l.environments {
+				if err := validate.EnvironmentName(env.Name()); err != nil {
+					l.report("environment", env.Name(), "%v", err)
+				}
+			}
+		},
+	},
+	{
+		name:     "environment-prefix",
+		severity: Error,
+		fn: func(l *linter) {
+			for _, env := range l.environments {
+				if env.UniqueResourcePrefix() == "" {
+					continue
+				}
+				if err := validate.EnvironmentNamePrefix(env.UniqueResourcePrefix()); err != nil {
+					l.report("environment", env.Name(), "unique resource prefix %q: %v", env.UniqueResourcePrefix(), err)
+				}
+			}
+		},
+	},
+	{
+		name:     "duplicate-prefix",
+		severity: Error,
+		fn: func(l *linter) {
+			seen := make(map[string]string)
+			for _, env := range l.environments {
+				prefix := env.UniqueResourcePrefix()
+				if prefix == "" {
+					continue
+				}
+				if other, exists := seen[prefix]; exists {
+					l.report("environment", env.Name(), "unique resource prefix %q is also used by environment %s", prefix, other)
+					continue
+				}
+				seen[prefix] = env.Name()
+			}
+		},
+	},
+	{
+		name:     "missing-template",
+		severity: Error,
+		fn: func(l *linter) {
+			templates := make(map[string]struct{})
+			for _, env := range l.environments {
+				if env.Lifecycle().IsTemplate() {
+					templates[env.Name()] = struct{}{}
+				}
+			}
+			for _, env := range l.environments {
+				if !env.Lifecycle().IsDynamic() {
+					continue
+				}
+				if _, exists := templates[env.Template()]; !exists {
+					l.report("environment", env.Name(), "template %q does not exist", env.Template())
+				}
+			}
+		},
+	},
+	{
+		name:     "artifact-bucket",
+		severity: Warning,
+		fn: func(l *linter) {
+			for _, cluster := range l.clusters {
+				if cluster.ArtifactBucket() == "" {
+					l.report("cluster", cluster.Name(), "artifact bucket is empty")
+				}
+			}
+		},
+	},
+	{
+		name:     "unknown-cluster",
+		severity: Error,
+		fn: func(l *linter) {
+			known := make(map[string]struct{})
+			for _, cluster := range l.clusters {
+				known[cluster.Name()] = struct{}{}
+			}
+			for _, release := range l.releases {
+				if _, exists := known[release.ClusterName()]; !exists {
+					l.report("release", release.FullName(), "cluster %q does not exist", release.ClusterName())
+				}
+			}
+		},
+	},
+	{
+		name:     "invalid-schedule",
+		severity: Error,
+		fn: func(l *linter) {
+			for _, env := range l.environments {
+				if env.OfflineSchedule() == nil {
+					continue
+				}
+				if err := env.OfflineSchedule().Validate(); err != nil {
+					l.report("environment", env.Name(), "schedule is invalid and won't be applied: %v", err)
+				}
+			}
+		},
+	},
+	{
+		name:     "schedule-window",
+		severity: Warning,
+		fn: func(l *linter) {
+			for _, env := range l.environments {
+				if env.OfflineSchedule() != nil {
+					// cron and weekly schedules replace the daily ones; unreadable schedules are reported by invalid-schedule
+					if env.OfflineSchedule().Validate() != nil {
+						continue
+					}
+					if err := env.OfflineSchedule().CheckConflicts(l.options.Now, l.options.ScheduleWindow); err != nil {
+						l.report("environment", env.Name(), "stop and start schedules conflict with the %s apply-schedule window: %v", l.options.ScheduleWindow, err)
+					}
+					continue
+				}
+				if !env.OfflineScheduleBeginEnabled() || !env.OfflineScheduleEndEnabled() {
+					continue
+				}
+				gap := timeOfDayGap(env.OfflineScheduleBeginTime(), env.OfflineScheduleEndTime())
+				if gap < l.options.ScheduleWindow {
+					l.report("environment", env.Name(), "stop and start schedules are %s apart, less than the %s apply-schedule window", gap, l.options.ScheduleWindow)
+				}
+			}
+		},
+	},
+}
+
+// timeOfDayGap returns the shortest distance between two times of day, ignoring dates
+func timeOfDayGap(a time.Time, b time.Time) time.Duration {
+	day := 24 * time.Hour
+	diff := (timeOfDay(a) - timeOfDay(b)) % day
+	if diff < 0 {
+		diff = -diff
+	}
+	if day-diff < diff {
+		return day - diff
+	}
+	return diff
+}
+
+func timeOfDay(t time.Time) time.Duration {
+	t = t.UTC()
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
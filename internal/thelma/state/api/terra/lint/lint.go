@@ -0,0 +1,177 @@
+// Package lint checks a terra.State for invariant violations, such as invalid environment names or releases that
+// reference clusters that don't exist
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/pkg/errors"
+)
+
+// DefaultScheduleWindow matches the default --from-past window used by `thelma bees apply-schedule`
+const DefaultScheduleWindow = 20 * time.Minute
+
+// Severity of a violation
+type Severity string
+
+const (
+	// Error violations are likely to cause render, sync, or provisioning failures
+	Error Severity = "error"
+	// Warning violations are suspicious but not necessarily broken
+	Warning Severity = "warning"
+)
+
+// rank is used to compare severities; higher is more severe
+func (s Severity) rank() int {
+	switch s {
+	case Error:
+		return 2
+	case Warning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeast returns true if this severity is at least as severe as the other
+func (s Severity) AtLeast(other Severity) bool {
+	return s.rank() >= other.rank()
+}
+
+// ParseSeverity converts a severity name like "error" or "warning" into a Severity
+func ParseSeverity(name string) (Severity, error) {
+	switch Severity(name) {
+	case Error, Warning:
+		return Severity(name), nil
+	}
+	return "", errors.Errorf("unknown severity %q, expected one of: %s, %s", name, Error, Warning)
+}
+
+// Violation is a single problem found in state
+type Violation struct {
+	// Check is the name of the check that reported the violation, eg. "environment-name"
+	Check string `json:"check" yaml:"check"`
+	// Severity is the severity of the violation
+	Severity Severity `json:"severity" yaml:"severity"`
+	// Kind is the kind of entity the violation was found in ("cluster", "environment", or "release")
+	Kind string `json:"kind" yaml:"kind"`
+	// Name is the name of the entity the violation was found in
+	Name string `json:"name" yaml:"name"`
+	// Message describes the violation
+	Message string `json:"message" yaml:"message"`
+}
+
+// Report is the result of linting a state
+type Report struct {
+	Violations []Violation `json:"violations" yaml:"violations"`
+}
+
+// Count returns the number of violations at or above the given severity
+func (r *Report) Count(severity Severity) int {
+	var count int
+	for _, v := range r.Violations {
+		if v.Severity.AtLeast(severity) {
+			count++
+		}
+	}
+	return count
+}
+
+// String renders the report in a human-readable format, one violation per line
+func (r *Report) String() string {
+	if len(r.Violations) == 0 {
+		return "no violations"
+	}
+	var sb strings.Builder
+	counts := make(map[Severity]int)
+	for _, v := range r.Violations {
+		sb.WriteString(fmt.Sprintf("%s: %s %s: %s [%s]\n", v.Severity, v.Kind, v.Name, v.Message, v.Check))
+		counts[v.Severity]++
+	}
+	sb.WriteString(fmt.Sprintf("%d error(s), %d warning(s)\n", counts[Error], counts[Warning]))
+	return sb.String()
+}
+
+// Options for a lint run
+type Options struct {
+	// ScheduleWindow is the minimum permitted gap between an environment's stop and start schedules
+	ScheduleWindow time.Duration
+	// Now is when cron and weekly schedules are checked from; defaults to the current time
+	Now time.Time
+}
+
+// Option function for configuring Options
+type Option func(*Options)
+
+// State lints the given state, returning a report of all violations found
+func State(state terra.State, opts ...Option) (*Report, error) {
+	options := Options{
+		ScheduleWindow: DefaultScheduleWindow,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.Now.IsZero() {
+		options.Now = time.Now()
+	}
+
+	clusters, err := state.Clusters().All()
+	if err != nil {
+		return nil, err
+	}
+	environments, err := state.Environments().All()
+	if err != nil {
+		return nil, err
+	}
+	releases, err := state.Releases().All()
+	if err != nil {
+		return nil, err
+	}
+	// state returns objects in no particular order; sort them so reports are stable
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name() < clusters[j].Name() })
+	sort.Slice(environments, func(i, j int) bool { return environments[i].Name() < environments[j].Name() })
+	sort.Slice(releases, func(i, j int) bool { return releases[i].FullName() < releases[j].FullName() })
+
+	l := &linter{
+		options:      options,
+		clusters:     clusters,
+		environments: environments,
+		releases:     releases,
+		violations:   []Violation{},
+	}
+
+	for _, c := range checks {
+		c.run(l)
+	}
+
+	return &Report{Violations: l.violations}, nil
+}
+
+// linter holds state being linted and accumulates violations
+type linter struct {
+	options      Options
+	clusters     []terra.Cluster
+	environments []terra.Environment
+	releases     []terra.Release
+	violations   []Violation
+	currentCheck check
+}
+
+func (l *linter) report(kind string, name string, format string, args ...interface{}) {
+	l.violations = append(l.violations, Violation{
+		Check:    l.currentCheck.name,
+		Severity: l.currentCheck.severity,
+		Kind:     kind,
+		Name:     name,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c check) run(l *linter) {
+	l.currentCheck = c
+	c.fn(l)
+}
@@ -0,0 +1,183 @@
+package lint
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/mocks"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const badState = `
+clusters:
+  - name: terra-dev
+    base: terra
+    address: https://10.0.0.1
+    project: broad-dsde-dev
+environments:
+  - name: dev
+    base: live
+    lifecycle: static
+    defaultCluster: terra-dev
+  - name: swatomation
+    base: bee
+    lifecycle: template
+    defaultCluster: terra-dev
+  - name: Bad_Name
+    base: bee
+    lifecycle: dynamic
+    template: swatomation
+    defaultCluster: terra-dev
+    uniqueResourcePrefix: e101
+    offlineSchedule:
+      schedule:
+        timeZone: UTC
+        stop:
+          cron: 0 25 * * *
+  - name: orphan-bee
+    base: bee
+    lifecycle: dynamic
+    template: deleted-template
+    defaultCluster: terra-dev
+    uniqueResourcePrefix: e101
+    offlineSchedule:
+      begin:
+        enabled: true
+        time: 2023-01-01T23:55:00Z
+      end:
+        enabled: true
+        time: 2023-01-01T00:05:00Z
+releases:
+  - name: sam
+    chart: sam
+    environment: dev
+    cluster: terra-dev
+    namespace: terra-dev
+    chartVersion: 0.34.0
+    appVersion: 1.2.3
+`
+
+func Test_State(t *testing.T) {
+	report, err := State(loadState(t, badState))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Violation{
+		{Check: "environment-name", Severity: Error, Kind: "environment", Name: "Bad_Name", Message: `environment name must match regular expression \A[a-z][a-z0-9]*(-[a-z0-9]+)*\z`},
+		{Check: "duplicate-prefix", Severity: Error, Kind: "environment", Name: "orphan-bee", Message: `unique resource prefix "e101" is also used by environment Bad_Name`},
+		{Check: "missing-template", Severity: Error, Kind: "environment", Name: "orphan-bee", Message: `template "deleted-template" does not exist`},
+		{Check: "invalid-schedule", Severity: Error, Kind: "environment", Name: "Bad_Name", Message: "schedule is invalid and won't be applied: invalid stop rule: invalid cron expression \"0 25 * * *\": value 25 in hour field is out of range [0, 23]"},
+		{Check: "schedule-window", Severity: Warning, Kind: "environment", Name: "orphan-bee", Message: "stop and start schedules are 10m0s apart, less than the 20m0s apply-schedule window"},
+	}, report.Violations)
+
+	assert.Equal(t, 4, report.Count(Error))
+	assert.Equal(t, 5, report.Count(Warning))
+}
+
+func Test_StateScheduleWindow(t *testing.T) {
+	report, err := State(loadState(t, badState), func(options *Options) {
+		options.ScheduleWindow = 5 * time.Minute
+	})
+	require.NoError(t, err)
+	assert.Len(t, report.Violations, 4)
+	assert.Equal(t, 4, report.Count(Warning))
+}
+
+func Test_StateScheduleWindowCron(t *testing.T) {
+	state := loadState(t, `
+clusters:
+  - name: terra-dev
+    base: terra
+    address: https://10.0.0.1
+    project: broad-dsde-dev
+environments:
+  - name: swatomation
+    base: bee
+    lifecycle: template
+    defaultCluster: terra-dev
+  - name: cron-bee
+    base: bee
+    lifecycle: dynamic
+    template: swatomation
+    defaultCluster: terra-dev
+    uniqueResourcePrefix: e101
+    offlineSchedule:
+      schedule:
+        timeZone: UTC
+        stop:
+          cron: 0 19 * * *
+        start:
+          weekly:
+            tue: "19:10"
+`)
+	report, err := State(state, func(options *Options) {
+		options.Now = time.Date(2023, 2, 27, 12, 0, 0, 0, time.UTC)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{
+		{Check: "schedule-window", Severity: Warning, Kind: "environment", Name: "cron-bee", Message: "stop and start schedules conflict with the 20m0s apply-schedule window: stop at 2023-02-28T19:00:00Z and start at 2023-02-28T19:10:00Z are only 10m0s apart; stop and start times must be at least 20m0s apart"},
+	}, report.Violations)
+
+	report, err = State(state, func(options *Options) {
+		options.ScheduleWindow = 5 * time.Minute
+	})
+	require.NoError(t, err)
+	assert.Empty(t, report.Violations)
+}
+
+func Test_StateClusters(t *testing.T) {
+	cluster := &mocks.Cluster{}
+	cluster.EXPECT().Name().Return("terra-dev")
+	cluster.EXPECT().ArtifactBucket().Return("")
+
+	release := &mocks.Release{}
+	release.EXPECT().FullName().Return("yale-terra-staging")
+	release.EXPECT().ClusterName().Return("terra-staging")
+
+	clusters := &mocks.Clusters{}
+	clusters.EXPECT().All().Return([]terra.Cluster{cluster}, nil)
+	environments := &mocks.Environments{}
+	environments.EXPECT().All().Return(nil, nil)
+	releases := &mocks.Releases{}
+	releases.EXPECT().All().Return([]terra.Release{release}, nil)
+
+	state := &mocks.State{}
+	state.EXPECT().Clusters().Return(clusters)
+	state.EXPECT().Environments().Return(environments)
+	state.EXPECT().Releases().Return(releases)
+
+	report, err := State(state)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Violation{
+		{Check: "artifact-bucket", Severity: Warning, Kind: "cluster", Name: "terra-dev", Message: "artifact bucket is empty"},
+		{Check: "unknown-cluster", Severity: Error, Kind: "release", Name: "yale-terra-staging", Message: `cluster "terra-staging" does not exist`},
+	}, report.Violations)
+
+	assert.Equal(t, `warning: cluster terra-dev: artifact bucket is empty [artifact-bucket]
+error: release yale-terra-staging: cluster "terra-staging" does not exist [unknown-cluster]
+1 error(s), 1 warning(s)
+`, report.String())
+}
+
+func Test_timeOfDayGap(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2023, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+	assert.Equal(t, 10*time.Minute, timeOfDayGap(at(23, 55), at(0, 5)))
+	assert.Equal(t, 10*time.Minute, timeOfDayGap(at(0, 5), at(23, 55)))
+	assert.Equal(t, 12*time.Hour, timeOfDayGap(at(6, 0), at(18, 0)))
+	assert.Equal(t, time.Duration(0), timeOfDayGap(at(6, 0), at(6, 0).Add(48*time.Hour)))
+}
+
+func loadState(t *testing.T, content string) terra.State {
+	stateFile := path.Join(t.TempDir(), "state.yaml")
+	require.NoError(t, os.WriteFile(stateFile, []byte(content), 0644))
+	state, err := file.NewStateLoader(stateFile).Load()
+	require.NoError(t, err)
+	return state
+}
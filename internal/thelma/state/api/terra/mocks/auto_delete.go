@@ -103,6 +103,47 @@ func (_c *AutoDelete_Enabled_Call) RunAndReturn(run func() bool) *AutoDelete_Ena
 	return _c
 }
 
+// NotifiedFor provides a mock function with given fields:
+func (_m *AutoDelete) NotifiedFor() time.Time {
+	ret := _m.Called()
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
+// AutoDelete_NotifiedFor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NotifiedFor'
+type AutoDelete_NotifiedFor_Call struct {
+	*mock.Call
+}
+
+// NotifiedFor is a helper method to define mock.On call
+func (_e *AutoDelete_Expecter) NotifiedFor() *AutoDelete_NotifiedFor_Call {
+	return &AutoDelete_NotifiedFor_Call{Call: _e.mock.On("NotifiedFor")}
+}
+
+func (_c *AutoDelete_NotifiedFor_Call) Run(run func()) *AutoDelete_NotifiedFor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *AutoDelete_NotifiedFor_Call) Return(_a0 time.Time) *AutoDelete_NotifiedFor_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AutoDelete_NotifiedFor_Call) RunAndReturn(run func() time.Time) *AutoDelete_NotifiedFor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewAutoDelete creates a new instance of AutoDelete. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAutoDelete(t interface {
@@ -5,8 +5,11 @@ package mocks
 import (
 	time "time"
 
-	terra "github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
 	mock "github.com/stretchr/testify/mock"
+
+	schedule "github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+
+	terra "github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
 )
 
 // Environment is an autogenerated mock type for the Environment type
@@ -559,6 +562,49 @@ func (_c *Environment_Offline_Call) RunAndReturn(run func() bool) *Environment_O
 	return _c
 }
 
+// OfflineSchedule provides a mock function with given fields:
+func (_m *Environment) OfflineSchedule() *schedule.Schedule {
+	ret := _m.Called()
+
+	var r0 *schedule.Schedule
+	if rf, ok := ret.Get(0).(func() *schedule.Schedule); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.Schedule)
+		}
+	}
+
+	return r0
+}
+
+// Environment_OfflineSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OfflineSchedule'
+type Environment_OfflineSchedule_Call struct {
+	*mock.Call
+}
+
+// OfflineSchedule is a helper method to define mock.On call
+func (_e *Environment_Expecter) OfflineSchedule() *Environment_OfflineSchedule_Call {
+	return &Environment_OfflineSchedule_Call{Call: _e.mock.On("OfflineSchedule")}
+}
+
+func (_c *Environment_OfflineSchedule_Call) Run(run func()) *Environment_OfflineSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Environment_OfflineSchedule_Call) Return(_a0 *schedule.Schedule) *Environment_OfflineSchedule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Environment_OfflineSchedule_Call) RunAndReturn(run func() *schedule.Schedule) *Environment_OfflineSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // OfflineScheduleBeginEnabled provides a mock function with given fields:
 func (_m *Environment) OfflineScheduleBeginEnabled() bool {
 	ret := _m.Called()
@@ -846,6 +892,47 @@ func (_c *Environment_PreventDeletion_Call) RunAndReturn(run func() bool) *Envir
 	return _c
 }
 
+// Profile provides a mock function with given fields:
+func (_m *Environment) Profile() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Environment_Profile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Profile'
+type Environment_Profile_Call struct {
+	*mock.Call
+}
+
+// Profile is a helper method to define mock.On call
+func (_e *Environment_Expecter) Profile() *Environment_Profile_Call {
+	return &Environment_Profile_Call{Call: _e.mock.On("Profile")}
+}
+
+func (_c *Environment_Profile_Call) Run(run func()) *Environment_Profile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Environment_Profile_Call) Return(_a0 string) *Environment_Profile_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Environment_Profile_Call) RunAndReturn(run func() string) *Environment_Profile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ReleaseType provides a mock function with given fields:
 func (_m *Environment) ReleaseType() terra.ReleaseType {
 	ret := _m.Called()
@@ -971,6 +1058,47 @@ func (_c *Environment_RequiredRole_Call) RunAndReturn(run func() string) *Enviro
 	return _c
 }
 
+// StartOnHolidays provides a mock function with given fields:
+func (_m *Environment) StartOnHolidays() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Environment_StartOnHolidays_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartOnHolidays'
+type Environment_StartOnHolidays_Call struct {
+	*mock.Call
+}
+
+// StartOnHolidays is a helper method to define mock.On call
+func (_e *Environment_Expecter) StartOnHolidays() *Environment_StartOnHolidays_Call {
+	return &Environment_StartOnHolidays_Call{Call: _e.mock.On("StartOnHolidays")}
+}
+
+func (_c *Environment_StartOnHolidays_Call) Run(run func()) *Environment_StartOnHolidays_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Environment_StartOnHolidays_Call) Return(_a0 bool) *Environment_StartOnHolidays_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Environment_StartOnHolidays_Call) RunAndReturn(run func() bool) *Environment_StartOnHolidays_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Template provides a mock function with given fields:
 func (_m *Environment) Template() string {
 	ret := _m.Called()
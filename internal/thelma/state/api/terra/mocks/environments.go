@@ -5,6 +5,8 @@ package mocks
 import (
 	terra "github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Environments is an autogenerated mock type for the Environments type
@@ -458,6 +460,92 @@ func (_c *Environments_PinVersions_Call) RunAndReturn(run func(string, map[strin
 	return _c
 }
 
+// SetAutoDeleteAfter provides a mock function with given fields: name, after
+func (_m *Environments) SetAutoDeleteAfter(name string, after time.Time) error {
+	ret := _m.Called(name, after)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(name, after)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Environments_SetAutoDeleteAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAutoDeleteAfter'
+type Environments_SetAutoDeleteAfter_Call struct {
+	*mock.Call
+}
+
+// SetAutoDeleteAfter is a helper method to define mock.On call
+//   - name string
+//   - after time.Time
+func (_e *Environments_Expecter) SetAutoDeleteAfter(name interface{}, after interface{}) *Environments_SetAutoDeleteAfter_Call {
+	return &Environments_SetAutoDeleteAfter_Call{Call: _e.mock.On("SetAutoDeleteAfter", name, after)}
+}
+
+func (_c *Environments_SetAutoDeleteAfter_Call) Run(run func(name string, after time.Time)) *Environments_SetAutoDeleteAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Environments_SetAutoDeleteAfter_Call) Return(_a0 error) *Environments_SetAutoDeleteAfter_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Environments_SetAutoDeleteAfter_Call) RunAndReturn(run func(string, time.Time) error) *Environments_SetAutoDeleteAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetAutoDeleteNotified provides a mock function with given fields: name, after
+func (_m *Environments) SetAutoDeleteNotified(name string, after time.Time) error {
+	ret := _m.Called(name, after)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(name, after)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Environments_SetAutoDeleteNotified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAutoDeleteNotified'
+type Environments_SetAutoDeleteNotified_Call struct {
+	*mock.Call
+}
+
+// SetAutoDeleteNotified is a helper method to define mock.On call
+//   - name string
+//   - after time.Time
+func (_e *Environments_Expecter) SetAutoDeleteNotified(name interface{}, after interface{}) *Environments_SetAutoDeleteNotified_Call {
+	return &Environments_SetAutoDeleteNotified_Call{Call: _e.mock.On("SetAutoDeleteNotified", name, after)}
+}
+
+func (_c *Environments_SetAutoDeleteNotified_Call) Run(run func(name string, after time.Time)) *Environments_SetAutoDeleteNotified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Environments_SetAutoDeleteNotified_Call) Return(_a0 error) *Environments_SetAutoDeleteNotified_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Environments_SetAutoDeleteNotified_Call) RunAndReturn(run func(string, time.Time) error) *Environments_SetAutoDeleteNotified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetOffline provides a mock function with given fields: name, offline
 func (_m *Environments) SetOffline(name string, offline bool) error {
 	ret := _m.Called(name, offline)
@@ -501,6 +589,49 @@ func (_c *Environments_SetOffline_Call) RunAndReturn(run func(string, bool) erro
 	return _c
 }
 
+// SetSchedule provides a mock function with given fields: name, options
+func (_m *Environments) SetSchedule(name string, options terra.ScheduleOptions) error {
+	ret := _m.Called(name, options)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, terra.ScheduleOptions) error); ok {
+		r0 = rf(name, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Environments_SetSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetSchedule'
+type Environments_SetSchedule_Call struct {
+	*mock.Call
+}
+
+// SetSchedule is a helper method to define mock.On call
+//   - name string
+//   - options terra.ScheduleOptions
+func (_e *Environments_Expecter) SetSchedule(name interface{}, options interface{}) *Environments_SetSchedule_Call {
+	return &Environments_SetSchedule_Call{Call: _e.mock.On("SetSchedule", name, options)}
+}
+
+func (_c *Environments_SetSchedule_Call) Run(run func(name string, options terra.ScheduleOptions)) *Environments_SetSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(terra.ScheduleOptions))
+	})
+	return _c
+}
+
+func (_c *Environments_SetSchedule_Call) Return(_a0 error) *Environments_SetSchedule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Environments_SetSchedule_Call) RunAndReturn(run func(string, terra.ScheduleOptions) error) *Environments_SetSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UnpinVersions provides a mock function with given fields: environmentName
 func (_m *Environments) UnpinVersions(environmentName string) (map[string]terra.VersionOverride, error) {
 	ret := _m.Called(environmentName)
@@ -0,0 +1,124 @@
+package watch
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/diff"
+)
+
+// EventType identifies the kind of change an Event describes
+type EventType string
+
+const (
+	ClusterCreated            EventType = "ClusterCreated"
+	ClusterDeleted            EventType = "ClusterDeleted"
+	ClusterChanged            EventType = "ClusterChanged"
+	EnvironmentCreated        EventType = "EnvironmentCreated"
+	EnvironmentDeleted        EventType = "EnvironmentDeleted"
+	EnvironmentOfflineChanged EventType = "EnvironmentOfflineChanged"
+	EnvironmentChanged        EventType = "EnvironmentChanged"
+	ReleaseCreated            EventType = "ReleaseCreated"
+	ReleaseDeleted            EventType = "ReleaseDeleted"
+	ReleaseVersionChanged     EventType = "ReleaseVersionChanged"
+	ReleaseChanged            EventType = "ReleaseChanged"
+)
+
+// Event describes a single change observed between two consecutive state snapshots
+type Event struct {
+	// Type is the type of the event
+	Type EventType `json:"type"`
+	// Time is when the change was observed
+	Time time.Time `json:"time"`
+	// Name is the name of the cluster, environment, or release that changed
+	Name string `json:"name"`
+	// Destination is the name of the release's environment or cluster (release events only)
+	Destination string `json:"destination,omitempty"`
+	// Fields are the field-level changes included in this event, if any
+	Fields []diff.FieldChange `json:"fields,omitempty"`
+}
+
+// offlineFields are environment fields reported as EnvironmentOfflineChanged events
+var offlineFields = []string{"offline"}
+
+// versionFields are release fields reported as ReleaseVersionChanged events
+var versionFields = []string{"chartVersion", "appVersion", "terraHelmfileRef"}
+
+// Events converts a diff into a list of events, in the order clusters, environments, releases.
+// Field changes for a single entity are split across events by type, so that (for example) a release whose app version
+// and namespace both changed produces one ReleaseVersionChanged and one ReleaseChanged event.
+func Events(d *diff.Diff, observedAt time.Time) []Event {
+	var events []Event
+
+	for _, c := range d.Clusters {
+		events = append(events, destinationEvents(c, observedAt, ClusterCreated, ClusterDeleted, "", ClusterChanged, nil)...)
+	}
+	for _, c := range d.Environments {
+		events = append(events, destinationEvents(c, observedAt, EnvironmentCreated, EnvironmentDeleted, EnvironmentOfflineChanged, EnvironmentChanged, offlineFields)...)
+	}
+	for _, c := range d.Releases {
+		event := Event{Time: observedAt, Name: c.Name, Destination: c.Destination}
+		switch c.Change {
+		case diff.Added:
+			event.Type = ReleaseCreated
+			events = append(events, event)
+		case diff.Removed:
+			event.Type = ReleaseDeleted
+			events = append(events, event)
+		default:
+			events = append(events, splitFields(event, c.Fields, ReleaseVersionChanged, ReleaseChanged, versionFields)...)
+		}
+	}
+
+	return events
+}
+
+func destinationEvents(c diff.DestinationChange, observedAt time.Time, created EventType, deleted EventType, special EventType, changed EventType, specialFields []string) []Event {
+	event := Event{Time: observedAt, Name: c.Name}
+	switch c.Change {
+	case diff.Added:
+		event.Type = created
+		return []Event{event}
+	case diff.Removed:
+		event.Type = deleted
+		return []Event{event}
+	default:
+		return splitFields(event, c.Fields, special, changed, specialFields)
+	}
+}
+
+// splitFields emits one event of the special type for any fields in specialFields, and one event of the
+// changed type for all remaining fields
+func splitFields(template Event, fields []diff.FieldChange, special EventType, changed EventType, specialFields []string) []Event {
+	var specialChanges, otherChanges []diff.FieldChange
+	for _, f := range fields {
+		if contains(specialFields, f.Field) {
+			specialChanges = append(specialChanges, f)
+		} else {
+			otherChanges = append(otherChanges, f)
+		}
+	}
+
+	var events []Event
+	if len(specialChanges) > 0 {
+		e := template
+		e.Type = special
+		e.Fields = specialChanges
+		events = append(events, e)
+	}
+	if len(otherChanges) > 0 {
+		e := template
+		e.Type = changed
+		e.Fields = otherChanges
+		events = append(events, e)
+	}
+	return events
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Sink receives events emitted by a Watcher
+type Sink interface {
+	// Send delivers a batch of events. If Send returns an error, the entire batch may be retried, so delivery is
+	// at-least-once
+	Send(ctx context.Context, events []Event) error
+}
+
+// NewJSONLinesSink returns a Sink that writes each event to the given writer as a single line of JSON
+func NewJSONLinesSink(w io.Writer) Sink {
+	return &jsonLinesSink{encoder: json.NewEncoder(w)}
+}
+
+type jsonLinesSink struct {
+	encoder *json.Encoder
+}
+
+func (s *jsonLinesSink) Send(_ context.Context, events []Event) error {
+	for _, event := range events {
+		if err := s.encoder.Encode(event); err != nil {
+			return errors.Errorf("error writing %s event: %v", event.Type, err)
+		}
+	}
+	return nil
+}
+
+// NewWebhookSink returns a Sink that POSTs each event as JSON to the given URL
+func NewWebhookSink(url string, timeout time.Duration) Sink {
+	return &webhookSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *webhookSink) Send(ctx context.Context, events []Event) error {
+	for _, event := range events {
+		body, err := json.Marshal(event)
+		if err != nil {
+			return errors.Errorf("error marshalling %s event: %v", event.Type, err)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+		if err != nil {
+			return errors.Errorf("error building webhook request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return errors.Errorf("error sending %s event to webhook: %v", event.Type, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return errors.Errorf("webhook returned status %d for %s event", resp.StatusCode, event.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/diff"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const before = `
+clusters:
+  - name: terra-dev
+    base: terra
+    address: https://10.0.0.1
+    project: broad-dsde-dev
+environments:
+  - name: dev
+    base: live
+    lifecycle: static
+    defaultCluster: terra-dev
+releases:
+  - name: sam
+    chart: sam
+    environment: dev
+    cluster: terra-dev
+    namespace: terra-dev
+    chartVersion: 0.34.0
+    appVersion: 1.2.3
+`
+
+const after = `
+clusters:
+  - name: terra-dev
+    base: terra
+    address: https://10.0.0.1
+    project: broad-dsde-dev
+environments:
+  - name: dev
+    base: live
+    lifecycle: static
+    defaultCluster: terra-dev
+    offline: true
+    owner: jdoe@broadinstitute.org
+  - name: staging
+    base: live
+    lifecycle: static
+    defaultCluster: terra-dev
+releases:
+  - name: sam
+    chart: sam
+    environment: dev
+    cluster: terra-dev
+    namespace: sam
+    chartVersion: 0.34.0
+    appVersion: 1.2.4
+`
+
+var observedAt = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func Test_Events(t *testing.T) {
+	d, err := diff.States(loadState(t, before), loadState(t, after))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Event{
+		{Type: EnvironmentOfflineChanged, Time: observedAt, Name: "dev", Fields: []diff.FieldChange{{Field: "offline", Old: "false", New: "true"}}},
+		{Type: EnvironmentChanged, Time: observedAt, Name: "dev", Fields: []diff.FieldChange{{Field: "owner", Old: "", New: "jdoe@broadinstitute.org"}}},
+		{Type: EnvironmentCreated, Time: observedAt, Name: "staging"},
+		{Type: ReleaseVersionChanged, Time: observedAt, Name: "sam", Destination: "dev", Fields: []diff.FieldChange{{Field: "appVersion", Old: "1.2.3", New: "1.2.4"}}},
+		{Type: ReleaseChanged, Time: observedAt, Name: "sam", Destination: "dev", Fields: []diff.FieldChange{{Field: "namespace", Old: "terra-dev", New: "sam"}}},
+	}, Events(d, observedAt))
+}
+
+func Test_Watch(t *testing.T) {
+	loader := &fakeStateLoader{
+		results: []fakeResult{
+			{state: loadState(t, before)},
+			{err: errors.Errorf("sherlock is down")},
+			{state: loadState(t, before)},
+			{state: loadState(t, after)},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sink := &fakeSink{failures: 1, onSend: cancel}
+
+	w := New(loader, sink, func(options *Options) {
+		options.Interval = time.Millisecond
+		options.MaxBackoff = 5 * time.Millisecond
+	})
+	require.NoError(t, w.Watch(ctx))
+
+	// first send failed, so events should have been re-sent on the next poll, with no new events added
+	require.Len(t, sink.sent, 1)
+	assert.Len(t, sink.sent[0], 5)
+	assert.Equal(t, EnvironmentOfflineChanged, sink.sent[0][0].Type)
+}
+
+func Test_WatchMaxPending(t *testing.T) {
+	loader := &fakeStateLoader{
+		results: []fakeResult{
+			{state: loadState(t, before)},
+			{state: loadState(t, after)},
+			{state: loadState(t, before)},
+			{state: loadState(t, after)},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sink := &fakeSink{failures: 2, onSend: cancel}
+
+	w := New(loader, sink, func(options *Options) {
+		options.Interval = time.Millisecond
+		options.MaxBackoff = 5 * time.Millisecond
+		options.MaxPending = 6
+	})
+	require.NoError(t, w.Watch(ctx))
+
+	// 15 events were observed while the sink was down, but only the newest 6 were held for retry
+	require.Len(t, sink.sent, 1)
+	require.Len(t, sink.sent[0], 6)
+	var types []EventType
+	for _, event := range sink.sent[0][1:] {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []EventType{EnvironmentOfflineChanged, EnvironmentChanged, EnvironmentCreated, ReleaseVersionChanged, ReleaseChanged}, types)
+}
+
+func Test_WatchInitialLoadError(t *testing.T) {
+	loader := &fakeStateLoader{results: []fakeResult{{err: errors.Errorf("sherlock is down")}}}
+	err := New(loader, &fakeSink{}).Watch(context.Background())
+	assert.ErrorContains(t, err, "error loading initial state: sherlock is down")
+}
+
+func Test_JSONLinesSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONLinesSink(&buf)
+	require.NoError(t, sink.Send(context.Background(), []Event{
+		{Type: EnvironmentCreated, Time: observedAt, Name: "staging"},
+		{Type: ReleaseDeleted, Time: observedAt, Name: "sam", Destination: "dev"},
+	}))
+	assert.Equal(t, `{"type":"EnvironmentCreated","time":"2023-01-02T03:04:05Z","name":"staging"}
+{"type":"ReleaseDeleted","time":"2023-01-02T03:04:05Z","name":"sam","destination":"dev"}
+`, buf.String())
+}
+
+func Test_WebhookSink(t *testing.T) {
+	var received []Event
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var event Event
+		require.NoError(t, json.Unmarshal(body, &event))
+		received = append(received, event)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, time.Second)
+	events := []Event{{Type: EnvironmentCreated, Time: observedAt, Name: "staging"}}
+	require.NoError(t, sink.Send(context.Background(), events))
+	assert.Equal(t, events, received)
+
+	status = http.StatusInternalServerError
+	assert.ErrorContains(t, sink.Send(context.Background(), events), "webhook returned status 500")
+}
+
+type fakeResult struct {
+	state terra.State
+	err   error
+}
+
+// fakeStateLoader returns the configured results in order, repeating the last one indefinitely
+type fakeStateLoader struct {
+	results []fakeResult
+	calls   int
+}
+
+func (f *fakeStateLoader) Load() (terra.State, error) {
+	return f.Reload()
+}
+
+func (f *fakeStateLoader) Reload() (terra.State, error) {
+	i := f.calls
+	if i >= len(f.results) {
+		i = len(f.results) - 1
+	}
+	f.calls++
+	return f.results[i].state, f.results[i].err
+}
+
+// fakeSink records events, failing the first n sends
+type fakeSink struct {
+	mutex    sync.Mutex
+	failures int
+	sent     [][]Event
+	onSend   func()
+}
+
+func (f *fakeSink) Send(_ context.Context, events []Event) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		return errors.Errorf("webhook is down")
+	}
+	f.sent = append(f.sent, events)
+	if f.onSend != nil {
+		f.onSend()
+	}
+	return nil
+}
+
+func loadState(t *testing.T, content string) terra.State {
+	stateFile := path.Join(t.TempDir(), "state.yaml")
+	require.NoError(t, os.WriteFile(stateFile, []byte(content), 0644))
+	state, err := file.NewStateLoader(stateFile).Load()
+	require.NoError(t, err)
+	return state
+}
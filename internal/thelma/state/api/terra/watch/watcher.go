@@ -0,0 +1,122 @@
+// Package watch polls Thelma state for changes and emits typed events describing them
+package watch
+
+import (
+	"context"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra/diff"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type Options struct {
+	// Interval how often to poll for state changes
+	Interval time.Duration
+	// BackoffMultiplier how much to multiply the poll interval by after each consecutive failed reload
+	BackoffMultiplier float64
+	// MaxBackoff maximum poll interval after failed reloads
+	MaxBackoff time.Duration
+	// MaxPending maximum number of unsent events to hold for retry; when exceeded, the oldest events are dropped.
+	// Zero or less means no limit.
+	MaxPending int
+}
+
+type Option func(*Options)
+
+// Watcher polls a terra.StateLoader for changes
+type Watcher interface {
+	// Watch polls for changes until the context is cancelled, sending events to the sink.
+	// Failed reloads and failed sends are retried with backoff; events that could not be sent are retried
+	// on the next poll, up to Options.MaxPending. Returns nil when the context is cancelled, or an error if the initial state could
+	// not be loaded.
+	Watch(ctx context.Context) error
+}
+
+// New returns a new Watcher that reloads state from the given loader and sends events to the given sink
+func New(stateLoader terra.StateLoader, sink Sink, options ...Option) Watcher {
+	opts := Options{
+		Interval:          time.Minute,
+		BackoffMultiplier: 2,
+		MaxBackoff:        10 * time.Minute,
+		MaxPending:        1000,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+	return &watcher{
+		stateLoader: stateLoader,
+		sink:        sink,
+		options:     opts,
+		now:         time.Now,
+	}
+}
+
+type watcher struct {
+	stateLoader terra.StateLoader
+	sink        Sink
+	options     Options
+	now         func() time.Time
+}
+
+func (w *watcher) Watch(ctx context.Context) error {
+	previous, err := w.stateLoader.Reload()
+	if err != nil {
+		return errors.Errorf("error loading initial state: %v", err)
+	}
+
+	interval := w.options.Interval
+	var pending []Event
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+
+		current, err := w.stateLoader.Reload()
+		if err != nil {
+			interval = w.backoff(interval)
+			log.Warn().Err(err).Dur("retry-interval", interval).Msgf("error reloading state, will retry: %v", err)
+			continue
+		}
+
+		d, err := diff.States(previous, current)
+		if err != nil {
+			interval = w.backoff(interval)
+			log.Warn().Err(err).Dur("retry-interval", interval).Msgf("error comparing state snapshots, will retry: %v", err)
+			continue
+		}
+		previous = current
+
+		events := Events(d, w.now())
+		log.Debug().Msgf("observed %d state change event(s)", len(events))
+		pending = append(pending, events...)
+		if w.options.MaxPending > 0 && len(pending) > w.options.MaxPending {
+			dropped := len(pending) - w.options.MaxPending
+			log.Error().Int("max-pending", w.options.MaxPending).Msgf("too many unsent events, dropping the oldest %d", dropped)
+			pending = pending[dropped:]
+		}
+		if len(pending) == 0 {
+			interval = w.options.Interval
+			continue
+		}
+		if err = w.sink.Send(ctx, pending); err != nil {
+			interval = w.backoff(interval)
+			log.Warn().Err(err).Dur("retry-interval", interval).Msgf("error sending %d event(s), will retry: %v", len(pending), err)
+			continue
+		}
+		pending = nil
+		interval = w.options.Interval
+	}
+}
+
+// backoff returns the next poll interval after a failed reload or send
+func (w *watcher) backoff(interval time.Duration) time.Duration {
+	next := time.Duration(float64(interval) * w.options.BackoffMultiplier)
+	if next > w.options.MaxBackoff {
+		return w.options.MaxBackoff
+	}
+	return next
+}
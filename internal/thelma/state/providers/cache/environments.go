@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+)
+
+// environments implements terra.Environments. Queries are answered by the wrapped (possibly cached) view, while
+// mutations are made against live state and invalidate the cache.
+type environments struct {
+	terra.Environments
+	loader *stateLoader
+}
+
+func (e *environments) CreateFromTemplate(template terra.Environment, opts terra.CreateOptions) (string, error) {
+	var name string
+	err := e.mutate(func(live terra.Environments) error {
+		liveTemplate, err := live.Get(template.Name())
+		if err != nil {
+			return err
+		}
+		name, err = live.CreateFromTemplate(liveTemplate, opts)
+		return err
+	})
+	return name, err
+}
+
+func (e *environments) EnableRelease(environmentName string, releaseName string) error {
+	return e.mutate(func(live terra.Environments) error {
+		return live.EnableRelease(environmentName, releaseName)
+	})
+}
+
+func (e *environments) DisableRelease(environmentName string, releaseName string) error {
+	return e.mutate(func(live terra.Environments) error {
+		return live.DisableRelease(environmentName, releaseName)
+	})
+}
+
+func (e *environments) PinVersions(environmentName string, versions map[string]terra.VersionOverride) (map[string]terra.VersionOverride, error) {
+	var result map[string]terra.VersionOverride
+	err := e.mutate(func(live terra.Environments) error {
+		var err error
+		result, err = live.PinVersions(environmentName, versions)
+		return err
+	})
+	return result, err
+}
+
+func (e *environments) UnpinVersions(environmentName string) (map[string]terra.VersionOverride, error) {
+	var result map[string]terra.VersionOverride
+	err := e.mutate(func(live terra.Environments) error {
+		var err error
+		result, err = live.UnpinVersions(environmentName)
+		return err
+	})
+	return result, err
+}
+
+func (e *environments) PinEnvironmentToTerraHelmfileRef(environmentName string, terraHelmfileRef string) error {
+	return e.mutate(func(live terra.Environments) error {
+		return live.PinEnvironmentToTerraHelmfileRef(environmentName, terraHelmfileRef)
+	})
+}
+
+func (e *environments) Delete(name string) error {
+	return e.mutate(func(live terra.Environments) error {
+		return live.Delete(name)
+	})
+}
+
+func (e *environments) SetOffline(name string, offline bool) error {
+	return e.mutate(func(live terra.Environments) error {
+		return live.SetOffline(name, offline)
+	})
+}
+
+func (e *environments) SetAutoDeleteAfter(name string, after time.Time) error {
+	return e.mutate(func(live terra.Environments) error {
+		return live.SetAutoDeleteAfter(name, after)
+	})
+}
+
+func (e *environments) SetAutoDeleteNotified(name string, after time.Time) error {
+	return e.mutate(func(live terra.Environments) error {
+		return live.SetAutoDeleteNotified(name, after)
+	})
+}
+
+func (e *environments) SetSchedule(name string, options terra.ScheduleOptions) error {
+	return e.mutate(func(live terra.Environments) error {
+		return live.SetSchedule(name, options)
+	})
+}
+
+// mutate runs fn against live state, invalidating the cache afterwards (even if fn fails, since it may have
+// partially succeeded)
+func (e *environments) mutate(fn func(live terra.Environments) error) error {
+	live, err := e.loader.live()
+	if err != nil {
+		return err
+	}
+	defer e.loader.invalidate()
+	return fn(live.Environments())
+}
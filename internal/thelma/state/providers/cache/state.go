@@ -0,0 +1,26 @@
+package cache
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+)
+
+// state wraps a terra.State, which may have been read from the cache file, so that mutations are made through
+// the underlying loader's state instead
+type state struct {
+	terra.State
+	loader *stateLoader
+}
+
+func newState(s terra.State, loader *stateLoader) terra.State {
+	return &state{
+		State:  s,
+		loader: loader,
+	}
+}
+
+func (s *state) Environments() terra.Environments {
+	return &environments{
+		Environments: s.State.Environments(),
+		loader:       s.loader,
+	}
+}
@@ -0,0 +1,146 @@
+// Package cache implements a terra.StateLoader that caches state from another provider on disk, so that
+// repeated Thelma invocations don't have to re-download state from Sherlock every time.
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type stateLoader struct {
+	file       string
+	ttl        time.Duration
+	underlying terra.StateLoader
+	cached     terra.State
+}
+
+// NewStateLoader returns a terra.StateLoader that caches state loaded by the underlying loader in the given file.
+//
+// Load() returns cached state if the cache file is younger than ttl, and otherwise loads state from the underlying
+// loader and updates the cache. If the underlying loader fails (eg. because Sherlock is unreachable), Load() falls
+// back to the cached state, no matter how old, and logs a warning.
+//
+// Reload() always loads state from the underlying loader.
+//
+// Mutations made through the returned state (eg. creating or pinning environments) are always made through the
+// underlying loader's state, and invalidate the cache.
+func NewStateLoader(file string, ttl time.Duration, underlying terra.StateLoader) terra.StateLoader {
+	return &stateLoader{
+		file:       file,
+		ttl:        ttl,
+		underlying: underlying,
+	}
+}
+
+func (s *stateLoader) Load() (terra.State, error) {
+	if s.cached != nil {
+		return s.cached, nil
+	}
+
+	age, exists := s.cacheAge()
+	if exists && age < s.ttl {
+		_state, err := s.readCache()
+		if err == nil {
+			log.Debug().Msgf("using cached state from %s (%s old)", s.file, age.Round(time.Second))
+			s.cached = _state
+			return _state, nil
+		}
+		log.Warn().Err(err).Msgf("error reading state cache %s, will reload state", s.file)
+	}
+
+	_state, err := s.Reload()
+	if err == nil {
+		return _state, nil
+	}
+	if !exists {
+		return nil, err
+	}
+
+	stale, cacheErr := s.readCache()
+	if cacheErr != nil {
+		log.Debug().Err(cacheErr).Msgf("error reading state cache %s", s.file)
+		return nil, err
+	}
+	log.Warn().Msgf("!!! Failed to load state: %v", err)
+	log.Warn().Msgf("!!! Falling back to cached state from %s, which is %s old and may be OUT OF DATE", s.file, age.Round(time.Second))
+	s.cached = stale
+	return stale, nil
+}
+
+func (s *stateLoader) Reload() (terra.State, error) {
+	live, err := s.underlying.Reload()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.writeCache(live); err != nil {
+		log.Warn().Err(err).Msgf("error updating state cache %s", s.file)
+	}
+	s.cached = newState(live, s)
+	return s.cached, nil
+}
+
+// live returns state from the underlying loader, for use in mutations
+func (s *stateLoader) live() (terra.State, error) {
+	return s.underlying.Load()
+}
+
+// invalidate removes the cache file and forgets state loaded in this process, so that the next Load() will load
+// state from the underlying loader
+func (s *stateLoader) invalidate() {
+	s.cached = nil
+	if err := os.Remove(s.file); err != nil && !os.IsNotExist(err) {
+		log.Warn().Err(err).Msgf("error removing state cache %s", s.file)
+	}
+}
+
+// cacheAge returns the age of the cache file, and false if it does not exist
+func (s *stateLoader) cacheAge() (time.Duration, bool) {
+	info, err := os.Stat(s.file)
+	if err != nil {
+		return 0, false
+	}
+	return time.Since(info.ModTime()), true
+}
+
+func (s *stateLoader) readCache() (terra.State, error) {
+	_state, err := file.NewStateLoader(s.file).Load()
+	if err != nil {
+		return nil, err
+	}
+	return newState(_state, s), nil
+}
+
+// writeCache atomically replaces the cache file with a snapshot of the given state
+func (s *stateLoader) writeCache(_state terra.State) error {
+	doc, err := file.NewDocument(_state)
+	if err != nil {
+		return err
+	}
+	content, err := file.MarshalDocument(doc, true)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(s.file), 0755); err != nil {
+		return errors.Errorf("error creating state cache directory: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.file), filepath.Base(s.file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.file)
+}
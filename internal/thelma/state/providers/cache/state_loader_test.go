@@ -0,0 +1,200 @@
+package cache
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStateYaml = `
+clusters:
+  - name: terra-qa-bees
+    base: bee-cluster
+    address: https://10.0.0.1
+    project: broad-dsde-qa
+environments:
+  - name: swatomation
+    base: bee
+    lifecycle: template
+    defaultCluster: terra-qa-bees
+releases:
+  - name: sam
+    chart: sam
+    environment: swatomation
+    cluster: terra-qa-bees
+    namespace: terra-swatomation
+    chartVersion: 0.34.0
+    appVersion: 1.2.3
+`
+
+// fakeLoader wraps a file state loader, counting reloads and optionally failing
+type fakeLoader struct {
+	terra.StateLoader
+	reloads int
+	fail    bool
+}
+
+func (f *fakeLoader) Load() (terra.State, error) {
+	if f.fail {
+		return nil, errors.Errorf("sherlock is unreachable")
+	}
+	return f.StateLoader.Load()
+}
+
+func (f *fakeLoader) Reload() (terra.State, error) {
+	if f.fail {
+		return nil, errors.Errorf("sherlock is unreachable")
+	}
+	f.reloads++
+	return f.StateLoader.Reload()
+}
+
+type testFiles struct {
+	live  string
+	cache string
+}
+
+func setup(t *testing.T) (testFiles, *fakeLoader) {
+	dir := t.TempDir()
+	files := testFiles{
+		live:  path.Join(dir, "live.yaml"),
+		cache: path.Join(dir, "caches", "state.json"),
+	}
+	require.NoError(t, os.WriteFile(files.live, []byte(testStateYaml), 0644))
+	return files, &fakeLoader{StateLoader: file.NewStateLoader(files.live)}
+}
+
+func Test_LoadPopulatesCache(t *testing.T) {
+	files, underlying := setup(t)
+
+	_state, err := NewStateLoader(files.cache, time.Hour, underlying).Load()
+	require.NoError(t, err)
+	assert.Equal(t, 1, underlying.reloads)
+	assertHasSwatomation(t, _state)
+	assert.FileExists(t, files.cache)
+
+	// a new loader should use the cache instead of the underlying loader
+	_state, err = NewStateLoader(files.cache, time.Hour, underlying).Load()
+	require.NoError(t, err)
+	assert.Equal(t, 1, underlying.reloads)
+	assertHasSwatomation(t, _state)
+}
+
+func Test_LoadIgnoresExpiredCache(t *testing.T) {
+	files, underlying := setup(t)
+
+	_, err := NewStateLoader(files.cache, time.Hour, underlying).Load()
+	require.NoError(t, err)
+	ageCache(t, files.cache, 2*time.Hour)
+
+	_, err = NewStateLoader(files.cache, time.Hour, underlying).Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2, underlying.reloads)
+}
+
+func Test_ReloadBypassesCache(t *testing.T) {
+	files, underlying := setup(t)
+
+	loader := NewStateLoader(files.cache, time.Hour, underlying)
+	_, err := loader.Load()
+	require.NoError(t, err)
+	_, err = loader.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, 2, underlying.reloads)
+}
+
+func Test_LoadFallsBackToStaleCache(t *testing.T) {
+	files, underlying := setup(t)
+
+	_, err := NewStateLoader(files.cache, time.Hour, underlying).Load()
+	require.NoError(t, err)
+	ageCache(t, files.cache, 48*time.Hour)
+
+	underlying.fail = true
+	_state, err := NewStateLoader(files.cache, time.Hour, underlying).Load()
+	require.NoError(t, err)
+	assertHasSwatomation(t, _state)
+
+	// Reload should not fall back
+	_, err = NewStateLoader(files.cache, time.Hour, underlying).Reload()
+	assert.ErrorContains(t, err, "sherlock is unreachable")
+}
+
+func Test_LoadFailsWithoutCache(t *testing.T) {
+	files, underlying := setup(t)
+	underlying.fail = true
+
+	_, err := NewStateLoader(files.cache, time.Hour, underlying).Load()
+	assert.ErrorContains(t, err, "sherlock is unreachable")
+}
+
+func Test_MutationsUseLiveStateAndInvalidateCache(t *testing.T) {
+	files, underlying := setup(t)
+
+	_, err := NewStateLoader(files.cache, time.Hour, underlying).Load()
+	require.NoError(t, err)
+
+	// load state from cache, then create an environment
+	_state, err := NewStateLoader(files.cache, time.Hour, underlying).Load()
+	require.NoError(t, err)
+	assert.Equal(t, 1, underlying.reloads)
+
+	template, err := _state.Environments().Get("swatomation")
+	require.NoError(t, err)
+	name, err := _state.Environments().CreateFromTemplate(template, terra.CreateOptions{Name: "my-bee"})
+	require.NoError(t, err)
+	assert.Equal(t, "my-bee", name)
+
+	// the environment should have been written to live state, not the cache, and the cache should be invalidated
+	assert.NoFileExists(t, files.cache)
+	live, err := file.ReadDocument(files.live)
+	require.NoError(t, err)
+	assert.Len(t, live.Environments, 2)
+
+	_state, err = NewStateLoader(files.cache, time.Hour, underlying).Load()
+	require.NoError(t, err)
+	exists, err := _state.Environments().Exists("my-bee")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func assertHasSwatomation(t *testing.T, _state terra.State) {
+	env, err := _state.Environments().Get("swatomation")
+	require.NoError(t, err)
+	assert.Equal(t, terra.Template, env.Lifecycle())
+	releases, err := _state.Releases().All()
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+	assert.Equal(t, "1.2.3", releases[0].AppVersion())
+}
+
+func ageCache(t *testing.T, cacheFile string, age time.Duration) {
+	mtime := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(cacheFile, mtime, mtime))
+}
+
+func Test_MutationsInvalidateLoadedState(t *testing.T) {
+	files, underlying := setup(t)
+	loader := NewStateLoader(files.cache, time.Hour, underlying)
+	_state, err := loader.Load()
+	require.NoError(t, err)
+
+	template, err := _state.Environments().Get("swatomation")
+	require.NoError(t, err)
+	_, err = _state.Environments().CreateFromTemplate(template, terra.CreateOptions{Name: "my-bee"})
+	require.NoError(t, err)
+
+	// state loaded earlier in the same process shouldn't be returned after a mutation
+	_state, err = loader.Load()
+	require.NoError(t, err)
+	exists, err := _state.Environments().Exists("my-bee")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
@@ -0,0 +1,21 @@
+package file
+
+import "time"
+
+type autoDelete struct {
+	enabled     bool
+	after       time.Time
+	notifiedFor time.Time
+}
+
+func (a autoDelete) Enabled() bool {
+	return a.enabled
+}
+
+func (a autoDelete) After() time.Time {
+	return a.after
+}
+
+func (a autoDelete) NotifiedFor() time.Time {
+	return a.notifiedFor
+}
@@ -0,0 +1,63 @@
+package file
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+)
+
+const clusterDefaultLocation = "us-central1-a"
+
+type cluster struct {
+	address       string
+	googleProject string
+	location      string
+	releases      map[string]*release
+	destination
+}
+
+func (c *cluster) Releases() []terra.Release {
+	return sortedReleases(c.releases)
+}
+
+func (c *cluster) Address() string {
+	return c.address
+}
+
+func (c *cluster) Project() string {
+	return c.googleProject
+}
+
+func (c *cluster) ProjectSuffix() string {
+	tokens := strings.Split(c.Project(), "-")
+	return tokens[len(tokens)-1]
+}
+
+func (c *cluster) Location() string {
+	if c.location == "" {
+		return clusterDefaultLocation
+	}
+	return c.location
+}
+
+func (c *cluster) ReleaseType() terra.ReleaseType {
+	return terra.ClusterReleaseType
+}
+
+func (c *cluster) ArtifactBucket() string {
+	return fmt.Sprintf("thelma-artifacts-%s", c.name)
+}
+
+// sortedReleases returns the releases in the map, sorted by name, so that output is stable across invocations
+func sortedReleases(releases map[string]*release) []terra.Release {
+	var result []terra.Release
+	for _, r := range releases {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
@@ -0,0 +1,41 @@
+package file
+
+import (
+	"sort"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+)
+
+type clusters struct {
+	state *state
+}
+
+func newClustersView(s *state) terra.Clusters {
+	return &clusters{
+		state: s,
+	}
+}
+
+func (c *clusters) All() ([]terra.Cluster, error) {
+	var result []terra.Cluster
+	for _, cluster := range c.state.clusters {
+		result = append(result, cluster)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result, nil
+}
+
+func (c *clusters) Get(name string) (terra.Cluster, error) {
+	cluster, exists := c.state.clusters[name]
+	if !exists {
+		return nil, nil
+	}
+	return cluster, nil
+}
+
+func (c *clusters) Exists(name string) (bool, error) {
+	_, exists := c.state.clusters[name]
+	return exists, nil
+}
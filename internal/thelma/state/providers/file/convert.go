@@ -0,0 +1,142 @@
+package file
+
+import (
+	"sort"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+)
+
+// NewDocument serializes all clusters, environments, and releases in the given state into a Document.
+// Entries are sorted by name so that snapshots of the same state are identical and easy to diff.
+func NewDocument(state terra.State) (*Document, error) {
+	doc := &Document{Version: DocumentVersion}
+
+	allClusters, err := state.Clusters().All()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range allClusters {
+		addCluster(doc, c)
+	}
+
+	allEnvironments, err := state.Environments().All()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range allEnvironments {
+		addEnvironment(doc, e)
+	}
+
+	sortDocument(doc)
+	return doc, nil
+}
+
+// sortDocument sorts the entries in the document by name (and releases by destination)
+func sortDocument(doc *Document) {
+	sort.Slice(doc.Clusters, func(i, j int) bool {
+		return doc.Clusters[i].Name < doc.Clusters[j].Name
+	})
+	sort.Slice(doc.Environments, func(i, j int) bool {
+		return doc.Environments[i].Name < doc.Environments[j].Name
+	})
+	sort.Slice(doc.Releases, func(i, j int) bool {
+		if doc.Releases[i].destinationName() != doc.Releases[j].destinationName() {
+			return doc.Releases[i].destinationName() < doc.Releases[j].destinationName()
+		}
+		return doc.Releases[i].Name < doc.Releases[j].Name
+	})
+}
+
+// addCluster adds a cluster and all of its releases to the document
+func addCluster(doc *Document, c terra.Cluster) {
+	doc.upsertCluster(fromCluster(c))
+	for _, r := range c.Releases() {
+		doc.upsertRelease(fromRelease(r))
+	}
+}
+
+// addEnvironment adds an environment and all of its releases to the document
+func addEnvironment(doc *Document, e terra.Environment) {
+	doc.upsertEnvironment(fromEnvironment(e))
+	for _, r := range e.Releases() {
+		doc.upsertRelease(fromRelease(r))
+	}
+}
+
+func fromCluster(c terra.Cluster) Cluster {
+	return Cluster{
+		Name:             c.Name(),
+		Base:             c.Base(),
+		Address:          c.Address(),
+		Project:          c.Project(),
+		Location:         c.Location(),
+		RequiredRole:     c.RequiredRole(),
+		TerraHelmfileRef: c.TerraHelmfileRef(),
+	}
+}
+
+func fromEnvironment(e terra.Environment) Environment {
+	env := Environment{
+		Name:                 e.Name(),
+		Base:                 e.Base(),
+		Lifecycle:            e.Lifecycle().String(),
+		Template:             e.Template(),
+		DefaultNamespace:     e.Namespace(),
+		BaseDomain:           e.BaseDomain(),
+		NamePrefixesDomain:   e.NamePrefixesDomain(),
+		UniqueResourcePrefix: e.UniqueResourcePrefix(),
+		Owner:                e.Owner(),
+		RequiredRole:         e.RequiredRole(),
+		TerraHelmfileRef:     e.TerraHelmfileRef(),
+		PreventDeletion:      e.PreventDeletion(),
+		CreatedAt:            e.CreatedAt(),
+		Offline:              e.Offline(),
+		EnableJanitor:        e.EnableJanitor(),
+	}
+	if e.DefaultCluster() != nil {
+		env.DefaultCluster = e.DefaultCluster().Name()
+	}
+	if e.AutoDelete() != nil && e.AutoDelete().Enabled() {
+		env.AutoDelete.Enabled = true
+		env.AutoDelete.After = e.AutoDelete().After()
+		env.AutoDelete.NotifiedFor = e.AutoDelete().NotifiedFor()
+	}
+	if e.OfflineScheduleBeginEnabled() {
+		env.OfflineSchedule.Begin.Enabled = true
+		env.OfflineSchedule.Begin.Time = e.OfflineScheduleBeginTime()
+	}
+	if e.OfflineScheduleEndEnabled() {
+		env.OfflineSchedule.End.Enabled = true
+		env.OfflineSchedule.End.Time = e.OfflineScheduleEndTime()
+		env.OfflineSchedule.End.Weekends = e.OfflineScheduleEndWeekends()
+	}
+	env.OfflineSchedule.Schedule = e.OfflineSchedule()
+	env.OfflineSchedule.StartOnHolidays = e.StartOnHolidays()
+	env.Profile = e.Profile()
+	return env
+}
+
+func fromRelease(r terra.Release) Release {
+	result := Release{
+		Name:             r.Name(),
+		Chart:            r.ChartName(),
+		Repo:             r.Repo(),
+		Cluster:          r.ClusterName(),
+		Namespace:        r.Namespace(),
+		ChartVersion:     r.ChartVersion(),
+		AppVersion:       r.AppVersion(),
+		TerraHelmfileRef: r.TerraHelmfileRef(),
+	}
+	if r.FullName() != r.Name()+"-"+r.Destination().Name() {
+		result.FullName = r.FullName()
+	}
+	if r.IsAppRelease() {
+		result.Environment = r.Destination().Name()
+		if appRelease, ok := r.(terra.AppRelease); ok {
+			result.Subdomain = appRelease.Subdomain()
+			result.Protocol = appRelease.Protocol()
+			result.Port = appRelease.Port()
+		}
+	}
+	return result
+}
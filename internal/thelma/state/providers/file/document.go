@@ -0,0 +1,217 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DocumentVersion is the version of the state file format written by this package.
+// It should be incremented whenever a backwards-incompatible change is made to the Document structure.
+const DocumentVersion = 1
+
+// Document is the root type of a state file. It can be serialized as either YAML or JSON.
+//
+// Example:
+//
+//	version: 1
+//	clusters:
+//	  - name: terra-dev
+//	    base: terra
+//	    address: https://35.238.186.116
+//	    project: broad-dsde-dev
+//	    location: us-central1-a
+//	environments:
+//	  - name: dev
+//	    base: live
+//	    lifecycle: static
+//	    defaultCluster: terra-dev
+//	releases:
+//	  - name: sam
+//	    chart: sam
+//	    repo: terra-helm
+//	    environment: dev
+//	    cluster: terra-dev
+//	    namespace: terra-dev
+//	    chartVersion: 0.34.0
+//	    appVersion: 0.1.0
+type Document struct {
+	// Version of the file format; see DocumentVersion
+	Version int `json:"version" yaml:"version"`
+	// Clusters in state
+	Clusters []Cluster `json:"clusters" yaml:"clusters"`
+	// Environments in state
+	Environments []Environment `json:"environments" yaml:"environments"`
+	// Releases in state, both cluster and app releases
+	Releases []Release `json:"releases" yaml:"releases"`
+}
+
+// Cluster is the serialized form of a terra.Cluster
+type Cluster struct {
+	Name             string `json:"name" yaml:"name"`
+	Base             string `json:"base" yaml:"base"`
+	Address          string `json:"address" yaml:"address"`
+	Project          string `json:"project" yaml:"project"`
+	Location         string `json:"location,omitempty" yaml:"location,omitempty"`
+	RequiredRole     string `json:"requiredRole,omitempty" yaml:"requiredRole,omitempty"`
+	TerraHelmfileRef string `json:"terraHelmfileRef,omitempty" yaml:"terraHelmfileRef,omitempty"`
+}
+
+// Environment is the serialized form of a terra.Environment
+type Environment struct {
+	Name                 string          `json:"name" yaml:"name"`
+	Base                 string          `json:"base" yaml:"base"`
+	Lifecycle            string          `json:"lifecycle" yaml:"lifecycle"`
+	Template             string          `json:"template,omitempty" yaml:"template,omitempty"`
+	Profile              string          `json:"profile,omitempty" yaml:"profile,omitempty"`
+	DefaultCluster       string          `json:"defaultCluster,omitempty" yaml:"defaultCluster,omitempty"`
+	DefaultNamespace     string          `json:"defaultNamespace,omitempty" yaml:"defaultNamespace,omitempty"`
+	BaseDomain           string          `json:"baseDomain,omitempty" yaml:"baseDomain,omitempty"`
+	NamePrefixesDomain   bool            `json:"namePrefixesDomain,omitempty" yaml:"namePrefixesDomain,omitempty"`
+	UniqueResourcePrefix string          `json:"uniqueResourcePrefix,omitempty" yaml:"uniqueResourcePrefix,omitempty"`
+	Owner                string          `json:"owner,omitempty" yaml:"owner,omitempty"`
+	RequiredRole         string          `json:"requiredRole,omitempty" yaml:"requiredRole,omitempty"`
+	TerraHelmfileRef     string          `json:"terraHelmfileRef,omitempty" yaml:"terraHelmfileRef,omitempty"`
+	PreventDeletion      bool            `json:"preventDeletion,omitempty" yaml:"preventDeletion,omitempty"`
+	AutoDelete           AutoDelete      `json:"autoDelete,omitempty" yaml:"autoDelete,omitempty"`
+	CreatedAt            time.Time       `json:"createdAt,omitempty" yaml:"createdAt,omitempty"`
+	Offline              bool            `json:"offline,omitempty" yaml:"offline,omitempty"`
+	OfflineSchedule      OfflineSchedule `json:"offlineSchedule,omitempty" yaml:"offlineSchedule,omitempty"`
+	EnableJanitor        bool            `json:"enableJanitor,omitempty" yaml:"enableJanitor,omitempty"`
+}
+
+// AutoDelete is the serialized form of a terra.AutoDelete
+type AutoDelete struct {
+	Enabled     bool      `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	After       time.Time `json:"after,omitempty" yaml:"after,omitempty"`
+	NotifiedFor time.Time `json:"notifiedFor,omitempty" yaml:"notifiedFor,omitempty"`
+}
+
+// OfflineSchedule holds an environment's start/stop schedule settings
+type OfflineSchedule struct {
+	// Begin is the daily time the environment should go offline (stop)
+	Begin struct {
+		Enabled bool      `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+		Time    time.Time `json:"time,omitempty" yaml:"time,omitempty"`
+	} `json:"begin,omitempty" yaml:"begin,omitempty"`
+	// End is the daily time the environment should come back online (start)
+	End struct {
+		Enabled  bool      `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+		Time     time.Time `json:"time,omitempty" yaml:"time,omitempty"`
+		Weekends bool      `json:"weekends,omitempty" yaml:"weekends,omitempty"`
+	} `json:"end,omitempty" yaml:"end,omitempty"`
+	// Schedule is a cron or weekly schedule, used instead of Begin and End
+	Schedule *schedule.Schedule `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	// StartOnHolidays is true if the start schedule still applies on holidays
+	StartOnHolidays bool `json:"startOnHolidays,omitempty" yaml:"startOnHolidays,omitempty"`
+}
+
+// Release is the serialized form of a terra.Release. Exactly one of Environment or Cluster
+// determines the release's destination: if Environment is set the release is an app release, otherwise
+// it is a cluster release deployed to Cluster.
+type Release struct {
+	// Name is the short name of the release, unique within its destination (eg. "sam")
+	Name string `json:"name" yaml:"name"`
+	// FullName is the globally-unique name of the release. Defaults to "<name>-<destination>"
+	FullName         string `json:"fullName,omitempty" yaml:"fullName,omitempty"`
+	Chart            string `json:"chart" yaml:"chart"`
+	Repo             string `json:"repo,omitempty" yaml:"repo,omitempty"`
+	Environment      string `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Cluster          string `json:"cluster" yaml:"cluster"`
+	Namespace        string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	ChartVersion     string `json:"chartVersion,omitempty" yaml:"chartVersion,omitempty"`
+	AppVersion       string `json:"appVersion,omitempty" yaml:"appVersion,omitempty"`
+	TerraHelmfileRef string `json:"terraHelmfileRef,omitempty" yaml:"terraHelmfileRef,omitempty"`
+	Subdomain        string `json:"subdomain,omitempty" yaml:"subdomain,omitempty"`
+	Protocol         string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Port             int    `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+// destinationName returns the name of the environment or cluster this release is deployed to
+func (r Release) destinationName() string {
+	if r.Environment != "" {
+		return r.Environment
+	}
+	return r.Cluster
+}
+
+// ReadDocument reads a state document from the given file. Files with a .json extension are parsed as JSON,
+// all others as YAML.
+func ReadDocument(file string) (*Document, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Errorf("error reading state file %s: %v", file, err)
+	}
+	doc, err := ParseDocument(content, isJson(file))
+	if err != nil {
+		return nil, errors.Errorf("error parsing state file %s: %v", file, err)
+	}
+	return doc, nil
+}
+
+// ParseDocument parses a state document from raw JSON or YAML content
+func ParseDocument(content []byte, asJson bool) (*Document, error) {
+	var doc Document
+	if asJson {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, err
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, err
+		}
+	}
+	if doc.Version > DocumentVersion {
+		return nil, errors.Errorf("unsupported state file version %d (this version of Thelma supports up to %d)", doc.Version, DocumentVersion)
+	}
+	return &doc, nil
+}
+
+// WriteDocument writes a state document to the given file, using JSON if the file has a .json extension
+// and YAML otherwise.
+func WriteDocument(file string, doc *Document) error {
+	content, err := MarshalDocument(doc, isJson(file))
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return errors.Errorf("error creating directory for state file %s: %v", file, err)
+	}
+	if err = os.WriteFile(file, content, 0644); err != nil {
+		return errors.Errorf("error writing state file %s: %v", file, err)
+	}
+	return nil
+}
+
+// MarshalDocument serializes a state document to JSON or YAML
+func MarshalDocument(doc *Document, asJson bool) ([]byte, error) {
+	doc.Version = DocumentVersion
+	if asJson {
+		return json.MarshalIndent(doc, "", "  ")
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func isJson(file string) bool {
+	return strings.EqualFold(filepath.Ext(file), ".json")
+}
@@ -0,0 +1,129 @@
+package file
+
+import (
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+)
+
+type environment struct {
+	createdAt                   time.Time
+	defaultCluster              terra.Cluster
+	defaultNamespace            string
+	releases                    map[string]*release
+	lifecycle                   terra.Lifecycle
+	template                    string
+	baseDomain                  string
+	namePrefixesDomain          bool
+	uniqueResourcePrefix        string
+	owner                       string
+	preventDeletion             bool
+	autoDelete                  autoDelete
+	offline                     bool
+	offlineScheduleBeginEnabled bool
+	offlineScheduleBeginTime    time.Time
+	offlineScheduleEndEnabled   bool
+	offlineScheduleEndTime      time.Time
+	offlineScheduleEndWeekends  bool
+	offlineSchedule             *schedule.Schedule
+	startOnHolidays             bool
+	profile                     string
+	enableJanitor               bool
+	destination
+}
+
+func (e *environment) Releases() []terra.Release {
+	return sortedReleases(e.releases)
+}
+
+func (e *environment) CreatedAt() time.Time {
+	return e.createdAt
+}
+
+func (e *environment) DefaultCluster() terra.Cluster {
+	return e.defaultCluster
+}
+
+func (e *environment) Lifecycle() terra.Lifecycle {
+	return e.lifecycle
+}
+
+func (e *environment) Template() string {
+	return e.template
+}
+
+func (e *environment) ReleaseType() terra.ReleaseType {
+	return terra.AppReleaseType
+}
+
+func (e *environment) Namespace() string {
+	if e.defaultNamespace != "" {
+		return e.defaultNamespace
+	}
+	return e.Name()
+}
+
+func (e *environment) BaseDomain() string {
+	return e.baseDomain
+}
+
+func (e *environment) NamePrefixesDomain() bool {
+	return e.namePrefixesDomain
+}
+
+func (e *environment) UniqueResourcePrefix() string {
+	return e.uniqueResourcePrefix
+}
+
+func (e *environment) Owner() string {
+	return e.owner
+}
+
+func (e *environment) PreventDeletion() bool {
+	return e.preventDeletion
+}
+
+func (e *environment) AutoDelete() terra.AutoDelete {
+	return e.autoDelete
+}
+
+func (e *environment) Offline() bool {
+	return e.offline
+}
+
+func (e *environment) OfflineScheduleBeginEnabled() bool {
+	return e.offlineScheduleBeginEnabled
+}
+
+func (e *environment) OfflineScheduleBeginTime() time.Time {
+	return e.offlineScheduleBeginTime
+}
+
+func (e *environment) OfflineScheduleEndEnabled() bool {
+	return e.offlineScheduleEndEnabled
+}
+
+func (e *environment) OfflineScheduleEndTime() time.Time {
+	return e.offlineScheduleEndTime
+}
+
+func (e *environment) OfflineScheduleEndWeekends() bool {
+	return e.offlineScheduleEndWeekends
+}
+
+func (e *environment) OfflineSchedule() *schedule.Schedule {
+	return e.offlineSchedule
+}
+
+func (e *environment) StartOnHolidays() bool {
+	return e.startOnHolidays
+}
+
+func (e *environment) Profile() string {
+	return e.profile
+}
+
+func (e *environment) EnableJanitor() bool {
+	return e.enableJanitor
+}
@@ -0,0 +1,350 @@
+package file
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// number of random characters to append to a template name when generating a new environment name
+const generatedNameSuffixLength = 6
+
+var lowerAlpha = []rune("abcdefghijklmnopqrstuvwxyz")
+var lowerAlphaNumeric = []rune("abcdefghijklmnopqrstuvwxyz0123456789")
+
+type environments struct {
+	state *state
+}
+
+func newEnvironmentsView(s *state) terra.Environments {
+	return &environments{
+		state: s,
+	}
+}
+
+func (e *environments) All() ([]terra.Environment, error) {
+	var result []terra.Environment
+	for _, environment := range e.state.environments {
+		result = append(result, environment)
+	}
+	return result, nil
+}
+
+func (e *environments) Get(name string) (terra.Environment, error) {
+	env, exists := e.state.environments[name]
+	if !exists {
+		return nil, errors.Errorf("environment %q does not exist", name)
+	}
+	return env, nil
+}
+
+func (e *environments) Exists(name string) (bool, error) {
+	_, exists := e.state.environments[name]
+	return exists, nil
+}
+
+func (e *environments) CreateFromTemplate(template terra.Environment, options terra.CreateOptions) (string, error) {
+	if !template.Lifecycle().IsTemplate() {
+		return "", errors.Errorf("environment %s is not a template (lifecycle is %s)", template.Name(), template.Lifecycle())
+	}
+
+	var name string
+	err := e.state.store.update(func(doc *Document) error {
+		tmplPtr, err := doc.mustGetEnvironment(template.Name())
+		if err != nil {
+			return err
+		}
+		// copy the template so we don't hold a pointer into doc.Environments while appending to it
+		tmpl := *tmplPtr
+
+		name = options.Name
+		if name == "" {
+			name = generateEnvironmentName(doc, tmpl.Name)
+		}
+		if doc.environment(name) != nil {
+			return errors.Errorf("can't create environment %q: an environment by that name already exists", name)
+		}
+
+		env := Environment{
+			Name:                 name,
+			Base:                 tmpl.Base,
+			Lifecycle:            terra.Dynamic.String(),
+			Template:             tmpl.Name,
+			Profile:              options.Profile,
+			DefaultCluster:       tmpl.DefaultCluster,
+			DefaultNamespace:     fmt.Sprintf("terra-%s", name),
+			BaseDomain:           tmpl.BaseDomain,
+			NamePrefixesDomain:   tmpl.NamePrefixesDomain,
+			UniqueResourcePrefix: generateUniqueResourcePrefix(doc),
+			Owner:                options.Owner,
+			RequiredRole:         tmpl.RequiredRole,
+			TerraHelmfileRef:     tmpl.TerraHelmfileRef,
+			CreatedAt:            time.Now(),
+			EnableJanitor:        tmpl.EnableJanitor,
+		}
+		if options.AutoDelete.Enabled {
+			env.AutoDelete.Enabled = true
+			env.AutoDelete.After = options.AutoDelete.After
+		}
+		env.OfflineSchedule = offlineSchedule(options.ScheduleOptions)
+		doc.upsertEnvironment(env)
+
+		var copied []Release
+		for _, r := range doc.appReleasesIn(tmpl.Name) {
+			copied = append(copied, copyReleaseTo(*r, env))
+		}
+		doc.Releases = append(doc.Releases, copied...)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (e *environments) EnableRelease(environmentName string, releaseName string) error {
+	return e.state.store.update(func(doc *Document) error {
+		return enableRelease(doc, environmentName, releaseName)
+	})
+}
+
+func (e *environments) DisableRelease(environmentName string, releaseName string) error {
+	return e.state.store.update(func(doc *Document) error {
+		return disableRelease(doc, environmentName, releaseName)
+	})
+}
+
+func (e *environments) PinVersions(environmentName string, versions map[string]terra.VersionOverride) (map[string]terra.VersionOverride, error) {
+	err := e.state.store.update(func(doc *Document) error {
+		if _, err := doc.mustGetEnvironment(environmentName); err != nil {
+			return err
+		}
+		for releaseName, override := range versions {
+			r := doc.release(environmentName, releaseName)
+			if r == nil {
+				return errors.Errorf("can't pin versions for release %q: it does not exist in environment %q", releaseName, environmentName)
+			}
+			if override.AppVersion != "" {
+				r.AppVersion = override.AppVersion
+			}
+			if override.ChartVersion != "" {
+				r.ChartVersion = override.ChartVersion
+			}
+			if override.TerraHelmfileRef != "" {
+				r.TerraHelmfileRef = override.TerraHelmfileRef
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (e *environments) PinEnvironmentToTerraHelmfileRef(environmentName string, terraHelmfileRef string) error {
+	return e.state.store.update(func(doc *Document) error {
+		env, err := doc.mustGetEnvironment(environmentName)
+		if err != nil {
+			return err
+		}
+		env.TerraHelmfileRef = terraHelmfileRef
+		for _, r := range doc.appReleasesIn(environmentName) {
+			r.TerraHelmfileRef = terraHelmfileRef
+		}
+		return nil
+	})
+}
+
+// UnpinVersions resets the environment's terra-helmfile ref and the versions of all its releases to those of the
+// environment's template. It returns the versions that were in place before they were reset.
+func (e *environments) UnpinVersions(environmentName string) (map[string]terra.VersionOverride, error) {
+	removed := make(map[string]terra.VersionOverride)
+	err := e.state.store.update(func(doc *Document) error {
+		env, err := doc.mustGetEnvironment(environmentName)
+		if err != nil {
+			return err
+		}
+		if env.Template == "" {
+			return errors.Errorf("can't unpin versions for environment %q: it has no template to reset versions to", environmentName)
+		}
+		tmpl, err := doc.mustGetEnvironment(env.Template)
+		if err != nil {
+			return err
+		}
+		env.TerraHelmfileRef = tmpl.TerraHelmfileRef
+		for _, r := range doc.appReleasesIn(environmentName) {
+			templateRelease := doc.release(tmpl.Name, r.Name)
+			if templateRelease == nil {
+				log.Debug().Msgf("release %s in %s is not in template %s, won't reset its versions", r.Name, environmentName, tmpl.Name)
+				continue
+			}
+			removed[r.Name] = terra.VersionOverride{
+				AppVersion:       r.AppVersion,
+				ChartVersion:     r.ChartVersion,
+				TerraHelmfileRef: r.TerraHelmfileRef,
+			}
+			r.AppVersion = templateRelease.AppVersion
+			r.ChartVersion = templateRelease.ChartVersion
+			r.TerraHelmfileRef = templateRelease.TerraHelmfileRef
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+func (e *environments) Delete(name string) error {
+	return e.state.store.update(func(doc *Document) error {
+		if _, err := doc.mustGetEnvironment(name); err != nil {
+			return err
+		}
+		doc.removeEnvironment(name)
+		return nil
+	})
+}
+
+func (e *environments) SetOffline(name string, offline bool) error {
+	return e.state.store.update(func(doc *Document) error {
+		env, err := doc.mustGetEnvironment(name)
+		if err != nil {
+			return err
+		}
+		env.Offline = offline
+		return nil
+	})
+}
+
+func (e *environments) SetAutoDeleteAfter(name string, after time.Time) error {
+	return e.state.store.update(func(doc *Document) error {
+		env, err := doc.mustGetEnvironment(name)
+		if err != nil {
+			return err
+		}
+		env.AutoDelete.Enabled = true
+		env.AutoDelete.After = after
+		return nil
+	})
+}
+
+func (e *environments) SetAutoDeleteNotified(name string, after time.Time) error {
+	return e.state.store.update(func(doc *Document) error {
+		env, err := doc.mustGetEnvironment(name)
+		if err != nil {
+			return err
+		}
+		env.AutoDelete.NotifiedFor = after
+		return nil
+	})
+}
+
+func (e *environments) SetSchedule(name string, options terra.ScheduleOptions) error {
+	return e.state.store.update(func(doc *Document) error {
+		env, err := doc.mustGetEnvironment(name)
+		if err != nil {
+			return err
+		}
+		env.OfflineSchedule = offlineSchedule(options)
+		return nil
+	})
+}
+
+// offlineSchedule converts schedule options to their serialized form
+func offlineSchedule(options terra.ScheduleOptions) OfflineSchedule {
+	var result OfflineSchedule
+	if options.StopSchedule.Enabled {
+		result.Begin.Enabled = true
+		result.Begin.Time = options.StopSchedule.RepeatingTime
+	}
+	if options.StartSchedule.Enabled {
+		result.End.Enabled = true
+		result.End.Time = options.StartSchedule.RepeatingTime
+		result.End.Weekends = options.StartSchedule.Weekends
+	}
+	result.Schedule = options.Schedule
+	result.StartOnHolidays = options.StartOnHolidays
+	return result
+}
+
+// enableRelease copies a release from a dynamic environment's template into the environment
+func enableRelease(doc *Document, environmentName string, releaseName string) error {
+	env, err := doc.mustGetEnvironment(environmentName)
+	if err != nil {
+		return err
+	}
+	if env.Lifecycle != terra.Dynamic.String() {
+		return errors.Errorf("enabling releases is only supported for dynamic environments")
+	}
+	if doc.release(environmentName, releaseName) != nil {
+		log.Debug().Msgf("release %s is already enabled in %s", releaseName, environmentName)
+		return nil
+	}
+	templateRelease := doc.release(env.Template, releaseName)
+	if templateRelease == nil {
+		return errors.Errorf("unable to enable release, %q does not exist in template %q", releaseName, env.Template)
+	}
+	doc.Releases = append(doc.Releases, copyReleaseTo(*templateRelease, *env))
+	return nil
+}
+
+// disableRelease removes a release from a dynamic environment
+func disableRelease(doc *Document, environmentName string, releaseName string) error {
+	env, err := doc.mustGetEnvironment(environmentName)
+	if err != nil {
+		return err
+	}
+	if env.Lifecycle != terra.Dynamic.String() {
+		return errors.Errorf("disabling releases is only supported in dynamic environments")
+	}
+	doc.removeRelease(environmentName, releaseName)
+	return nil
+}
+
+// copyReleaseTo returns a copy of the given app release, re-targeted to the given environment
+func copyReleaseTo(r Release, env Environment) Release {
+	r.Environment = env.Name
+	r.FullName = ""
+	if env.DefaultNamespace != "" {
+		r.Namespace = env.DefaultNamespace
+	}
+	return r
+}
+
+// generateEnvironmentName generates a unique name for a new environment based on its template
+func generateEnvironmentName(doc *Document, templateName string) string {
+	for {
+		name := fmt.Sprintf("%s-%s", templateName, randomString(lowerAlphaNumeric, generatedNameSuffixLength))
+		if doc.environment(name) == nil {
+			return name
+		}
+	}
+}
+
+// generateUniqueResourcePrefix generates a resource prefix matching [a-z][a-z0-9]{3} that is
+// not used by any other environment in the document
+func generateUniqueResourcePrefix(doc *Document) string {
+	used := make(map[string]struct{})
+	for _, e := range doc.Environments {
+		used[e.UniqueResourcePrefix] = struct{}{}
+	}
+	for {
+		prefix := randomString(lowerAlpha, 1) + randomString(lowerAlphaNumeric, 3)
+		if _, exists := used[prefix]; !exists {
+			return prefix
+		}
+	}
+}
+
+func randomString(alphabet []rune, length int) string {
+	buf := make([]rune, length)
+	for i := range buf {
+		buf[i] = alphabet[rand.Intn(len(alphabet))]
+	}
+	return string(buf)
+}
@@ -0,0 +1,219 @@
+package file
+
+import (
+	"testing"
+	"time"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadTestState(t *testing.T) (terra.StateLoader, terra.State) {
+	file := writeTestStateFile(t, "state.yaml", testStateYaml)
+	loader := NewStateLoader(file)
+	state, err := loader.Load()
+	require.NoError(t, err)
+	return loader, state
+}
+
+func TestCreateFromTemplate(t *testing.T) {
+	loader, state := loadTestState(t)
+
+	template, err := state.Environments().Get("swatomation")
+	require.NoError(t, err)
+
+	var opts terra.CreateOptions
+	opts.Owner = "someone@broadinstitute.org"
+	opts.AutoDelete.Enabled = true
+	opts.AutoDelete.After = time.Now().Add(6 * time.Hour)
+	opts.StartSchedule.Enabled = true
+	opts.StartSchedule.Weekends = true
+	opts.Schedule = &schedule.Schedule{
+		TimeZone: "Europe/London",
+		Stop:     &schedule.Rule{Cron: "0 19 * * mon-fri"},
+	}
+	opts.StartOnHolidays = true
+	opts.Profile = "minimal"
+
+	name, err := state.Environments().CreateFromTemplate(template, opts)
+	require.NoError(t, err)
+	assert.Regexp(t, `^swatomation-[a-z0-9]{6}$`, name)
+
+	state, err = loader.Reload()
+	require.NoError(t, err)
+
+	bee, err := state.Environments().Get(name)
+	require.NoError(t, err)
+	assert.Equal(t, terra.Dynamic, bee.Lifecycle())
+	assert.Equal(t, "swatomation", bee.Template())
+	assert.Equal(t, "terra-"+name, bee.Namespace())
+	assert.Equal(t, "terra-qa-bees", bee.DefaultCluster().Name())
+	assert.Equal(t, "someone@broadinstitute.org", bee.Owner())
+	assert.Regexp(t, `^[a-z][a-z0-9]{3}$`, bee.UniqueResourcePrefix())
+	assert.True(t, bee.AutoDelete().Enabled())
+	assert.True(t, bee.OfflineScheduleEndEnabled())
+	assert.True(t, bee.OfflineScheduleEndWeekends())
+	assert.False(t, bee.OfflineScheduleBeginEnabled())
+	require.NotNil(t, bee.OfflineSchedule())
+	assert.Equal(t, "Europe/London", bee.OfflineSchedule().TimeZone)
+	assert.Equal(t, "0 19 * * mon-fri", bee.OfflineSchedule().Stop.Cron)
+	assert.Nil(t, bee.OfflineSchedule().Start)
+	assert.True(t, bee.StartOnHolidays())
+	assert.Equal(t, "minimal", bee.Profile())
+	assert.Len(t, bee.Releases(), 2)
+	for _, r := range bee.Releases() {
+		assert.Equal(t, "terra-"+name, r.Namespace())
+	}
+
+	opts.Name = name
+	_, err = state.Environments().CreateFromTemplate(template, opts)
+	assert.ErrorContains(t, err, "already exists")
+
+	dev, err := state.Environments().Get("dev")
+	require.NoError(t, err)
+	_, err = state.Environments().CreateFromTemplate(dev, terra.CreateOptions{})
+	assert.ErrorContains(t, err, "is not a template")
+}
+
+func TestPinAndUnpinVersions(t *testing.T) {
+	loader, state := loadTestState(t)
+
+	_, err := state.Environments().PinVersions("fiab-funky-chipmunk", map[string]terra.VersionOverride{
+		"sam": {AppVersion: "9.9.9"},
+	})
+	require.NoError(t, err)
+
+	_, err = state.Environments().PinVersions("fiab-funky-chipmunk", map[string]terra.VersionOverride{
+		"leonardo": {AppVersion: "9.9.9"},
+	})
+	assert.ErrorContains(t, err, `it does not exist in environment "fiab-funky-chipmunk"`)
+
+	require.NoError(t, state.Environments().PinEnvironmentToTerraHelmfileRef("fiab-funky-chipmunk", "other-branch"))
+
+	state, err = loader.Reload()
+	require.NoError(t, err)
+	bee, err := state.Environments().Get("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	assert.Equal(t, "other-branch", bee.TerraHelmfileRef())
+	assert.Equal(t, "9.9.9", bee.Releases()[0].AppVersion())
+	assert.Equal(t, "0.35.0", bee.Releases()[0].ChartVersion())
+	assert.Equal(t, "other-branch", bee.Releases()[0].TerraHelmfileRef())
+
+	removed, err := state.Environments().UnpinVersions("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]terra.VersionOverride{
+		"sam": {AppVersion: "9.9.9", ChartVersion: "0.35.0", TerraHelmfileRef: "other-branch"},
+	}, removed)
+
+	state, err = loader.Reload()
+	require.NoError(t, err)
+	bee, err = state.Environments().Get("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	assert.Equal(t, "HEAD", bee.TerraHelmfileRef())
+	assert.Equal(t, "1.2.2", bee.Releases()[0].AppVersion())
+	assert.Equal(t, "0.33.0", bee.Releases()[0].ChartVersion())
+	assert.Equal(t, "", bee.Releases()[0].TerraHelmfileRef())
+
+	_, err = state.Environments().UnpinVersions("dev")
+	assert.ErrorContains(t, err, "no template")
+}
+
+func TestEnableDisableRelease(t *testing.T) {
+	loader, state := loadTestState(t)
+
+	require.NoError(t, state.Environments().EnableRelease("fiab-funky-chipmunk", "leonardo"))
+	assert.ErrorContains(t, state.Environments().EnableRelease("fiab-funky-chipmunk", "rawls"), `"rawls" does not exist in template`)
+	assert.ErrorContains(t, state.Environments().EnableRelease("dev", "leonardo"), "only supported for dynamic environments")
+
+	state, err := loader.Reload()
+	require.NoError(t, err)
+	bee, err := state.Environments().Get("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	require.Len(t, bee.Releases(), 2)
+	assert.Equal(t, "leonardo", bee.Releases()[0].Name())
+	assert.Equal(t, "terra-fiab-funky-chipmunk", bee.Releases()[0].Namespace())
+
+	require.NoError(t, state.Environments().DisableRelease("fiab-funky-chipmunk", "sam"))
+	state, err = loader.Reload()
+	require.NoError(t, err)
+	bee, err = state.Environments().Get("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	require.Len(t, bee.Releases(), 1)
+	assert.Equal(t, "leonardo", bee.Releases()[0].Name())
+}
+
+func TestSetAutoDeleteAfter(t *testing.T) {
+	loader, state := loadTestState(t)
+
+	after := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, state.Environments().SetAutoDeleteAfter("fiab-funky-chipmunk", after))
+	state, err := loader.Reload()
+	require.NoError(t, err)
+	bee, err := state.Environments().Get("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	assert.True(t, bee.AutoDelete().Enabled())
+	assert.True(t, after.Equal(bee.AutoDelete().After()))
+	assert.True(t, bee.AutoDelete().NotifiedFor().IsZero())
+
+	require.NoError(t, state.Environments().SetAutoDeleteNotified("fiab-funky-chipmunk", after))
+	state, err = loader.Reload()
+	require.NoError(t, err)
+	bee, err = state.Environments().Get("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	assert.True(t, after.Equal(bee.AutoDelete().NotifiedFor()))
+
+	assert.ErrorContains(t, state.Environments().SetAutoDeleteAfter("nope", after), "does not exist")
+	assert.ErrorContains(t, state.Environments().SetAutoDeleteNotified("nope", after), "does not exist")
+}
+
+func TestSetSchedule(t *testing.T) {
+	loader, state := loadTestState(t)
+
+	var opts terra.ScheduleOptions
+	opts.Schedule = &schedule.Schedule{TimeZone: "UTC", Stop: &schedule.Rule{Cron: "0 19 * * *"}}
+	opts.StartOnHolidays = true
+	require.NoError(t, state.Environments().SetSchedule("fiab-funky-chipmunk", opts))
+	state, err := loader.Reload()
+	require.NoError(t, err)
+	bee, err := state.Environments().Get("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	assert.Equal(t, opts.Schedule, bee.OfflineSchedule())
+	assert.True(t, bee.StartOnHolidays())
+
+	// clearing
+	require.NoError(t, state.Environments().SetSchedule("fiab-funky-chipmunk", terra.ScheduleOptions{}))
+	state, err = loader.Reload()
+	require.NoError(t, err)
+	bee, err = state.Environments().Get("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	assert.Nil(t, bee.OfflineSchedule())
+	assert.False(t, bee.StartOnHolidays())
+
+	assert.ErrorContains(t, state.Environments().SetSchedule("nope", opts), "does not exist")
+}
+
+func TestSetOfflineAndDelete(t *testing.T) {
+	loader, state := loadTestState(t)
+
+	require.NoError(t, state.Environments().SetOffline("fiab-funky-chipmunk", false))
+	state, err := loader.Reload()
+	require.NoError(t, err)
+	bee, err := state.Environments().Get("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	assert.False(t, bee.Offline())
+	assert.Empty(t, bee.Releases()[0].HelmfileOverlays())
+
+	require.NoError(t, state.Environments().Delete("fiab-funky-chipmunk"))
+	assert.ErrorContains(t, state.Environments().Delete("fiab-funky-chipmunk"), "does not exist")
+
+	state, err = loader.Reload()
+	require.NoError(t, err)
+	exists, err := state.Environments().Exists("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	releases, err := state.Releases().All()
+	require.NoError(t, err)
+	assert.Len(t, releases, 4)
+}
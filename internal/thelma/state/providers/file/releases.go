@@ -0,0 +1,28 @@
+package file
+
+import "github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+
+type releases struct {
+	state *state
+}
+
+func newReleasesView(s *state) terra.Releases {
+	return &releases{
+		state: s,
+	}
+}
+
+func (r *releases) All() ([]terra.Release, error) {
+	var result []terra.Release
+
+	allDestinations, err := r.state.Destinations().All()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, destination := range allDestinations {
+		result = append(result, destination.Releases()...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package file
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/pkg/errors"
+)
+
+// state is an implementer of terra.State, backed by a state file
+type state struct {
+	store        *store
+	environments map[string]*environment
+	clusters     map[string]*cluster
+}
+
+func (s *state) Destinations() terra.Destinations {
+	return newDestinationsView(s)
+}
+
+func (s *state) Environments() terra.Environments {
+	return newEnvironmentsView(s)
+}
+
+func (s *state) Clusters() terra.Clusters {
+	return newClustersView(s)
+}
+
+func (s *state) Releases() terra.Releases {
+	return newReleasesView(s)
+}
+
+// buildState assembles an in-memory terra.State from a state document
+func buildState(doc *Document, _store *store) (*state, error) {
+	_clusters := make(map[string]*cluster)
+	for _, c := range doc.Clusters {
+		if _, exists := _clusters[c.Name]; exists {
+			return nil, errors.Errorf("cluster %q is defined more than once", c.Name)
+		}
+		_clusters[c.Name] = &cluster{
+			address:       c.Address,
+			googleProject: c.Project,
+			location:      c.Location,
+			releases:      make(map[string]*release),
+			destination: destination{
+				name:             c.Name,
+				base:             c.Base,
+				requiredRole:     c.RequiredRole,
+				destinationType:  terra.ClusterDestination,
+				terraHelmfileRef: c.TerraHelmfileRef,
+			},
+		}
+	}
+
+	_environments := make(map[string]*environment)
+	for _, e := range doc.Environments {
+		if _, exists := _environments[e.Name]; exists {
+			return nil, errors.Errorf("environment %q is defined more than once", e.Name)
+		}
+		if _, exists := _clusters[e.DefaultCluster]; e.DefaultCluster != "" && !exists {
+			return nil, errors.Errorf("environment %q has unknown default cluster %q", e.Name, e.DefaultCluster)
+		}
+		var lifecycle terra.Lifecycle
+		if err := lifecycle.FromString(e.Lifecycle); err != nil {
+			return nil, errors.Errorf("environment %q: %v", e.Name, err)
+		}
+
+		env := &environment{
+			createdAt:                   e.CreatedAt,
+			defaultNamespace:            e.DefaultNamespace,
+			releases:                    make(map[string]*release),
+			lifecycle:                   lifecycle,
+			template:                    e.Template,
+			baseDomain:                  e.BaseDomain,
+			namePrefixesDomain:          e.NamePrefixesDomain,
+			uniqueResourcePrefix:        e.UniqueResourcePrefix,
+			owner:                       e.Owner,
+			preventDeletion:             e.PreventDeletion,
+			autoDelete:                  autoDelete{enabled: e.AutoDelete.Enabled, after: e.AutoDelete.After, notifiedFor: e.AutoDelete.NotifiedFor},
+			offline:                     e.Offline,
+			offlineScheduleBeginEnabled: e.OfflineSchedule.Begin.Enabled,
+			offlineScheduleBeginTime:    e.OfflineSchedule.Begin.Time,
+			offlineScheduleEndEnabled:   e.OfflineSchedule.End.Enabled,
+			offlineScheduleEndTime:      e.OfflineSchedule.End.Time,
+			offlineScheduleEndWeekends:  e.OfflineSchedule.End.Weekends,
+			offlineSchedule:             e.OfflineSchedule.Schedule,
+			startOnHolidays:             e.OfflineSchedule.StartOnHolidays,
+			profile:                     e.Profile,
+			enableJanitor:               e.EnableJanitor,
+			destination: destination{
+				name:             e.Name,
+				base:             e.Base,
+				requiredRole:     e.RequiredRole,
+				destinationType:  terra.EnvironmentDestination,
+				terraHelmfileRef: e.TerraHelmfileRef,
+			},
+		}
+		// avoid storing a typed nil pointer in the interface field
+		if c, exists := _clusters[e.DefaultCluster]; exists {
+			env.defaultCluster = c
+		}
+		_environments[e.Name] = env
+	}
+
+	for _, r := range doc.Releases {
+		_cluster, exists := _clusters[r.Cluster]
+		if !exists {
+			return nil, errors.Errorf("release %q has unknown cluster %q", r.Name, r.Cluster)
+		}
+		_release := &release{
+			name:         r.Name,
+			fullName:     r.FullName,
+			chartVersion: r.ChartVersion,
+			chartName:    r.Chart,
+			repo:         r.Repo,
+			namespace:    r.Namespace,
+			cluster:      _cluster,
+			helmfileRef:  r.TerraHelmfileRef,
+			appVersion:   r.AppVersion,
+			subdomain:    r.Subdomain,
+			protocol:     r.Protocol,
+			port:         r.Port,
+		}
+
+		if r.Environment == "" {
+			if _, exists = _cluster.releases[r.Name]; exists {
+				return nil, errors.Errorf("release %q is defined more than once in cluster %q", r.Name, r.Cluster)
+			}
+			_release.releaseType = terra.ClusterReleaseType
+			_release.destination = _cluster
+			_cluster.releases[r.Name] = _release
+			continue
+		}
+
+		_environment, exists := _environments[r.Environment]
+		if !exists {
+			return nil, errors.Errorf("release %q has unknown environment %q", r.Name, r.Environment)
+		}
+		if _, exists = _environment.releases[r.Name]; exists {
+			return nil, errors.Errorf("release %q is defined more than once in environment %q", r.Name, r.Environment)
+		}
+		if _environment.offline {
+			_release.helmfileOverlays = []string{"offline"}
+		}
+		_release.releaseType = terra.AppReleaseType
+		_release.destination = _environment
+		_environment.releases[r.Name] = _release
+	}
+
+	return &state{
+		store:        _store,
+		environments: _environments,
+		clusters:     _clusters,
+	}, nil
+}
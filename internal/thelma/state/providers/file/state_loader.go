@@ -0,0 +1,40 @@
+package file
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/rs/zerolog/log"
+)
+
+type stateLoader struct {
+	store  *store
+	cached terra.State
+}
+
+// NewStateLoader returns a terra.StateLoader that reads state from a YAML or JSON state file (see Document).
+// Mutations made through the returned state (eg. creating or pinning environments) are written back to the same file.
+func NewStateLoader(file string) terra.StateLoader {
+	return &stateLoader{
+		store: &store{file: file},
+	}
+}
+
+func (s *stateLoader) Load() (terra.State, error) {
+	if s.cached == nil {
+		return s.Reload()
+	}
+	return s.cached, nil
+}
+
+func (s *stateLoader) Reload() (terra.State, error) {
+	log.Debug().Msgf("loading state from %s", s.store.file)
+	doc, err := s.store.read()
+	if err != nil {
+		return nil, err
+	}
+	_state, err := buildState(doc, s.store)
+	if err != nil {
+		return nil, err
+	}
+	s.cached = _state
+	return _state, nil
+}
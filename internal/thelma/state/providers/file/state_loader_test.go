@@ -0,0 +1,229 @@
+package file
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStateYaml = `
+version: 1
+clusters:
+  - name: terra-dev
+    base: terra
+    address: https://35.238.186.116
+    project: broad-dsde-dev
+    location: us-central1-a
+    requiredRole: all-users
+    terraHelmfileRef: HEAD
+  - name: terra-qa-bees
+    base: bee-cluster
+    address: https://35.224.175.229
+    project: broad-dsde-qa
+environments:
+  - name: dev
+    base: live
+    lifecycle: static
+    defaultCluster: terra-dev
+    defaultNamespace: terra-dev
+    baseDomain: dsde-dev.broadinstitute.org
+    requiredRole: all-users
+    preventDeletion: true
+  - name: swatomation
+    base: bee
+    lifecycle: template
+    defaultCluster: terra-qa-bees
+    defaultNamespace: terra-swatomation
+    baseDomain: bee.envs-terra.bio
+    namePrefixesDomain: true
+    terraHelmfileRef: HEAD
+  - name: fiab-funky-chipmunk
+    base: bee
+    lifecycle: dynamic
+    template: swatomation
+    defaultCluster: terra-qa-bees
+    defaultNamespace: terra-fiab-funky-chipmunk
+    baseDomain: bee.envs-terra.bio
+    namePrefixesDomain: true
+    uniqueResourcePrefix: e101
+    owner: jdoe@broadinstitute.org
+    offline: true
+    autoDelete:
+      enabled: true
+      after: 2023-01-02T03:04:05Z
+    offlineSchedule:
+      begin:
+        enabled: true
+        time: 2023-01-01T23:00:00Z
+releases:
+  - name: yale
+    chart: yale
+    repo: terra-helm
+    cluster: terra-dev
+    namespace: yale
+    chartVersion: 0.1.0
+    appVersion: 0.2.0
+  - name: sam
+    chart: sam
+    repo: terra-helm
+    environment: dev
+    cluster: terra-dev
+    namespace: terra-dev
+    chartVersion: 0.34.0
+    appVersion: 1.2.3
+  - name: sam
+    chart: sam
+    repo: terra-helm
+    environment: swatomation
+    cluster: terra-qa-bees
+    namespace: terra-swatomation
+    chartVersion: 0.33.0
+    appVersion: 1.2.2
+  - name: leonardo
+    chart: leonardo
+    repo: terra-helm
+    environment: swatomation
+    cluster: terra-qa-bees
+    namespace: terra-swatomation
+    chartVersion: 0.5.0
+    appVersion: 4.5.6
+  - name: sam
+    chart: sam
+    repo: terra-helm
+    environment: fiab-funky-chipmunk
+    cluster: terra-qa-bees
+    namespace: terra-fiab-funky-chipmunk
+    chartVersion: 0.35.0
+    appVersion: 1.2.4
+    terraHelmfileRef: my-branch
+`
+
+func writeTestStateFile(t *testing.T, name string, content string) string {
+	file := path.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+	return file
+}
+
+func TestStateLoading(t *testing.T) {
+	file := writeTestStateFile(t, "state.yaml", testStateYaml)
+	state, err := NewStateLoader(file).Load()
+	require.NoError(t, err)
+
+	clusters, err := state.Clusters().All()
+	require.NoError(t, err)
+	assert.Len(t, clusters, 2)
+
+	environments, err := state.Environments().All()
+	require.NoError(t, err)
+	assert.Len(t, environments, 3)
+
+	releases, err := state.Releases().All()
+	require.NoError(t, err)
+	assert.Len(t, releases, 5)
+
+	devCluster, err := state.Clusters().Get("terra-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "broad-dsde-dev", devCluster.Project())
+	assert.Equal(t, "dev", devCluster.ProjectSuffix())
+	assert.Equal(t, "thelma-artifacts-terra-dev", devCluster.ArtifactBucket())
+	require.Len(t, devCluster.Releases(), 1)
+	assert.Equal(t, "yale", devCluster.Releases()[0].Name())
+	assert.Equal(t, "yale-terra-dev", devCluster.Releases()[0].FullName())
+	assert.True(t, devCluster.Releases()[0].IsClusterRelease())
+
+	qaCluster, err := state.Clusters().Get("terra-qa-bees")
+	require.NoError(t, err)
+	assert.Equal(t, clusterDefaultLocation, qaCluster.Location())
+
+	dev, err := state.Environments().Get("dev")
+	require.NoError(t, err)
+	assert.Equal(t, terra.Static, dev.Lifecycle())
+	assert.Equal(t, "terra-dev", dev.DefaultCluster().Name())
+	assert.True(t, dev.PreventDeletion())
+	require.Len(t, dev.Releases(), 1)
+	sam, ok := dev.Releases()[0].(terra.AppRelease)
+	require.True(t, ok)
+	assert.Equal(t, "sam-dev", sam.FullName())
+	assert.Equal(t, "1.2.3", sam.AppVersion())
+	assert.Equal(t, "https://sam.dsde-dev.broadinstitute.org", sam.URL())
+	assert.Empty(t, sam.HelmfileOverlays())
+
+	bee, err := state.Environments().Get("fiab-funky-chipmunk")
+	require.NoError(t, err)
+	assert.Equal(t, terra.Dynamic, bee.Lifecycle())
+	assert.Equal(t, "swatomation", bee.Template())
+	assert.Equal(t, "e101", bee.UniqueResourcePrefix())
+	assert.Equal(t, "jdoe@broadinstitute.org", bee.Owner())
+	assert.True(t, bee.Offline())
+	assert.True(t, bee.AutoDelete().Enabled())
+	assert.Equal(t, 2023, bee.AutoDelete().After().Year())
+	assert.True(t, bee.OfflineScheduleBeginEnabled())
+	assert.Equal(t, 23, bee.OfflineScheduleBeginTime().Hour())
+	assert.False(t, bee.OfflineScheduleEndEnabled())
+	require.Len(t, bee.Releases(), 1)
+	beeSam := bee.Releases()[0].(terra.AppRelease)
+	assert.Equal(t, []string{"offline"}, beeSam.HelmfileOverlays())
+	assert.Equal(t, "my-branch", beeSam.TerraHelmfileRef())
+	assert.Equal(t, "https://sam.fiab-funky-chipmunk.bee.envs-terra.bio", beeSam.URL())
+
+	_, err = state.Environments().Get("does-not-exist")
+	assert.ErrorContains(t, err, "does not exist")
+}
+
+func TestStateLoadingJson(t *testing.T) {
+	file := writeTestStateFile(t, "state.json", `{"version": 1, "clusters": [{"name": "terra-dev", "base": "terra", "address": "https://1.2.3.4", "project": "broad-dsde-dev"}]}`)
+	state, err := NewStateLoader(file).Load()
+	require.NoError(t, err)
+
+	exists, err := state.Clusters().Exists("terra-dev")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestStateLoadingErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		errContains string
+	}{
+		{
+			name:        "unsupported version",
+			content:     "version: 99",
+			errContains: "unsupported state file version 99",
+		},
+		{
+			name:        "unknown field",
+			content:     "foo: bar",
+			errContains: "field foo not found",
+		},
+		{
+			name:        "bad lifecycle",
+			content:     "environments: [{name: dev, base: live, lifecycle: permanent}]",
+			errContains: "unknown lifecycle type permanent",
+		},
+		{
+			name:        "unknown cluster",
+			content:     "releases: [{name: sam, chart: sam, cluster: nope}]",
+			errContains: `release "sam" has unknown cluster "nope"`,
+		},
+		{
+			name:        "duplicate environment",
+			content:     "environments: [{name: dev, base: live, lifecycle: static}, {name: dev, base: live, lifecycle: static}]",
+			errContains: `environment "dev" is defined more than once`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := writeTestStateFile(t, "state.yaml", tc.content)
+			_, err := NewStateLoader(file).Load()
+			assert.ErrorContains(t, err, tc.errContains)
+		})
+	}
+
+	_, err := NewStateLoader(path.Join(t.TempDir(), "missing.yaml")).Load()
+	assert.ErrorContains(t, err, "error reading state file")
+}
@@ -0,0 +1,75 @@
+package file
+
+import (
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/rs/zerolog/log"
+)
+
+type stateWriter struct {
+	store *store
+}
+
+// NewStateWriter returns a terra.StateWriter that writes clusters, environments, and releases to a YAML or JSON
+// state file (see Document). The file is created if it does not exist; existing entries with the same names are
+// overwritten.
+func NewStateWriter(file string) terra.StateWriter {
+	return &stateWriter{
+		store: &store{file: file},
+	}
+}
+
+func (s *stateWriter) WriteClusters(clusters []terra.Cluster) error {
+	return s.store.update(func(doc *Document) error {
+		for _, c := range clusters {
+			log.Debug().Msgf("writing cluster %s to %s", c.Name(), s.store.file)
+			addCluster(doc, c)
+		}
+		return nil
+	})
+}
+
+func (s *stateWriter) WriteEnvironments(environments []terra.Environment) ([]string, error) {
+	written := make([]string, 0)
+	err := s.store.update(func(doc *Document) error {
+		for _, e := range environments {
+			log.Debug().Msgf("writing environment %s to %s", e.Name(), s.store.file)
+			addEnvironment(doc, e)
+			written = append(written, e.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return written, nil
+}
+
+func (s *stateWriter) DeleteEnvironments(environments []terra.Environment) ([]string, error) {
+	deleted := make([]string, 0)
+	err := s.store.update(func(doc *Document) error {
+		for _, e := range environments {
+			if doc.environment(e.Name()) == nil {
+				continue
+			}
+			doc.removeEnvironment(e.Name())
+			deleted = append(deleted, e.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+func (s *stateWriter) EnableRelease(environment terra.Environment, releaseName string) error {
+	return s.store.update(func(doc *Document) error {
+		return enableRelease(doc, environment.Name(), releaseName)
+	})
+}
+
+func (s *stateWriter) DisableRelease(environmentName string, releaseName string) error {
+	return s.store.update(func(doc *Document) error {
+		return disableRelease(doc, environmentName, releaseName)
+	})
+}
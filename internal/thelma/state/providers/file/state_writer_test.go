@@ -0,0 +1,54 @@
+package file
+
+import (
+	"path"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateWriterRoundTrip(t *testing.T) {
+	_, original := loadTestState(t)
+
+	for _, ext := range []string{"yaml", "json"} {
+		t.Run(ext, func(t *testing.T) {
+			file := path.Join(t.TempDir(), "exported."+ext)
+			writer := NewStateWriter(file)
+
+			clusters, err := original.Clusters().All()
+			require.NoError(t, err)
+			require.NoError(t, writer.WriteClusters(clusters))
+
+			environments, err := original.Environments().All()
+			require.NoError(t, err)
+			written, err := writer.WriteEnvironments(environments)
+			require.NoError(t, err)
+			assert.Len(t, written, 3)
+
+			expected, err := NewDocument(original)
+			require.NoError(t, err)
+
+			copied, err := NewStateLoader(file).Load()
+			require.NoError(t, err)
+			actual, err := NewDocument(copied)
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, expected.Clusters, actual.Clusters)
+			assert.ElementsMatch(t, expected.Environments, actual.Environments)
+			assert.ElementsMatch(t, expected.Releases, actual.Releases)
+
+			bee, err := copied.Environments().Get("fiab-funky-chipmunk")
+			require.NoError(t, err)
+			deleted, err := writer.DeleteEnvironments([]terra.Environment{bee})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"fiab-funky-chipmunk"}, deleted)
+
+			doc, err := ReadDocument(file)
+			require.NoError(t, err)
+			assert.Nil(t, doc.environment("fiab-funky-chipmunk"))
+			assert.Len(t, doc.Releases, 4)
+		})
+	}
+}
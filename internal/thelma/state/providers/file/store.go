@@ -0,0 +1,138 @@
+package file
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// store manages reads and writes to the state file backing this provider
+type store struct {
+	file string
+}
+
+// read loads the state document from disk
+func (s *store) read() (*Document, error) {
+	return ReadDocument(s.file)
+}
+
+// update performs a read-modify-write of the state document. If the state file does not exist yet, fn is
+// passed an empty document.
+func (s *store) update(fn func(doc *Document) error) error {
+	doc, err := s.read()
+	if err != nil {
+		if _, statErr := os.Stat(s.file); !os.IsNotExist(statErr) {
+			return err
+		}
+		doc = &Document{}
+	}
+	if err = fn(doc); err != nil {
+		return err
+	}
+	return WriteDocument(s.file, doc)
+}
+
+func (d *Document) cluster(name string) *Cluster {
+	for i := range d.Clusters {
+		if d.Clusters[i].Name == name {
+			return &d.Clusters[i]
+		}
+	}
+	return nil
+}
+
+func (d *Document) environment(name string) *Environment {
+	for i := range d.Environments {
+		if d.Environments[i].Name == name {
+			return &d.Environments[i]
+		}
+	}
+	return nil
+}
+
+// mustGetEnvironment is like environment but returns an error if no such environment exists
+func (d *Document) mustGetEnvironment(name string) (*Environment, error) {
+	env := d.environment(name)
+	if env == nil {
+		return nil, errors.Errorf("environment %q does not exist", name)
+	}
+	return env, nil
+}
+
+// release returns the release with the given name in the given environment or cluster
+func (d *Document) release(destinationName string, releaseName string) *Release {
+	for i := range d.Releases {
+		if d.Releases[i].destinationName() == destinationName && d.Releases[i].Name == releaseName {
+			return &d.Releases[i]
+		}
+	}
+	return nil
+}
+
+// appReleasesIn returns pointers to all the app releases in the given environment
+func (d *Document) appReleasesIn(environmentName string) []*Release {
+	var result []*Release
+	for i := range d.Releases {
+		if d.Releases[i].Environment == environmentName {
+			result = append(result, &d.Releases[i])
+		}
+	}
+	return result
+}
+
+// upsertCluster adds the cluster to the document, replacing any existing cluster with the same name
+func (d *Document) upsertCluster(c Cluster) {
+	if existing := d.cluster(c.Name); existing != nil {
+		*existing = c
+		return
+	}
+	d.Clusters = append(d.Clusters, c)
+}
+
+// upsertEnvironment adds the environment to the document, replacing any existing environment with the same name
+func (d *Document) upsertEnvironment(e Environment) {
+	if existing := d.environment(e.Name); existing != nil {
+		*existing = e
+		return
+	}
+	d.Environments = append(d.Environments, e)
+}
+
+// upsertRelease adds the release to the document, replacing any existing release with the same name and destination
+func (d *Document) upsertRelease(r Release) {
+	if existing := d.release(r.destinationName(), r.Name); existing != nil {
+		*existing = r
+		return
+	}
+	d.Releases = append(d.Releases, r)
+}
+
+// removeEnvironment removes the environment and all of its releases from the document
+func (d *Document) removeEnvironment(name string) {
+	var environments []Environment
+	for _, e := range d.Environments {
+		if e.Name != name {
+			environments = append(environments, e)
+		}
+	}
+	d.Environments = environments
+
+	var releases []Release
+	for _, r := range d.Releases {
+		if r.Environment != name {
+			releases = append(releases, r)
+		}
+	}
+	d.Releases = releases
+}
+
+// removeRelease removes a release from the document
+func (d *Document) removeRelease(destinationName string, releaseName string) {
+	var releases []Release
+	for _, r := range d.Releases {
+		if r.destinationName() != destinationName || r.Name != releaseName {
+			releases = append(releases, r)
+		}
+	}
+	d.Releases = releases
+}
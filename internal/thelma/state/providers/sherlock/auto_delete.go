@@ -3,8 +3,9 @@ package sherlock
 import "time"
 
 type autoDelete struct {
-	enabled bool
-	after   time.Time
+	enabled     bool
+	after       time.Time
+	notifiedFor time.Time
 }
 
 func (a autoDelete) Enabled() bool {
@@ -14,3 +15,7 @@ func (a autoDelete) Enabled() bool {
 func (a autoDelete) After() time.Time {
 	return a.after
 }
+
+func (a autoDelete) NotifiedFor() time.Time {
+	return a.notifiedFor
+}
@@ -2,6 +2,7 @@ package sherlock
 
 import (
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
 	"time"
 )
 
@@ -24,6 +25,9 @@ type environment struct {
 	offlineScheduleEndEnabled   bool
 	offlineScheduleEndTime      time.Time
 	offlineScheduleEndWeekends  bool
+	offlineSchedule             *schedule.Schedule
+	startOnHolidays             bool
+	profile                     string
 	enableJanitor               bool
 	destination
 }
@@ -122,6 +126,18 @@ func (e *environment) OfflineScheduleEndWeekends() bool {
 	return e.offlineScheduleEndWeekends
 }
 
+func (e *environment) OfflineSchedule() *schedule.Schedule {
+	return e.offlineSchedule
+}
+
+func (e *environment) StartOnHolidays() bool {
+	return e.startOnHolidays
+}
+
+func (e *environment) Profile() string {
+	return e.profile
+}
+
 func (e *environment) EnableJanitor() bool {
 	return e.enableJanitor
 }
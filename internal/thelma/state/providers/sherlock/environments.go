@@ -1,6 +1,8 @@
 package sherlock
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
@@ -99,3 +101,15 @@ func (e *environments) Delete(name string) error {
 func (e *environments) SetOffline(name string, offline bool) error {
 	return e.state.sherlock.SetEnvironmentOffline(name, offline)
 }
+
+func (e *environments) SetAutoDeleteAfter(name string, after time.Time) error {
+	return e.state.sherlock.SetEnvironmentDeleteAfter(name, after)
+}
+
+func (e *environments) SetAutoDeleteNotified(name string, after time.Time) error {
+	return e.state.sherlock.SetEnvironmentDeleteAfterNotified(name, after)
+}
+
+func (e *environments) SetSchedule(name string, options terra.ScheduleOptions) error {
+	return e.state.sherlock.SetEnvironmentSchedule(name, options)
+}
@@ -3,6 +3,7 @@ package sherlock
 import (
 	"github.com/broadinstitute/thelma/internal/thelma/clients/sherlock"
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"time"
@@ -89,6 +90,14 @@ retry:
 			if stateEnvironment.Offline != nil {
 				offline = *stateEnvironment.Offline
 			}
+			metadata, err := sherlock.EnvironmentMetadataFromDescription(stateEnvironment.Description)
+			if err != nil {
+				log.Warn().Msgf("environment '%s' has an unreadable schedule: %v", stateEnvironment.Name, err)
+				metadata.Schedule = schedule.Invalid(err)
+			}
+			if metadata.DeleteAfterNotified != nil {
+				envAutoDelete.notifiedFor = *metadata.DeleteAfterNotified
+			}
 
 			_environments[stateEnvironment.Name] = &environment{
 				createdAt:                   time.Time(stateEnvironment.CreatedAt),
@@ -109,6 +118,9 @@ retry:
 				offlineScheduleEndEnabled:   stateEnvironment.OfflineScheduleEndEnabled,
 				offlineScheduleEndTime:      time.Time(stateEnvironment.OfflineScheduleEndTime),
 				offlineScheduleEndWeekends:  stateEnvironment.OfflineScheduleEndWeekends,
+				offlineSchedule:             metadata.Schedule,
+				startOnHolidays:             metadata.StartOnHolidays,
+				profile:                     metadata.Profile,
 				enableJanitor:               stateEnvironment.EnableJanitor,
 				destination: destination{
 					name:             stateEnvironment.Name,
@@ -5,7 +5,6 @@ import (
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
 	statemocks "github.com/broadinstitute/thelma/internal/thelma/state/api/terra/mocks"
 	"strings"
-	"time"
 )
 
 type builder struct {
@@ -121,10 +120,21 @@ func (b *builder) populateEnvironmentSet() {
 		env.EXPECT().PreventDeletion().Return(false)
 		env.EXPECT().Owner().Return(e.Owner)
 		env.EXPECT().EnableJanitor().Return(e.EnableJanitor)
+		env.EXPECT().CreatedAt().Return(e.CreatedAt)
+		env.EXPECT().Offline().Return(e.Offline)
+		env.EXPECT().OfflineScheduleBeginEnabled().Return(e.OfflineScheduleBeginEnabled)
+		env.EXPECT().OfflineScheduleBeginTime().Return(e.OfflineScheduleBeginTime)
+		env.EXPECT().OfflineScheduleEndEnabled().Return(e.OfflineScheduleEndEnabled)
+		env.EXPECT().OfflineScheduleEndTime().Return(e.OfflineScheduleEndTime)
+		env.EXPECT().OfflineScheduleEndWeekends().Return(e.OfflineScheduleEndWeekends)
+		env.EXPECT().OfflineSchedule().Return(e.OfflineSchedule)
+		env.EXPECT().StartOnHolidays().Return(e.StartOnHolidays)
+		env.EXPECT().Profile().Return(e.Profile)
 
 		autodelete := new(statemocks.AutoDelete)
-		autodelete.EXPECT().Enabled().Return(false)
-		autodelete.EXPECT().After().Return(time.Time{})
+		autodelete.EXPECT().Enabled().Return(e.AutoDeleteEnabled)
+		autodelete.EXPECT().After().Return(e.AutoDeleteAfter)
+		autodelete.EXPECT().NotifiedFor().Return(e.AutoDeleteNotifiedFor)
 		env.EXPECT().AutoDelete().Return(autodelete)
 		b.environmentSet[e.Name] = env
 	}
@@ -0,0 +1,172 @@
+package statefixtures
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportOptions options for generating fixture data from state
+type ExportOptions struct {
+	// Anonymize if true, replace environment owners and unique resource prefixes with generated values
+	Anonymize bool
+}
+
+type ExportOption func(*ExportOptions)
+
+// FromState generates fixture data from the given state, so that fixtures can be regenerated from live state
+// instead of being written by hand
+func FromState(state terra.State, opts ...ExportOption) (*FixtureData, error) {
+	var options ExportOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	clusters, err := state.Clusters().All()
+	if err != nil {
+		return nil, err
+	}
+	environments, err := state.Environments().All()
+	if err != nil {
+		return nil, err
+	}
+	releases, err := state.Releases().All()
+	if err != nil {
+		return nil, err
+	}
+
+	// sort so that output (and generated anonymized values) are stable across runs
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name() < clusters[j].Name() })
+	sort.Slice(environments, func(i, j int) bool { return environments[i].Name() < environments[j].Name() })
+	sort.Slice(releases, func(i, j int) bool { return releases[i].FullName() < releases[j].FullName() })
+
+	var data FixtureData
+	for _, c := range clusters {
+		data.Clusters = append(data.Clusters, Cluster{
+			Name:             c.Name(),
+			Base:             c.Base(),
+			Address:          c.Address(),
+			Project:          c.Project(),
+			Location:         c.Location(),
+			RequiredRole:     c.RequiredRole(),
+			TerraHelmfileRef: c.TerraHelmfileRef(),
+		})
+	}
+
+	anonymizer := newAnonymizer()
+	for _, e := range environments {
+		env := Environment{
+			Name:                        e.Name(),
+			Base:                        e.Base(),
+			Template:                    e.Template(),
+			Lifecycle:                   e.Lifecycle(),
+			UniqueResourcePrefix:        e.UniqueResourcePrefix(),
+			RequiredRole:                e.RequiredRole(),
+			TerraHelmfileRef:            e.TerraHelmfileRef(),
+			Owner:                       e.Owner(),
+			EnableJanitor:               e.EnableJanitor(),
+			CreatedAt:                   e.CreatedAt(),
+			Offline:                     e.Offline(),
+			OfflineScheduleBeginEnabled: e.OfflineScheduleBeginEnabled(),
+			OfflineScheduleBeginTime:    e.OfflineScheduleBeginTime(),
+			OfflineScheduleEndEnabled:   e.OfflineScheduleEndEnabled(),
+			OfflineScheduleEndTime:      e.OfflineScheduleEndTime(),
+			OfflineScheduleEndWeekends:  e.OfflineScheduleEndWeekends(),
+			OfflineSchedule:             e.OfflineSchedule(),
+			StartOnHolidays:             e.StartOnHolidays(),
+			Profile:                     e.Profile(),
+			AutoDeleteEnabled:           e.AutoDelete().Enabled(),
+			AutoDeleteAfter:             e.AutoDelete().After(),
+			AutoDeleteNotifiedFor:       e.AutoDelete().NotifiedFor(),
+		}
+		if e.DefaultCluster() != nil {
+			env.DefaultCluster = e.DefaultCluster().Name()
+		}
+		if options.Anonymize {
+			env.Owner = anonymizer.owner(env.Owner)
+			env.UniqueResourcePrefix = anonymizer.prefix(env.UniqueResourcePrefix)
+		}
+		data.Environments = append(data.Environments, env)
+	}
+
+	charts := make(map[string]string)
+	for _, r := range releases {
+		charts[r.ChartName()] = r.Repo()
+
+		release := Release{
+			FullName:         r.FullName(),
+			Repo:             r.Repo(),
+			Chart:            r.ChartName(),
+			Cluster:          r.ClusterName(),
+			Namespace:        r.Namespace(),
+			AppVersion:       r.AppVersion(),
+			ChartVersion:     r.ChartVersion(),
+			TerraHelmfileRef: r.TerraHelmfileRef(),
+		}
+		// fixtures default release names to chart names, so only set the name if they differ
+		if r.Name() != r.ChartName() {
+			release.Name = r.Name()
+		}
+		if r.IsAppRelease() {
+			appRelease := r.(terra.AppRelease)
+			release.Environment = appRelease.Environment().Name()
+			release.Port = appRelease.Port()
+			release.Protocol = appRelease.Protocol()
+			release.Subdomain = appRelease.Subdomain()
+		}
+		data.Releases = append(data.Releases, release)
+	}
+
+	var chartNames []string
+	for name := range charts {
+		chartNames = append(chartNames, name)
+	}
+	sort.Strings(chartNames)
+	for _, name := range chartNames {
+		data.Charts = append(data.Charts, Chart{Name: name, Repo: charts[name]})
+	}
+
+	return &data, nil
+}
+
+// Marshal serializes fixture data to YAML, in the format understood by LoadFixtureFromFile
+func (f *FixtureData) Marshal() ([]byte, error) {
+	return yaml.Marshal(f)
+}
+
+// anonymizer consistently replaces owners and prefixes with generated values, so that environments that shared
+// an owner before anonymization still share one afterwards
+type anonymizer struct {
+	owners   map[string]string
+	prefixes map[string]string
+}
+
+func newAnonymizer() *anonymizer {
+	return &anonymizer{
+		owners:   make(map[string]string),
+		prefixes: make(map[string]string),
+	}
+}
+
+func (a *anonymizer) owner(owner string) string {
+	if owner == "" {
+		return ""
+	}
+	if _, exists := a.owners[owner]; !exists {
+		a.owners[owner] = fmt.Sprintf("user%d@example.com", len(a.owners)+1)
+	}
+	return a.owners[owner]
+}
+
+// prefix generates a replacement prefix that is still a valid unique resource prefix ([a-z][a-z0-9]{3})
+func (a *anonymizer) prefix(prefix string) string {
+	if prefix == "" {
+		return ""
+	}
+	if _, exists := a.prefixes[prefix]; !exists {
+		a.prefixes[prefix] = fmt.Sprintf("p%03d", len(a.prefixes)+1)
+	}
+	return a.prefixes[prefix]
+}
@@ -2,6 +2,8 @@ package statefixtures
 
 import (
 	"github.com/broadinstitute/thelma/internal/thelma/state/api/terra"
+	"github.com/broadinstitute/thelma/internal/thelma/utils/schedule"
+	"time"
 )
 
 // FixtureData root type for a fixture definition file
@@ -33,6 +35,25 @@ type Environment struct {
 	TerraHelmfileRef     string
 	Owner                string
 	EnableJanitor        bool
+	CreatedAt            time.Time
+	Offline              bool
+	// OfflineScheduleBeginEnabled is true if the environment has a stop schedule
+	OfflineScheduleBeginEnabled bool
+	OfflineScheduleBeginTime    time.Time
+	// OfflineScheduleEndEnabled is true if the environment has a start schedule
+	OfflineScheduleEndEnabled  bool
+	OfflineScheduleEndTime     time.Time
+	OfflineScheduleEndWeekends bool
+	// OfflineSchedule is an optional cron or weekly schedule
+	OfflineSchedule *schedule.Schedule `yaml:"offlineschedule,omitempty"`
+	// StartOnHolidays is true if the start schedule still applies on holidays
+	StartOnHolidays bool `yaml:"startonholidays,omitempty"`
+	// Profile is the name of the release profile applied to the environment
+	Profile           string `yaml:"profile,omitempty"`
+	AutoDeleteEnabled bool
+	AutoDeleteAfter   time.Time
+	// AutoDeleteNotifiedFor is the deletion time the owner was last notified about
+	AutoDeleteNotifiedFor time.Time
 }
 
 type Chart struct {
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/broadinstitute/thelma/internal/thelma/state/providers/file"
+	"github.com/broadinstitute/thelma/internal/thelma/state/testing/statefixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const liveState = `
+clusters:
+  - name: terra-qa-bees
+    base: terra
+    address: https://10.0.0.1
+    project: broad-dsde-qa
+environments:
+  - name: swatomation
+    base: bee
+    lifecycle: template
+    defaultCluster: terra-qa-bees
+  - name: fiab-jdoe-funky-bee
+    base: bee
+    lifecycle: dynamic
+    template: swatomation
+    defaultCluster: terra-qa-bees
+    uniqueResourcePrefix: e3f1
+    owner: jdoe@broadinstitute.org
+  - name: fiab-jdoe-sleepy-bee
+    base: bee
+    lifecycle: dynamic
+    template: swatomation
+    defaultCluster: terra-qa-bees
+    uniqueResourcePrefix: a9b2
+    owner: jdoe@broadinstitute.org
+    offline: true
+releases:
+  - name: sam
+    chart: sam
+    environment: fiab-jdoe-funky-bee
+    cluster: terra-qa-bees
+    namespace: terra-fiab-jdoe-funky-bee
+    chartVersion: 0.34.0
+    appVersion: 1.2.3
+  - name: yale
+    chart: yale
+    cluster: terra-qa-bees
+    namespace: yale
+    chartVersion: 0.1.0
+    appVersion: 0.0.1
+`
+
+func TestFromState(t *testing.T) {
+	stateFile := path.Join(t.TempDir(), "state.yaml")
+	require.NoError(t, os.WriteFile(stateFile, []byte(liveState), 0644))
+	state, err := file.NewStateLoader(stateFile).Load()
+	require.NoError(t, err)
+
+	data, err := statefixtures.FromState(state)
+	require.NoError(t, err)
+	content, err := data.Marshal()
+	require.NoError(t, err)
+
+	fixtureFile := path.Join(t.TempDir(), "fixture.yaml")
+	require.NoError(t, os.WriteFile(fixtureFile, content, 0644))
+	fixture, err := statefixtures.LoadFixtureFromFile(fixtureFile)
+	require.NoError(t, err)
+
+	bee := fixture.Environment("fiab-jdoe-sleepy-bee")
+	assert.Equal(t, "jdoe@broadinstitute.org", bee.Owner())
+	assert.Equal(t, "a9b2", bee.UniqueResourcePrefix())
+	assert.True(t, bee.Offline())
+	assert.Equal(t, "swatomation", bee.Template())
+	assert.Equal(t, "terra-qa-bees", bee.DefaultCluster().Name())
+
+	assert.Len(t, fixture.AllReleases(), 2)
+	sam := fixture.Release("sam", "fiab-jdoe-funky-bee")
+	assert.Equal(t, "1.2.3", sam.AppVersion())
+	yale := fixture.Release("yale", "terra-qa-bees")
+	assert.True(t, yale.IsClusterRelease())
+}
+
+func TestFromStateAnonymized(t *testing.T) {
+	stateFile := path.Join(t.TempDir(), "state.yaml")
+	require.NoError(t, os.WriteFile(stateFile, []byte(liveState), 0644))
+	state, err := file.NewStateLoader(stateFile).Load()
+	require.NoError(t, err)
+
+	data, err := statefixtures.FromState(state, func(options *statefixtures.ExportOptions) {
+		options.Anonymize = true
+	})
+	require.NoError(t, err)
+
+	owners := make(map[string]string)
+	prefixes := make(map[string]string)
+	for _, env := range data.Environments {
+		owners[env.Name] = env.Owner
+		prefixes[env.Name] = env.UniqueResourcePrefix
+	}
+	assert.Equal(t, map[string]string{
+		"swatomation":          "",
+		"fiab-jdoe-funky-bee":  "user1@example.com",
+		"fiab-jdoe-sleepy-bee": "user1@example.com",
+	}, owners)
+	assert.Equal(t, map[string]string{
+		"swatomation":          "",
+		"fiab-jdoe-funky-bee":  "p001",
+		"fiab-jdoe-sleepy-bee": "p002",
+	}, prefixes)
+
+	content, err := data.Marshal()
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "jdoe@broadinstitute.org")
+	assert.NotContains(t, string(content), "e3f1")
+}
@@ -0,0 +1,49 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		at      string
+		want    bool
+		wantErr string
+	}{
+		{expr: "0 19 * * 1-4", at: "2023-02-23T19:00:00Z", want: true},  // Thursday
+		{expr: "0 19 * * 1-4", at: "2023-02-24T19:00:00Z", want: false}, // Friday
+		{expr: "0 19 * * mon-thu", at: "2023-02-20T19:00:00Z", want: true},
+		{expr: "*/15 * * * *", at: "2023-02-20T10:45:00Z", want: true},
+		{expr: "*/15 * * * *", at: "2023-02-20T10:50:00Z", want: false},
+		{expr: "5/20 * * * *", at: "2023-02-20T10:45:00Z", want: true},
+		{expr: "0 7 * * 7", at: "2023-02-26T07:00:00Z", want: true},   // Sunday as 7
+		{expr: "0 7 1 * mon", at: "2023-03-01T07:00:00Z", want: true}, // day of month OR day of week
+		{expr: "0 7 1 * mon", at: "2023-02-20T07:00:00Z", want: true},
+		{expr: "0 7 1 * mon", at: "2023-02-21T07:00:00Z", want: false},
+		{expr: "0 7 1 jan *", at: "2023-01-01T07:00:00Z", want: true},
+		{expr: "0 7 1 jan *", at: "2023-02-01T07:00:00Z", want: false},
+		{expr: "0 7 * *", wantErr: "expected 5 fields"},
+		{expr: "60 7 * * *", wantErr: "out of range"},
+		{expr: "0 7 * * fun", wantErr: `invalid value "fun"`},
+		{expr: "0 9-7 * * *", wantErr: "invalid range"},
+		{expr: "*/0 7 * * *", wantErr: "invalid step"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr+" at "+tt.at, func(t *testing.T) {
+			expr, err := parseCron(tt.expr)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			at, err := time.Parse(time.RFC3339, tt.at)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, expr.matches(at))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package schedule
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// dateFormat is the format of Holiday dates
+const dateFormat = "2006-01-02"
+
+// Holiday is a date that start schedules don't fire on
+type Holiday struct {
+	// Date in YYYY-MM-DD format
+	Date string `json:"date" yaml:"date"`
+	// Name optional human-readable name, eg. "Christmas Day"
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// Holidays is a holiday calendar, mapping YYYY-MM-DD dates to holiday names. A nil Holidays has no holidays.
+type Holidays map[string]string
+
+// NewHolidays builds a calendar from a list of holidays
+func NewHolidays(holidays []Holiday) (Holidays, error) {
+	result := make(Holidays)
+	for _, holiday := range holidays {
+		if _, err := time.Parse(dateFormat, holiday.Date); err != nil {
+			return nil, errors.Errorf("invalid holiday date %q: expected YYYY-MM-DD", holiday.Date)
+		}
+		result[holiday.Date] = holiday.Name
+	}
+	return result, nil
+}
+
+// IsHoliday returns if the date is a holiday. Like IsWeekendDay, the same time can be different dates in different
+// timezones, so pass a time in the schedule's location.
+func (h Holidays) IsHoliday(date time.Time) bool {
+	_, exists := h[date.Format(dateFormat)]
+	return exists
+}
+
+// Name returns the name of the holiday on the date, or "" if there isn't one (or it has no name)
+func (h Holidays) Name(date time.Time) string {
+	return h[date.Format(dateFormat)]
+}
+
+// ParseICS returns the dates of all events in an iCalendar (.ics) file, such as a public holiday calendar exported
+// from Google Calendar. All-day events spanning multiple days include each day; timed events include only their
+// start date. Recurrence rules aren't expanded, so a recurring event only counts on its first date.
+func ParseICS(content []byte) ([]Holiday, error) {
+	var holidays []Holiday
+	var inEvent bool
+	var start, end, summary string
+	var allDay bool
+
+	for _, line := range unfoldICS(content) {
+		property, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		name, _, _ := strings.Cut(property, ";")
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent = true
+				start, end, summary, allDay = "", "", "", false
+			}
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || !inEvent {
+				continue
+			}
+			inEvent = false
+			dates, err := icsEventDates(start, end, allDay)
+			if err != nil {
+				return nil, errors.Errorf("error parsing event %q: %v", summary, err)
+			}
+			for _, date := range dates {
+				holidays = append(holidays, Holiday{Date: date, Name: summary})
+			}
+		case "DTSTART":
+			if inEvent {
+				start = value
+				// a DATE value (20231225) rather than a DATE-TIME (20231225T090000Z) marks an all-day event
+				allDay = len(value) == 8
+			}
+		case "DTEND":
+			if inEvent {
+				end = value
+			}
+		case "SUMMARY":
+			if inEvent {
+				summary = unescapeICS(value)
+			}
+		}
+	}
+	return holidays, nil
+}
+
+// icsEventDates returns the YYYY-MM-DD dates an event covers. All-day events end on (but don't include) DTEND.
+func icsEventDates(start string, end string, allDay bool) ([]string, error) {
+	if len(start) < 8 {
+		return nil, errors.Errorf("invalid DTSTART %q", start)
+	}
+	first, err := time.Parse("20060102", start[:8])
+	if err != nil {
+		return nil, errors.Errorf("invalid DTSTART %q", start)
+	}
+	last := first
+	if allDay && len(end) >= 8 {
+		endDate, err := time.Parse("20060102", end[:8])
+		if err != nil {
+			return nil, errors.Errorf("invalid DTEND %q", end)
+		}
+		if endDate.After(first) {
+			last = endDate.AddDate(0, 0, -1)
+		}
+	}
+	var dates []string
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date.Format(dateFormat))
+	}
+	return dates, nil
+}
+
+// unfoldICS splits iCalendar content into logical lines; long lines are folded onto lines starting with whitespace
+func unfoldICS(content []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func unescapeICS(value string) string {
+	return strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,69 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"X-WR-CALNAME:Holidays\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20231225\r\n" +
+	"DTEND;VALUE=DATE:20231226\r\n" +
+	"SUMMARY:Christmas Day\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20231123\r\n" +
+	"DTEND;VALUE=DATE:20231125\r\n" +
+	"SUMMARY:Thanksgiving\\, and the day\r\n" +
+	"  after\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=America/New_York:20230704T090000\r\n" +
+	"DTEND;TZID=America/New_York:20230704T170000\r\n" +
+	"SUMMARY:Independence Day\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	holidays, err := ParseICS([]byte(testICS))
+	require.NoError(t, err)
+	assert.Equal(t, []Holiday{
+		{Date: "2023-12-25", Name: "Christmas Day"},
+		{Date: "2023-11-23", Name: "Thanksgiving, and the day after"},
+		{Date: "2023-11-24", Name: "Thanksgiving, and the day after"},
+		{Date: "2023-07-04", Name: "Independence Day"},
+	}, holidays)
+
+	_, err = ParseICS([]byte("BEGIN:VEVENT\nDTSTART:2023\nSUMMARY:Bad\nEND:VEVENT\n"))
+	assert.ErrorContains(t, err, `error parsing event "Bad"`)
+}
+
+func TestHolidays(t *testing.T) {
+	holidays, err := NewHolidays([]Holiday{{Date: "2023-12-25", Name: "Christmas Day"}, {Date: "2023-12-26"}})
+	require.NoError(t, err)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	christmasMorning := time.Date(2023, 12, 25, 7, 0, 0, 0, newYork)
+	assert.True(t, holidays.IsHoliday(christmasMorning))
+	assert.Equal(t, "Christmas Day", holidays.Name(christmasMorning))
+	assert.True(t, holidays.IsHoliday(time.Date(2023, 12, 26, 7, 0, 0, 0, newYork)))
+	assert.Equal(t, "", holidays.Name(time.Date(2023, 12, 26, 7, 0, 0, 0, newYork)))
+
+	// 2023-12-24 22:00 in New York is already Christmas in UTC
+	christmasEve := time.Date(2023, 12, 24, 22, 0, 0, 0, newYork)
+	assert.False(t, holidays.IsHoliday(christmasEve))
+	assert.True(t, holidays.IsHoliday(christmasEve.UTC()))
+
+	var none Holidays
+	assert.False(t, none.IsHoliday(christmasMorning))
+
+	_, err = NewHolidays([]Holiday{{Date: "12/25/2023"}})
+	assert.ErrorContains(t, err, "invalid holiday date")
+}
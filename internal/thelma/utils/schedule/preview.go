@@ -0,0 +1,175 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultApplyWindow is how far back `thelma bees apply-schedule` looks for transitions by default. A stop and a
+// start closer together than this can land in the same run, in which case neither is applied.
+const DefaultApplyWindow = 20 * time.Minute
+
+// previewHorizon is how far ahead Upcoming and CheckConflicts look for transitions. A year covers every cron
+// expression except ones that only fire on Feb 29.
+const previewHorizon = 366 * 24 * time.Hour
+
+// dailyHorizon is how far ahead Daily looks; daily schedules repeat weekly, so two weeks is plenty
+const dailyHorizon = 14 * 24 * time.Hour
+
+// Action is what a Transition does to an environment
+type Action string
+
+const (
+	Stop  Action = "stop"
+	Start Action = "start"
+)
+
+// Transition is a single scheduled stop or start
+type Transition struct {
+	Action Action    `json:"action" yaml:"action"`
+	Time   time.Time `json:"time" yaml:"time"`
+}
+
+// Conflict is a pair of opposite transitions close enough together that apply-schedule could see both in one run
+type Conflict struct {
+	First  Transition    `json:"first" yaml:"first"`
+	Second Transition    `json:"second" yaml:"second"`
+	Gap    time.Duration `json:"gap" yaml:"gap"`
+}
+
+func (c Conflict) String() string {
+	return string(c.First.Action) + " at " + c.First.Time.Format(time.RFC3339) + " and " +
+		string(c.Second.Action) + " at " + c.Second.Time.Format(time.RFC3339) + " are only " + c.Gap.String() + " apart"
+}
+
+// Upcoming returns the next n transitions after from, in the schedule's time zone. Transitions more than a year out
+// aren't included.
+func (s *Schedule) Upcoming(from time.Time, n int) ([]Transition, error) {
+	const chunk = 7 * 24 * time.Hour
+	var result []Transition
+	// scan a week at a time so frequent cron schedules don't walk the whole horizon. Transitions excludes its
+	// bounds, so each chunk starts just before its start time to include transitions exactly on the boundary.
+	for since := from; since.Before(from.Add(previewHorizon)) && len(result) < n; since = since.Add(chunk) {
+		transitions, err := s.between(since.Add(-time.Nanosecond), since.Add(chunk))
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range transitions {
+			if t.Time.After(from) {
+				result = append(result, t)
+			}
+		}
+	}
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result, nil
+}
+
+// CheckConflicts returns an error describing the first stop and start, within a year of from, that are less than
+// window apart
+func (s *Schedule) CheckConflicts(from time.Time, window time.Duration) error {
+	transitions, err := s.between(from, from.Add(previewHorizon))
+	if err != nil {
+		return err
+	}
+	return conflictsError(Conflicts(transitions, window), window)
+}
+
+func (s *Schedule) between(since time.Time, until time.Time) ([]Transition, error) {
+	stops, starts, err := s.Transitions(since, until)
+	if err != nil {
+		return nil, err
+	}
+	return merge(stops, starts), nil
+}
+
+// Daily is the legacy daily schedule set by `thelma bee create --daily-stop-time`/`--daily-start-time`: stop every
+// day at Stop's time of day, and start on weekdays (plus weekends if StartWeekends) at Start's time of day. Each
+// time is evaluated in its own location. A nil time means that transition is disabled.
+type Daily struct {
+	Stop          *time.Time
+	Start         *time.Time
+	StartWeekends bool
+}
+
+// Upcoming returns the next n transitions after from. Transitions more than two weeks out aren't included.
+func (d Daily) Upcoming(from time.Time, n int) []Transition {
+	transitions := d.between(from, from.Add(dailyHorizon))
+	if len(transitions) > n {
+		transitions = transitions[:n]
+	}
+	return transitions
+}
+
+// CheckConflicts returns an error describing the first stop and start that are less than window apart
+func (d Daily) CheckConflicts(from time.Time, window time.Duration) error {
+	return conflictsError(Conflicts(d.between(from, from.Add(dailyHorizon)), window), window)
+}
+
+func (d Daily) between(since time.Time, until time.Time) []Transition {
+	var stops, starts []time.Time
+	if d.Stop != nil {
+		stops = dailyTimes(*d.Stop, since, until, true)
+	}
+	if d.Start != nil {
+		starts = dailyTimes(*d.Start, since, until, d.StartWeekends)
+	}
+	return merge(stops, starts)
+}
+
+// dailyTimes returns the daily repetitions of timeOfDay between since and until, exclusive, in timeOfDay's location
+func dailyTimes(timeOfDay time.Time, since time.Time, until time.Time, weekends bool) []time.Time {
+	var result []time.Time
+	sinceLocal := since.In(timeOfDay.Location())
+	for day := time.Date(sinceLocal.Year(), sinceLocal.Month(), sinceLocal.Day(), 0, 0, 0, 0, timeOfDay.Location()); day.Before(until); day = day.AddDate(0, 0, 1) {
+		if !weekends && IsWeekendDay(day) {
+			continue
+		}
+		t := replaceDateOfTime(timeOfDay, day)
+		if t.After(since) && t.Before(until) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// Conflicts returns each adjacent stop and start in transitions (which must be sorted) that are less than window
+// apart
+func Conflicts(transitions []Transition, window time.Duration) []Conflict {
+	var result []Conflict
+	for i := 1; i < len(transitions); i++ {
+		first, second := transitions[i-1], transitions[i]
+		if first.Action == second.Action {
+			continue
+		}
+		if gap := second.Time.Sub(first.Time); gap < window {
+			result = append(result, Conflict{First: first, Second: second, Gap: gap})
+		}
+	}
+	return result
+}
+
+func conflictsError(conflicts []Conflict, window time.Duration) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return errors.Errorf("%s; stop and start times must be at least %s apart", conflicts[0].String(), window)
+}
+
+// merge combines stop and start times into transitions sorted by time. Simultaneous transitions sort stop first.
+func merge(stops []time.Time, starts []time.Time) []Transition {
+	var result []Transition
+	for _, t := range stops {
+		result = append(result, Transition{Action: Stop, Time: t})
+	}
+	for _, t := range starts {
+		result = append(result, Transition{Action: Start, Time: t})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result
+}
@@ -0,0 +1,164 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Upcoming(t *testing.T) {
+	schedule := &Schedule{
+		TimeZone: "America/New_York",
+		Stop:     &Rule{Weekly: map[string]string{"mon": "19:00", "tue": "19:00", "wed": "19:00", "thu": "19:00", "fri": "15:00"}},
+		Start:    &Rule{Cron: "0 7 * * mon-fri"},
+	}
+	// Thursday afternoon
+	from, err := time.Parse(time.RFC3339, "2023-02-23T12:00:00-05:00")
+	require.NoError(t, err)
+
+	transitions, err := schedule.Upcoming(from, 4)
+	require.NoError(t, err)
+	require.Len(t, transitions, 4)
+
+	var got []string
+	for _, transition := range transitions {
+		got = append(got, string(transition.Action)+" "+transition.Time.Format(time.RFC3339))
+	}
+	assert.Equal(t, []string{
+		"stop 2023-02-23T19:00:00-05:00",
+		"start 2023-02-24T07:00:00-05:00",
+		"stop 2023-02-24T15:00:00-05:00",
+		"start 2023-02-27T07:00:00-05:00",
+	}, got)
+
+	t.Run("transition exactly at from is excluded", func(t *testing.T) {
+		at, err := time.Parse(time.RFC3339, "2023-02-23T19:00:00-05:00")
+		require.NoError(t, err)
+		transitions, err := schedule.Upcoming(at, 1)
+		require.NoError(t, err)
+		require.Len(t, transitions, 1)
+		assert.Equal(t, Start, transitions[0].Action)
+	})
+
+	t.Run("rare cron", func(t *testing.T) {
+		yearly := &Schedule{TimeZone: "UTC", Stop: &Rule{Cron: "0 0 1 jan *"}}
+		transitions, err := yearly.Upcoming(from, 3)
+		require.NoError(t, err)
+		require.Len(t, transitions, 1)
+		assert.Equal(t, "2024-01-01T00:00:00Z", transitions[0].Time.Format(time.RFC3339))
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		_, err := (&Schedule{Stop: &Rule{Cron: "0 7 * * *"}}).Upcoming(from, 1)
+		assert.ErrorContains(t, err, "time zone")
+	})
+}
+
+func TestSchedule_CheckConflicts(t *testing.T) {
+	from, err := time.Parse(time.RFC3339, "2023-02-20T00:00:00Z")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		wantErr  string
+	}{
+		{
+			name: "far apart",
+			schedule: &Schedule{
+				TimeZone: "America/New_York",
+				Stop:     &Rule{Cron: "0 19 * * mon-fri"},
+				Start:    &Rule{Cron: "0 7 * * mon-fri"},
+			},
+		},
+		{
+			name: "simultaneous",
+			schedule: &Schedule{
+				TimeZone: "America/New_York",
+				Stop:     &Rule{Cron: "0 19 * * *"},
+				Start:    &Rule{Weekly: map[string]string{"wed": "19:00"}},
+			},
+			wantErr: "stop at 2023-02-22T19:00:00-05:00 and start at 2023-02-22T19:00:00-05:00 are only 0s apart",
+		},
+		{
+			name: "too close on one day only",
+			schedule: &Schedule{
+				TimeZone: "Europe/London",
+				Stop:     &Rule{Weekly: map[string]string{"mon": "19:00", "fri": "15:00"}},
+				Start:    &Rule{Weekly: map[string]string{"fri": "15:10"}},
+			},
+			wantErr: "stop at 2023-02-24T15:00:00Z and start at 2023-02-24T15:10:00Z are only 10m0s apart; stop and start times must be at least 20m0s apart",
+		},
+		{
+			name: "too close once a year",
+			schedule: &Schedule{
+				TimeZone: "UTC",
+				Stop:     &Rule{Cron: "0 0 * * *"},
+				Start:    &Rule{Cron: "5 0 25 dec *"},
+			},
+			wantErr: "only 5m0s apart",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.schedule.CheckConflicts(from, DefaultApplyWindow)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestDaily(t *testing.T) {
+	stop, err := time.Parse(time.RFC3339, "2023-01-01T19:00:00-05:00")
+	require.NoError(t, err)
+	start, err := time.Parse(time.RFC3339, "2023-01-01T07:00:00-05:00")
+	require.NoError(t, err)
+	// Friday evening
+	from, err := time.Parse(time.RFC3339, "2023-02-24T18:00:00-05:00")
+	require.NoError(t, err)
+
+	daily := Daily{Stop: &stop, Start: &start}
+	transitions := daily.Upcoming(from, 4)
+	var got []string
+	for _, transition := range transitions {
+		got = append(got, string(transition.Action)+" "+transition.Time.Format(time.RFC3339))
+	}
+	assert.Equal(t, []string{
+		"stop 2023-02-24T19:00:00-05:00",
+		"stop 2023-02-25T19:00:00-05:00",
+		"stop 2023-02-26T19:00:00-05:00",
+		"start 2023-02-27T07:00:00-05:00",
+	}, got)
+	assert.NoError(t, daily.CheckConflicts(from, DefaultApplyWindow))
+
+	daily.StartWeekends = true
+	assert.Equal(t, Start, daily.Upcoming(from, 2)[1].Action)
+
+	tooClose := start.Add(10 * time.Minute)
+	daily.Stop = &tooClose
+	assert.ErrorContains(t, daily.CheckConflicts(from, DefaultApplyWindow), "only 10m0s apart")
+}
+
+func TestConflicts(t *testing.T) {
+	at := func(minutes int) time.Time {
+		return time.Date(2023, 2, 24, 0, minutes, 0, 0, time.UTC)
+	}
+	transitions := []Transition{
+		{Action: Stop, Time: at(0)},
+		{Action: Stop, Time: at(5)},
+		{Action: Start, Time: at(30)},
+		{Action: Stop, Time: at(40)},
+	}
+	conflicts := Conflicts(transitions, 20*time.Minute)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, at(30), conflicts[0].First.Time)
+	assert.Equal(t, 10*time.Minute, conflicts[0].Gap)
+
+	assert.Len(t, Conflicts(transitions, 30*time.Minute), 2)
+	assert.Empty(t, Conflicts(transitions, 10*time.Minute))
+}
@@ -0,0 +1,261 @@
+package schedule
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	// embed the IANA time zone database so schedules work on hosts without one
+	_ "time/tzdata"
+)
+
+// Schedule is a start/stop schedule for an environment, evaluated in an explicit IANA time zone. Unlike the daily
+// schedules checked by CheckDailyScheduleMatch, it can express different times on different days, eg.
+// "stop 19:00 Mon-Thu and 15:00 Fri, start 07:00 Mon-Fri":
+//
+//	timeZone: America/New_York
+//	stop:
+//	  weekly: {mon: "19:00", tue: "19:00", wed: "19:00", thu: "19:00", fri: "15:00"}
+//	start:
+//	  cron: "0 7 * * mon-fri"
+type Schedule struct {
+	// TimeZone IANA time zone name the schedule is evaluated in, eg. "America/New_York"
+	TimeZone string `json:"timeZone" yaml:"timeZone"`
+	// Stop when the environment should go offline
+	Stop *Rule `json:"stop,omitempty" yaml:"stop,omitempty"`
+	// Start when the environment should come back online
+	Start *Rule `json:"start,omitempty" yaml:"start,omitempty"`
+
+	// invalid is set on schedules that couldn't be read from state, see Invalid
+	invalid error
+}
+
+// Invalid returns a placeholder for a schedule that couldn't be read from state, whose Validate returns err. This
+// lets commands that act on schedules report the problem instead of treating the environment as unscheduled.
+func Invalid(err error) *Schedule {
+	return &Schedule{invalid: err}
+}
+
+// Rule is a set of times that a schedule transition happens at. Exactly one of Cron or Weekly should be set.
+type Rule struct {
+	// Cron a standard 5-field cron expression, eg. "0 19 * * mon-thu"
+	Cron string `json:"cron,omitempty" yaml:"cron,omitempty"`
+	// Weekly maps three-letter weekday names ("mon") to a 24-hour time of day ("19:00"). Days that aren't listed
+	// have no transition.
+	Weekly map[string]string `json:"weekly,omitempty" yaml:"weekly,omitempty"`
+}
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Validate checks that the schedule has a valid time zone and well-formed rules
+func (s *Schedule) Validate() error {
+	if s.invalid != nil {
+		return s.invalid
+	}
+	if _, err := s.location(); err != nil {
+		return err
+	}
+	if s.Stop == nil && s.Start == nil {
+		return errors.Errorf("schedule must have a stop rule, a start rule, or both")
+	}
+	if s.Stop != nil {
+		if err := s.Stop.Validate(); err != nil {
+			return errors.Errorf("invalid stop rule: %v", err)
+		}
+	}
+	if s.Start != nil {
+		if err := s.Start.Validate(); err != nil {
+			return errors.Errorf("invalid start rule: %v", err)
+		}
+	}
+	return nil
+}
+
+// StopMatches returns true if a stop transition happened between since and now, exclusive
+func (s *Schedule) StopMatches(since time.Time, now time.Time) bool {
+	return s.matches(s.Stop, since, now)
+}
+
+// StartMatches returns true if a start transition happened between since and now, exclusive
+func (s *Schedule) StartMatches(since time.Time, now time.Time) bool {
+	return s.matches(s.Start, since, now)
+}
+
+func (s *Schedule) matches(rule *Rule, since time.Time, now time.Time) bool {
+	if rule == nil {
+		return false
+	}
+	location, err := s.location()
+	if err != nil {
+		return false
+	}
+	transitions, err := rule.transitions(location, since, now)
+	if err != nil {
+		return false
+	}
+	return len(transitions) > 0
+}
+
+// Transitions returns the times of stop and start transitions that happen between since and now, exclusive, in
+// the schedule's time zone
+func (s *Schedule) Transitions(since time.Time, now time.Time) (stops []time.Time, starts []time.Time, err error) {
+	location, err := s.location()
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.Stop != nil {
+		if stops, err = s.Stop.transitions(location, since, now); err != nil {
+			return nil, nil, err
+		}
+	}
+	if s.Start != nil {
+		if starts, err = s.Start.transitions(location, since, now); err != nil {
+			return nil, nil, err
+		}
+	}
+	return stops, starts, nil
+}
+
+func (s *Schedule) location() (*time.Location, error) {
+	if s.TimeZone == "" || s.TimeZone == "Local" {
+		return nil, errors.Errorf("schedule must have an explicit IANA time zone, eg. America/New_York")
+	}
+	location, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, errors.Errorf("invalid time zone %q: %v", s.TimeZone, err)
+	}
+	return location, nil
+}
+
+// Validate checks that exactly one of Cron or Weekly is set, and that it is well-formed
+func (r *Rule) Validate() error {
+	if (r.Cron == "") == (len(r.Weekly) == 0) {
+		return errors.Errorf("exactly one of cron or weekly must be set")
+	}
+	if r.Cron != "" {
+		_, err := parseCron(r.Cron)
+		return err
+	}
+	_, err := r.weeklyTimes()
+	return err
+}
+
+// transitions returns the times the rule fires between since and now, exclusive, in the given location
+func (r *Rule) transitions(location *time.Location, since time.Time, now time.Time) ([]time.Time, error) {
+	var result []time.Time
+	if r.Cron != "" {
+		expr, err := parseCron(r.Cron)
+		if err != nil {
+			return nil, err
+		}
+		// check each minute in the window; a local time skipped by a DST change never fires
+		t := since.In(location).Truncate(time.Minute)
+		if !t.After(since) {
+			t = t.Add(time.Minute)
+		}
+		for ; t.Before(now); t = t.Add(time.Minute) {
+			if expr.matches(t) {
+				result = append(result, t)
+			}
+		}
+		return result, nil
+	}
+
+	times, err := r.weeklyTimes()
+	if err != nil {
+		return nil, err
+	}
+	sinceLocal := since.In(location)
+	nowLocal := now.In(location)
+	for day := time.Date(sinceLocal.Year(), sinceLocal.Month(), sinceLocal.Day(), 0, 0, 0, 0, location); !day.After(nowLocal); day = day.AddDate(0, 0, 1) {
+		timeOfDay, exists := times[day.Weekday()]
+		if !exists {
+			continue
+		}
+		t := time.Date(day.Year(), day.Month(), day.Day(), timeOfDay.Hour(), timeOfDay.Minute(), 0, 0, location)
+		if t.After(since) && t.Before(now) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (r *Rule) weeklyTimes() (map[time.Weekday]time.Time, error) {
+	times := make(map[time.Weekday]time.Time)
+	for day, timeOfDay := range r.Weekly {
+		weekday, err := ParseWeekday(day)
+		if err != nil {
+			return nil, err
+		}
+		t, err := time.Parse("15:04", timeOfDay)
+		if err != nil {
+			return nil, errors.Errorf("invalid time %q for %s: expected 24-hour HH:MM", timeOfDay, day)
+		}
+		times[weekday] = t
+	}
+	return times, nil
+}
+
+// ParseWeekday parses a three-letter or full weekday name, case-insensitively
+func ParseWeekday(name string) (time.Weekday, error) {
+	lower := strings.ToLower(name)
+	for i, weekdayName := range weekdayNames {
+		if lower == weekdayName || lower == strings.ToLower(time.Weekday(i).String()) {
+			return time.Weekday(i), nil
+		}
+	}
+	return 0, errors.Errorf("invalid weekday %q, expected one of %s", name, strings.Join(weekdayNames, ", "))
+}
+
+// ParseWeekly parses weekly times in the form "mon-thu=19:00,fri=15:00" into the form used by Rule.Weekly.
+// Day ranges wrap around the end of the week, so "sat-sun" is the weekend.
+func ParseWeekly(s string) (map[string]string, error) {
+	weekly := make(map[string]string)
+	for _, entry := range strings.Split(s, ",") {
+		days, timeOfDay, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			return nil, errors.Errorf("invalid weekly schedule entry %q, expected <days>=<HH:MM>", entry)
+		}
+		if _, err := time.Parse("15:04", timeOfDay); err != nil {
+			return nil, errors.Errorf("invalid time %q in weekly schedule entry %q: expected 24-hour HH:MM", timeOfDay, entry)
+		}
+
+		first, last, isRange := strings.Cut(days, "-")
+		start, err := ParseWeekday(first)
+		if err != nil {
+			return nil, err
+		}
+		end := start
+		if isRange {
+			if end, err = ParseWeekday(last); err != nil {
+				return nil, err
+			}
+		}
+		for day := start; ; day = (day + 1) % 7 {
+			weekly[weekdayNames[day]] = timeOfDay
+			if day == end {
+				break
+			}
+		}
+	}
+	return weekly, nil
+}
+
+// String returns a short human-readable description of the rule
+func (r *Rule) String() string {
+	if r.Cron != "" {
+		return "cron " + r.Cron
+	}
+	var entries []string
+	for day, timeOfDay := range r.Weekly {
+		entries = append(entries, day+"="+timeOfDay)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		di, _ := ParseWeekday(entries[i][:3])
+		dj, _ := ParseWeekday(entries[j][:3])
+		return (di+6)%7 < (dj+6)%7 // Monday first
+	})
+	return strings.Join(entries, ",")
+}
@@ -0,0 +1,185 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Matches(t *testing.T) {
+	// stop 19:00 Mon-Thu and 15:00 Fri, stay off all weekend, start 07:00 weekdays
+	schedule := &Schedule{
+		TimeZone: "America/New_York",
+		Stop:     &Rule{Weekly: map[string]string{"mon": "19:00", "tue": "19:00", "wed": "19:00", "thu": "19:00", "fri": "15:00"}},
+		Start:    &Rule{Cron: "0 7 * * mon-fri"},
+	}
+	require.NoError(t, schedule.Validate())
+
+	tests := []struct {
+		name      string
+		since     string
+		now       string
+		wantStop  bool
+		wantStart bool
+	}{
+		{
+			name:     "thursday stop",
+			since:    "2023-02-23T18:50:00-05:00",
+			now:      "2023-02-23T19:10:00-05:00",
+			wantStop: true,
+		},
+		{
+			name:  "no thursday stop at friday time",
+			since: "2023-02-23T14:50:00-05:00",
+			now:   "2023-02-23T15:10:00-05:00",
+		},
+		{
+			name:     "friday stop",
+			since:    "2023-02-24T14:50:00-05:00",
+			now:      "2023-02-24T15:10:00-05:00",
+			wantStop: true,
+		},
+		{
+			name:     "friday stop, evaluated in UTC",
+			since:    "2023-02-24T19:50:00Z",
+			now:      "2023-02-24T20:10:00Z",
+			wantStop: true,
+		},
+		{
+			name:  "no saturday start",
+			since: "2023-02-25T06:50:00-05:00",
+			now:   "2023-02-25T07:10:00-05:00",
+		},
+		{
+			name:      "monday start",
+			since:     "2023-02-27T06:50:00-05:00",
+			now:       "2023-02-27T07:10:00-05:00",
+			wantStart: true,
+		},
+		{
+			name:  "window is exclusive",
+			since: "2023-02-27T07:00:00-05:00",
+			now:   "2023-02-27T07:10:00-05:00",
+		},
+		{
+			name:      "monday start after DST change",
+			since:     "2023-03-13T06:50:00-04:00",
+			now:       "2023-03-13T07:10:00-04:00",
+			wantStart: true,
+		},
+		{
+			name:     "stop across midnight in UTC",
+			since:    "2023-02-27T23:50:00Z",
+			now:      "2023-02-28T00:10:00Z",
+			wantStop: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since := testTimeFactory(t, tt.since)
+			now := testTimeFactory(t, tt.now)
+			assert.Equal(t, tt.wantStop, schedule.StopMatches(since, now), "stop")
+			assert.Equal(t, tt.wantStart, schedule.StartMatches(since, now), "start")
+		})
+	}
+}
+
+func TestSchedule_Transitions(t *testing.T) {
+	schedule := &Schedule{
+		TimeZone: "Europe/London",
+		Stop:     &Rule{Weekly: map[string]string{"fri": "17:30"}},
+		Start:    &Rule{Cron: "0 8 * * 1"},
+	}
+	stops, starts, err := schedule.Transitions(
+		testTimeFactory(t, "2023-02-20T00:00:00Z"),
+		testTimeFactory(t, "2023-03-06T00:00:00Z"),
+	)
+	require.NoError(t, err)
+	location, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2023, 2, 24, 17, 30, 0, 0, location),
+		time.Date(2023, 3, 3, 17, 30, 0, 0, location),
+	}, stops)
+	assert.Equal(t, []time.Time{
+		time.Date(2023, 2, 20, 8, 0, 0, 0, location),
+		time.Date(2023, 2, 27, 8, 0, 0, 0, location),
+	}, starts)
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		wantErr  string
+	}{
+		{
+			name:     "missing time zone",
+			schedule: Schedule{Stop: &Rule{Cron: "0 19 * * *"}},
+			wantErr:  "explicit IANA time zone",
+		},
+		{
+			name:     "invalid time zone",
+			schedule: Schedule{TimeZone: "Mars/Olympus_Mons", Stop: &Rule{Cron: "0 19 * * *"}},
+			wantErr:  "invalid time zone",
+		},
+		{
+			name:     "no rules",
+			schedule: Schedule{TimeZone: "UTC"},
+			wantErr:  "must have a stop rule",
+		},
+		{
+			name:     "cron and weekly",
+			schedule: Schedule{TimeZone: "UTC", Stop: &Rule{Cron: "0 19 * * *", Weekly: map[string]string{"mon": "19:00"}}},
+			wantErr:  "invalid stop rule: exactly one",
+		},
+		{
+			name:     "invalid weekday",
+			schedule: Schedule{TimeZone: "UTC", Start: &Rule{Weekly: map[string]string{"funday": "07:00"}}},
+			wantErr:  `invalid weekday "funday"`,
+		},
+		{
+			name:     "invalid time",
+			schedule: Schedule{TimeZone: "UTC", Start: &Rule{Weekly: map[string]string{"mon": "7am"}}},
+			wantErr:  `invalid time "7am"`,
+		},
+		{
+			name:     "unreadable",
+			schedule: *Invalid(errors.Errorf("error parsing schedule")),
+			wantErr:  "error parsing schedule",
+		},
+		{
+			name:     "valid",
+			schedule: Schedule{TimeZone: "UTC", Start: &Rule{Weekly: map[string]string{"Monday": "07:00"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseWeekly(t *testing.T) {
+	weekly, err := ParseWeekly("mon-thu=19:00, fri=15:00")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"mon": "19:00", "tue": "19:00", "wed": "19:00", "thu": "19:00", "fri": "15:00"}, weekly)
+	assert.Equal(t, "mon=19:00,tue=19:00,wed=19:00,thu=19:00,fri=15:00", (&Rule{Weekly: weekly}).String())
+
+	weekly, err = ParseWeekly("sat-sun=10:00")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"sat": "10:00", "sun": "10:00"}, weekly)
+
+	_, err = ParseWeekly("mon")
+	assert.ErrorContains(t, err, "expected <days>=<HH:MM>")
+	_, err = ParseWeekly("mon=25:00")
+	assert.ErrorContains(t, err, "invalid time")
+}